@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/config"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"github.com/protocyber/kelasgo-api/internal/server"
+	"github.com/protocyber/kelasgo-api/internal/util"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// userRow holds the stored text of the users columns tagged with the encrypted serializer, read without it
+type userRow struct {
+	ID          uuid.UUID
+	Phone       *string
+	Address     *string
+	DateOfBirth *string
+	PhoneIndex  *string
+}
+
+// EncryptUsers encrypts the sensitive columns of users written before encryption was enabled. Encrypted
+// values are skipped, so it is safe to run again. It also fills the blind index used to search by phone,
+// hashing the normalized number as searches do. With -decrypt it writes the plaintext back instead, which
+// is needed before rolling back the migration that widened the columns.
+func main() {
+	decrypt := flag.Bool("decrypt", false, "write the plaintext back instead of encrypting")
+	batchSize := flag.Int("batch-size", 500, "number of users updated per transaction")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load configuration")
+	}
+
+	// Setup logger
+	server.SetupLogger(cfg)
+
+	dbConns, err := database.NewConnections(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to connect to database")
+	}
+	defer dbConns.Close()
+
+	convert := func(value string) (string, error) {
+		if database.IsEncrypted(value) {
+			return value, nil
+		}
+		return dbConns.UserFieldCipher.Encrypt(value)
+	}
+	if *decrypt {
+		convert = dbConns.UserFieldCipher.Decrypt
+	}
+
+	var scanned, updated int
+	var rows []userRow
+	err = dbConns.Write.WithContext(context.Background()).
+		Table("users").
+		Select("id, phone, address, date_of_birth, phone_index").
+		FindInBatches(&rows, *batchSize, func(_ *gorm.DB, batch int) error {
+			return dbConns.Write.Transaction(func(tx *gorm.DB) error {
+				for _, row := range rows {
+					scanned++
+					changes := map[string]interface{}{}
+					for column, value := range map[string]*string{
+						"phone":         row.Phone,
+						"address":       row.Address,
+						"date_of_birth": row.DateOfBirth,
+					} {
+						if value == nil {
+							continue
+						}
+						converted, err := convert(*value)
+						if err != nil {
+							return fmt.Errorf("user %s column %s: %w", row.ID, column, err)
+						}
+						if converted != *value {
+							changes[column] = converted
+						}
+					}
+					if row.Phone != nil {
+						phone, err := dbConns.UserFieldCipher.Decrypt(*row.Phone)
+						if err != nil {
+							return fmt.Errorf("user %s column phone: %w", row.ID, err)
+						}
+						// Searches hash the normalized number, and rows written before normalization may hold another form
+						phone = *util.NormalizeIndonesianPhonePtr(&phone)
+						if index := dbConns.UserFieldCipher.BlindIndex(phone); row.PhoneIndex == nil || *row.PhoneIndex != index {
+							changes["phone_index"] = index
+						}
+					}
+					if len(changes) == 0 {
+						continue
+					}
+					if err := tx.Table("users").Where("id = ?", row.ID).UpdateColumns(changes).Error; err != nil {
+						return fmt.Errorf("update user %s: %w", row.ID, err)
+					}
+					updated++
+				}
+				return nil
+			})
+		}).Error
+	if err != nil {
+		log.Fatal().Err(err).Int("users_updated", updated).Msg("Failed to convert user columns")
+	}
+
+	log.Info().
+		Bool("decrypt", *decrypt).
+		Int("users_scanned", scanned).
+		Int("users_updated", updated).
+		Msg("User columns converted")
+}
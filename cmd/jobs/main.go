@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/protocyber/kelasgo-api/internal/app"
+	"github.com/protocyber/kelasgo-api/internal/jobs"
+	"github.com/protocyber/kelasgo-api/internal/server"
+	"github.com/rs/zerolog/log"
+)
+
+// Jobs runs a registered job once and exits, e.g. when the API scheduler is disabled and jobs run from cron:
+//
+//	*/15 * * * * go run ./cmd/jobs -job auto-absent
+func main() {
+	job := flag.String("job", "", "job to run: "+jobs.AutoAbsentName)
+	date := flag.String("date", "", "for "+jobs.AutoAbsentName+", mark this YYYY-MM-DD date in every enabled tenant instead of the days that are over")
+	flag.Parse()
+
+	application, err := app.NewApp()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize application")
+	}
+
+	// Setup logger
+	server.SetupLogger(application.Config)
+
+	defer application.DBConns.Close()
+	defer application.Redis.Close()
+
+	// Stop between tenants on interrupt, the tenant being processed is finished first
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *job == jobs.AutoAbsentName && *date != "" {
+		day, parseErr := time.Parse("2006-01-02", *date)
+		if parseErr != nil {
+			log.Fatal().Err(parseErr).Msg("Invalid date, use the YYYY-MM-DD format")
+		}
+		err = application.AutoAbsentJob.RunDate(ctx, day)
+	} else {
+		// Logged by the scheduler with the job's request ID
+		err = application.Scheduler.Run(ctx, *job)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("job", *job).Strs("jobs", application.Scheduler.Names()).Msg("Job not completed")
+		stop()
+		os.Exit(1)
+	}
+}
@@ -1,11 +1,22 @@
 package main
 
 import (
+	"context"
+
 	"github.com/protocyber/kelasgo-api/internal/app"
 	"github.com/protocyber/kelasgo-api/internal/server"
 	"github.com/rs/zerolog/log"
 )
 
+//	@title						KelasGo API
+//	@version					1.0
+//	@description				Multi-tenant school management API.
+//	@BasePath					/v1
+//	@securityDefinitions.apikey	BearerAuth
+//	@in							header
+//	@name						Authorization
+//	@description				Bearer token, e.g. "Bearer {token}"
+
 func main() {
 	// Initialize the application with all dependencies
 	application, err := app.NewApp()
@@ -22,6 +33,9 @@ func main() {
 	}
 	log.Info().Msg("Database connections healthy")
 
+	// Tenant isolation relies on row level security as well as query filters, warn when it is not in effect
+	checkRowLevelSecurity(application)
+
 	// Create and start server
 	srv := server.New(application, server.SetupRoutes)
 
@@ -30,3 +44,30 @@ func main() {
 		log.Fatal().Err(err).Msg("Failed to start server")
 	}
 }
+
+// checkRowLevelSecurity logs a warning for every way tenant tables are not isolated by row level security
+func checkRowLevelSecurity(application *app.App) {
+	report, err := application.DBConns.CheckRowLevelSecurity(context.Background())
+	if err != nil {
+		log.Warn().Err(err).Msg("Row level security check failed")
+		return
+	}
+
+	unprotected := report.Unprotected()
+	ownedUnforced := report.OwnedUnforced()
+	if len(unprotected) > 0 {
+		log.Warn().
+			Strs("tables", unprotected).
+			Msg("Row level security is not enabled on tenant tables, run the migrations")
+	}
+	if report.BypassRole {
+		log.Warn().Msg("Database role is a superuser or has BYPASSRLS, row level security does not apply to it")
+	} else if len(ownedUnforced) > 0 {
+		log.Warn().
+			Strs("tables", ownedUnforced).
+			Msg("Database role owns tenant tables without FORCE ROW LEVEL SECURITY, their policies do not apply to it")
+	}
+	if len(unprotected) == 0 && len(ownedUnforced) == 0 && !report.BypassRole {
+		log.Info().Int("tables", len(report.Tables)).Msg("Row level security enabled on all tenant tables")
+	}
+}
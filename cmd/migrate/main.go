@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/protocyber/kelasgo-api/internal/config"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"github.com/protocyber/kelasgo-api/internal/server"
+	"github.com/protocyber/kelasgo-api/migrations"
+	"github.com/rs/zerolog/log"
+)
+
+const usage = `Usage: migrate <command> [args]
+
+Commands:
+  up [steps]       Apply pending migrations (default: all)
+  down [steps]     Revert applied migrations, newest first (default: 1)
+  force <version>  Set the version without running migrations, 0 for none
+  version          Show the current version
+  status           List migrations and whether they are applied`
+
+// Migrate applies the SQL migrations embedded from migrations/postgres to the write database
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	command, arg := os.Args[1], ""
+	if len(os.Args) > 2 {
+		arg = os.Args[2]
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load configuration")
+	}
+
+	// Setup logger
+	server.SetupLogger(cfg)
+
+	available, err := database.LoadMigrations(migrations.Postgres, "postgres")
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load migrations")
+	}
+
+	dbConns, err := database.NewConnections(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to connect to database")
+	}
+	defer dbConns.Close()
+
+	sqlDB, err := dbConns.Write.DB()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to get database instance")
+	}
+
+	migrator := database.NewMigrator(sqlDB, available)
+	migrator.OnApply = func(migration database.Migration, up bool) {
+		direction := "up"
+		if !up {
+			direction = "down"
+		}
+		log.Info().
+			Uint64("version", migration.Version).
+			Str("name", migration.Name).
+			Str("direction", direction).
+			Msg("Migration applied")
+	}
+
+	if err := run(context.Background(), migrator, command, arg); err != nil {
+		log.Fatal().Err(err).Str("command", command).Msg("Migration failed")
+	}
+}
+
+// run executes a migrate command
+func run(c context.Context, migrator *database.Migrator, command, arg string) error {
+	switch command {
+	case "up", "down":
+		steps := 0
+		if command == "down" {
+			steps = 1
+		}
+		if arg != "" {
+			n, err := strconv.Atoi(arg)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("steps must be a positive number, got %q", arg)
+			}
+			steps = n
+		}
+
+		var count int
+		var err error
+		if command == "up" {
+			count, err = migrator.Up(c, steps)
+		} else {
+			count, err = migrator.Down(c, steps)
+		}
+		if err != nil {
+			return err
+		}
+		version, _, err := migrator.Version(c)
+		if err != nil {
+			return err
+		}
+		log.Info().Int("migrations", count).Uint64("version", version).Msgf("Migrate %s complete", command)
+		return nil
+
+	case "force":
+		version, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("version must be a number, got %q", arg)
+		}
+		if err := migrator.Force(c, version); err != nil {
+			return err
+		}
+		log.Info().Uint64("version", version).Msg("Migration version forced")
+		return nil
+
+	case "version":
+		version, dirty, err := migrator.Version(c)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d", version)
+		if dirty {
+			fmt.Print(" (dirty)")
+		}
+		fmt.Println()
+		return nil
+
+	case "status":
+		statuses, _, dirty, err := migrator.Status(c)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%-8s %d_%s\n", state, status.Version, status.Name)
+		}
+		if dirty {
+			fmt.Println("database is dirty, fix the failed migration and run force <version>")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown command %q\n\n%s", command, usage)
+}
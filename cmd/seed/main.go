@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+
+	"github.com/protocyber/kelasgo-api/internal/config"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"github.com/protocyber/kelasgo-api/internal/seed"
+	"github.com/protocyber/kelasgo-api/internal/server"
+	"github.com/rs/zerolog/log"
+)
+
+// Seed bootstraps a migrated database with the default roles, the first tenant and a Developer user
+// from the seed section of the configuration. It is safe to run again.
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load configuration")
+	}
+
+	// Setup logger
+	server.SetupLogger(cfg)
+
+	dbConns, err := database.NewConnections(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to connect to database")
+	}
+	defer dbConns.Close()
+
+	result, err := seed.Run(context.Background(), dbConns.Write, cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to seed database")
+	}
+
+	log.Info().
+		Int("roles_created", result.RolesCreated).
+		Str("tenant_id", result.TenantID.String()).
+		Bool("tenant_created", result.TenantCreated).
+		Str("user_id", result.UserID.String()).
+		Bool("user_created", result.UserCreated).
+		Str("username", cfg.Seed.Username).
+		Msg("Database seeded")
+}
@@ -2,24 +2,56 @@ package app
 
 import (
 	"context"
+	"time"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/protocyber/kelasgo-api/internal/config"
 	"github.com/protocyber/kelasgo-api/internal/domain/handler"
 	"github.com/protocyber/kelasgo-api/internal/domain/repository"
 	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/cache"
 	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/event"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/mail"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/scheduler"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/sms"
+	"github.com/protocyber/kelasgo-api/internal/jobs"
 	"github.com/protocyber/kelasgo-api/internal/util"
 )
 
 // App represents the main application structure
 type App struct {
-	AuthHandler    *handler.AuthHandler
-	UserHandler    *handler.UserHandler
-	StudentHandler *handler.StudentHandler
-	DBConns        *database.DatabaseConnections
-	JWTService     *util.JWTService
-	Config         *config.Config
+	AuthHandler          *handler.AuthHandler
+	UserHandler          *handler.UserHandler
+	StudentHandler       *handler.StudentHandler
+	ClassHandler         *handler.ClassHandler
+	EnrollmentHandler    *handler.EnrollmentHandler
+	ClassSubjectHandler  *handler.ClassSubjectHandler
+	AttendanceHandler    *handler.AttendanceHandler
+	FeeHandler           *handler.FeeHandler
+	TenantUserHandler    *handler.TenantUserHandler
+	TeacherHandler       *handler.TeacherHandler
+	GradeHandler         *handler.GradeHandler
+	ReportCardHandler    *handler.ReportCardHandler
+	GPAHandler           *handler.GPAHandler
+	NotificationHandler  *handler.NotificationHandler
+	DashboardHandler     *handler.DashboardHandler
+	AccountHandler       *handler.AccountHandler
+	DiagnosticsHandler   *handler.DiagnosticsHandler
+	APIKeyHandler        *handler.APIKeyHandler
+	APIKeyService        service.APIKeyService
+	TenantSettingHandler *handler.TenantSettingHandler
+	TenantSettingService service.TenantSettingService
+	PermissionRepo       repository.PermissionRepository
+	UserRepo             repository.UserRepository
+	AutoAbsentJob        *jobs.AutoAbsent
+	Scheduler            *scheduler.Scheduler
+	DBConns              *database.DatabaseConnections
+	Mailer               *mail.Mailer
+	SMSDispatcher        *sms.Dispatcher
+	EventBus             *event.Bus
+	Redis                *cache.Redis
+	JWTService           *util.JWTService
+	Config               *config.Config
 }
 
 // NewApp creates and initializes a new App instance with all dependencies
@@ -36,46 +68,163 @@ func NewApp() (*App, error) {
 		return nil, err
 	}
 
-	// Initialize validator
-	validator := validator.New()
-
 	// Initialize JWT service
 	jwtConfig := &config.JWTConfig{
-		Secret:     cfg.JWT.Secret,
-		ExpireTime: cfg.JWT.ExpireTime,
+		Secret:         cfg.JWT.Secret,
+		ExpireTime:     cfg.JWT.ExpireTime,
+		Issuer:         cfg.JWT.Issuer,
+		Audience:       cfg.JWT.Audience,
+		RoleExpireTime: cfg.JWT.RoleExpireTime,
 	}
 	jwtService := util.NewJWTService(jwtConfig)
 
+	// Initialize mail workers
+	mailer := mail.NewMailer(cfg)
+
+	// Initialize SMS workers with the configured provider
+	smsSender, err := sms.NewSender(cfg)
+	if err != nil {
+		return nil, err
+	}
+	smsDispatcher := sms.NewDispatcher(cfg, smsSender)
+
+	// Initialize Redis client
+	redis := cache.NewRedis(cfg)
+
+	// Initialize the event bus services publish domain events to
+	eventBus, err := event.NewBus(cfg, redis)
+	if err != nil {
+		return nil, err
+	}
+	event.SubscribeAuditLog(eventBus)
+
+	// Initialize password hasher
+	passwordHasher := util.NewPasswordHasher(cfg.Security.BcryptCost)
+
 	// Create app context
 	appCtx, err := util.NewAppContext(context.Background(), cfg)
 	if err != nil {
 		return nil, err
 	}
 
+	// Initialize validator, enforcing the configured password policy on "password" tagged fields
+	validator := util.NewValidator(appCtx.GetPasswordPolicy())
+
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(dbConns)
 	roleRepo := repository.NewRoleRepository(dbConns)
 	tenantUserRepo := repository.NewTenantUserRepository(dbConns)
 	tenantUserRoleRepo := repository.NewTenantUserRoleRepository(dbConns)
+	tenantRepo := repository.NewTenantRepository(dbConns)
 	studentRepo := repository.NewStudentRepository(dbConns)
+	classRepo := repository.NewClassRepository(dbConns)
+	academicYearRepo := repository.NewAcademicYearRepository(dbConns)
+	classSubjectRepo := repository.NewClassSubjectRepository(dbConns)
+	enrollmentRepo := repository.NewEnrollmentRepository(dbConns)
+	subjectRepo := repository.NewSubjectRepository(dbConns)
+	teacherRepo := repository.NewTeacherRepository(dbConns)
+	attendanceRepo := repository.NewAttendanceRepository(dbConns)
+	scheduleRepo := repository.NewScheduleRepository(dbConns)
+	gradeRepo := repository.NewGradeRepository(dbConns)
+	studentFeeRepo := repository.NewStudentFeeRepository(dbConns)
+	permissionRepo := repository.NewPermissionRepository(dbConns)
+	notificationRepo := repository.NewNotificationRepository(dbConns)
+	dashboardRepo := repository.NewDashboardRepository(dbConns)
+	accountRepo := repository.NewAccountRepository(dbConns)
+	diagnosticsRepo := repository.NewDiagnosticsRepository(dbConns)
+	sequenceRepo := repository.NewSequenceRepository(dbConns)
+	apiKeyRepo := repository.NewAPIKeyRepository(dbConns)
+	tenantSettingRepo := repository.NewTenantSettingRepository(dbConns)
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, roleRepo, tenantUserRepo, tenantUserRoleRepo, jwtService)
-	userService := service.NewUserService(userRepo, roleRepo, tenantUserRepo, tenantUserRoleRepo)
-	studentService := service.NewStudentService(studentRepo, tenantUserRepo)
+	tenantSettingService := service.NewTenantSettingService(tenantSettingRepo, redis, cfg)
+	authService := service.NewAuthService(userRepo, roleRepo, tenantUserRepo, tenantUserRoleRepo, permissionRepo, jwtService, passwordHasher, mailer, tenantSettingService, &cfg.Auth)
+	userService := service.NewUserService(userRepo, roleRepo, tenantUserRepo, tenantUserRoleRepo, passwordHasher, appCtx.GetPasswordPolicy(), mailer, eventBus)
+	studentService := service.NewStudentService(studentRepo, tenantUserRepo, tenantUserRoleRepo, tenantSettingService, classRepo, academicYearRepo, sequenceRepo, eventBus)
+	classService := service.NewClassService(classRepo, studentRepo, academicYearRepo, classSubjectRepo, gradeRepo, teacherRepo)
+	enrollmentService := service.NewEnrollmentService(enrollmentRepo, classSubjectRepo, studentRepo, academicYearRepo, gradeRepo)
+	classSubjectService := service.NewClassSubjectService(classSubjectRepo, classRepo, subjectRepo, teacherRepo)
+	attendanceService := service.NewAttendanceService(attendanceRepo, scheduleRepo, studentRepo, academicYearRepo)
+	feeService := service.NewFeeService(studentFeeRepo, tenantRepo, sequenceRepo)
+	tenantUserService := service.NewTenantUserService(tenantUserRepo, tenantUserRoleRepo, roleRepo)
+	teacherService := service.NewTeacherService(teacherRepo, scheduleRepo)
+	gradeService := service.NewGradeService(gradeRepo, classSubjectRepo)
+	reportCardService := service.NewReportCardService(studentRepo, tenantRepo, academicYearRepo, gradeRepo, attendanceService)
+	gpaService := service.NewGPAService(studentRepo, academicYearRepo, gradeRepo)
+	notificationService := service.NewNotificationService(notificationRepo, userRepo, tenantUserRepo, mailer, smsDispatcher)
+	accountService := service.NewAccountService(accountRepo, userRepo, passwordHasher, &cfg.Auth)
+	diagnosticsService := service.NewDiagnosticsService(diagnosticsRepo)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo)
+	dashboardService := service.NewDashboardService(dashboardRepo, academicYearRepo, teacherRepo, teacherService, classSubjectService, gradeService, studentService, attendanceService, feeService)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService, validator, appCtx)
 	userHandler := handler.NewUserHandler(userService, validator, appCtx)
 	studentHandler := handler.NewStudentHandler(studentService, validator, appCtx)
+	classHandler := handler.NewClassHandler(classService, validator, appCtx)
+	enrollmentHandler := handler.NewEnrollmentHandler(enrollmentService, validator, appCtx)
+	classSubjectHandler := handler.NewClassSubjectHandler(classSubjectService, validator, appCtx)
+	attendanceHandler := handler.NewAttendanceHandler(attendanceService, validator, appCtx)
+	feeHandler := handler.NewFeeHandler(feeService, validator, appCtx)
+	tenantUserHandler := handler.NewTenantUserHandler(tenantUserService, validator, appCtx)
+	teacherHandler := handler.NewTeacherHandler(teacherService, validator, appCtx)
+	gradeHandler := handler.NewGradeHandler(gradeService, validator, appCtx)
+	reportCardHandler := handler.NewReportCardHandler(reportCardService, validator, appCtx)
+	gpaHandler := handler.NewGPAHandler(gpaService, appCtx)
+	notificationHandler := handler.NewNotificationHandler(notificationService, validator, appCtx)
+	dashboardHandler := handler.NewDashboardHandler(dashboardService, appCtx)
+	accountHandler := handler.NewAccountHandler(accountService, validator, appCtx)
+	diagnosticsHandler := handler.NewDiagnosticsHandler(diagnosticsService, appCtx)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService, validator, appCtx)
+	tenantSettingHandler := handler.NewTenantSettingHandler(tenantSettingService, validator, appCtx)
+
+	// Initialize jobs
+	autoAbsentJob := jobs.NewAutoAbsent(tenantRepo, tenantSettingService, attendanceService)
+
+	// Register the jobs to schedule, the server starts the scheduler
+	// Without a Redis host there is no lock to elect an instance, so each instance runs the jobs
+	var schedulerLock *cache.Redis
+	if cfg.Cache.Redis.Primary.Host != "" {
+		schedulerLock = redis
+	}
+	jobScheduler := scheduler.New(schedulerLock)
+	jobScheduler.Register(jobs.AutoAbsentName, cfg.GetAutoAbsentInterval(), func(ctx context.Context) error {
+		return autoAbsentJob.Run(ctx, time.Now())
+	})
 
 	// Create and return the app
 	return &App{
-		AuthHandler:    authHandler,
-		UserHandler:    userHandler,
-		StudentHandler: studentHandler,
-		DBConns:        dbConns,
-		JWTService:     jwtService,
-		Config:         cfg,
+		AuthHandler:          authHandler,
+		UserHandler:          userHandler,
+		StudentHandler:       studentHandler,
+		ClassHandler:         classHandler,
+		EnrollmentHandler:    enrollmentHandler,
+		ClassSubjectHandler:  classSubjectHandler,
+		AttendanceHandler:    attendanceHandler,
+		FeeHandler:           feeHandler,
+		TenantUserHandler:    tenantUserHandler,
+		TeacherHandler:       teacherHandler,
+		GradeHandler:         gradeHandler,
+		ReportCardHandler:    reportCardHandler,
+		GPAHandler:           gpaHandler,
+		NotificationHandler:  notificationHandler,
+		DashboardHandler:     dashboardHandler,
+		AccountHandler:       accountHandler,
+		DiagnosticsHandler:   diagnosticsHandler,
+		APIKeyHandler:        apiKeyHandler,
+		APIKeyService:        apiKeyService,
+		TenantSettingHandler: tenantSettingHandler,
+		TenantSettingService: tenantSettingService,
+		PermissionRepo:       permissionRepo,
+		UserRepo:             userRepo,
+		AutoAbsentJob:        autoAbsentJob,
+		Scheduler:            jobScheduler,
+		DBConns:              dbConns,
+		Mailer:               mailer,
+		SMSDispatcher:        smsDispatcher,
+		EventBus:             eventBus,
+		Redis:                redis,
+		JWTService:           jwtService,
+		Config:               cfg,
 	}, nil
 }
@@ -0,0 +1,79 @@
+package apperror
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Error kinds services report. Match them with errors.Is.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrInternal     = errors.New("internal error")
+)
+
+// Error is an error of a known kind carrying a message that is safe to show to clients
+type Error struct {
+	Kind    error
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap exposes the kind so errors.Is(err, ErrNotFound) matches
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// NotFound reports a missing resource, or one that belongs to another tenant
+func NotFound(message string) error {
+	return &Error{Kind: ErrNotFound, Message: message}
+}
+
+// Conflict reports a request clashing with existing data, e.g. a duplicate unique value
+func Conflict(message string) error {
+	return &Error{Kind: ErrConflict, Message: message}
+}
+
+// Validation reports a request that is invalid for the current state of the data
+func Validation(message string) error {
+	return &Error{Kind: ErrValidation, Message: message}
+}
+
+// Forbidden reports an operation the caller is not allowed to perform
+func Forbidden(message string) error {
+	return &Error{Kind: ErrForbidden, Message: message}
+}
+
+// Unauthorized reports missing or wrong credentials
+func Unauthorized(message string) error {
+	return &Error{Kind: ErrUnauthorized, Message: message}
+}
+
+// Internal reports an unexpected failure. The cause is logged by the caller, not exposed.
+func Internal(message string) error {
+	return &Error{Kind: ErrInternal, Message: message}
+}
+
+// HTTPStatus maps an error to its HTTP status code. Errors without a kind are internal errors.
+func HTTPStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, ErrUnauthorized):
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
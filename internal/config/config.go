@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -24,6 +25,28 @@ type PGConnectionConfig = struct {
 type JWTConfig = struct {
 	Secret     string `mapstructure:"secret"`
 	ExpireTime int    `mapstructure:"expire_time"` // in hours
+	// RoleExpireTime overrides ExpireTime for tokens of users with the given role names, in minutes
+	RoleExpireTime map[string]int `mapstructure:"role_expire_time"`
+	Issuer         string         `mapstructure:"issuer"`
+	Audience       string         `mapstructure:"audience"`
+}
+
+type AuthConfig = struct {
+	RequireEmailVerification    bool   `mapstructure:"require_email_verification"`
+	EmailVerificationExpireTime int    `mapstructure:"email_verification_expire_time"` // in hours
+	EmailVerificationURL        string `mapstructure:"email_verification_url"`
+	DefaultRole                 string `mapstructure:"default_role"`           // Role assigned on self-registration, none when empty
+	SelfRegistration            bool   `mapstructure:"self_registration"`      // Users may join a tenant by naming it at registration
+	AllowAccountDeletion        bool   `mapstructure:"allow_account_deletion"` // Users may delete and anonymize their own account
+	SingleTenantMode            bool   `mapstructure:"single_tenant_mode"`     // Login selects the tenant of users with exactly one membership
+}
+
+type PasswordPolicyConfig = struct {
+	MinLength     int  `mapstructure:"min_length"` // Never below 6
+	RequireUpper  bool `mapstructure:"require_upper"`
+	RequireLower  bool `mapstructure:"require_lower"`
+	RequireDigit  bool `mapstructure:"require_digit"`
+	RequireSymbol bool `mapstructure:"require_symbol"`
 }
 
 type CORSConfig = struct {
@@ -35,15 +58,33 @@ type CORSConfig = struct {
 	MaxAgeSeconds    int    `mapstructure:"max_age_seconds"`
 }
 
+type CompressionConfig = struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	Level         int    `mapstructure:"level"`          // gzip level from 1 (fastest) to 9 (smallest), -1 for the default
+	MinSize       int    `mapstructure:"min_size"`       // in bytes, smaller responses are sent uncompressed
+	ExcludedPaths string `mapstructure:"excluded_paths"` // Comma-separated path prefixes never compressed
+}
+
 // Config holds all configuration for our application
 type Config struct {
 	Server struct {
-		Host                         string `mapstructure:"host"`
-		Port                         string `mapstructure:"port"`
-		Env                          string `mapstructure:"env"`
-		LogLevel                     string `mapstructure:"log_level"`
-		ShutdownCleanupPeriodSeconds int    `mapstructure:"shutdown_cleanup_period_seconds"`
-		ShutdownGracePeriodSeconds   int    `mapstructure:"shutdown_grace_period_seconds"`
+		Host     string `mapstructure:"host"`
+		Port     string `mapstructure:"port"`
+		Env      string `mapstructure:"env"`
+		LogLevel string `mapstructure:"log_level"`
+		// TrustedProxies are the IPs or CIDRs of load balancers whose X-Forwarded-For and X-Real-IP headers
+		// give the client IP, none by default so the headers can't be spoofed by clients
+		TrustedProxies []string `mapstructure:"trusted_proxies"`
+		// RequestTimeoutMS bounds every request, 0 disables the timeout
+		RequestTimeoutMS int `mapstructure:"request_timeout_ms"`
+		// RequestTimeoutBypass are the routes exempt from the request timeout, e.g. "/v1/auth/me/export"
+		RequestTimeoutBypass []string `mapstructure:"request_timeout_bypass"`
+		// RequestLogSkip are the routes whose requests are not logged, e.g. "/v1/health" polled by probes
+		RequestLogSkip []string `mapstructure:"request_log_skip"`
+		Shutdown       struct {
+			CleanupPeriodSeconds int `mapstructure:"cleanup_period_seconds"`
+			GracePeriodSeconds   int `mapstructure:"grace_period_seconds"`
+		} `mapstructure:"shutdown"`
 	} `mapstructure:"server"`
 
 	Database struct {
@@ -51,10 +92,36 @@ type Config struct {
 			Read  PGConnectionConfig `mapstructure:"read"`
 			Write PGConnectionConfig `mapstructure:"write"`
 		} `mapstructure:"pg"`
+		// QueryTimeoutMS bounds every database statement, 0 disables the timeout
+		QueryTimeoutMS int `mapstructure:"query_timeout_ms"`
+		// SlowQueryMS logs statements taking at least this long at SlowQueryLogLevel, 0 disables it
+		SlowQueryMS       int    `mapstructure:"slow_query_ms"`
+		SlowQueryLogLevel string `mapstructure:"slow_query_log_level"`
+		// RedactQueryParams logs statements with placeholders instead of their bound values
+		RedactQueryParams bool `mapstructure:"redact_query_params"`
+		Search            struct {
+			// SimilarityThreshold is the minimum trigram word similarity (0-1) for fuzzy matches, 0 disables fuzzy matching
+			SimilarityThreshold float64 `mapstructure:"similarity_threshold"`
+		} `mapstructure:"search"`
 	} `mapstructure:"db"`
 
 	JWT JWTConfig `mapstructure:"jwt"`
 
+	Auth AuthConfig `mapstructure:"auth"`
+
+	Security struct {
+		BcryptCost int                  `mapstructure:"bcrypt_cost"`
+		Password   PasswordPolicyConfig `mapstructure:"password"` // Rules for new passwords
+		RateLimit  struct {
+			// AvailabilityCheck is how many username/email availability checks a client IP may make per minute
+			AvailabilityCheck int `mapstructure:"availability_check"`
+		} `mapstructure:"rate_limit"`
+		// APIKeys enables API key authentication of server-to-server integrations on /v1/integrations
+		APIKeys struct {
+			Enabled bool `mapstructure:"enabled"`
+		} `mapstructure:"api_keys"`
+	} `mapstructure:"security"`
+
 	Logger struct {
 		Level  string `mapstructure:"log_level"`
 		Format string `mapstructure:"format"` // json, console
@@ -68,11 +135,15 @@ type Config struct {
 		Timezone    string `mapstructure:"timezone"`
 		Locale      string `mapstructure:"locale"`
 		Pagination  struct {
-			DefaultLimit int  `mapstructure:"default_limit"`
-			MaxLimit     int  `mapstructure:"max_limit"`
-			Enabled      bool `mapstructure:"enabled"`
+			DefaultLimit         int   `mapstructure:"default_limit"`
+			MaxLimit             int   `mapstructure:"max_limit"`
+			Enabled              bool  `mapstructure:"enabled"`
+			LargeResultThreshold int64 `mapstructure:"large_result_threshold"` // Total rows from which list responses carry a size warning, 0 disables it
+			MetaWarning          bool  `mapstructure:"meta_warning"`           // Repeat result size warnings in the response meta
 		} `mapstructure:"pagination"`
-		CORS CORSConfig `mapstructure:"cors"`
+		CORS         CORSConfig        `mapstructure:"cors"`
+		Compression  CompressionConfig `mapstructure:"compression"`
+		BulkMaxItems int               `mapstructure:"bulk_max_items"` // Most IDs accepted by a bulk request
 	} `mapstructure:"app"`
 
 	Mail struct {
@@ -80,9 +151,48 @@ type Config struct {
 		Port     int    `mapstructure:"port"`
 		Username string `mapstructure:"username"`
 		Password string `mapstructure:"password"`
+		From     string `mapstructure:"from"`
 		Workers  int    `mapstructure:"workers"`
 	} `mapstructure:"mail"`
 
+	SMS struct {
+		Provider   string `mapstructure:"provider"` // "log" or "twilio"
+		BaseURL    string `mapstructure:"base_url"` // For Twilio compatible providers
+		AccountSID string `mapstructure:"account_sid"`
+		AuthToken  string `mapstructure:"auth_token"`
+		From       string `mapstructure:"from"`
+		Workers    int    `mapstructure:"workers"`
+		Timeout    int    `mapstructure:"timeout"` // in seconds
+	} `mapstructure:"sms"`
+
+	// Events configures the bus services publish domain events to
+	Events struct {
+		Backend string `mapstructure:"backend"` // "memory" for a single instance or "redis" to share events across instances
+		Workers int    `mapstructure:"workers"`
+	} `mapstructure:"events"`
+
+	// Jobs configures the recurring background jobs, scheduled by the API or run once by cmd/jobs
+	Jobs struct {
+		Scheduler struct {
+			Enabled bool `mapstructure:"enabled"` // Run the jobs at their intervals in the API process
+		} `mapstructure:"scheduler"`
+		AutoAbsent struct {
+			Enabled         bool   `mapstructure:"enabled"`          // Default of the attendance_auto_absent tenant setting
+			After           string `mapstructure:"after"`            // Default of the attendance_auto_absent_after tenant setting, "HH:MM"
+			IntervalMinutes int    `mapstructure:"interval_minutes"` // How often the scheduler runs the job, 0 disables it
+		} `mapstructure:"auto_absent"`
+	} `mapstructure:"jobs"`
+
+	// Seed is the first tenant and Developer user created by cmd/seed on a fresh database
+	Seed struct {
+		TenantName   string `mapstructure:"tenant_name"`
+		TenantDomain string `mapstructure:"tenant_domain"` // Optional, the tenant is looked up by name when empty
+		Username     string `mapstructure:"username"`
+		Email        string `mapstructure:"email"`
+		FullName     string `mapstructure:"full_name"`
+		Password     string `mapstructure:"password"` // Prefer SEED_PASSWORD over keeping it in the file
+	} `mapstructure:"seed"`
+
 	Cache struct {
 		Redis struct {
 			Primary struct {
@@ -92,6 +202,10 @@ type Config struct {
 				DB       int    `mapstructure:"db"`
 			} `mapstructure:"primary"`
 		} `mapstructure:"redis"`
+		// IdempotencyTTL is how long replayable responses of create requests are kept, in hours
+		IdempotencyTTL int `mapstructure:"idempotency_ttl"`
+		// TenantSettingsTTL is how long a tenant's settings are cached, in seconds
+		TenantSettingsTTL int `mapstructure:"tenant_settings_ttl"`
 	} `mapstructure:"cache"`
 
 	External struct {
@@ -120,6 +234,10 @@ func Load() (*Config, error) {
 	viper.SetDefault("server.port", "8080")
 	viper.SetDefault("server.env", "development")
 	viper.SetDefault("server.log_level", "info")
+	viper.SetDefault("server.trusted_proxies", []string{})
+	viper.SetDefault("server.request_timeout_ms", 30000)
+	viper.SetDefault("server.request_timeout_bypass", []string{"/v1/auth/me/export", "/v1/students/:id/report-card", "/v1/fees/:id/receipt"})
+	viper.SetDefault("server.request_log_skip", []string{"/v1/health"})
 	viper.SetDefault("server.shutdown.cleanup_period_seconds", 3)
 	viper.SetDefault("server.shutdown.grace_period_seconds", 3)
 
@@ -133,6 +251,9 @@ func Load() (*Config, error) {
 	viper.SetDefault("app.pagination.default_limit", 10)
 	viper.SetDefault("app.pagination.max_limit", 100)
 	viper.SetDefault("app.pagination.enabled", true)
+	viper.SetDefault("app.pagination.large_result_threshold", 10000)
+	viper.SetDefault("app.pagination.meta_warning", true)
+	viper.SetDefault("app.bulk_max_items", 200)
 
 	viper.SetDefault("app.cors.enabled", true)
 	viper.SetDefault("app.cors.allow_credentials", true)
@@ -141,15 +262,72 @@ func Load() (*Config, error) {
 	viper.SetDefault("app.cors.allowed_origins", "http://localhost:8080,http://127.0.0.1:8080")
 	viper.SetDefault("app.cors.max_age_seconds", 300)
 
+	viper.SetDefault("app.compression.enabled", true)
+	viper.SetDefault("app.compression.level", -1)
+	viper.SetDefault("app.compression.min_size", 1024)
+	viper.SetDefault("app.compression.excluded_paths", "")
+
 	viper.SetDefault("mail.host", "smtp.gmail.com")
 	viper.SetDefault("mail.port", 587)
 	viper.SetDefault("mail.workers", 5)
 
+	viper.SetDefault("sms.provider", "log")
+	viper.SetDefault("sms.workers", 2)
+	viper.SetDefault("sms.timeout", 10)
+
+	viper.SetDefault("events.backend", "memory")
+	viper.SetDefault("events.workers", 2)
+
+	viper.SetDefault("jobs.scheduler.enabled", true)
+	viper.SetDefault("jobs.auto_absent.enabled", false)
+	viper.SetDefault("jobs.auto_absent.after", "18:00")
+	viper.SetDefault("jobs.auto_absent.interval_minutes", 15)
+
+	viper.SetDefault("seed.tenant_name", "KelasGo")
+	viper.SetDefault("seed.tenant_domain", "")
+	viper.SetDefault("seed.username", "developer")
+	viper.SetDefault("seed.email", "")
+	viper.SetDefault("seed.full_name", "Developer")
+	viper.SetDefault("seed.password", "")
+
 	viper.SetDefault("cache.redis.primary.host", "localhost")
 	viper.SetDefault("cache.redis.primary.port", 6379)
 	viper.SetDefault("cache.redis.primary.db", 1)
+	viper.SetDefault("cache.idempotency_ttl", 24)
+	viper.SetDefault("cache.tenant_settings_ttl", 300)
 
 	viper.SetDefault("jwt.expire_time", 24) // in hours
+	viper.SetDefault("jwt.issuer", "kelasgo-api")
+	viper.SetDefault("jwt.audience", "kelasgo-api")
+
+	viper.SetDefault("security.bcrypt_cost", 12)
+	viper.SetDefault("security.password.min_length", 6)
+	viper.SetDefault("security.password.require_upper", false)
+	viper.SetDefault("security.password.require_lower", false)
+	viper.SetDefault("security.password.require_digit", false)
+	viper.SetDefault("security.password.require_symbol", false)
+	viper.SetDefault("security.rate_limit.availability_check", 20)
+	viper.SetDefault("security.api_keys.enabled", false)
+
+	viper.SetDefault("auth.require_email_verification", false)
+	viper.SetDefault("auth.email_verification_expire_time", 24) // in hours
+	viper.SetDefault("auth.default_role", "")
+	viper.SetDefault("auth.self_registration", false)
+	viper.SetDefault("auth.allow_account_deletion", true)
+	viper.SetDefault("auth.single_tenant_mode", false)
+
+	for _, pool := range []string{"read", "write"} {
+		viper.SetDefault("db.pg."+pool+".max_connection_lifetime", "5m")
+		viper.SetDefault("db.pg."+pool+".max_idle_connection", 10)
+		viper.SetDefault("db.pg."+pool+".max_open_connection", 10)
+	}
+	viper.SetDefault("db.query_timeout_ms", 10000)
+	viper.SetDefault("db.slow_query_ms", 200)
+	viper.SetDefault("db.slow_query_log_level", "warn")
+	viper.SetDefault("db.redact_query_params", true)
+	viper.SetDefault("db.search.similarity_threshold", 0.4)
+
+	viper.SetDefault("encryption.key.users", "")
 
 	// Read from YAML config file
 	viper.SetConfigName("config")
@@ -179,11 +357,25 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
 
+	// A comma-separated environment variable may leave spaces around the entries
+	trustedProxies := cfg.Server.TrustedProxies[:0]
+	for _, proxy := range cfg.Server.TrustedProxies {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+	cfg.Server.TrustedProxies = trustedProxies
+
 	// Set JWT expire time (in hours) manually if not set
 	if cfg.JWT.ExpireTime == 0 {
 		cfg.JWT.ExpireTime = 24
 	}
 
+	// Verification links point at the API unless a frontend URL is configured
+	if cfg.Auth.EmailVerificationURL == "" {
+		cfg.Auth.EmailVerificationURL = strings.TrimRight(cfg.App.URL, "/") + "/v1/auth/verify-email"
+	}
+
 	// Set logger format
 	cfg.Logger.Format = "json" // Default to JSON format
 	if cfg.IsDevelopment() {
@@ -235,6 +427,55 @@ func (c *Config) GetHost() string {
 	return c.Server.Host
 }
 
+// GetRequestTimeout returns how long a request may take before it is cut off, 0 when it is not bounded
+func (c *Config) GetRequestTimeout() time.Duration {
+	if c.Server.RequestTimeoutMS <= 0 {
+		return 0
+	}
+	return time.Duration(c.Server.RequestTimeoutMS) * time.Millisecond
+}
+
+// GetShutdownGracePeriod returns how long graceful shutdown may take before in-flight work is dropped
+func (c *Config) GetShutdownGracePeriod() time.Duration {
+	if c.Server.Shutdown.GracePeriodSeconds <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(c.Server.Shutdown.GracePeriodSeconds) * time.Second
+}
+
+// GetShutdownCleanupPeriod returns how long flushing queued work and closing connections may take once the
+// HTTP server has stopped
+func (c *Config) GetShutdownCleanupPeriod() time.Duration {
+	if c.Server.Shutdown.CleanupPeriodSeconds <= 0 {
+		return 3 * time.Second
+	}
+	return time.Duration(c.Server.Shutdown.CleanupPeriodSeconds) * time.Second
+}
+
+// GetIdempotencyTTL returns how long idempotency keys are remembered
+func (c *Config) GetIdempotencyTTL() time.Duration {
+	if c.Cache.IdempotencyTTL <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(c.Cache.IdempotencyTTL) * time.Hour
+}
+
+// GetTenantSettingsTTL returns how long tenant settings are cached
+func (c *Config) GetTenantSettingsTTL() time.Duration {
+	if c.Cache.TenantSettingsTTL <= 0 {
+		return 5 * time.Minute
+	}
+	return time.Duration(c.Cache.TenantSettingsTTL) * time.Second
+}
+
+// GetAutoAbsentInterval returns how often the scheduler runs the auto absent job, 0 when it is not scheduled
+func (c *Config) GetAutoAbsentInterval() time.Duration {
+	if c.Jobs.AutoAbsent.IntervalMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(c.Jobs.AutoAbsent.IntervalMinutes) * time.Minute
+}
+
 // IsProduction returns true if the server environment is production
 func (c *Config) IsProduction() bool {
 	return c.Server.Env == "production"
@@ -0,0 +1,129 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeleteAccountRequest confirms deleting the authenticated user's account with their password
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// AccountExportProfile is the user's profile as stored
+type AccountExportProfile struct {
+	ID              uuid.UUID  `json:"id"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	FullName        string     `json:"full_name"`
+	Birthplace      *string    `json:"birthplace"`
+	Birthday        *time.Time `json:"birthday"`
+	Gender          *string    `json:"gender"`
+	DateOfBirth     *time.Time `json:"date_of_birth"`
+	Phone           *string    `json:"phone"`
+	Address         *string    `json:"address"`
+	IsActive        bool       `json:"is_active"`
+	EmailVerified   bool       `json:"email_verified"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// AccountExportStudent is the user's student record in a tenant
+type AccountExportStudent struct {
+	ID            uuid.UUID  `json:"id"`
+	StudentNumber string     `json:"student_number"`
+	AdmissionDate time.Time  `json:"admission_date"`
+	ClassID       *uuid.UUID `json:"class_id"`
+	ClassName     *string    `json:"class_name"`
+	ParentID      *uuid.UUID `json:"parent_id"`
+}
+
+// AccountExportTeacher is the user's teacher record in a tenant
+type AccountExportTeacher struct {
+	ID             uuid.UUID  `json:"id"`
+	EmployeeNumber *string    `json:"employee_number"`
+	HireDate       *time.Time `json:"hire_date"`
+	Qualification  *string    `json:"qualification"`
+	Position       *string    `json:"position"`
+	Birthplace     *string    `json:"birthplace"`
+	Birthday       *time.Time `json:"birthday"`
+	Gender         *string    `json:"gender"`
+}
+
+// AccountExportEnrollment is a subject the student is enrolled in
+type AccountExportEnrollment struct {
+	ID             uuid.UUID  `json:"id"`
+	ClassSubjectID *uuid.UUID `json:"class_subject_id"`
+	ClassName      *string    `json:"class_name"`
+	SubjectName    *string    `json:"subject_name"`
+	AcademicYear   *string    `json:"academic_year"`
+}
+
+// AccountExportGrade is a grade of one of the student's enrollments
+type AccountExportGrade struct {
+	ID           uuid.UUID  `json:"id"`
+	EnrollmentID *uuid.UUID `json:"enrollment_id"`
+	SubjectName  *string    `json:"subject_name"`
+	GradeType    string     `json:"grade_type"`
+	Score        *float64   `json:"score"`
+	Semester     *int       `json:"semester"`
+	Remarks      *string    `json:"remarks"`
+}
+
+// AccountExportAttendance is an attendance record of the student
+type AccountExportAttendance struct {
+	ID             uuid.UUID  `json:"id"`
+	ScheduleID     *uuid.UUID `json:"schedule_id"`
+	AttendanceDate string     `json:"attendance_date"`
+	Status         string     `json:"status"`
+	Remarks        *string    `json:"remarks"`
+}
+
+// AccountExportFee is a fee billed to the student
+type AccountExportFee struct {
+	ID             uuid.UUID  `json:"id"`
+	FeeTypeName    *string    `json:"fee_type_name"`
+	Amount         float64    `json:"amount"`
+	DueDate        string     `json:"due_date"`
+	Status         string     `json:"status"`
+	PaymentDate    *string    `json:"payment_date"`
+	PaymentMethod  *string    `json:"payment_method"`
+	Notes          *string    `json:"notes"`
+	AcademicYearID *uuid.UUID `json:"academic_year_id"`
+}
+
+// AccountExportNotification is a notification sent to the user
+type AccountExportNotification struct {
+	ID        uuid.UUID `json:"id"`
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	IsRead    bool      `json:"is_read"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AccountExportMembership holds the user's records in one tenant
+type AccountExportMembership struct {
+	TenantID      uuid.UUID                   `json:"tenant_id"`
+	TenantName    string                      `json:"tenant_name"`
+	IsActive      bool                        `json:"is_active"`
+	JoinedAt      time.Time                   `json:"joined_at"`
+	Roles         []string                    `json:"roles"`
+	Student       *AccountExportStudent       `json:"student"`
+	Teacher       *AccountExportTeacher       `json:"teacher"`
+	Enrollments   []AccountExportEnrollment   `json:"enrollments"`
+	Grades        []AccountExportGrade        `json:"grades"`
+	Attendance    []AccountExportAttendance   `json:"attendance"`
+	Fees          []AccountExportFee          `json:"fees"`
+	Notifications []AccountExportNotification `json:"notifications"`
+}
+
+// AccountExportResponse is every personal record kept about the user, for data portability requests
+type AccountExportResponse struct {
+	ExportedAt              time.Time                        `json:"exported_at"`
+	Profile                 AccountExportProfile             `json:"profile"`
+	NotificationPreferences []NotificationPreferenceResponse `json:"notification_preferences"`
+	Memberships             []AccountExportMembership        `json:"memberships"`
+}
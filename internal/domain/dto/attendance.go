@@ -25,7 +25,43 @@ type AttendanceQueryParams struct {
 	QueryParams
 	StudentID  *uuid.UUID `query:"student_id" validate:"omitempty,uuid"`
 	ScheduleID *uuid.UUID `query:"schedule_id" validate:"omitempty,uuid"`
-	DateFrom   *time.Time `query:"date_from"`
-	DateTo     *time.Time `query:"date_to"`
-	Status     *string    `query:"status" validate:"omitempty,oneof=present absent late excused"`
+	// ClassID keeps the attendance of every schedule of the class's class subjects
+	ClassID  *uuid.UUID `query:"class_id" validate:"omitempty,uuid"`
+	DateFrom *time.Time `query:"date_from"`
+	DateTo   *time.Time `query:"date_to"`
+	Status   *string    `query:"status" validate:"omitempty,oneof=present absent late excused"`
+}
+
+// AttendanceScanRequest records attendance from a scanned student QR code
+type AttendanceScanRequest struct {
+	Payload    string    `json:"payload" validate:"required,max=200"`
+	ScheduleID uuid.UUID `json:"schedule_id" validate:"required"`
+}
+
+// AttendanceScanResponse is the attendance recorded for a scanned student
+type AttendanceScanResponse struct {
+	AttendanceID    uuid.UUID `json:"attendance_id"`
+	StudentID       uuid.UUID `json:"student_id"`
+	StudentNumber   string    `json:"student_number"`
+	ScheduleID      uuid.UUID `json:"schedule_id"`
+	Status          string    `json:"status"`
+	AttendanceDate  string    `json:"attendance_date"`
+	AlreadyRecorded bool      `json:"already_recorded"`
+}
+
+// AttendanceStatusSummary holds the count and share of sessions with a given status
+type AttendanceStatusSummary struct {
+	Status     string  `json:"status"`
+	Count      int64   `json:"count"`
+	Percentage float64 `json:"percentage"`
+}
+
+// AttendanceSummaryResponse summarizes a student's attendance over a date range
+type AttendanceSummaryResponse struct {
+	StudentID         uuid.UUID                 `json:"student_id"`
+	DateFrom          string                    `json:"date_from"`
+	DateTo            string                    `json:"date_to"`
+	ScheduledSessions int64                     `json:"scheduled_sessions"`
+	RecordedSessions  int64                     `json:"recorded_sessions"`
+	Statuses          []AttendanceStatusSummary `json:"statuses"`
 }
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,8 +9,21 @@ import (
 
 // Auth DTOs
 type LoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
+	Identifier string `json:"identifier" validate:"required_without_all=Email Username"` // Username or email
+	Email      string `json:"email" validate:"omitempty,email"`                          // Deprecated: use Identifier
+	Username   string `json:"username"`                                                  // Deprecated: use Identifier
+	Password   string `json:"password" validate:"required,min=6"`
+}
+
+// GetIdentifier returns the login identifier, falling back to the deprecated email/username fields
+func (r LoginRequest) GetIdentifier() string {
+	if identifier := strings.TrimSpace(r.Identifier); identifier != "" {
+		return identifier
+	}
+	if r.Email != "" {
+		return r.Email
+	}
+	return r.Username
 }
 
 type LoginResponse struct {
@@ -21,19 +35,51 @@ type LoginResponse struct {
 
 type RegisterRequest struct {
 	Email    string `json:"email" validate:"required,email,max=100"`
-	Password string `json:"password" validate:"required,min=6"`
+	Password string `json:"password" validate:"required,password"`
 	FullName string `json:"full_name" validate:"required,max=100"`
 	Username string `json:"username" validate:"required,min=3,max=50"`
-	Phone    string `json:"phone" validate:"omitempty,max=20"`
+	Phone    string `json:"phone" validate:"omitempty,max=20,id_phone"`
+	TenantID string `json:"tenant_id" validate:"omitempty,uuid"` // Optional, joins the tenant with its default role when it accepts self-registration
 }
 
 type UserInfo struct {
-	ID       uuid.UUID  `json:"id"`
-	TenantID *uuid.UUID `json:"tenant_id,omitempty"` // Optional, null if no tenant selected
-	Username string     `json:"username"`
-	Email    string     `json:"email"`
-	FullName string     `json:"full_name"`
-	Role     string     `json:"role,omitempty"` // Optional, only present when tenant is selected
+	ID                 uuid.UUID  `json:"id"`
+	TenantID           *uuid.UUID `json:"tenant_id,omitempty"` // Optional, null if no tenant selected
+	Username           string     `json:"username"`
+	Email              string     `json:"email"`
+	FullName           string     `json:"full_name"`
+	Role               string     `json:"role,omitempty"`                 // Optional, only present when tenant is selected
+	MustChangePassword bool       `json:"must_change_password,omitempty"` // The token only allows changing the password
+}
+
+// MeResponse represents the authenticated user's own profile
+type MeResponse struct {
+	ID            uuid.UUID        `json:"id"`
+	TenantID      *uuid.UUID       `json:"tenant_id,omitempty"` // Currently selected tenant from token claims
+	Username      string           `json:"username"`
+	Email         string           `json:"email"`
+	FullName      string           `json:"full_name"`
+	Phone         *string          `json:"phone,omitempty"`
+	IsActive      bool             `json:"is_active"`
+	EmailVerified bool             `json:"email_verified"` // Whether the user confirmed their email address
+	Role          string           `json:"role,omitempty"` // Primary role in the selected tenant
+	Roles         []string         `json:"roles"`          // All roles in the selected tenant
+	Tenants       []UserTenantInfo `json:"tenants"`        // All active tenant memberships
+}
+
+// PermissionsResponse lists what the authenticated user may do in the selected tenant
+type PermissionsResponse struct {
+	TenantID    uuid.UUID `json:"tenant_id"`
+	Roles       []string  `json:"roles"`       // All roles in the selected tenant
+	Permissions []string  `json:"permissions"` // Sorted union of the permissions granted to the roles
+}
+
+// UserTenantInfo summarizes a tenant the user belongs to
+type UserTenantInfo struct {
+	TenantID     uuid.UUID `json:"tenant_id"`
+	TenantName   string    `json:"tenant_name"`
+	TenantUserID uuid.UUID `json:"tenant_user_id"`
+	IsActive     bool      `json:"is_active"`
 }
 
 type TokenClaims struct {
@@ -58,5 +104,23 @@ type TenantSelectionResponse struct {
 
 type ChangePasswordRequest struct {
 	CurrentPassword string `json:"current_password" validate:"required"`
-	NewPassword     string `json:"new_password" validate:"required,min=6"`
+	NewPassword     string `json:"new_password" validate:"required,password"`
+}
+
+// ResendVerificationRequest asks for a new email verification link
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// CheckAvailabilityRequest holds the username and/or email to check before registering
+type CheckAvailabilityRequest struct {
+	Username string `query:"username" validate:"required_without=Email,omitempty,min=3,max=50"`
+	Email    string `query:"email" validate:"required_without=Username,omitempty,email,max=100"`
+}
+
+// AvailabilityResponse reports whether the checked username and email are still free to register;
+// fields that were not checked are omitted
+type AvailabilityResponse struct {
+	Username *bool `json:"username,omitempty"`
+	Email    *bool `json:"email,omitempty"`
 }
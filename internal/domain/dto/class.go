@@ -18,3 +18,81 @@ type UpdateClassRequest struct {
 	HomeroomTeacherID *uuid.UUID `json:"homeroom_teacher_id" validate:"omitempty,uuid"`
 	AcademicYearID    *uuid.UUID `json:"academic_year_id" validate:"omitempty,uuid"`
 }
+
+// PromoteClassRequest moves all students of a class to a target class.
+// Either TargetClassID or GradeIncrement must be provided.
+type PromoteClassRequest struct {
+	TargetClassID          *uuid.UUID `json:"target_class_id" validate:"required_without=GradeIncrement,omitempty"`
+	GradeIncrement         *int       `json:"grade_increment" validate:"required_without=TargetClassID,omitempty,min=1,max=11"`
+	RequireConsecutiveYear bool       `json:"require_consecutive_year"`
+}
+
+// AssignHomeroomTeacherRequest sets the homeroom teacher of a class; a null teacher clears it
+type AssignHomeroomTeacherRequest struct {
+	TeacherID *uuid.UUID `json:"teacher_id"`
+}
+
+// HomeroomClass is a class the teacher is homeroom teacher of
+type HomeroomClass struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// HomeroomTeacherResponse is the class after its homeroom teacher changed. OtherHomeroomClasses lists the
+// other classes the teacher is already homeroom teacher of, as a warning.
+type HomeroomTeacherResponse struct {
+	ID                   uuid.UUID       `json:"id"`
+	Name                 string          `json:"name"`
+	GradeLevel           *int            `json:"grade_level,omitempty"`
+	AcademicYearID       *uuid.UUID      `json:"academic_year_id,omitempty"`
+	HomeroomTeacherID    *uuid.UUID      `json:"homeroom_teacher_id"`
+	HomeroomTeacherName  *string         `json:"homeroom_teacher_name,omitempty"`
+	OtherHomeroomClasses []HomeroomClass `json:"other_homeroom_classes,omitempty"`
+}
+
+type PromoteClassResponse struct {
+	SourceClassID uuid.UUID `json:"source_class_id"`
+	TargetClassID uuid.UUID `json:"target_class_id"`
+	StudentsMoved int64     `json:"students_moved"`
+}
+
+// GradebookQueryParams filters the grades included in a class gradebook
+type GradebookQueryParams struct {
+	GradeType string `query:"grade_type" validate:"omitempty,oneof=Assignment Midterm Final Other"`
+}
+
+// GradebookSubject is a column of the gradebook
+type GradebookSubject struct {
+	ClassSubjectID uuid.UUID  `json:"class_subject_id"`
+	SubjectID      *uuid.UUID `json:"subject_id,omitempty"`
+	Name           string     `json:"name"`
+	Code           string     `json:"code"`
+}
+
+// GradebookCell holds a student's grades for one subject; scores are null when there are no grades yet.
+// Without a grade type filter, FinalScore weighs the average of each grade type by the tenant's weights
+// and LetterGrade follows it; with a filter, LetterGrade follows AverageScore.
+type GradebookCell struct {
+	ClassSubjectID uuid.UUID `json:"class_subject_id"`
+	AverageScore   *float64  `json:"average_score"`
+	FinalScore     *float64  `json:"final_score,omitempty"`
+	LetterGrade    *string   `json:"letter_grade"`
+	GradeCount     int64     `json:"grade_count"`
+}
+
+// GradebookRow is a student's row in the gradebook, with cells in the same order as the subjects
+type GradebookRow struct {
+	StudentID     uuid.UUID       `json:"student_id"`
+	StudentNumber string          `json:"student_number"`
+	FullName      string          `json:"full_name"`
+	Grades        []GradebookCell `json:"grades"`
+}
+
+// GradebookResponse is a students × subjects matrix of grades for a class
+type GradebookResponse struct {
+	ClassID        uuid.UUID          `json:"class_id"`
+	AcademicYearID uuid.UUID          `json:"academic_year_id"`
+	GradeType      string             `json:"grade_type,omitempty"`
+	Subjects       []GradebookSubject `json:"subjects"`
+	Rows           []GradebookRow     `json:"rows"`
+}
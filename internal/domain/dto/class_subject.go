@@ -6,8 +6,8 @@ import (
 
 // ClassSubject DTOs (linking class, subject, and teacher)
 type CreateClassSubjectRequest struct {
-	ClassID   *uuid.UUID `json:"class_id" validate:"omitempty,uuid"`
-	SubjectID *uuid.UUID `json:"subject_id" validate:"omitempty,uuid"`
+	ClassID   *uuid.UUID `json:"class_id" validate:"required,uuid"`
+	SubjectID *uuid.UUID `json:"subject_id" validate:"required,uuid"`
 	TeacherID *uuid.UUID `json:"teacher_id" validate:"omitempty,uuid"`
 }
 
@@ -17,6 +17,34 @@ type UpdateClassSubjectRequest struct {
 	TeacherID *uuid.UUID `json:"teacher_id" validate:"omitempty,uuid"`
 }
 
+// TeacherClassSubjectItem is a class and subject pair for the teacher to teach
+type TeacherClassSubjectItem struct {
+	ClassID   uuid.UUID `json:"class_id" validate:"required"`
+	SubjectID uuid.UUID `json:"subject_id" validate:"required"`
+}
+
+// AssignTeacherClassSubjectsRequest assigns several subjects to a teacher at once
+type AssignTeacherClassSubjectsRequest struct {
+	Assignments []TeacherClassSubjectItem `json:"assignments" validate:"required,min=1,dive"`
+}
+
+// AssignedClassSubject is a class subject created by a bulk teacher assignment
+type AssignedClassSubject struct {
+	ID        uuid.UUID `json:"id"`
+	ClassID   uuid.UUID `json:"class_id"`
+	SubjectID uuid.UUID `json:"subject_id"`
+}
+
+// AssignTeacherClassSubjectsResponse lists the created class subjects and the pairs skipped because the
+// subject was already assigned to the class or repeated in the request
+type AssignTeacherClassSubjectsResponse struct {
+	TeacherID    uuid.UUID                 `json:"teacher_id"`
+	CreatedCount int                       `json:"created_count"`
+	SkippedCount int                       `json:"skipped_count"`
+	Created      []AssignedClassSubject    `json:"created"`
+	Skipped      []TeacherClassSubjectItem `json:"skipped"`
+}
+
 type ClassSubjectQueryParams struct {
 	QueryParams
 	ClassID   *uuid.UUID `query:"class_id" validate:"omitempty,uuid"`
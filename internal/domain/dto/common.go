@@ -1,5 +1,9 @@
 package dto
 
+import (
+	"time"
+)
+
 // Enums to match database schema
 type Gender string
 
@@ -20,12 +24,28 @@ const (
 	DaySunday    DayOfWeek = "minggu"
 )
 
+// ErrorCodePasswordChangeRequired is the response code of requests rejected until the user changed the
+// temporary password set by an admin
+const ErrorCodePasswordChangeRequired = "password_change_required"
+
 // Common response structures
 type Response struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
+	Success bool         `json:"success"`
+	Message string       `json:"message"`
+	Data    interface{}  `json:"data,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"`
+	// Code identifies errors clients are expected to handle, e.g. ErrorCodePasswordChangeRequired
+	Code string `json:"code,omitempty"`
+	// RequestID repeats the X-Request-ID header on responses not produced by a handler
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// FieldError describes a validation failure on a single request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Message string `json:"message"`
 }
 
 type PaginationMeta struct {
@@ -33,6 +53,8 @@ type PaginationMeta struct {
 	Limit      int   `json:"limit"`
 	TotalRows  int64 `json:"total_rows"`
 	TotalPages int   `json:"total_pages"`
+	// Warning advises clients paging through a large result set, see BaseHandler.RespondPaginated
+	Warning string `json:"warning,omitempty"`
 }
 
 type PaginatedResponse struct {
@@ -49,4 +71,7 @@ type QueryParams struct {
 	Search  string `query:"search"`
 	SortBy  string `query:"sort_by"`
 	SortDir string `query:"sort_dir" validate:"omitempty,oneof=asc desc"`
+	// CreatedAfter and UpdatedAfter keep rows created or changed after the time, for incremental sync
+	CreatedAfter *time.Time `query:"created_after"`
+	UpdatedAfter *time.Time `query:"updated_after"`
 }
@@ -0,0 +1,90 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+)
+
+// DashboardAcademicYear is the active academic year shown on the dashboard
+type DashboardAcademicYear struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	StartDate string    `json:"start_date"`
+	EndDate   string    `json:"end_date"`
+}
+
+// DashboardOutstandingFees is the number and total amount of unpaid, partial and overdue fees
+type DashboardOutstandingFees struct {
+	Count       int64   `json:"count"`
+	TotalAmount float64 `json:"total_amount"`
+}
+
+// DashboardAttendance is today's attendance and the share of recorded sessions attended
+type DashboardAttendance struct {
+	Date     string `json:"date"`
+	Expected int64  `json:"expected_sessions"`
+	Recorded int64  `json:"recorded_sessions"`
+	Present  int64  `json:"present"`
+	Late     int64  `json:"late"`
+	Absent   int64  `json:"absent"`
+	Excused  int64  `json:"excused"`
+	// Rate is the percentage of recorded sessions attended present or late, null when none are recorded
+	Rate *float64 `json:"rate"`
+}
+
+// DashboardSummaryResponse holds the landing screen numbers of a tenant. Teachers see their own classes
+// and students; tenant wide numbers they are not shown are omitted.
+type DashboardSummaryResponse struct {
+	Scope              string                    `json:"scope"` // "tenant" or "teacher"
+	TotalStudents      int64                     `json:"total_students"`
+	TotalTeachers      *int64                    `json:"total_teachers,omitempty"`
+	TotalClasses       int64                     `json:"total_classes"`
+	ActiveAcademicYear *DashboardAcademicYear    `json:"active_academic_year"`
+	OutstandingFees    *DashboardOutstandingFees `json:"outstanding_fees,omitempty"`
+	Attendance         DashboardAttendance       `json:"attendance_today"`
+}
+
+// DashboardResponse is the landing screen of the caller's role, one of three variants told apart by Role:
+//   - "admin" (Admin, Developer, Staff): Summary with the tenant wide numbers
+//   - "teacher": Summary scoped to the teacher's classes, and Teacher
+//   - "parent": Parent
+type DashboardResponse struct {
+	Role    string                    `json:"role"`
+	Summary *DashboardSummaryResponse `json:"summary,omitempty"`
+	Teacher *TeacherDashboard         `json:"teacher,omitempty"`
+	Parent  *ParentDashboard          `json:"parent,omitempty"`
+}
+
+// TeacherDashboardClass is a subject the teacher teaches in a class
+type TeacherDashboardClass struct {
+	ClassSubjectID uuid.UUID  `json:"class_subject_id"`
+	ClassID        *uuid.UUID `json:"class_id,omitempty"`
+	ClassName      *string    `json:"class_name,omitempty"`
+	SubjectID      *uuid.UUID `json:"subject_id,omitempty"`
+	SubjectName    *string    `json:"subject_name,omitempty"`
+}
+
+// TeacherDashboard holds a teacher's classes, today's timetable and the class subjects still missing scores
+// in the active academic year
+type TeacherDashboard struct {
+	TeacherID     uuid.UUID               `json:"teacher_id"`
+	Classes       []TeacherDashboardClass `json:"classes"`
+	TodaySchedule []TeacherScheduleSlot   `json:"today_schedule"`
+	PendingGrades []PendingGradeEntry     `json:"pending_grades"`
+}
+
+// ParentDashboardChild is a child of the parent with their attendance and fee totals
+type ParentDashboardChild struct {
+	StudentID     uuid.UUID  `json:"student_id"`
+	StudentNumber string     `json:"student_number"`
+	FullName      string     `json:"full_name"`
+	ClassID       *uuid.UUID `json:"class_id,omitempty"`
+	ClassName     *string    `json:"class_name,omitempty"`
+	// Attendance covers the active academic year, null when there is none
+	Attendance *AttendanceSummaryResponse `json:"attendance"`
+	Fees       FeeAmountSummary           `json:"fees"`
+}
+
+// ParentDashboard holds the children of a parent
+type ParentDashboard struct {
+	Children []ParentDashboardChild `json:"children"`
+}
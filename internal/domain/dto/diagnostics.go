@@ -0,0 +1,24 @@
+package dto
+
+import "github.com/google/uuid"
+
+// SessionIsolationProbeResponse is the outcome of one pooled connection in the session isolation check
+type SessionIsolationProbeResponse struct {
+	Pool         string `json:"pool"` // write or read
+	BackendPID   int    `json:"backend_pid"`
+	ScopedRows   int64  `json:"scoped_rows"`
+	ForeignRows  int64  `json:"foreign_rows"`
+	LeakedTenant string `json:"leaked_tenant"`
+	LeakedRows   int64  `json:"leaked_rows"`
+	Error        string `json:"error,omitempty"`
+	Isolated     bool   `json:"isolated"`
+}
+
+// SessionIsolationResponse reports whether a tenant context set on pooled connections stays within its transaction
+type SessionIsolationResponse struct {
+	TenantID   uuid.UUID                       `json:"tenant_id"`
+	Table      string                          `json:"table"`
+	BypassRole bool                            `json:"bypass_role"`
+	Isolated   bool                            `json:"isolated"`
+	Probes     []SessionIsolationProbeResponse `json:"probes"`
+}
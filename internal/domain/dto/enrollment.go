@@ -6,8 +6,8 @@ import (
 
 // Enrollment DTOs
 type CreateEnrollmentRequest struct {
-	StudentID      *uuid.UUID `json:"student_id" validate:"omitempty,uuid"`
-	ClassSubjectID *uuid.UUID `json:"class_subject_id" validate:"omitempty,uuid"`
+	StudentID      *uuid.UUID `json:"student_id" validate:"required,uuid"`
+	ClassSubjectID *uuid.UUID `json:"class_subject_id" validate:"required,uuid"`
 	AcademicYearID *uuid.UUID `json:"academic_year_id" validate:"omitempty,uuid"`
 }
 
@@ -23,3 +23,55 @@ type EnrollmentQueryParams struct {
 	ClassSubjectID *uuid.UUID `query:"class_subject_id" validate:"omitempty,uuid"`
 	AcademicYearID *uuid.UUID `query:"academic_year_id" validate:"omitempty,uuid"`
 }
+
+// BulkEnrollRequest enrolls every student of the class subject's class
+type BulkEnrollRequest struct {
+	ClassSubjectID uuid.UUID  `json:"class_subject_id" validate:"required,uuid"`
+	AcademicYearID *uuid.UUID `json:"academic_year_id" validate:"omitempty,uuid"`
+}
+
+type BulkEnrollResponse struct {
+	ClassSubjectID uuid.UUID  `json:"class_subject_id"`
+	AcademicYearID *uuid.UUID `json:"academic_year_id,omitempty"`
+	Enrolled       int        `json:"enrolled"`
+	Skipped        int        `json:"skipped"`
+}
+
+// EnrollmentGradeTypeSummary is the average score of one grade type of an enrollment
+type EnrollmentGradeTypeSummary struct {
+	GradeType    string   `json:"grade_type"`
+	AverageScore *float64 `json:"average_score"`
+	GradeCount   int64    `json:"grade_count"`
+}
+
+// EnrollmentGradeSummary combines the grades of an enrollment like the gradebook does; scores are null without grades
+type EnrollmentGradeSummary struct {
+	AverageScore *float64                     `json:"average_score"`
+	FinalScore   *float64                     `json:"final_score"`
+	LetterGrade  *string                      `json:"letter_grade"`
+	GradeCount   int64                        `json:"grade_count"`
+	GradeTypes   []EnrollmentGradeTypeSummary `json:"grade_types"`
+}
+
+// EnrollmentHistoryItem is one enrollment of a student's history with its class subject and teacher
+type EnrollmentHistoryItem struct {
+	EnrollmentID     uuid.UUID              `json:"enrollment_id"`
+	AcademicYearID   *uuid.UUID             `json:"academic_year_id,omitempty"`
+	AcademicYearName string                 `json:"academic_year_name,omitempty"`
+	ClassSubjectID   *uuid.UUID             `json:"class_subject_id,omitempty"`
+	ClassID          *uuid.UUID             `json:"class_id,omitempty"`
+	ClassName        string                 `json:"class_name,omitempty"`
+	SubjectID        *uuid.UUID             `json:"subject_id,omitempty"`
+	SubjectName      string                 `json:"subject_name,omitempty"`
+	SubjectCode      string                 `json:"subject_code,omitempty"`
+	Credit           int                    `json:"credit"`
+	TeacherID        *uuid.UUID             `json:"teacher_id,omitempty"`
+	TeacherName      string                 `json:"teacher_name,omitempty"`
+	Grades           EnrollmentGradeSummary `json:"grades"`
+}
+
+// EnrollmentHistoryResponse lists every enrollment of a student across academic years, oldest year first
+type EnrollmentHistoryResponse struct {
+	StudentID   uuid.UUID               `json:"student_id"`
+	Enrollments []EnrollmentHistoryItem `json:"enrollments"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+)
+
+// GPAQueryParams selects the academic year of a GPA, the active one by default
+type GPAQueryParams struct {
+	AcademicYearID *uuid.UUID `query:"academic_year_id"`
+}
+
+// GPASubject is the grade point of one class subject a student is enrolled in. Subjects without credits or
+// without a final score yet are listed but not counted.
+type GPASubject struct {
+	ClassSubjectID uuid.UUID  `json:"class_subject_id"`
+	SubjectID      *uuid.UUID `json:"subject_id,omitempty"`
+	Name           string     `json:"name"`
+	Code           string     `json:"code"`
+	Credit         int        `json:"credit"`
+	FinalScore     *float64   `json:"final_score"`
+	LetterGrade    *string    `json:"letter_grade"`
+	GradePoint     *float64   `json:"grade_point"`
+	Counted        bool       `json:"counted"`
+}
+
+// GPASummary is a credit weighted grade point average; GPA is null when no subject counts
+type GPASummary struct {
+	GPA             *float64 `json:"gpa"`
+	Credits         int      `json:"credits"`
+	SubjectsCounted int      `json:"subjects_counted"`
+}
+
+// GPAYear is a student's GPA within one academic year
+type GPAYear struct {
+	GPASummary
+	AcademicYearID uuid.UUID    `json:"academic_year_id"`
+	AcademicYear   string       `json:"academic_year"`
+	Subjects       []GPASubject `json:"subjects"`
+}
+
+// GPAResponse is a student's GPA within an academic year and over every year, on a scale up to MaxGradePoint.
+// AcademicYear is null when no year was given and none is active.
+type GPAResponse struct {
+	StudentID     uuid.UUID  `json:"student_id"`
+	MaxGradePoint float64    `json:"max_grade_point"`
+	AcademicYear  *GPAYear   `json:"academic_year"`
+	Cumulative    GPASummary `json:"cumulative"`
+}
@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -9,11 +11,113 @@ type CreateGradeRequest struct {
 	EnrollmentID *uuid.UUID `json:"enrollment_id" validate:"omitempty,uuid"`
 	GradeType    string     `json:"grade_type" validate:"required,oneof=Assignment Midterm Final Other"`
 	Score        *float64   `json:"score,omitempty" validate:"omitempty,min=0,max=100"`
+	Semester     *int       `json:"semester,omitempty" validate:"omitempty,oneof=1 2"`
 	Remarks      *string    `json:"remarks,omitempty"`
 }
 
 type UpdateGradeRequest struct {
 	GradeType *string  `json:"grade_type" validate:"omitempty,oneof=Assignment Midterm Final Other"`
 	Score     *float64 `json:"score,omitempty" validate:"omitempty,min=0,max=100"`
+	Semester  *int     `json:"semester,omitempty" validate:"omitempty,oneof=1 2"`
 	Remarks   *string  `json:"remarks,omitempty"`
 }
+
+// BulkGradeEntry is one grade of a bulk grade entry, for a student by enrollment or student ID
+type BulkGradeEntry struct {
+	EnrollmentID *uuid.UUID `json:"enrollment_id,omitempty" validate:"required_without=StudentID"`
+	StudentID    *uuid.UUID `json:"student_id,omitempty" validate:"required_without=EnrollmentID"`
+	GradeType    string     `json:"grade_type" validate:"required,oneof=Assignment Midterm Final Other"`
+	Score        *float64   `json:"score,omitempty" validate:"omitempty,min=0,max=100"`
+	Semester     *int       `json:"semester,omitempty" validate:"omitempty,oneof=1 2"`
+	Remarks      *string    `json:"remarks,omitempty"`
+}
+
+// BulkGradeRequest enters the grades of a class subject's students at once
+type BulkGradeRequest struct {
+	Grades []BulkGradeEntry `json:"grades" validate:"required,min=1,max=500,dive"`
+}
+
+// BulkGradeResult is the outcome of one entry of a bulk grade request, by its position in the request
+type BulkGradeResult struct {
+	Index        int        `json:"index"`
+	EnrollmentID *uuid.UUID `json:"enrollment_id,omitempty"`
+	GradeID      *uuid.UUID `json:"grade_id,omitempty"`
+	Created      bool       `json:"created"`
+	Success      bool       `json:"success"`
+	Error        string     `json:"error,omitempty"`
+}
+
+// BulkGradeResponse summarizes a bulk grade request
+type BulkGradeResponse struct {
+	ClassSubjectID uuid.UUID         `json:"class_subject_id"`
+	Saved          int               `json:"saved"`
+	Failed         int               `json:"failed"`
+	Results        []BulkGradeResult `json:"results"`
+}
+
+// GradingScaleBand is a letter grade given to scores at or above MinScore, worth GradePoint in GPAs
+type GradingScaleBand struct {
+	Letter     string   `json:"letter" validate:"required,max=5"`
+	MinScore   *float64 `json:"min_score" validate:"required,min=0,max=100"`
+	GradePoint *float64 `json:"grade_point,omitempty" validate:"omitempty,min=0,max=10"`
+}
+
+// GradeTypeWeight is the relative weight of a grade type in final scores
+type GradeTypeWeight struct {
+	GradeType string   `json:"grade_type" validate:"required,oneof=Assignment Midterm Final Other"`
+	Weight    *float64 `json:"weight" validate:"required,min=0,max=100"`
+}
+
+// UpdateGradingScaleRequest replaces a tenant's grading scale and grade type weights
+type UpdateGradingScaleRequest struct {
+	Bands   []GradingScaleBand `json:"bands" validate:"required,min=1,max=20,dive"`
+	Weights []GradeTypeWeight  `json:"weights" validate:"required,min=1,max=4,dive"`
+}
+
+// PendingGradeEntry is a class subject of a teacher with enrolled students that have no score yet
+type PendingGradeEntry struct {
+	ClassSubjectID   uuid.UUID `json:"class_subject_id"`
+	ClassName        *string   `json:"class_name,omitempty"`
+	SubjectName      *string   `json:"subject_name,omitempty"`
+	EnrolledStudents int64     `json:"enrolled_students"`
+	UngradedStudents int64     `json:"ungraded_students"`
+}
+
+// GradingScaleResponse is a tenant's grading scale, ordered by descending minimum score, and grade type weights.
+// The default flags are set while the tenant uses the built-in bands or weights.
+type GradingScaleResponse struct {
+	Bands          []GradingScaleBand `json:"bands"`
+	Weights        []GradeTypeWeight  `json:"weights"`
+	DefaultBands   bool               `json:"default_bands"`
+	DefaultWeights bool               `json:"default_weights"`
+}
+
+// GradeExportQueryParams optionally limits a grade export to one semester
+type GradeExportQueryParams struct {
+	Semester *int `query:"semester" validate:"omitempty,oneof=1 2"`
+}
+
+// GradeExportRow is a student's row of a grade export, with an average per grade type of the export
+type GradeExportRow struct {
+	StudentID         uuid.UUID  `json:"student_id"`
+	StudentNumber     string     `json:"student_number"`
+	FullName          string     `json:"full_name"`
+	GradeTypeAverages []*float64 `json:"grade_type_averages"`
+	AverageScore      *float64   `json:"average_score"`
+	FinalScore        *float64   `json:"final_score"`
+	LetterGrade       *string    `json:"letter_grade"`
+	GradeCount        int64      `json:"grade_count"`
+}
+
+// GradeExport is the grade sheet of a class subject, one row per enrolled student
+type GradeExport struct {
+	ClassSubjectID uuid.UUID        `json:"class_subject_id"`
+	ClassName      string           `json:"class_name"`
+	SubjectName    string           `json:"subject_name"`
+	SubjectCode    string           `json:"subject_code"`
+	TeacherName    string           `json:"teacher_name"`
+	Semester       *int             `json:"semester,omitempty"`
+	GradeTypes     []string         `json:"grade_types"`
+	Rows           []GradeExportRow `json:"rows"`
+	GeneratedAt    time.Time        `json:"generated_at"`
+}
@@ -6,7 +6,8 @@ import (
 
 // Notification DTOs
 type CreateNotificationRequest struct {
-	UserID  *uuid.UUID `json:"user_id" validate:"omitempty,uuid"`
+	UserID  *uuid.UUID `json:"user_id" validate:"required"`
+	Type    string     `json:"type" validate:"omitempty,oneof=general attendance grade fee"`
 	Title   string     `json:"title" validate:"required,max=100"`
 	Message string     `json:"message" validate:"required"`
 }
@@ -16,3 +17,31 @@ type UpdateNotificationRequest struct {
 	Message *string `json:"message,omitempty"`
 	IsRead  *bool   `json:"is_read,omitempty"`
 }
+
+// NotificationDeliveryResponse reports the channels a notification was queued on besides the in-app list
+type NotificationDeliveryResponse struct {
+	NotificationID uuid.UUID `json:"notification_id"`
+	Type           string    `json:"type"`
+	EmailQueued    bool      `json:"email_queued"`
+	SMSQueued      bool      `json:"sms_queued"`
+}
+
+// NotificationPreference is the choice of channels for one notification type
+type NotificationPreference struct {
+	Type  string `json:"type" validate:"required,oneof=general attendance grade fee"`
+	Email *bool  `json:"email" validate:"required"`
+	SMS   *bool  `json:"sms" validate:"required"`
+}
+
+// UpdateNotificationPreferencesRequest sets the channels of one or more notification types
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreference `json:"preferences" validate:"required,min=1,max=10,dive"`
+}
+
+// NotificationPreferenceResponse is the effective choice of channels for a notification type
+type NotificationPreferenceResponse struct {
+	Type      string `json:"type"`
+	Email     bool   `json:"email"`
+	SMS       bool   `json:"sms"`
+	IsDefault bool   `json:"is_default"`
+}
@@ -5,7 +5,7 @@ import "time"
 // Parent DTOs
 type CreateParentRequest struct {
 	FullName     string     `json:"full_name" validate:"required,max=100"`
-	Phone        *string    `json:"phone" validate:"omitempty,max=20"`
+	Phone        *string    `json:"phone" validate:"omitempty,max=20,id_phone"`
 	Email        *string    `json:"email" validate:"omitempty,email,max=100"`
 	Address      *string    `json:"address,omitempty"`
 	Relationship *string    `json:"relationship" validate:"omitempty,max=50"`
@@ -16,7 +16,7 @@ type CreateParentRequest struct {
 
 type UpdateParentRequest struct {
 	FullName     *string    `json:"full_name" validate:"omitempty,max=100"`
-	Phone        *string    `json:"phone" validate:"omitempty,max=20"`
+	Phone        *string    `json:"phone" validate:"omitempty,max=20,id_phone"`
 	Email        *string    `json:"email" validate:"omitempty,email,max=100"`
 	Address      *string    `json:"address,omitempty"`
 	Relationship *string    `json:"relationship" validate:"omitempty,max=50"`
@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportCardQueryParams selects the academic year and optionally the semester of a report card
+type ReportCardQueryParams struct {
+	AcademicYearID *uuid.UUID `query:"academic_year_id"`
+	Semester       *int       `query:"semester" validate:"omitempty,oneof=1 2"`
+}
+
+// ReportCardSubject is a student's result for one enrolled subject
+type ReportCardSubject struct {
+	ClassSubjectID uuid.UUID `json:"class_subject_id"`
+	Name           string    `json:"name"`
+	Code           string    `json:"code"`
+	AverageScore   *float64  `json:"average_score"`
+	FinalScore     *float64  `json:"final_score"`
+	LetterGrade    *string   `json:"letter_grade"`
+	GradeCount     int64     `json:"grade_count"`
+}
+
+// ReportCard combines a student's profile, subject results and attendance for an academic year or semester
+type ReportCard struct {
+	SchoolName    string                     `json:"school_name"`
+	StudentID     uuid.UUID                  `json:"student_id"`
+	StudentNumber string                     `json:"student_number"`
+	FullName      string                     `json:"full_name"`
+	ClassName     string                     `json:"class_name"`
+	AcademicYear  string                     `json:"academic_year"`
+	Semester      *int                       `json:"semester,omitempty"`
+	Subjects      []ReportCardSubject        `json:"subjects"`
+	OverallScore  *float64                   `json:"overall_score"`
+	OverallLetter *string                    `json:"overall_letter"`
+	Attendance    *AttendanceSummaryResponse `json:"attendance,omitempty"`
+	GeneratedAt   time.Time                  `json:"generated_at"`
+}
+
+// TranscriptQueryParams selects the format of a transcript
+type TranscriptQueryParams struct {
+	Format string `query:"format" validate:"omitempty,oneof=json pdf"`
+}
+
+// TranscriptSubject is a student's final result for one class subject of an academic year
+type TranscriptSubject struct {
+	ClassSubjectID uuid.UUID  `json:"class_subject_id"`
+	SubjectID      *uuid.UUID `json:"subject_id,omitempty"`
+	Name           string     `json:"name"`
+	Code           string     `json:"code"`
+	Credit         int        `json:"credit"`
+	AverageScore   *float64   `json:"average_score"`
+	FinalScore     *float64   `json:"final_score"`
+	LetterGrade    *string    `json:"letter_grade"`
+	GradeCount     int64      `json:"grade_count"`
+}
+
+// TranscriptYear is the breakdown of one academic year of a transcript
+type TranscriptYear struct {
+	AcademicYearID  *uuid.UUID          `json:"academic_year_id"`
+	AcademicYear    string              `json:"academic_year"`
+	Subjects        []TranscriptSubject `json:"subjects"`
+	Credits         int                 `json:"credits"`
+	WeightedAverage *float64            `json:"weighted_average"`
+	LetterGrade     *string             `json:"letter_grade"`
+}
+
+// TranscriptSubjectSummary is the cumulative result of a subject over every year it was taken
+type TranscriptSubjectSummary struct {
+	SubjectID   *uuid.UUID `json:"subject_id,omitempty"`
+	Name        string     `json:"name"`
+	Code        string     `json:"code"`
+	Years       int        `json:"years"`
+	Credits     int        `json:"credits"`
+	FinalScore  *float64   `json:"final_score"`
+	LetterGrade *string    `json:"letter_grade"`
+}
+
+// Transcript is a student's cumulative academic record across every academic year
+type Transcript struct {
+	SchoolName        string                     `json:"school_name"`
+	StudentID         uuid.UUID                  `json:"student_id"`
+	StudentNumber     string                     `json:"student_number"`
+	FullName          string                     `json:"full_name"`
+	Status            string                     `json:"status"`
+	AdmissionDate     time.Time                  `json:"admission_date"`
+	Years             []TranscriptYear           `json:"years"`
+	Subjects          []TranscriptSubjectSummary `json:"subjects"`
+	TotalCredits      int                        `json:"total_credits"`
+	CumulativeAverage *float64                   `json:"cumulative_average"`
+	CumulativeLetter  *string                    `json:"cumulative_letter"`
+	GeneratedAt       time.Time                  `json:"generated_at"`
+}
@@ -8,8 +8,9 @@ import (
 
 // Student DTOs
 type CreateStudentRequest struct {
-	TenantUserID  uuid.UUID  `json:"tenant_user_id" validate:"required,uuid"`
-	StudentNumber string     `json:"student_number" validate:"required,max=50"`
+	TenantUserID uuid.UUID `json:"tenant_user_id" validate:"required,uuid"`
+	// StudentNumber is generated from the tenant's student number format when omitted
+	StudentNumber string     `json:"student_number" validate:"omitempty,max=50"`
 	AdmissionDate time.Time  `json:"admission_date" validate:"required"`
 	ClassID       *uuid.UUID `json:"class_id" validate:"omitempty,uuid"`
 	ParentID      *uuid.UUID `json:"parent_id" validate:"omitempty,uuid"`
@@ -24,10 +25,84 @@ type UpdateStudentRequest struct {
 
 type StudentQueryParams struct {
 	QueryParams
-	ClassID  *uuid.UUID `query:"class_id" validate:"omitempty,uuid"`
-	ParentID *uuid.UUID `query:"parent_id" validate:"omitempty,uuid"`
+	ClassID      *uuid.UUID `query:"class_id" validate:"omitempty,uuid"`
+	ParentID     *uuid.UUID `query:"parent_id" validate:"omitempty,uuid"`
+	EnrolledFrom *time.Time `query:"enrolled_from"`
+	EnrolledTo   *time.Time `query:"enrolled_to"`
+	// EnrollmentStatus is derived from whether the student has any enrollment records
+	EnrollmentStatus string `query:"enrollment_status" validate:"omitempty,oneof=enrolled not_enrolled"`
+	// Status limits the list to a lifecycle status; empty lists active students and "all" lists every status
+	Status string `query:"status" validate:"omitempty,oneof=active graduated withdrawn suspended all"`
+	// Fields limits each student to these JSON fields; naming a relation also expands it
+	Fields []string `query:"fields" validate:"omitempty,dive,oneof=id tenant_id tenant_user_id student_number admission_date class_id parent_id status status_changed_at age created_at updated_at tenant_user class parent guardians"`
+	// Expand lists the relations to load; students are returned without relations by default
+	Expand []string `query:"expand" validate:"omitempty,dive,oneof=tenant_user class parent guardians"`
 }
 
+// StudentRelations lists the relations of a student that can be expanded
+var StudentRelations = []string{"tenant_user", "class", "parent", "guardians"}
+
 type BulkDeleteStudentRequest struct {
 	IDs []uuid.UUID `json:"ids" validate:"required,min=1,dive,required"`
 }
+
+// BulkAssignClassRequest moves the selected students into a class
+type BulkAssignClassRequest struct {
+	IDs     []uuid.UUID `json:"ids" validate:"required,min=1,dive,required"`
+	ClassID uuid.UUID   `json:"class_id" validate:"required"`
+}
+
+type BulkAssignClassResponse struct {
+	ClassID      uuid.UUID   `json:"class_id"`
+	UpdatedCount int64       `json:"updated_count"`
+	SkippedCount int         `json:"skipped_count"`
+	SkippedIDs   []uuid.UUID `json:"skipped_ids"`
+}
+
+// UpdateStudentNumberFormatRequest sets how the tenant's student numbers are generated
+type UpdateStudentNumberFormatRequest struct {
+	Prefix              string `json:"prefix" validate:"omitempty,max=20,printascii,excludes= "`
+	IncludeAcademicYear bool   `json:"include_academic_year"`
+	SequenceDigits      int    `json:"sequence_digits" validate:"required,min=1,max=10"`
+}
+
+// StudentNumberFormatResponse is the tenant's student number format with an example of a generated number.
+// Default is set while the tenant uses the built-in format.
+type StudentNumberFormatResponse struct {
+	Prefix              string `json:"prefix"`
+	IncludeAcademicYear bool   `json:"include_academic_year"`
+	SequenceDigits      int    `json:"sequence_digits"`
+	Example             string `json:"example"`
+	Default             bool   `json:"default"`
+}
+
+// ChangeStudentStatusRequest moves a student to another lifecycle status
+type ChangeStudentStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=active graduated withdrawn suspended"`
+}
+
+// AddStudentGuardianRequest links a parent to a student as a guardian, or updates the existing link.
+// A primary guardian replaces the previous one and becomes the student's parent_id.
+type AddStudentGuardianRequest struct {
+	ParentID     uuid.UUID `json:"parent_id" validate:"required"`
+	Relationship *string   `json:"relationship" validate:"omitempty,max=50"`
+	IsPrimary    bool      `json:"is_primary"`
+}
+
+// TransferStudentRequest moves a student into another tenant the caller administers
+type TransferStudentRequest struct {
+	DestinationTenantID uuid.UUID  `json:"destination_tenant_id" validate:"required,uuid"`
+	StudentNumber       *string    `json:"student_number" validate:"omitempty,max=50"`
+	AdmissionDate       *time.Time `json:"admission_date,omitempty"`
+	DeactivateSource    bool       `json:"deactivate_source"`
+}
+
+type TransferStudentResponse struct {
+	SourceStudentID      uuid.UUID `json:"source_student_id"`
+	StudentID            uuid.UUID `json:"student_id"`
+	DestinationTenantID  uuid.UUID `json:"destination_tenant_id"`
+	TenantUserID         uuid.UUID `json:"tenant_user_id"`
+	StudentNumber        string    `json:"student_number"`
+	StudentNumberChanged bool      `json:"student_number_changed"`
+	SourceDeactivated    bool      `json:"source_deactivated"`
+}
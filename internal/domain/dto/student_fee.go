@@ -28,6 +28,52 @@ type UpdateStudentFeeRequest struct {
 	Notes         *string    `json:"notes,omitempty"`
 }
 
+// RecordFeePaymentRequest records one payment toward a fee; the payment date defaults to today
+type RecordFeePaymentRequest struct {
+	Amount        float64    `json:"amount" validate:"required,gt=0"`
+	PaymentDate   *time.Time `json:"payment_date,omitempty"`
+	PaymentMethod string     `json:"payment_method" validate:"required,max=50"`
+	Notes         *string    `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// FeePayment is one payment made toward a fee
+type FeePayment struct {
+	ID            uuid.UUID  `json:"id"`
+	Amount        float64    `json:"amount"`
+	PaymentDate   string     `json:"payment_date"`
+	PaymentMethod string     `json:"payment_method"`
+	ReceiptNumber *string    `json:"receipt_number,omitempty"`
+	Notes         *string    `json:"notes,omitempty"`
+	RecordedBy    *uuid.UUID `json:"recorded_by,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// FeePaymentsResponse is a fee's payment history with what is paid and what remains
+type FeePaymentsResponse struct {
+	FeeID         uuid.UUID    `json:"fee_id"`
+	Amount        float64      `json:"amount"`
+	TotalPaid     float64      `json:"total_paid"`
+	PenaltyAmount float64      `json:"penalty_amount"`
+	Remaining     float64      `json:"remaining"`
+	Status        string       `json:"status"`
+	Payments      []FeePayment `json:"payments"`
+}
+
+// UpdateFeeTypePenaltyRequest sets the late penalty of a fee type: a flat amount or a percentage of the fee,
+// charged once the fee is past its due date. A null penalty type removes the penalty.
+type UpdateFeeTypePenaltyRequest struct {
+	PenaltyType  *string `json:"penalty_type" validate:"omitempty,oneof=flat percentage"`
+	PenaltyValue float64 `json:"penalty_value" validate:"min=0"`
+}
+
+// FeeTypePenaltyResponse is the late penalty of a fee type
+type FeeTypePenaltyResponse struct {
+	FeeTypeID    uuid.UUID `json:"fee_type_id"`
+	Name         string    `json:"name"`
+	PenaltyType  *string   `json:"penalty_type"`
+	PenaltyValue float64   `json:"penalty_value"`
+}
+
 type FeeQueryParams struct {
 	QueryParams
 	StudentID      *uuid.UUID `query:"student_id" validate:"omitempty,uuid"`
@@ -35,3 +81,48 @@ type FeeQueryParams struct {
 	AcademicYearID *uuid.UUID `query:"academic_year_id" validate:"omitempty,uuid"`
 	Status         *string    `query:"status" validate:"omitempty,oneof=paid unpaid partial overdue"`
 }
+
+// FeeAmountSummary holds billed, collected, outstanding, and overdue totals
+type FeeAmountSummary struct {
+	FeeCount         int64   `json:"fee_count"`
+	TotalBilled      float64 `json:"total_billed"`
+	TotalCollected   float64 `json:"total_collected"`
+	TotalOutstanding float64 `json:"total_outstanding"`
+	TotalOverdue     float64 `json:"total_overdue"`
+	TotalPenalty     float64 `json:"total_penalty"`
+}
+
+// FeeStatusSummary holds the number and amount of fees with a given status
+type FeeStatusSummary struct {
+	Status   string  `json:"status"`
+	FeeCount int64   `json:"fee_count"`
+	Amount   float64 `json:"amount"`
+}
+
+// FeeTypeSummary holds the fee totals of a single fee type
+type FeeTypeSummary struct {
+	FeeTypeID   *uuid.UUID `json:"fee_type_id,omitempty"`
+	FeeTypeName string     `json:"fee_type_name"`
+	FeeAmountSummary
+}
+
+// FeeSummaryResponse aggregates student fees by status and fee type
+type FeeSummaryResponse struct {
+	AcademicYearID *uuid.UUID         `json:"academic_year_id,omitempty"`
+	FeeTypeID      *uuid.UUID         `json:"fee_type_id,omitempty"`
+	StudentID      *uuid.UUID         `json:"student_id,omitempty"`
+	Totals         FeeAmountSummary   `json:"totals"`
+	ByStatus       []FeeStatusSummary `json:"by_status"`
+	ByFeeType      []FeeTypeSummary   `json:"by_fee_type"`
+}
+
+// OutstandingFeeStudent summarizes the unpaid, partial, and overdue fees of a student
+type OutstandingFeeStudent struct {
+	StudentID        uuid.UUID `json:"student_id"`
+	StudentNumber    string    `json:"student_number"`
+	FullName         string    `json:"full_name"`
+	FeeCount         int64     `json:"fee_count"`
+	TotalOutstanding float64   `json:"total_outstanding"`
+	PenaltyAmount    float64   `json:"penalty_amount"`
+	OldestDueDate    time.Time `json:"oldest_due_date"`
+}
@@ -34,3 +34,35 @@ type TeacherQueryParams struct {
 	QueryParams
 	DepartmentID *uuid.UUID `query:"department_id" validate:"omitempty,uuid"`
 }
+
+// TeacherScheduleSlot is a weekly timetable entry of a teacher
+type TeacherScheduleSlot struct {
+	ScheduleID     uuid.UUID  `json:"schedule_id"`
+	ClassSubjectID uuid.UUID  `json:"class_subject_id"`
+	ClassID        *uuid.UUID `json:"class_id,omitempty"`
+	ClassName      *string    `json:"class_name,omitempty"`
+	SubjectID      *uuid.UUID `json:"subject_id,omitempty"`
+	SubjectName    *string    `json:"subject_name,omitempty"`
+	DayOfWeek      string     `json:"day_of_week"`
+	StartTime      string     `json:"start_time"`
+	EndTime        string     `json:"end_time"`
+	Room           *string    `json:"room,omitempty"`
+	HasConflict    bool       `json:"has_conflict"`
+}
+
+// TeacherScheduleConflict is a pair of timetable entries of the same teacher that overlap
+type TeacherScheduleConflict struct {
+	DayOfWeek        string    `json:"day_of_week"`
+	FirstScheduleID  uuid.UUID `json:"first_schedule_id"`
+	SecondScheduleID uuid.UUID `json:"second_schedule_id"`
+	OverlapStart     string    `json:"overlap_start"`
+	OverlapEnd       string    `json:"overlap_end"`
+}
+
+// TeacherScheduleResponse is a teacher's weekly timetable with its workload and conflicts
+type TeacherScheduleResponse struct {
+	TeacherID         uuid.UUID                 `json:"teacher_id"`
+	TotalHoursPerWeek float64                   `json:"total_hours_per_week"`
+	Slots             []TeacherScheduleSlot     `json:"slots"`
+	Conflicts         []TeacherScheduleConflict `json:"conflicts"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "encoding/json"
+
+// UpdateTenantSettingsRequest sets tenant settings by key; a null value resets the key to its default
+type UpdateTenantSettingsRequest struct {
+	Settings map[string]json.RawMessage `json:"settings" validate:"required,min=1"`
+}
+
+// TenantSettingsResponse holds the value in effect of every known tenant setting
+type TenantSettingsResponse struct {
+	Settings   map[string]json.RawMessage `json:"settings"`
+	Customized []string                   `json:"customized"` // Keys set by the tenant, the others have their default
+}
@@ -17,9 +17,16 @@ type UpdateTenantUserRequest struct {
 
 type TenantUserQueryParams struct {
 	QueryParams
-	TenantID uuid.UUID `query:"tenant_id" validate:"omitempty,uuid"`
-	UserID   uuid.UUID `query:"user_id" validate:"omitempty,uuid"`
-	IsActive *bool     `query:"is_active"`
+	TenantID uuid.UUID  `query:"tenant_id" validate:"omitempty,uuid"`
+	UserID   uuid.UUID  `query:"user_id" validate:"omitempty,uuid"`
+	RoleID   *uuid.UUID `query:"role_id" validate:"omitempty,uuid"`
+	IsActive *bool      `query:"is_active"`
+}
+
+// UpdateTenantUserRolesRequest assigns and revokes roles of a tenant membership in one call
+type UpdateTenantUserRolesRequest struct {
+	Assign []uuid.UUID `json:"assign" validate:"required_without=Revoke,omitempty,dive,required"`
+	Revoke []uuid.UUID `json:"revoke" validate:"required_without=Assign,omitempty,dive,required"`
 }
 
 type TenantUserResponse struct {
@@ -9,14 +9,14 @@ import (
 // User DTOs
 type CreateUserRequest struct {
 	Username    string     `json:"username" validate:"required,min=3,max=50"`
-	Password    string     `json:"password" validate:"required,min=6"`
+	Password    string     `json:"password" validate:"required,password"`
 	Email       string     `json:"email" validate:"omitempty,email,max=100"`
 	FullName    string     `json:"full_name" validate:"required,max=100"`
 	Birthplace  *string    `json:"birthplace" validate:"omitempty,max=100"`
 	Birthday    *time.Time `json:"birthday,omitempty"`
 	Gender      *Gender    `json:"gender" validate:"omitempty,oneof=male female"`
 	DateOfBirth *time.Time `json:"date_of_birth,omitempty"`
-	Phone       *string    `json:"phone" validate:"omitempty,max=20"`
+	Phone       *string    `json:"phone" validate:"omitempty,max=20,id_phone"`
 	Address     *string    `json:"address,omitempty"`
 	RoleID      *uuid.UUID `json:"role_id,omitempty"`
 	IsActive    *bool      `json:"is_active,omitempty"`
@@ -29,7 +29,7 @@ type UpdateUserRequest struct {
 	Birthday    *time.Time `json:"birthday,omitempty"`
 	Gender      *Gender    `json:"gender" validate:"omitempty,oneof=male female"`
 	DateOfBirth *time.Time `json:"date_of_birth,omitempty"`
-	Phone       *string    `json:"phone" validate:"omitempty,max=20"`
+	Phone       *string    `json:"phone" validate:"omitempty,max=20,id_phone"`
 	Address     *string    `json:"address,omitempty"`
 	RoleID      *uuid.UUID `json:"role_id,omitempty"`
 	IsActive    *bool      `json:"is_active,omitempty"`
@@ -44,3 +44,15 @@ type UserQueryParams struct {
 type BulkDeleteUserRequest struct {
 	IDs []uuid.UUID `json:"ids" validate:"required,min=1,dive,required"`
 }
+
+// ResetUserPasswordRequest represents an admin setting a temporary password for a user
+type ResetUserPasswordRequest struct {
+	Password  string `json:"password,omitempty" validate:"omitempty,password"` // Generated when empty
+	SendEmail bool   `json:"send_email"`                                       // Email the temporary password to the user
+}
+
+// ResetUserPasswordResponse represents the result of an admin password reset
+type ResetUserPasswordResponse struct {
+	TemporaryPassword string `json:"temporary_password,omitempty"` // The generated password, unless it was emailed
+	EmailSent         bool   `json:"email_sent"`
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// AccountHandler handles requests about the authenticated user's own account data
+type AccountHandler struct {
+	BaseHandler
+	accountService service.AccountService
+	validator      *validator.Validate
+}
+
+// NewAccountHandler creates a new account handler
+func NewAccountHandler(accountService service.AccountService, validator *validator.Validate, appCtx *util.AppContext) *AccountHandler {
+	return &AccountHandler{
+		BaseHandler:    NewBaseHandler(appCtx),
+		accountService: accountService,
+		validator:      validator,
+	}
+}
+
+// Export handles downloading every personal record kept about the authenticated user
+//
+//	@Summary		Export the authenticated user's personal data
+//	@Description	Returns the profile and, per tenant, the roles, student or teacher record, enrollments, grades, attendance, fees and notifications
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	dto.Response{data=dto.AccountExportResponse}
+//	@Failure		401	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/auth/me/export [get]
+func (h *AccountHandler) Export(c *gin.Context) {
+	userID, exists := h.ValidateUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{
+			Success: false,
+			Message: "Unauthorized",
+			Error:   "User ID not found in context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	export, err := h.accountService.Export(serviceCtx, userID)
+	if err != nil {
+		h.RespondError(c, "Failed to export account data", err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="kelasgo-account-export.json"`)
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Account data exported successfully",
+		Data:    export,
+	})
+}
+
+// Delete handles deleting the authenticated user's account after re-entering their password
+//
+//	@Summary		Delete the authenticated user's account
+//	@Description	Anonymizes the profile, deactivates every tenant membership and deletes notifications; academic records are kept
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	dto.DeleteAccountRequest	true	"Current password"
+//	@Success		200	{object}	dto.Response
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/auth/me [delete]
+func (h *AccountHandler) Delete(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	userID, exists := h.ValidateUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{
+			Success: false,
+			Message: "Unauthorized",
+			Error:   "User ID not found in context",
+		})
+		return
+	}
+
+	var req dto.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", userID.String()).
+			Msg("Failed to bind delete account request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("user_id", userID.String()).
+			Msg("Delete account request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	if err := h.accountService.Delete(serviceCtx, userID, req); err != nil {
+		h.RespondError(c, "Failed to delete account", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Account deleted successfully",
+	})
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// APIKeyHandler handles managing the API keys of server-to-server integrations
+type APIKeyHandler struct {
+	BaseHandler
+	apiKeyService service.APIKeyService
+	validator     *validator.Validate
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyService service.APIKeyService, validator *validator.Validate, appCtx *util.AppContext) *APIKeyHandler {
+	return &APIKeyHandler{
+		BaseHandler:   NewBaseHandler(appCtx),
+		apiKeyService: apiKeyService,
+		validator:     validator,
+	}
+}
+
+// Create handles creating an API key for the current tenant
+//
+//	@Summary		Create an API key
+//	@Description	Creates a key for a server-to-server integration, sent in the X-API-Key header. The key is only returned in this response.
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.CreateAPIKeyRequest	true	"Name and scopes"
+//	@Success		201		{object}	dto.Response{data=dto.CreateAPIKeyResponse}
+//	@Failure		400		{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/api-keys [post]
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to bind create API key request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Msg("Create API key request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Msg("API key creation attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "API key creation requires a valid tenant context",
+		})
+		return
+	}
+
+	userID, _ := h.GetUserID(c)
+
+	serviceCtx := h.CreateServiceContext(c)
+	apiKey, err := h.apiKeyService.Create(serviceCtx, tenantID, userID, req)
+	if err != nil {
+		h.RespondError(c, "Failed to create API key", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "API key created successfully, store the key now as it cannot be retrieved again",
+		Data:    apiKey,
+	})
+}
+
+// List handles listing the API keys of the current tenant
+//
+//	@Summary		List API keys
+//	@Description	Lists the tenant's API keys, revoked ones included, without the keys themselves
+//	@Tags			api-keys
+//	@Produce		json
+//	@Success		200	{object}	dto.Response{data=[]dto.APIKeyResponse}
+//	@Failure		400	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/api-keys [get]
+func (h *APIKeyHandler) List(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Msg("List API keys attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Listing API keys requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	apiKeys, err := h.apiKeyService.List(serviceCtx, tenantID)
+	if err != nil {
+		h.RespondError(c, "Failed to retrieve API keys", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "API keys retrieved successfully",
+		Data:    apiKeys,
+	})
+}
+
+// Revoke handles revoking an API key of the current tenant
+//
+//	@Summary		Revoke an API key
+//	@Description	Stops the key from authenticating; it stays listed with its revocation time
+//	@Tags			api-keys
+//	@Produce		json
+//	@Param			id	path		string	true	"API key ID"
+//	@Success		200	{object}	dto.Response
+//	@Failure		400	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid API key ID format in revoke request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid API key ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("api_key_id", id.String()).
+			Msg("API key revocation attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "API key revocation requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	if err := h.apiKeyService.Revoke(serviceCtx, tenantID, id); err != nil {
+		h.RespondError(c, "Failed to revoke API key", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "API key revoked successfully",
+	})
+}
@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// AttendanceHandler handles attendance related requests
+type AttendanceHandler struct {
+	BaseHandler
+	attendanceService service.AttendanceService
+	validator         *validator.Validate
+}
+
+// NewAttendanceHandler creates a new attendance handler
+func NewAttendanceHandler(attendanceService service.AttendanceService, validator *validator.Validate, appCtx *util.AppContext) *AttendanceHandler {
+	return &AttendanceHandler{
+		BaseHandler:       NewBaseHandler(appCtx),
+		attendanceService: attendanceService,
+		validator:         validator,
+	}
+}
+
+// StudentSummary handles getting a student's attendance counts and percentages per status
+func (h *AttendanceHandler) StudentSummary(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid student ID format in attendance summary request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid student ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	dateFrom, err := h.GetOptionalDateQuery(c, "date_from")
+	if err != nil {
+		h.respondInvalidDate(c, id, err)
+		return
+	}
+	dateTo, err := h.GetOptionalDateQuery(c, "date_to")
+	if err != nil {
+		h.respondInvalidDate(c, id, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("student_id", id.String()).
+			Msg("Attendance summary attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Getting an attendance summary requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	summary, err := h.attendanceService.GetStudentSummary(serviceCtx, tenantID, id, dateFrom, dateTo)
+	if err != nil {
+		h.RespondError(c, "Failed to get attendance summary", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Attendance summary retrieved successfully",
+		Data:    summary,
+	})
+}
+
+// List handles listing attendance records filtered by student, schedule, class, date range and status
+func (h *AttendanceHandler) List(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	params, err := h.parseAttendanceQueryParams(c)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to parse attendance list query parameters")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		logger.Warn().
+			Err(err).
+			Interface("params", params).
+			Msg("Attendance list query parameters validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Msg("Attendance listing attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Attendance listing requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	attendance, meta, err := h.attendanceService.List(serviceCtx, tenantID, params)
+	if err != nil {
+		h.RespondError(c, "Failed to retrieve attendance", err)
+		return
+	}
+
+	h.RespondPaginated(c, "Attendance retrieved successfully", attendance, *meta)
+}
+
+// parseAttendanceQueryParams reads the pagination and filters of an attendance listing
+func (h *AttendanceHandler) parseAttendanceQueryParams(c *gin.Context) (dto.AttendanceQueryParams, error) {
+	params := dto.AttendanceQueryParams{
+		QueryParams: util.ParsePaginationParams(c),
+	}
+	if status := c.Query("status"); status != "" {
+		params.Status = &status
+	}
+
+	var err error
+	if params.StudentID, err = h.GetOptionalUUIDQuery(c, "student_id"); err != nil {
+		return params, fmt.Errorf("student_id must be a valid UUID")
+	}
+	if params.ScheduleID, err = h.GetOptionalUUIDQuery(c, "schedule_id"); err != nil {
+		return params, fmt.Errorf("schedule_id must be a valid UUID")
+	}
+	if params.ClassID, err = h.GetOptionalUUIDQuery(c, "class_id"); err != nil {
+		return params, fmt.Errorf("class_id must be a valid UUID")
+	}
+	if params.DateFrom, err = h.GetOptionalDateQuery(c, "date_from"); err != nil {
+		return params, fmt.Errorf("date_from must use the YYYY-MM-DD format")
+	}
+	if params.DateTo, err = h.GetOptionalDateQuery(c, "date_to"); err != nil {
+		return params, fmt.Errorf("date_to must use the YYYY-MM-DD format")
+	}
+	if params.DateFrom != nil && params.DateTo != nil && params.DateTo.Before(*params.DateFrom) {
+		return params, fmt.Errorf("date_to must not be before date_from")
+	}
+	return params, nil
+}
+
+// Scan handles recording a student as present from a scanned QR code
+func (h *AttendanceHandler) Scan(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	var req dto.AttendanceScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to bind attendance scan request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Msg("Attendance scan request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("schedule_id", req.ScheduleID.String()).
+			Msg("Attendance scan attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Recording attendance requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	result, err := h.attendanceService.Scan(serviceCtx, tenantID, req)
+	if err != nil {
+		h.RespondError(c, "Failed to record attendance", err)
+		return
+	}
+
+	status, message := http.StatusCreated, "Attendance recorded successfully"
+	if result.AlreadyRecorded {
+		status, message = http.StatusOK, "Attendance already recorded"
+	}
+	c.JSON(status, dto.Response{
+		Success: true,
+		Message: message,
+		Data:    result,
+	})
+}
+
+// respondInvalidDate writes a 400 response for a malformed date filter
+func (h *AttendanceHandler) respondInvalidDate(c *gin.Context, studentID uuid.UUID, err error) {
+	h.GetLogger(c).Error().
+		Err(err).
+		Str("student_id", studentID.String()).
+		Msg("Invalid date range in attendance summary request")
+	c.JSON(http.StatusBadRequest, dto.Response{
+		Success: false,
+		Message: "Invalid query parameters",
+		Error:   "date_from and date_to must use the YYYY-MM-DD format",
+	})
+}
@@ -2,11 +2,14 @@ package handler
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/protocyber/kelasgo-api/internal/domain/dto"
 	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
 	"github.com/protocyber/kelasgo-api/internal/util"
 )
 
@@ -27,6 +30,17 @@ func NewAuthHandler(authService service.AuthService, validator *validator.Valida
 }
 
 // Login handles user login
+//
+//	@Summary		Log in
+//	@Description	Returns a token without a tenant, to be exchanged with select-tenant. With auth.single_tenant_mode a user with exactly one active membership gets a tenant-scoped token right away.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	dto.LoginRequest	true	"Login credentials"
+//	@Success		200	{object}	dto.Response{data=dto.LoginResponse}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Router			/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	logger := h.GetLogger(c)
 
@@ -46,24 +60,16 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	if err := h.validator.Struct(req); err != nil {
 		logger.Warn().
 			Err(err).
-			Str("email", req.Email).
+			Str("identifier", req.GetIdentifier()).
 			Msg("Login request validation failed")
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Validation failed",
-			Error:   err.Error(),
-		})
+		h.RespondValidationError(c, err)
 		return
 	}
 
 	serviceCtx := h.CreateServiceContext(c)
 	response, err := h.authService.Login(serviceCtx, req)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, dto.Response{
-			Success: false,
-			Message: "Login failed",
-			Error:   err.Error(),
-		})
+		h.RespondError(c, "Login failed", err)
 		return
 	}
 
@@ -75,6 +81,17 @@ func (h *AuthHandler) Login(c *gin.Context) {
 }
 
 // Register handles user registration
+//
+//	@Summary		Register a user
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	dto.RegisterRequest	true	"Registration details"
+//	@Success		201	{object}	dto.Response{data=model.User}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		409	{object}	dto.Response
+//	@Router			/auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	logger := h.GetLogger(c)
 
@@ -97,33 +114,161 @@ func (h *AuthHandler) Register(c *gin.Context) {
 			Str("username", req.Username).
 			Str("email", req.Email).
 			Msg("Registration request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	user, err := h.authService.Register(serviceCtx, req)
+	if err != nil {
+		h.RespondError(c, "Registration failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "User registered successfully",
+		Data:    user,
+	})
+}
+
+// VerifyEmail handles confirming an email address from a verification link
+//
+//	@Summary		Verify an email address
+//	@Tags			auth
+//	@Produce		json
+//	@Param			token	query	string	true	"Verification token from the email link"
+//	@Success		200	{object}	dto.Response
+//	@Failure		400	{object}	dto.Response
+//	@Router			/auth/verify-email [get]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	token := c.Query("token")
+	if token == "" {
+		logger.Warn().
+			Msg("Email verification attempt without token")
 		c.JSON(http.StatusBadRequest, dto.Response{
 			Success: false,
-			Message: "Validation failed",
-			Error:   err.Error(),
+			Message: "Verification token required",
+			Error:   "The token query parameter is required",
 		})
 		return
 	}
 
 	serviceCtx := h.CreateServiceContext(c)
-	user, err := h.authService.Register(serviceCtx, req)
-	if err != nil {
+	if err := h.authService.VerifyEmail(serviceCtx, token); err != nil {
+		h.RespondError(c, "Email verification failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Email verified successfully",
+	})
+}
+
+// ResendVerification handles sending a new email verification link
+//
+//	@Summary		Resend the verification email
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	dto.ResendVerificationRequest	true	"Email address"
+//	@Success		200	{object}	dto.Response
+//	@Failure		400	{object}	dto.Response
+//	@Router			/auth/resend-verification [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	var req dto.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to bind resend verification request JSON")
 		c.JSON(http.StatusBadRequest, dto.Response{
 			Success: false,
-			Message: "Registration failed",
+			Message: "Invalid request body",
 			Error:   err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.Response{
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("email", req.Email).
+			Msg("Resend verification request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	if err := h.authService.ResendVerification(serviceCtx, req); err != nil {
+		h.RespondError(c, "Failed to resend verification email", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
-		Message: "User registered successfully",
-		Data:    user,
+		Message: "If the account exists and is not verified yet, a verification email has been sent",
+	})
+}
+
+// CheckAvailability handles checking whether a username or email is still free before registering
+//
+//	@Summary		Check username and email availability
+//	@Description	Returns whether each given value can still be registered. At least one of username and email is required. Requests are rate limited per client IP.
+//	@Tags			auth
+//	@Produce		json
+//	@Param			username	query	string	false	"Username to check"
+//	@Param			email	query	string	false	"Email address to check"
+//	@Success		200	{object}	dto.Response{data=dto.AvailabilityResponse}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		429	{object}	dto.Response
+//	@Router			/auth/check-availability [get]
+func (h *AuthHandler) CheckAvailability(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	req := dto.CheckAvailabilityRequest{
+		Username: strings.TrimSpace(c.Query("username")),
+		Email:    strings.TrimSpace(c.Query("email")),
+	}
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Msg("Availability check validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	availability, err := h.authService.CheckAvailability(serviceCtx, req)
+	if err != nil {
+		h.RespondError(c, "Failed to check availability", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Availability checked successfully",
+		Data:    availability,
 	})
 }
 
 // ChangePassword handles password change
+//
+//	@Summary		Change the password
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	dto.ChangePasswordRequest	true	"Current and new password"
+//	@Success		200	{object}	dto.Response
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/auth/change-password [post]
 func (h *AuthHandler) ChangePassword(c *gin.Context) {
 	logger := h.GetLogger(c)
 
@@ -156,22 +301,14 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 			Err(err).
 			Str("user_id", userID.String()).
 			Msg("Change password request validation failed")
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Validation failed",
-			Error:   err.Error(),
-		})
+		h.RespondValidationError(c, err)
 		return
 	}
 
 	serviceCtx := h.CreateServiceContext(c)
 	err := h.authService.ChangePassword(serviceCtx, userID, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Failed to change password",
-			Error:   err.Error(),
-		})
+		h.RespondError(c, "Failed to change password", err)
 		return
 	}
 
@@ -182,6 +319,20 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 }
 
 // SelectTenant handles tenant selection after authentication
+//
+//	@Summary		Select a tenant
+//	@Description	Returns a new token bound to the selected tenant
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body	dto.TenantSelectionRequest	true	"Tenant to select"
+//	@Success		200	{object}	dto.Response{data=dto.TenantSelectionResponse}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/auth/select-tenant [post]
 func (h *AuthHandler) SelectTenant(c *gin.Context) {
 	logger := h.GetLogger(c)
 
@@ -215,22 +366,14 @@ func (h *AuthHandler) SelectTenant(c *gin.Context) {
 			Str("user_id", userID.String()).
 			Str("tenant_id", req.TenantID).
 			Msg("Tenant selection request validation failed")
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Validation failed",
-			Error:   err.Error(),
-		})
+		h.RespondValidationError(c, err)
 		return
 	}
 
 	serviceCtx := h.CreateServiceContext(c)
 	response, err := h.authService.SelectTenant(serviceCtx, userID, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Tenant selection failed",
-			Error:   err.Error(),
-		})
+		h.RespondError(c, "Tenant selection failed", err)
 		return
 	}
 
@@ -242,6 +385,15 @@ func (h *AuthHandler) SelectTenant(c *gin.Context) {
 }
 
 // GetUserTenants handles getting all tenants for the authenticated user
+//
+//	@Summary		List the user's tenants
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	dto.Response{data=[]model.TenantUser}
+//	@Failure		401	{object}	dto.Response
+//	@Failure		500	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/auth/tenants [get]
 func (h *AuthHandler) GetUserTenants(c *gin.Context) {
 	// logger := h.GetLogger(c)
 
@@ -258,17 +410,121 @@ func (h *AuthHandler) GetUserTenants(c *gin.Context) {
 	serviceCtx := h.CreateServiceContext(c)
 	tenants, err := h.authService.GetUserTenants(serviceCtx, userID)
 	if err != nil {
+		h.RespondError(c, "Failed to get user tenants", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "User tenants retrieved successfully",
+		Data:    tenants,
+	})
+}
+
+// PasswordPolicy handles getting the rules new passwords must satisfy
+//
+//	@Summary		Get the password policy
+//	@Description	Returns the rules enforced on passwords at registration, user creation and password change
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	dto.Response{data=util.PasswordPolicy}
+//	@Router			/auth/password-policy [get]
+func (h *AuthHandler) PasswordPolicy(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Password policy retrieved successfully",
+		Data:    h.GetAppContext().GetPasswordPolicy(),
+	})
+}
+
+// Me handles getting the authenticated user's profile
+//
+//	@Summary		Get the authenticated user's profile
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	dto.Response{data=dto.MeResponse}
+//	@Failure		401	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/auth/me [get]
+func (h *AuthHandler) Me(c *gin.Context) {
+	userID, exists := h.ValidateUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{
+			Success: false,
+			Message: "Unauthorized",
+			Error:   "User ID not found in context",
+		})
+		return
+	}
+
+	// Selected tenant comes from the token, not from request headers
+	tenantID := middleware.GetTenantID(c)
+
+	serviceCtx := h.CreateServiceContext(c)
+	me, err := h.authService.GetMe(serviceCtx, userID, tenantID)
+	if err != nil {
+		h.RespondError(c, "Failed to get user profile", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "User profile retrieved successfully",
+		Data:    me,
+	})
+}
+
+// Permissions handles listing the authenticated user's effective permissions in the selected tenant
+//
+//	@Summary		List the authenticated user's permissions
+//	@Description	Returns the roles of the user in the tenant selected in the token and the permissions they grant
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	dto.Response{data=dto.PermissionsResponse}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/auth/me/permissions [get]
+func (h *AuthHandler) Permissions(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	userID, exists := h.ValidateUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{
+			Success: false,
+			Message: "Unauthorized",
+			Error:   "User ID not found in context",
+		})
+		return
+	}
+
+	// Permissions are scoped to the tenant selected in the token
+	tenantID := middleware.GetTenantID(c)
+	claims, ok := h.GetClaims(c)
+	if !ok || tenantID == uuid.Nil {
+		logger.Warn().
+			Str("user_id", userID.String()).
+			Msg("Permission listing attempt without a selected tenant")
 		c.JSON(http.StatusBadRequest, dto.Response{
 			Success: false,
-			Message: "Failed to get user tenants",
-			Error:   err.Error(),
+			Message: "Tenant ID required",
+			Error:   "Select a tenant before listing permissions",
 		})
 		return
 	}
 
+	serviceCtx := h.CreateServiceContext(c)
+	permissions, err := h.authService.GetPermissions(serviceCtx, userID, tenantID, claims.Role)
+	if err != nil {
+		h.RespondError(c, "Failed to get user permissions", err)
+		return
+	}
+
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
-		Message: "User tenants retrieved successfully",
-		Data:    tenants,
+		Message: "User permissions retrieved successfully",
+		Data:    permissions,
 	})
 }
@@ -2,9 +2,18 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
 	"github.com/protocyber/kelasgo-api/internal/util"
 )
 
@@ -36,6 +45,15 @@ func (b *BaseHandler) GetAppContext() *util.AppContext {
 	return b.appCtx
 }
 
+// GetLanguage returns the language of the response, resolved per request by LocaleMiddleware or else the
+// configured locale
+func (b *BaseHandler) GetLanguage(c *gin.Context) string {
+	if language := middleware.GetLocale(c); language != "" {
+		return language
+	}
+	return util.SupportedLanguage(b.appCtx.GetLocale())
+}
+
 // CreateServiceContext creates a context suitable for service layer
 func (b *BaseHandler) CreateServiceContext(c *gin.Context) context.Context {
 	return util.CreateServiceContextFromGin(c)
@@ -55,6 +73,17 @@ func (b *BaseHandler) GetUserID(c *gin.Context) (uuid.UUID, bool) {
 	return uuid.Nil, false
 }
 
+// GetClaims extracts the JWT claims set by the JWT middleware
+func (b *BaseHandler) GetClaims(c *gin.Context) (*util.JWTClaims, bool) {
+	claimsInterface, exists := c.Get("claims")
+	if !exists || claimsInterface == nil {
+		return nil, false
+	}
+
+	claims, ok := claimsInterface.(*util.JWTClaims)
+	return claims, ok
+}
+
 // GetTenantID extracts tenant ID from gin context as string
 func (b *BaseHandler) GetTenantID(c *gin.Context) (string, bool) {
 	tenantIDInterface, exists := c.Get(string(util.XTenantIDKey))
@@ -83,6 +112,85 @@ func (b *BaseHandler) GetTenantIDAsUUID(c *gin.Context) (uuid.UUID, bool) {
 	return uuid.Nil, false
 }
 
+// GetOptionalUUIDQuery parses an optional UUID query parameter, returning nil when it is absent
+func (b *BaseHandler) GetOptionalUUIDQuery(c *gin.Context, key string) (*uuid.UUID, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := uuid.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// GetOptionalDateQuery parses an optional YYYY-MM-DD query parameter, returning nil when it is absent
+func (b *BaseHandler) GetOptionalDateQuery(c *gin.Context, key string) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// GetOptionalTimestampQuery parses an optional RFC3339 query parameter, returning nil when it is absent
+func (b *BaseHandler) GetOptionalTimestampQuery(c *gin.Context, key string) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// ParseChangeFilters reads the created_after and updated_after list filters into params
+func (b *BaseHandler) ParseChangeFilters(c *gin.Context, params *dto.QueryParams) error {
+	var err error
+	if params.CreatedAfter, err = b.GetOptionalTimestampQuery(c, "created_after"); err != nil {
+		return fmt.Errorf("created_after must be an RFC3339 timestamp")
+	}
+	if params.UpdatedAfter, err = b.GetOptionalTimestampQuery(c, "updated_after"); err != nil {
+		return fmt.Errorf("updated_after must be an RFC3339 timestamp")
+	}
+	return nil
+}
+
+// GetOptionalBoolQuery parses an optional boolean query parameter, returning nil when it is absent
+func (b *BaseHandler) GetOptionalBoolQuery(c *gin.Context, key string) (*bool, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// GetListQuery parses an optional comma separated query parameter, returning nil when it is absent
+func (b *BaseHandler) GetListQuery(c *gin.Context, key string) []string {
+	var values []string
+	for _, value := range strings.Split(c.Query(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 // ValidateUserID checks if user ID exists in context and logs error if not
 func (b *BaseHandler) ValidateUserID(c *gin.Context) (uuid.UUID, bool) {
 	logger := b.GetLogger(c)
@@ -97,8 +205,116 @@ func (b *BaseHandler) ValidateUserID(c *gin.Context) (uuid.UUID, bool) {
 	return userID, true
 }
 
+// ValidateBulkSize checks that a bulk request stays within the configured item limit,
+// writing a 400 response and returning false when it does not
+func (b *BaseHandler) ValidateBulkSize(c *gin.Context, count int) bool {
+	maxItems := b.appCtx.GetBulkMaxItems()
+	if maxItems <= 0 || count <= maxItems {
+		return true
+	}
+
+	b.GetLogger(c).Warn().
+		Int("count", count).
+		Int("max_items", maxItems).
+		Msg("Bulk request exceeds the item limit")
+	c.JSON(http.StatusBadRequest, dto.Response{
+		Success: false,
+		Message: "Too many items",
+		Error:   fmt.Sprintf("A bulk request can contain at most %d items", maxItems),
+	})
+	return false
+}
+
+// RespondValidationError writes a 400 response with per-field validation errors
+func (b *BaseHandler) RespondValidationError(c *gin.Context, err error) {
+	language := b.GetLanguage(c)
+	fieldErrors := util.FormatValidationErrors(err, language, b.GetAppContext().GetPasswordPolicy())
+
+	errMsg := util.TranslateMessage(language, "One or more fields are invalid")
+	if len(fieldErrors) == 0 {
+		errMsg = err.Error()
+	}
+
+	c.JSON(http.StatusBadRequest, dto.Response{
+		Success: false,
+		Message: util.TranslateMessage(language, "Validation failed"),
+		Error:   errMsg,
+		Errors:  fieldErrors,
+	})
+}
+
+// RespondError writes a service error with the status matching its kind, e.g. 404 for apperror.ErrNotFound.
+// Errors of requests cut off by TimeoutMiddleware are answered with 503.
+func (b *BaseHandler) RespondError(c *gin.Context, message string, err error) {
+	if errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) {
+		c.JSON(http.StatusServiceUnavailable, dto.Response{
+			Success: false,
+			Message: message,
+			Error:   "request timed out",
+		})
+		return
+	}
+
+	c.JSON(apperror.HTTPStatus(err), dto.Response{
+		Success: false,
+		Message: message,
+		Error:   err.Error(),
+	})
+}
+
+// nearMaxLimitRatio is the share of the max page size from which a full page counts as truncated
+const nearMaxLimitRatio = 0.9
+
+// RespondPaginated writes a page of a list. Pages at or near the max page size with more rows after them get
+// X-Result-Truncated, and results whose total reaches the large result threshold get X-Total-Rows, so clients
+// know they are paging through a large set; the warning is repeated in the meta when configured.
+func (b *BaseHandler) RespondPaginated(c *gin.Context, message string, data interface{}, meta dto.PaginationMeta) {
+	_, maxLimit, _ := b.appCtx.GetPaginationDefaults()
+	largeResultThreshold, metaWarning := b.appCtx.GetResultSizeWarning()
+
+	truncated := maxLimit > 0 && float64(meta.Limit) >= float64(maxLimit)*nearMaxLimitRatio &&
+		int64(meta.Page)*int64(meta.Limit) < meta.TotalRows
+	large := largeResultThreshold > 0 && meta.TotalRows >= largeResultThreshold
+	if truncated || large {
+		c.Header("X-Total-Rows", strconv.FormatInt(meta.TotalRows, 10))
+	}
+	if truncated {
+		c.Header("X-Result-Truncated", "true")
+	}
+	if metaWarning {
+		switch {
+		case truncated:
+			meta.Warning = fmt.Sprintf("Result truncated to %d of %d rows; request the next pages or narrow the query", meta.Limit, meta.TotalRows)
+		case large:
+			meta.Warning = fmt.Sprintf("Large result of %d rows; narrow the query with filters where possible", meta.TotalRows)
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.PaginatedResponse{
+		Success: true,
+		Message: message,
+		Data:    data,
+		Meta:    meta,
+	})
+}
+
 // Deprecated: Use GetLogger and CreateServiceContext instead
 func (b *BaseHandler) ExtractContext(c *gin.Context) {
 	// This method is kept for backward compatibility
 	// New code should use GetLogger(c) and CreateServiceContext(c) directly
 }
+
+// RespondNotModified sets the weak ETag of a loaded resource and answers 304 Not Modified when it matches
+// the If-None-Match header, reporting whether the response was sent. Responses are marked private so tenant
+// data is only cached by the client, which revalidates on every request.
+func (b *BaseHandler) RespondNotModified(c *gin.Context, id uuid.UUID, versions ...time.Time) bool {
+	etag := util.WeakETag(id, versions...)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+
+	if util.ETagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return true
+	}
+	return false
+}
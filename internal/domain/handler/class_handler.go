@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// ClassHandler handles class related requests
+type ClassHandler struct {
+	BaseHandler
+	classService service.ClassService
+	validator    *validator.Validate
+}
+
+// NewClassHandler creates a new class handler
+func NewClassHandler(classService service.ClassService, validator *validator.Validate, appCtx *util.AppContext) *ClassHandler {
+	return &ClassHandler{
+		BaseHandler:  NewBaseHandler(appCtx),
+		classService: classService,
+		validator:    validator,
+	}
+}
+
+// Promote handles moving all students of a class to the next class
+func (h *ClassHandler) Promote(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid class ID format in promote request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid class ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req dto.PromoteClassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_id", id.String()).
+			Msg("Failed to bind promote class request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("class_id", id.String()).
+			Msg("Promote class request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("class_id", id.String()).
+			Msg("Class promotion attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Class promotion requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	result, err := h.classService.Promote(serviceCtx, tenantID, id, req)
+	if err != nil {
+		h.RespondError(c, "Failed to promote class", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Class promoted successfully",
+		Data:    result,
+	})
+}
+
+// AssignHomeroomTeacher handles setting or clearing the homeroom teacher of a class
+func (h *ClassHandler) AssignHomeroomTeacher(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid class ID format in homeroom teacher request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid class ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req dto.AssignHomeroomTeacherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_id", id.String()).
+			Msg("Failed to bind homeroom teacher request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("class_id", id.String()).
+			Msg("Homeroom teacher assignment attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Homeroom teacher assignment requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	result, err := h.classService.AssignHomeroomTeacher(serviceCtx, tenantID, id, req)
+	if err != nil {
+		h.RespondError(c, "Failed to assign homeroom teacher", err)
+		return
+	}
+
+	message := "Homeroom teacher assigned successfully"
+	if req.TeacherID == nil {
+		message = "Homeroom teacher cleared successfully"
+	} else if len(result.OtherHomeroomClasses) > 0 {
+		message = "Homeroom teacher assigned successfully; the teacher is also homeroom teacher of other classes"
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: message,
+		Data:    result,
+	})
+}
+
+// Gradebook handles getting the students × subjects grade matrix of a class
+func (h *ClassHandler) Gradebook(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid class ID format in gradebook request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid class ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	params := dto.GradebookQueryParams{
+		GradeType: c.Query("grade_type"),
+	}
+	if err := h.validator.Struct(params); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("class_id", id.String()).
+			Msg("Gradebook query validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("class_id", id.String()).
+			Msg("Gradebook attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Getting a gradebook requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	gradebook, err := h.classService.Gradebook(serviceCtx, tenantID, id, params)
+	if err != nil {
+		h.RespondError(c, "Failed to get gradebook", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Gradebook retrieved successfully",
+		Data:    gradebook,
+	})
+}
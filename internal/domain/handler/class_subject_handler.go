@@ -0,0 +1,412 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// ClassSubjectHandler handles class subject related requests
+type ClassSubjectHandler struct {
+	BaseHandler
+	classSubjectService service.ClassSubjectService
+	validator           *validator.Validate
+}
+
+// NewClassSubjectHandler creates a new class subject handler
+func NewClassSubjectHandler(classSubjectService service.ClassSubjectService, validator *validator.Validate, appCtx *util.AppContext) *ClassSubjectHandler {
+	return &ClassSubjectHandler{
+		BaseHandler:         NewBaseHandler(appCtx),
+		classSubjectService: classSubjectService,
+		validator:           validator,
+	}
+}
+
+// Create handles assigning a subject and teacher to a class
+func (h *ClassSubjectHandler) Create(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	var req dto.CreateClassSubjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to bind create class subject request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Msg("Create class subject request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Msg("Class subject creation attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Class subject creation requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	classSubject, err := h.classSubjectService.Create(serviceCtx, tenantID, req)
+	if err != nil {
+		h.RespondError(c, "Failed to create class subject", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Class subject created successfully",
+		Data:    classSubject,
+	})
+}
+
+// GetByID handles getting a class subject by ID
+func (h *ClassSubjectHandler) GetByID(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid class subject ID format in get request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid class subject ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("class_subject_id", id.String()).
+			Msg("Get class subject attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Getting a class subject requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	classSubject, err := h.classSubjectService.GetByID(serviceCtx, tenantID, id)
+	if err != nil {
+		h.RespondError(c, "Class subject not found", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Class subject retrieved successfully",
+		Data:    classSubject,
+	})
+}
+
+// Update handles class subject updates
+func (h *ClassSubjectHandler) Update(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid class subject ID format in update request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid class subject ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req dto.UpdateClassSubjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_subject_id", id.String()).
+			Msg("Failed to bind update class subject request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("class_subject_id", id.String()).
+			Msg("Update class subject request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("class_subject_id", id.String()).
+			Msg("Class subject update attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Class subject update requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	classSubject, err := h.classSubjectService.Update(serviceCtx, tenantID, id, req)
+	if err != nil {
+		h.RespondError(c, "Failed to update class subject", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Class subject updated successfully",
+		Data:    classSubject,
+	})
+}
+
+// Delete handles class subject deletion
+func (h *ClassSubjectHandler) Delete(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid class subject ID format in delete request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid class subject ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("class_subject_id", id.String()).
+			Msg("Class subject deletion attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Class subject deletion requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	err = h.classSubjectService.Delete(serviceCtx, tenantID, id)
+	if err != nil {
+		h.RespondError(c, "Failed to delete class subject", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Class subject deleted successfully",
+	})
+}
+
+// List handles listing class subjects with optional class, subject, and teacher filters
+func (h *ClassSubjectHandler) List(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	params := dto.ClassSubjectQueryParams{
+		QueryParams: util.ParsePaginationParams(c),
+	}
+
+	var err error
+	if params.ClassID, err = h.GetOptionalUUIDQuery(c, "class_id"); err == nil {
+		if params.SubjectID, err = h.GetOptionalUUIDQuery(c, "subject_id"); err == nil {
+			params.TeacherID, err = h.GetOptionalUUIDQuery(c, "teacher_id")
+		}
+	}
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Invalid filter in list class subjects request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Msg("List class subjects attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Listing class subjects requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	classSubjects, meta, err := h.classSubjectService.List(serviceCtx, tenantID, params)
+	if err != nil {
+		h.RespondError(c, "Failed to retrieve class subjects", err)
+		return
+	}
+
+	h.RespondPaginated(c, "Class subjects retrieved successfully", classSubjects, *meta)
+}
+
+// GetByTeacher handles listing everything a teacher teaches
+func (h *ClassSubjectHandler) GetByTeacher(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	teacherIDStr := c.Param("teacher_id")
+	teacherID, err := uuid.Parse(teacherIDStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("teacher_id_param", teacherIDStr).
+			Msg("Invalid teacher ID format in get class subjects by teacher request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid teacher ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	params := util.ParsePaginationParams(c)
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("teacher_id", teacherID.String()).
+			Msg("Get class subjects by teacher attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Getting class subjects by teacher requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	classSubjects, meta, err := h.classSubjectService.GetByTeacher(serviceCtx, tenantID, teacherID, params)
+	if err != nil {
+		h.RespondError(c, "Failed to retrieve class subjects by teacher", err)
+		return
+	}
+
+	h.RespondPaginated(c, "Class subjects retrieved successfully", classSubjects, *meta)
+}
+
+// AssignToTeacher handles assigning several class and subject pairs to a teacher at once
+func (h *ClassSubjectHandler) AssignToTeacher(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	teacherID, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid teacher ID format in class subject assignment request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid teacher ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req dto.AssignTeacherClassSubjectsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Str("teacher_id", teacherID.String()).
+			Msg("Failed to bind teacher class subject assignment request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("teacher_id", teacherID.String()).
+			Msg("Teacher class subject assignment request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	if !h.ValidateBulkSize(c, len(req.Assignments)) {
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("teacher_id", teacherID.String()).
+			Msg("Teacher class subject assignment attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Assigning subjects to a teacher requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	result, err := h.classSubjectService.AssignToTeacher(serviceCtx, tenantID, teacherID, req)
+	if err != nil {
+		h.RespondError(c, "Failed to assign subjects to teacher", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Subjects assigned to teacher successfully",
+		Data:    result,
+	})
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// DashboardHandler handles dashboard related requests
+type DashboardHandler struct {
+	BaseHandler
+	dashboardService service.DashboardService
+}
+
+// NewDashboardHandler creates a new dashboard handler
+func NewDashboardHandler(dashboardService service.DashboardService, appCtx *util.AppContext) *DashboardHandler {
+	return &DashboardHandler{
+		BaseHandler:      NewBaseHandler(appCtx),
+		dashboardService: dashboardService,
+	}
+}
+
+// Get handles getting the dashboard variant of the caller's role
+func (h *DashboardHandler) Get(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	userID, exists := h.ValidateUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{
+			Success: false,
+			Message: "Unauthorized",
+			Error:   "User ID not found in context",
+		})
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("user_id", userID.String()).
+			Msg("Dashboard attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Getting the dashboard requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	dashboard, err := h.dashboardService.Get(serviceCtx, tenantID, userID, c.GetString("role"))
+	if err != nil {
+		h.RespondError(c, "Failed to get dashboard", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Dashboard retrieved successfully",
+		Data:    dashboard,
+	})
+}
+
+// Summary handles getting the landing screen numbers for the caller's role
+func (h *DashboardHandler) Summary(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	userID, exists := h.ValidateUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{
+			Success: false,
+			Message: "Unauthorized",
+			Error:   "User ID not found in context",
+		})
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("user_id", userID.String()).
+			Msg("Dashboard summary attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Getting the dashboard summary requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	summary, err := h.dashboardService.GetSummary(serviceCtx, tenantID, userID, c.GetString("role"))
+	if err != nil {
+		h.RespondError(c, "Failed to get dashboard summary", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Dashboard summary retrieved successfully",
+		Data:    summary,
+	})
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// DiagnosticsHandler handles requests checking the deployment
+type DiagnosticsHandler struct {
+	BaseHandler
+	diagnosticsService service.DiagnosticsService
+}
+
+// NewDiagnosticsHandler creates a new diagnostics handler
+func NewDiagnosticsHandler(diagnosticsService service.DiagnosticsService, appCtx *util.AppContext) *DiagnosticsHandler {
+	return &DiagnosticsHandler{
+		BaseHandler:        NewBaseHandler(appCtx),
+		diagnosticsService: diagnosticsService,
+	}
+}
+
+// SessionIsolation handles checking that the tenant context does not leak between requests sharing a pooled
+// connection. It responds 503 when isolation does not hold, so health checks and integration tests can assert on it.
+func (h *DiagnosticsHandler) SessionIsolation(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().Msg("Session isolation check attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Checking session isolation requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	report, err := h.diagnosticsService.CheckSessionIsolation(serviceCtx, tenantID)
+	if err != nil {
+		h.RespondError(c, "Failed to check session isolation", err)
+		return
+	}
+
+	if !report.Isolated {
+		c.JSON(http.StatusServiceUnavailable, dto.Response{
+			Success: false,
+			Message: "Tenant session isolation does not hold",
+			Data:    report,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Tenant session isolation holds",
+		Data:    report,
+	})
+}
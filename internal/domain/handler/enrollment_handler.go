@@ -0,0 +1,317 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// EnrollmentHandler handles enrollment related requests
+type EnrollmentHandler struct {
+	BaseHandler
+	enrollmentService service.EnrollmentService
+	validator         *validator.Validate
+}
+
+// NewEnrollmentHandler creates a new enrollment handler
+func NewEnrollmentHandler(enrollmentService service.EnrollmentService, validator *validator.Validate, appCtx *util.AppContext) *EnrollmentHandler {
+	return &EnrollmentHandler{
+		BaseHandler:       NewBaseHandler(appCtx),
+		enrollmentService: enrollmentService,
+		validator:         validator,
+	}
+}
+
+// Create handles enrolling a student into a class subject
+func (h *EnrollmentHandler) Create(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	var req dto.CreateEnrollmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to bind create enrollment request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Msg("Create enrollment request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Msg("Enrollment creation attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Enrollment creation requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	enrollment, err := h.enrollmentService.Create(serviceCtx, tenantID, req)
+	if err != nil {
+		h.RespondError(c, "Failed to create enrollment", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Enrollment created successfully",
+		Data:    enrollment,
+	})
+}
+
+// BulkEnroll handles enrolling every student of a class into a class subject
+func (h *EnrollmentHandler) BulkEnroll(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	var req dto.BulkEnrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to bind bulk enroll request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Msg("Bulk enroll request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("class_subject_id", req.ClassSubjectID.String()).
+			Msg("Bulk enrollment attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Bulk enrollment requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	result, err := h.enrollmentService.BulkEnroll(serviceCtx, tenantID, req)
+	if err != nil {
+		h.RespondError(c, "Failed to enroll students", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Students enrolled successfully",
+		Data:    result,
+	})
+}
+
+// Delete handles enrollment deletion
+func (h *EnrollmentHandler) Delete(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid enrollment ID format in delete request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid enrollment ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("enrollment_id", id.String()).
+			Msg("Enrollment deletion attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Enrollment deletion requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	err = h.enrollmentService.Delete(serviceCtx, tenantID, id)
+	if err != nil {
+		h.RespondError(c, "Failed to delete enrollment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Enrollment deleted successfully",
+	})
+}
+
+// ListByStudent handles listing the enrollments of a student
+func (h *EnrollmentHandler) ListByStudent(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	studentIDStr := c.Param("student_id")
+	studentID, err := uuid.Parse(studentIDStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("student_id_param", studentIDStr).
+			Msg("Invalid student ID format in list enrollments request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid student ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	params := util.ParsePaginationParams(c)
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("student_id", studentID.String()).
+			Msg("List enrollments by student attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Listing enrollments requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	enrollments, meta, err := h.enrollmentService.ListByStudent(serviceCtx, tenantID, studentID, params)
+	if err != nil {
+		h.RespondError(c, "Failed to retrieve enrollments", err)
+		return
+	}
+
+	h.RespondPaginated(c, "Enrollments retrieved successfully", enrollments, *meta)
+}
+
+// ListByClassSubject handles listing the enrollments of a class subject
+func (h *EnrollmentHandler) ListByClassSubject(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	classSubjectIDStr := c.Param("class_subject_id")
+	classSubjectID, err := uuid.Parse(classSubjectIDStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_subject_id_param", classSubjectIDStr).
+			Msg("Invalid class subject ID format in list enrollments request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid class subject ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	params := util.ParsePaginationParams(c)
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("class_subject_id", classSubjectID.String()).
+			Msg("List enrollments by class subject attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Listing enrollments requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	enrollments, meta, err := h.enrollmentService.ListByClassSubject(serviceCtx, tenantID, classSubjectID, params)
+	if err != nil {
+		h.RespondError(c, "Failed to retrieve enrollments", err)
+		return
+	}
+
+	h.RespondPaginated(c, "Enrollments retrieved successfully", enrollments, *meta)
+}
+
+// History handles getting every enrollment of a student across academic years with grade summaries
+func (h *EnrollmentHandler) History(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	studentIDStr := c.Param("id")
+	studentID, err := uuid.Parse(studentIDStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", studentIDStr).
+			Msg("Invalid student ID format in enrollment history request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid student ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("student_id", studentID.String()).
+			Msg("Enrollment history attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Getting an enrollment history requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	history, err := h.enrollmentService.History(serviceCtx, tenantID, studentID)
+	if err != nil {
+		h.RespondError(c, "Failed to retrieve enrollment history", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Enrollment history retrieved successfully",
+		Data:    history,
+	})
+}
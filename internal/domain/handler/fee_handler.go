@@ -0,0 +1,442 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// FeeHandler handles student fee related requests
+type FeeHandler struct {
+	BaseHandler
+	feeService service.FeeService
+	validator  *validator.Validate
+}
+
+// NewFeeHandler creates a new fee handler
+func NewFeeHandler(feeService service.FeeService, validator *validator.Validate, appCtx *util.AppContext) *FeeHandler {
+	return &FeeHandler{
+		BaseHandler: NewBaseHandler(appCtx),
+		feeService:  feeService,
+		validator:   validator,
+	}
+}
+
+// Summary handles getting the fee collection summary for finance staff
+func (h *FeeHandler) Summary(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	var params dto.FeeQueryParams
+	var err error
+	if params.AcademicYearID, err = h.GetOptionalUUIDQuery(c, "academic_year_id"); err != nil {
+		h.respondInvalidFilter(c, "academic_year_id", err)
+		return
+	}
+	if params.FeeTypeID, err = h.GetOptionalUUIDQuery(c, "fee_type_id"); err != nil {
+		h.respondInvalidFilter(c, "fee_type_id", err)
+		return
+	}
+	if params.StudentID, err = h.GetOptionalUUIDQuery(c, "student_id"); err != nil {
+		h.respondInvalidFilter(c, "student_id", err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Msg("Fee summary attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Getting a fee summary requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	summary, err := h.feeService.Summary(serviceCtx, tenantID, params)
+	if err != nil {
+		h.RespondError(c, "Failed to get fee summary", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Fee summary retrieved successfully",
+		Data:    summary,
+	})
+}
+
+// ListOutstanding handles listing students with unpaid, partial, or overdue fees
+func (h *FeeHandler) ListOutstanding(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	params := dto.FeeQueryParams{
+		QueryParams: util.ParsePaginationParams(c),
+	}
+	var err error
+	if params.AcademicYearID, err = h.GetOptionalUUIDQuery(c, "academic_year_id"); err != nil {
+		h.respondInvalidFilter(c, "academic_year_id", err)
+		return
+	}
+	if params.FeeTypeID, err = h.GetOptionalUUIDQuery(c, "fee_type_id"); err != nil {
+		h.respondInvalidFilter(c, "fee_type_id", err)
+		return
+	}
+
+	if err := h.validator.Struct(params); err != nil {
+		logger.Warn().
+			Err(err).
+			Msg("Outstanding fees query validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Msg("Outstanding fees attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Listing outstanding fees requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	students, meta, err := h.feeService.ListOutstanding(serviceCtx, tenantID, params)
+	if err != nil {
+		h.RespondError(c, "Failed to retrieve outstanding fees", err)
+		return
+	}
+
+	h.RespondPaginated(c, "Outstanding fees retrieved successfully", students, *meta)
+}
+
+// RecordPayment handles recording a payment toward a fee
+//
+//	@Summary		Record a fee payment
+//	@Description	Appends an installment to the fee's payments. The fee becomes paid once the payments add up to its amount plus any late penalty and partial before that; payments above the remaining amount are rejected.
+//	@Tags			fees
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"Fee ID (UUID)"
+//	@Param			request	body	dto.RecordFeePaymentRequest	true	"Payment"
+//	@Success		201	{object}	dto.Response{data=dto.FeePaymentsResponse}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/fees/{id}/pay [post]
+func (h *FeeHandler) RecordPayment(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	id, ok := h.parseFeeID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.RecordFeePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Str("fee_id", id.String()).
+			Msg("Failed to bind fee payment request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("fee_id", id.String()).
+			Msg("Fee payment request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("fee_id", id.String()).
+			Msg("Fee payment attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Recording a fee payment requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	payments, err := h.feeService.RecordPayment(serviceCtx, tenantID, id, req)
+	if err != nil {
+		h.RespondError(c, "Failed to record fee payment", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Fee payment recorded successfully",
+		Data:    payments,
+	})
+}
+
+// ListPayments handles listing the payment history of a fee
+//
+//	@Summary		List a fee's payments
+//	@Tags			fees
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"Fee ID (UUID)"
+//	@Success		200	{object}	dto.Response{data=dto.FeePaymentsResponse}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/fees/{id}/payments [get]
+func (h *FeeHandler) ListPayments(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	id, ok := h.parseFeeID(c)
+	if !ok {
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("fee_id", id.String()).
+			Msg("Fee payments request without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Listing fee payments requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	payments, err := h.feeService.ListPayments(serviceCtx, tenantID, id)
+	if err != nil {
+		h.RespondError(c, "Failed to retrieve fee payments", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Fee payments retrieved successfully",
+		Data:    payments,
+	})
+}
+
+// Receipt handles getting the receipt of a paid fee, issuing it on first request
+//
+//	@Summary		Get a fee payment receipt
+//	@Description	Issues a receipt with a generated number the first time it is requested and returns the same receipt afterwards. Use format=pdf for a printable PDF.
+//	@Tags			fees
+//	@Produce		json,application/pdf
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"Fee ID (UUID)"
+//	@Param			format	query	string	false	"Response format"	Enums(json, pdf)
+//	@Success		200	{object}	dto.Response{data=model.FeeReceipt}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/fees/{id}/receipt [get]
+func (h *FeeHandler) Receipt(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	id, ok := h.parseFeeID(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "pdf" {
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error:   "format must be json or pdf",
+		})
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("fee_id", id.String()).
+			Msg("Fee receipt request without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Getting a fee receipt requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	if format == "pdf" {
+		receipt, document, err := h.feeService.ReceiptPDF(serviceCtx, tenantID, id)
+		if err != nil {
+			h.RespondError(c, "Failed to get fee receipt", err)
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="receipt-%s.pdf"`, receipt.ReceiptNumber))
+		c.Header("Cache-Control", "private, no-store")
+		c.Data(http.StatusOK, "application/pdf", document)
+		return
+	}
+
+	receipt, err := h.feeService.Receipt(serviceCtx, tenantID, id)
+	if err != nil {
+		h.RespondError(c, "Failed to get fee receipt", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Fee receipt retrieved successfully",
+		Data:    receipt,
+	})
+}
+
+// UpdateFeeTypePenalty handles setting the late payment penalty of a fee type
+//
+//	@Summary		Set a fee type's late penalty
+//	@Description	Sets a flat amount or a percentage of the fee charged once a fee of this type is past its due date. The penalty is added to what remains of the fee and to outstanding totals. A null penalty_type removes the penalty.
+//	@Tags			fees
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"Fee type ID (UUID)"
+//	@Param			request	body	dto.UpdateFeeTypePenaltyRequest	true	"Penalty policy"
+//	@Success		200	{object}	dto.Response{data=dto.FeeTypePenaltyResponse}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/fees/types/{id}/penalty [put]
+func (h *FeeHandler) UpdateFeeTypePenalty(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid fee type ID format")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid fee type ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req dto.UpdateFeeTypePenaltyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Str("fee_type_id", id.String()).
+			Msg("Failed to bind fee type penalty request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("fee_type_id", id.String()).
+			Msg("Fee type penalty request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("fee_type_id", id.String()).
+			Msg("Fee type penalty update attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Updating a fee type penalty requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	penalty, err := h.feeService.UpdateFeeTypePenalty(serviceCtx, tenantID, id, req)
+	if err != nil {
+		h.RespondError(c, "Failed to update fee type penalty", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Fee type penalty updated successfully",
+		Data:    penalty,
+	})
+}
+
+// parseFeeID reads the fee ID path parameter, writing a 400 response when it is not a UUID
+func (h *FeeHandler) parseFeeID(c *gin.Context) (uuid.UUID, bool) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.GetLogger(c).Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid fee ID format")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid fee ID format",
+			Error:   err.Error(),
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondInvalidFilter writes a 400 response for a malformed query filter
+func (h *FeeHandler) respondInvalidFilter(c *gin.Context, key string, err error) {
+	h.GetLogger(c).Error().
+		Err(err).
+		Str("filter", key).
+		Msg("Invalid filter in fee request")
+	c.JSON(http.StatusBadRequest, dto.Response{
+		Success: false,
+		Message: "Invalid query parameters",
+		Error:   key + " must be a valid UUID",
+	})
+}
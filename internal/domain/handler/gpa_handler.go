@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// GPAHandler handles grade point average requests
+type GPAHandler struct {
+	BaseHandler
+	gpaService service.GPAService
+}
+
+// NewGPAHandler creates a new GPA handler
+func NewGPAHandler(gpaService service.GPAService, appCtx *util.AppContext) *GPAHandler {
+	return &GPAHandler{
+		BaseHandler: NewBaseHandler(appCtx),
+		gpaService:  gpaService,
+	}
+}
+
+// Get handles getting a student's credit weighted GPA within an academic year and over every year
+//
+//	@Summary		Get a student's GPA
+//	@Description	Maps the final score of every enrolled subject to a grade point of the grading scale and weighs it by the subject's credits. Subjects without credits or final score are listed but not counted.
+//	@Tags			students
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"Student ID (UUID)"
+//	@Param			academic_year_id	query	string	false	"Academic year ID (UUID), defaults to the active year"
+//	@Success		200	{object}	dto.Response{data=dto.GPAResponse}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students/{id}/gpa [get]
+func (h *GPAHandler) Get(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid student ID format in GPA request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid student ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var params dto.GPAQueryParams
+	if value := c.Query("academic_year_id"); value != "" {
+		academicYearID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.Response{
+				Success: false,
+				Message: "Invalid query parameters",
+				Error:   "academic_year_id must be a UUID",
+			})
+			return
+		}
+		params.AcademicYearID = &academicYearID
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("student_id", id.String()).
+			Msg("GPA request without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Getting a GPA requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	gpa, err := h.gpaService.Get(serviceCtx, tenantID, id, params)
+	if err != nil {
+		h.RespondError(c, "Failed to compute GPA", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "GPA retrieved successfully",
+		Data:    gpa,
+	})
+}
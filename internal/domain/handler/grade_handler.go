@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// GradeHandler handles grade related requests
+type GradeHandler struct {
+	BaseHandler
+	gradeService service.GradeService
+	validator    *validator.Validate
+}
+
+// NewGradeHandler creates a new grade handler
+func NewGradeHandler(gradeService service.GradeService, validator *validator.Validate, appCtx *util.AppContext) *GradeHandler {
+	return &GradeHandler{
+		BaseHandler:  NewBaseHandler(appCtx),
+		gradeService: gradeService,
+		validator:    validator,
+	}
+}
+
+// GetScale handles getting the tenant's grading scale and grade type weights
+func (h *GradeHandler) GetScale(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Msg("Grading scale request without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Getting the grading scale requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	scale, err := h.gradeService.GetGradingScale(serviceCtx, tenantID)
+	if err != nil {
+		h.RespondError(c, "Failed to get grading scale", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Grading scale retrieved successfully",
+		Data:    scale,
+	})
+}
+
+// UpdateScale handles replacing the tenant's grading scale and grade type weights
+func (h *GradeHandler) UpdateScale(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	var req dto.UpdateGradingScaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to bind grading scale request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Msg("Grading scale request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Msg("Grading scale update attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Updating the grading scale requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	scale, err := h.gradeService.UpdateGradingScale(serviceCtx, tenantID, req)
+	if err != nil {
+		h.RespondError(c, "Failed to update grading scale", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Grading scale updated successfully",
+		Data:    scale,
+	})
+}
+
+// BulkSave handles entering the grades of a class subject's students in one request
+func (h *GradeHandler) BulkSave(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid class subject ID format in bulk grade request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid class subject ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req dto.BulkGradeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_subject_id", id.String()).
+			Msg("Failed to bind bulk grade request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("class_subject_id", id.String()).
+			Msg("Bulk grade request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("class_subject_id", id.String()).
+			Msg("Bulk grade entry attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Entering grades requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	result, err := h.gradeService.BulkSave(serviceCtx, tenantID, id, req)
+	if err != nil {
+		h.RespondError(c, "Failed to save grades", err)
+		return
+	}
+
+	message := "Grades saved successfully"
+	if result.Failed > 0 {
+		message = "Grades saved with some entries failed"
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: message,
+		Data:    result,
+	})
+}
+
+// Export handles streaming the grades of a class subject's students as an Excel download
+func (h *GradeHandler) Export(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid class subject ID format in grade export request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid class subject ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var params dto.GradeExportQueryParams
+	if value := c.Query("semester"); value != "" {
+		semester, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.Response{
+				Success: false,
+				Message: "Invalid query parameters",
+				Error:   "semester must be 1 or 2",
+			})
+			return
+		}
+		params.Semester = &semester
+	}
+	if err := h.validator.Struct(params); err != nil {
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("class_subject_id", id.String()).
+			Msg("Grade export attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Exporting grades requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	export, err := h.gradeService.Export(serviceCtx, tenantID, id, params)
+	if err != nil {
+		h.RespondError(c, "Failed to export grades", err)
+		return
+	}
+
+	filename := unsafeFilenameChars.ReplaceAllString(fmt.Sprintf("grades-%s-%s", export.ClassName, export.SubjectCode), "-")
+	if export.Semester != nil {
+		filename += fmt.Sprintf("-semester-%d", *export.Semester)
+	}
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.xlsx"`, filename))
+	c.Header("Cache-Control", "private, no-store")
+	c.Status(http.StatusOK)
+
+	// The workbook is streamed, so a failure past this point can only be logged
+	if err := h.gradeService.WriteExcel(c.Writer, export); err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_subject_id", id.String()).
+			Msg("Failed to write grade export")
+	}
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// NotificationHandler handles notification related requests
+type NotificationHandler struct {
+	BaseHandler
+	notificationService service.NotificationService
+	validator           *validator.Validate
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService service.NotificationService, validator *validator.Validate, appCtx *util.AppContext) *NotificationHandler {
+	return &NotificationHandler{
+		BaseHandler:         NewBaseHandler(appCtx),
+		notificationService: notificationService,
+		validator:           validator,
+	}
+}
+
+// Send handles sending a notification to a user of the tenant
+func (h *NotificationHandler) Send(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	var req dto.CreateNotificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to bind send notification request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Msg("Send notification request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Msg("Send notification attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Sending a notification requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	delivery, err := h.notificationService.Send(serviceCtx, tenantID, req)
+	if err != nil {
+		h.RespondError(c, "Failed to send notification", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Response{
+		Success: true,
+		Message: "Notification sent successfully",
+		Data:    delivery,
+	})
+}
+
+// GetPreferences handles getting the current user's notification channels per notification type
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, exists := h.ValidateUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{
+			Success: false,
+			Message: "Unauthorized",
+			Error:   "User ID not found in context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	preferences, err := h.notificationService.GetPreferences(serviceCtx, userID)
+	if err != nil {
+		h.RespondError(c, "Failed to get notification preferences", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Notification preferences retrieved successfully",
+		Data:    preferences,
+	})
+}
+
+// UpdatePreferences handles changing the current user's notification channels
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	userID, exists := h.ValidateUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{
+			Success: false,
+			Message: "Unauthorized",
+			Error:   "User ID not found in context",
+		})
+		return
+	}
+
+	var req dto.UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to bind notification preferences request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Msg("Notification preferences request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	preferences, err := h.notificationService.UpdatePreferences(serviceCtx, userID, req)
+	if err != nil {
+		h.RespondError(c, "Failed to update notification preferences", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Notification preferences updated successfully",
+		Data:    preferences,
+	})
+}
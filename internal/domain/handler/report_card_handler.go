@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// unsafeFilenameChars matches characters replaced in download file names
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ReportCardHandler handles report card related requests
+type ReportCardHandler struct {
+	BaseHandler
+	reportCardService service.ReportCardService
+	validator         *validator.Validate
+}
+
+// NewReportCardHandler creates a new report card handler
+func NewReportCardHandler(reportCardService service.ReportCardService, validator *validator.Validate, appCtx *util.AppContext) *ReportCardHandler {
+	return &ReportCardHandler{
+		BaseHandler:       NewBaseHandler(appCtx),
+		reportCardService: reportCardService,
+		validator:         validator,
+	}
+}
+
+// Download handles streaming a student's report card as a PDF download
+//
+//	@Summary		Download a student's report card
+//	@Description	Returns a PDF with the student's profile, subject results with letter grades and attendance summary.
+//	@Tags			students
+//	@Produce		application/pdf
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"Student ID (UUID)"
+//	@Param			academic_year_id	query	string	false	"Academic year ID (UUID), defaults to the active year"
+//	@Param			semester	query	int	false	"Semester"	Enums(1, 2)
+//	@Success		200	{file}	binary
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students/{id}/report-card [get]
+func (h *ReportCardHandler) Download(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid student ID format in report card request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid student ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	params, err := parseReportCardQueryParams(c)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("student_id", id.String()).
+			Msg("Invalid query parameters in report card request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if err := h.validator.Struct(params); err != nil {
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("student_id", id.String()).
+			Msg("Report card request without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Getting a report card requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	card, document, err := h.reportCardService.GeneratePDF(serviceCtx, tenantID, id, params)
+	if err != nil {
+		h.RespondError(c, "Failed to generate report card", err)
+		return
+	}
+
+	filename := unsafeFilenameChars.ReplaceAllString(fmt.Sprintf("report-card-%s-%s", card.StudentNumber, card.AcademicYear), "-")
+	if card.Semester != nil {
+		filename += fmt.Sprintf("-semester-%d", *card.Semester)
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, filename))
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, "application/pdf", document)
+}
+
+// Transcript handles getting a student's cumulative transcript as JSON or as a PDF download
+//
+//	@Summary		Get a student's academic transcript
+//	@Description	Returns the final result of every enrolled subject per academic year with credit weighted year and cumulative averages, and a summary per subject. With format=pdf the transcript is downloaded as a PDF.
+//	@Tags			students
+//	@Produce		json
+//	@Produce		application/pdf
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"Student ID (UUID)"
+//	@Param			format	query	string	false	"Response format"	Enums(json, pdf)
+//	@Success		200	{object}	dto.Response{data=dto.Transcript}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students/{id}/transcript [get]
+func (h *ReportCardHandler) Transcript(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid student ID format in transcript request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid student ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	params := dto.TranscriptQueryParams{
+		Format: c.Query("format"),
+	}
+	if err := h.validator.Struct(params); err != nil {
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("student_id", id.String()).
+			Msg("Transcript request without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Getting a transcript requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	if params.Format == "pdf" {
+		transcript, document, err := h.reportCardService.TranscriptPDF(serviceCtx, tenantID, id)
+		if err != nil {
+			h.RespondError(c, "Failed to generate transcript", err)
+			return
+		}
+
+		filename := unsafeFilenameChars.ReplaceAllString("transcript-"+transcript.StudentNumber, "-")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, filename))
+		c.Header("Cache-Control", "private, no-store")
+		c.Data(http.StatusOK, "application/pdf", document)
+		return
+	}
+
+	transcript, err := h.reportCardService.Transcript(serviceCtx, tenantID, id)
+	if err != nil {
+		h.RespondError(c, "Failed to generate transcript", err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Transcript retrieved successfully",
+		Data:    transcript,
+	})
+}
+
+// parseReportCardQueryParams reads the academic year and semester of a report card request
+func parseReportCardQueryParams(c *gin.Context) (dto.ReportCardQueryParams, error) {
+	var params dto.ReportCardQueryParams
+	if value := c.Query("academic_year_id"); value != "" {
+		academicYearID, err := uuid.Parse(value)
+		if err != nil {
+			return params, fmt.Errorf("academic_year_id must be a UUID")
+		}
+		params.AcademicYearID = &academicYearID
+	}
+	if value := c.Query("semester"); value != "" {
+		semester, err := strconv.Atoi(value)
+		if err != nil {
+			return params, fmt.Errorf("semester must be 1 or 2")
+		}
+		params.Semester = &semester
+	}
+	return params, nil
+}
@@ -1,12 +1,16 @@
 package handler
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
 	"github.com/protocyber/kelasgo-api/internal/domain/service"
 	"github.com/protocyber/kelasgo-api/internal/server/middleware"
 	"github.com/protocyber/kelasgo-api/internal/util"
@@ -29,6 +33,22 @@ func NewStudentHandler(studentService service.StudentService, validator *validat
 }
 
 // Create handles student creation
+//
+//	@Summary		Create a student
+//	@Description	When student_number is omitted the next number of the tenant's student number format is generated and returned.
+//	@Tags			students
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			request	body	dto.CreateStudentRequest	true	"Student details"
+//	@Success		201	{object}	dto.Response{data=model.Student}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Failure		409	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students [post]
 func (h *StudentHandler) Create(c *gin.Context) {
 	logger := h.GetLogger(c)
 
@@ -51,11 +71,7 @@ func (h *StudentHandler) Create(c *gin.Context) {
 			Str("student_number", req.StudentNumber).
 			Str("tenant_user_id", req.TenantUserID.String()).
 			Msg("Create student request validation failed")
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Validation failed",
-			Error:   err.Error(),
-		})
+		h.RespondValidationError(c, err)
 		return
 	}
 
@@ -76,11 +92,7 @@ func (h *StudentHandler) Create(c *gin.Context) {
 	serviceCtx := h.CreateServiceContext(c)
 	student, err := h.studentService.Create(serviceCtx, tenantID, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Failed to create student",
-			Error:   err.Error(),
-		})
+		h.RespondError(c, "Failed to create student", err)
 		return
 	}
 
@@ -92,6 +104,21 @@ func (h *StudentHandler) Create(c *gin.Context) {
 }
 
 // GetByID handles getting student by ID
+//
+//	@Summary		Get a student
+//	@Tags			students
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"Student ID (UUID)"
+//	@Param			If-None-Match	header	string	false	"ETag of a previously fetched student"
+//	@Success		200	{object}	dto.Response{data=model.Student}
+//	@Success		304	"Student unchanged since the given ETag"
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students/{id} [get]
 func (h *StudentHandler) GetByID(c *gin.Context) {
 	logger := h.GetLogger(c)
 
@@ -113,11 +140,11 @@ func (h *StudentHandler) GetByID(c *gin.Context) {
 	serviceCtx := h.CreateServiceContext(c)
 	student, err := h.studentService.GetByID(serviceCtx, id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, dto.Response{
-			Success: false,
-			Message: "Student not found",
-			Error:   err.Error(),
-		})
+		h.RespondError(c, "Student not found", err)
+		return
+	}
+
+	if h.RespondNotModified(c, student.ID, studentVersions(serviceCtx, student)...) {
 		return
 	}
 
@@ -129,6 +156,22 @@ func (h *StudentHandler) GetByID(c *gin.Context) {
 }
 
 // Update handles student update
+//
+//	@Summary		Update a student
+//	@Tags			students
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"Student ID (UUID)"
+//	@Param			request	body	dto.UpdateStudentRequest	true	"Fields to update"
+//	@Success		200	{object}	dto.Response{data=model.Student}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Failure		409	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students/{id} [put]
 func (h *StudentHandler) Update(c *gin.Context) {
 	logger := h.GetLogger(c)
 
@@ -166,22 +209,14 @@ func (h *StudentHandler) Update(c *gin.Context) {
 			Err(err).
 			Str("student_id", id.String()).
 			Msg("Update student request validation failed")
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Validation failed",
-			Error:   err.Error(),
-		})
+		h.RespondValidationError(c, err)
 		return
 	}
 
 	serviceCtx := h.CreateServiceContext(c)
 	student, err := h.studentService.Update(serviceCtx, id, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Failed to update student",
-			Error:   err.Error(),
-		})
+		h.RespondError(c, "Failed to update student", err)
 		return
 	}
 
@@ -193,6 +228,19 @@ func (h *StudentHandler) Update(c *gin.Context) {
 }
 
 // Delete handles student deletion
+//
+//	@Summary		Delete a student
+//	@Tags			students
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"Student ID (UUID)"
+//	@Success		200	{object}	dto.Response
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students/{id} [delete]
 func (h *StudentHandler) Delete(c *gin.Context) {
 	logger := h.GetLogger(c)
 
@@ -214,11 +262,7 @@ func (h *StudentHandler) Delete(c *gin.Context) {
 	serviceCtx := h.CreateServiceContext(c)
 	err = h.studentService.Delete(serviceCtx, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Failed to delete student",
-			Error:   err.Error(),
-		})
+		h.RespondError(c, "Failed to delete student", err)
 		return
 	}
 
@@ -229,6 +273,19 @@ func (h *StudentHandler) Delete(c *gin.Context) {
 }
 
 // BulkDelete handles bulk student deletion
+//
+//	@Summary		Delete several students
+//	@Tags			students
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			request	body	dto.BulkDeleteStudentRequest	true	"Student IDs"
+//	@Success		200	{object}	dto.Response
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students [delete]
 func (h *StudentHandler) BulkDelete(c *gin.Context) {
 	logger := h.GetLogger(c)
 
@@ -250,11 +307,11 @@ func (h *StudentHandler) BulkDelete(c *gin.Context) {
 			Err(err).
 			Interface("student_ids", req.IDs).
 			Msg("Bulk delete student request validation failed")
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Validation failed",
-			Error:   err.Error(),
-		})
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	if !h.ValidateBulkSize(c, len(req.IDs)) {
 		return
 	}
 
@@ -275,29 +332,124 @@ func (h *StudentHandler) BulkDelete(c *gin.Context) {
 	serviceCtx := h.CreateServiceContext(c)
 	err := h.studentService.BulkDelete(serviceCtx, tenantID, req.IDs)
 	if err != nil {
+		h.RespondError(c, "Failed to bulk delete students", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Students bulk deleted successfully",
+	})
+}
+
+// BulkAssignClass handles moving several students into a class
+//
+//	@Summary		Assign several students to a class
+//	@Tags			students
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			request	body	dto.BulkAssignClassRequest	true	"Student IDs and target class"
+//	@Success		200	{object}	dto.Response{data=dto.BulkAssignClassResponse}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students/bulk-class [patch]
+func (h *StudentHandler) BulkAssignClass(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	var req dto.BulkAssignClassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to bind bulk class assignment request JSON")
 		c.JSON(http.StatusBadRequest, dto.Response{
 			Success: false,
-			Message: "Failed to bulk delete students",
+			Message: "Invalid request body",
 			Error:   err.Error(),
 		})
 		return
 	}
 
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Interface("student_ids", req.IDs).
+			Msg("Bulk class assignment request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	if !h.ValidateBulkSize(c, len(req.IDs)) {
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Interface("student_ids", req.IDs).
+			Msg("Bulk class assignment attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Assigning students to a class requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	result, err := h.studentService.BulkAssignClass(serviceCtx, tenantID, req)
+	if err != nil {
+		h.RespondError(c, "Failed to assign students to class", err)
+		return
+	}
+
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
-		Message: "Students bulk deleted successfully",
+		Message: "Students assigned to class successfully",
+		Data:    result,
 	})
 }
 
 // List handles student listing with pagination
+//
+//	@Summary		List students
+//	@Tags			students
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			page	query	int	false	"Page number"
+//	@Param			limit	query	int	false	"Page size (max 100)"
+//	@Param			search	query	string	false	"Search term"
+//	@Param			sort_by	query	string	false	"Sort field" Enums(student_number, admission_date, created_at, updated_at)
+//	@Param			sort_dir	query	string	false	"Sort direction" Enums(asc, desc)
+//	@Param			created_after	query	string	false	"Only students created after this RFC3339 timestamp"
+//	@Param			updated_after	query	string	false	"Only students updated after this RFC3339 timestamp"
+//	@Param			fields	query	string	false	"Comma separated student fields to return, e.g. id,student_number,class"
+//	@Param			expand	query	string	false	"Comma separated relations to load: tenant_user, class, parent, guardians"
+//	@Param			class_id	query	string	false	"Filter by class ID"
+//	@Param			parent_id	query	string	false	"Filter by parent ID"
+//	@Param			enrolled_from	query	string	false	"Admission date lower bound (YYYY-MM-DD)"
+//	@Param			enrolled_to	query	string	false	"Admission date upper bound (YYYY-MM-DD)"
+//	@Param			enrollment_status	query	string	false	"Filter by enrollment" Enums(enrolled, not_enrolled)
+//	@Param			status	query	string	false	"Filter by lifecycle status, defaults to active" Enums(active, graduated, withdrawn, suspended, all)
+//	@Success		200	{object}	dto.PaginatedResponse{data=[]model.Student}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		500	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students [get]
 func (h *StudentHandler) List(c *gin.Context) {
 	logger := h.GetLogger(c)
 
-	var params dto.StudentQueryParams
-	if err := c.ShouldBindQuery(&params); err != nil {
+	params, err := h.parseStudentQueryParams(c)
+	if err != nil {
 		logger.Error().
 			Err(err).
-			Msg("Failed to bind student list query parameters")
+			Msg("Failed to parse student list query parameters")
 		c.JSON(http.StatusBadRequest, dto.Response{
 			Success: false,
 			Message: "Invalid query parameters",
@@ -311,11 +463,7 @@ func (h *StudentHandler) List(c *gin.Context) {
 			Err(err).
 			Interface("params", params).
 			Msg("Student list query parameters validation failed")
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Validation failed",
-			Error:   err.Error(),
-		})
+		h.RespondValidationError(c, err)
 		return
 	}
 
@@ -335,23 +483,36 @@ func (h *StudentHandler) List(c *gin.Context) {
 	serviceCtx := h.CreateServiceContext(c)
 	students, meta, err := h.studentService.List(serviceCtx, tenantID, params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Response{
-			Success: false,
-			Message: "Failed to retrieve students",
-			Error:   err.Error(),
-		})
+		h.RespondError(c, "Failed to retrieve students", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.PaginatedResponse{
-		Success: true,
-		Message: "Students retrieved successfully",
-		Data:    students,
-		Meta:    *meta,
-	})
+	h.respondStudentList(c, students, meta, params.Fields)
 }
 
 // GetByClass handles getting students by class ID
+//
+//	@Summary		List students of a class
+//	@Tags			students
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			class_id	path	string	true	"Class ID (UUID)"
+//	@Param			page	query	int	false	"Page number"
+//	@Param			limit	query	int	false	"Page size (max 100)"
+//	@Param			search	query	string	false	"Search term"
+//	@Param			sort_by	query	string	false	"Sort field" Enums(student_number, admission_date, created_at, updated_at)
+//	@Param			sort_dir	query	string	false	"Sort direction" Enums(asc, desc)
+//	@Param			created_after	query	string	false	"Only students created after this RFC3339 timestamp"
+//	@Param			updated_after	query	string	false	"Only students updated after this RFC3339 timestamp"
+//	@Param			fields	query	string	false	"Comma separated student fields to return, e.g. id,student_number,class"
+//	@Param			expand	query	string	false	"Comma separated relations to load: tenant_user, class, parent, guardians"
+//	@Success		200	{object}	dto.PaginatedResponse{data=[]model.Student}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		500	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students/class/{class_id} [get]
 func (h *StudentHandler) GetByClass(c *gin.Context) {
 	logger := h.GetLogger(c)
 
@@ -370,11 +531,15 @@ func (h *StudentHandler) GetByClass(c *gin.Context) {
 		return
 	}
 
-	var params dto.QueryParams
-	if err := c.ShouldBindQuery(&params); err != nil {
+	params := dto.StudentQueryParams{
+		QueryParams: util.ParsePaginationParams(c),
+		Fields:      h.GetListQuery(c, "fields"),
+		Expand:      h.GetListQuery(c, "expand"),
+	}
+	if err := h.ParseChangeFilters(c, &params.QueryParams); err != nil {
 		logger.Error().
 			Err(err).
-			Msg("Failed to bind query parameters for students by class")
+			Msg("Failed to parse students by class query parameters")
 		c.JSON(http.StatusBadRequest, dto.Response{
 			Success: false,
 			Message: "Invalid query parameters",
@@ -382,6 +547,14 @@ func (h *StudentHandler) GetByClass(c *gin.Context) {
 		})
 		return
 	}
+	if err := h.validator.Struct(params); err != nil {
+		logger.Warn().
+			Err(err).
+			Interface("params", params).
+			Msg("Students by class query parameters validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
 
 	// Get tenant ID from middleware context
 	tenantID := middleware.GetTenantID(c)
@@ -400,23 +573,36 @@ func (h *StudentHandler) GetByClass(c *gin.Context) {
 	serviceCtx := h.CreateServiceContext(c)
 	students, meta, err := h.studentService.GetByClass(serviceCtx, tenantID, classID, params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Response{
-			Success: false,
-			Message: "Failed to retrieve students by class",
-			Error:   err.Error(),
-		})
+		h.RespondError(c, "Failed to retrieve students by class", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.PaginatedResponse{
-		Success: true,
-		Message: "Students retrieved successfully",
-		Data:    students,
-		Meta:    *meta,
-	})
+	h.respondStudentList(c, students, meta, params.Fields)
 }
 
 // GetByParent handles getting students by parent ID
+//
+//	@Summary		List students of a parent
+//	@Tags			students
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			parent_id	path	string	true	"Parent ID (UUID)"
+//	@Param			page	query	int	false	"Page number"
+//	@Param			limit	query	int	false	"Page size (max 100)"
+//	@Param			search	query	string	false	"Search term"
+//	@Param			sort_by	query	string	false	"Sort field" Enums(student_number, admission_date, created_at, updated_at)
+//	@Param			sort_dir	query	string	false	"Sort direction" Enums(asc, desc)
+//	@Param			created_after	query	string	false	"Only students created after this RFC3339 timestamp"
+//	@Param			updated_after	query	string	false	"Only students updated after this RFC3339 timestamp"
+//	@Param			fields	query	string	false	"Comma separated student fields to return, e.g. id,student_number,class"
+//	@Param			expand	query	string	false	"Comma separated relations to load: tenant_user, class, parent, guardians"
+//	@Success		200	{object}	dto.PaginatedResponse{data=[]model.Student}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		500	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students/parent/{parent_id} [get]
 func (h *StudentHandler) GetByParent(c *gin.Context) {
 	logger := h.GetLogger(c)
 
@@ -435,11 +621,15 @@ func (h *StudentHandler) GetByParent(c *gin.Context) {
 		return
 	}
 
-	var params dto.QueryParams
-	if err := c.ShouldBindQuery(&params); err != nil {
+	params := dto.StudentQueryParams{
+		QueryParams: util.ParsePaginationParams(c),
+		Fields:      h.GetListQuery(c, "fields"),
+		Expand:      h.GetListQuery(c, "expand"),
+	}
+	if err := h.ParseChangeFilters(c, &params.QueryParams); err != nil {
 		logger.Error().
 			Err(err).
-			Msg("Failed to bind query parameters for students by parent")
+			Msg("Failed to parse students by parent query parameters")
 		c.JSON(http.StatusBadRequest, dto.Response{
 			Success: false,
 			Message: "Invalid query parameters",
@@ -447,6 +637,14 @@ func (h *StudentHandler) GetByParent(c *gin.Context) {
 		})
 		return
 	}
+	if err := h.validator.Struct(params); err != nil {
+		logger.Warn().
+			Err(err).
+			Interface("params", params).
+			Msg("Students by parent query parameters validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
 
 	// Get tenant ID from middleware context
 	tenantID := middleware.GetTenantID(c)
@@ -465,18 +663,599 @@ func (h *StudentHandler) GetByParent(c *gin.Context) {
 	serviceCtx := h.CreateServiceContext(c)
 	students, meta, err := h.studentService.GetByParent(serviceCtx, tenantID, parentID, params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Response{
+		h.RespondError(c, "Failed to retrieve students by parent", err)
+		return
+	}
+
+	h.respondStudentList(c, students, meta, params.Fields)
+}
+
+// Transfer handles moving a student into another tenant
+//
+//	@Summary		Transfer a student to another tenant
+//	@Tags			students
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"Student ID (UUID)"
+//	@Param			request	body	dto.TransferStudentRequest	true	"Destination tenant"
+//	@Success		200	{object}	dto.Response{data=dto.TransferStudentResponse}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students/{id}/transfer [post]
+func (h *StudentHandler) Transfer(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid student ID format in transfer request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid student ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req dto.TransferStudentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Str("student_id", id.String()).
+			Msg("Failed to bind transfer student request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("student_id", id.String()).
+			Msg("Transfer student request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	userID, exists := h.ValidateUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.Response{
+			Success: false,
+			Message: "Unauthorized",
+			Error:   "User ID not found in context",
+		})
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("student_id", id.String()).
+			Msg("Student transfer attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Student transfer requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	result, err := h.studentService.Transfer(serviceCtx, tenantID, id, userID, req)
+	if err != nil {
+		h.RespondError(c, "Failed to transfer student", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Student transferred successfully",
+		Data:    result,
+	})
+}
+
+// GetNumberFormat handles getting the format of the tenant's generated student numbers
+//
+//	@Summary		Get the student number format
+//	@Tags			students
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Success		200	{object}	dto.Response{data=dto.StudentNumberFormatResponse}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students/number-format [get]
+func (h *StudentHandler) GetNumberFormat(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Msg("Student number format request without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Getting the student number format requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	format, err := h.studentService.GetNumberFormat(serviceCtx, tenantID)
+	if err != nil {
+		h.RespondError(c, "Failed to get student number format", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Student number format retrieved successfully",
+		Data:    format,
+	})
+}
+
+// UpdateNumberFormat handles setting the format of the tenant's generated student numbers
+//
+//	@Summary		Update the student number format
+//	@Description	Generated numbers are the prefix, the start year of the active academic year when included, and the sequence zero-padded to sequence_digits. The sequence restarts every academic year.
+//	@Tags			students
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			request	body	dto.UpdateStudentNumberFormatRequest	true	"Student number format"
+//	@Success		200	{object}	dto.Response{data=dto.StudentNumberFormatResponse}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students/number-format [put]
+func (h *StudentHandler) UpdateNumberFormat(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	var req dto.UpdateStudentNumberFormatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to bind student number format request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
 			Success: false,
-			Message: "Failed to retrieve students by parent",
+			Message: "Invalid request body",
 			Error:   err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.PaginatedResponse{
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Msg("Student number format request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Msg("Student number format update attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Updating the student number format requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	format, err := h.studentService.UpdateNumberFormat(serviceCtx, tenantID, req)
+	if err != nil {
+		h.RespondError(c, "Failed to update student number format", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
-		Message: "Students retrieved successfully",
-		Data:    students,
-		Meta:    *meta,
+		Message: "Student number format updated successfully",
+		Data:    format,
 	})
 }
+
+// ChangeStatus handles moving a student to another lifecycle status
+//
+//	@Summary		Change a student's status
+//	@Description	Allowed transitions: active to graduated, withdrawn or suspended; suspended to active or withdrawn; withdrawn to active. Graduated is final. Enrollments, grades and attendance are kept.
+//	@Tags			students
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"Student ID (UUID)"
+//	@Param			request	body	dto.ChangeStudentStatusRequest	true	"New status"
+//	@Success		200	{object}	dto.Response{data=model.Student}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students/{id}/status [post]
+func (h *StudentHandler) ChangeStatus(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid student ID format in status change request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid student ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req dto.ChangeStudentStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Str("student_id", id.String()).
+			Msg("Failed to bind change student status request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("student_id", id.String()).
+			Msg("Change student status request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("student_id", id.String()).
+			Msg("Student status change attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Student status change requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	student, err := h.studentService.ChangeStatus(serviceCtx, tenantID, id, req)
+	if err != nil {
+		h.RespondError(c, "Failed to change student status", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Student status changed successfully",
+		Data:    student,
+	})
+}
+
+// AddGuardian handles linking a parent to a student as a guardian
+//
+//	@Summary		Add a guardian to a student
+//	@Description	Links a parent to the student, or updates the relationship and primary flag of an existing link. The primary guardian is the student's parent_id; marking a guardian primary replaces the previous one, and the first guardian is always primary. Returns the student's guardians.
+//	@Tags			students
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"Student ID (UUID)"
+//	@Param			request	body	dto.AddStudentGuardianRequest	true	"Guardian"
+//	@Success		200	{object}	dto.Response{data=[]model.StudentGuardian}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students/{id}/guardians [post]
+func (h *StudentHandler) AddGuardian(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid student ID format in add guardian request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid student ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req dto.AddStudentGuardianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Str("student_id", id.String()).
+			Msg("Failed to bind add student guardian request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("student_id", id.String()).
+			Msg("Add student guardian request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("student_id", id.String()).
+			Msg("Add student guardian attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Adding a guardian requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	guardians, err := h.studentService.AddGuardian(serviceCtx, tenantID, id, req)
+	if err != nil {
+		h.RespondError(c, "Failed to add guardian", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Guardian added successfully",
+		Data:    guardians,
+	})
+}
+
+// RemoveGuardian handles unlinking a parent from a student
+//
+//	@Summary		Remove a guardian from a student
+//	@Description	Unlinks the parent from the student. When the primary guardian is removed, the guardian linked first after it becomes primary, or the student's parent_id is cleared when none is left.
+//	@Tags			students
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"Student ID (UUID)"
+//	@Param			parent_id	path	string	true	"Parent ID (UUID)"
+//	@Success		200	{object}	dto.Response
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students/{id}/guardians/{parent_id} [delete]
+func (h *StudentHandler) RemoveGuardian(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid student ID format in remove guardian request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid student ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	parentIDStr := c.Param("parent_id")
+	parentID, err := uuid.Parse(parentIDStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("parent_id_param", parentIDStr).
+			Msg("Invalid parent ID format in remove guardian request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid parent ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("student_id", id.String()).
+			Msg("Remove student guardian attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Removing a guardian requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	if err := h.studentService.RemoveGuardian(serviceCtx, tenantID, id, parentID); err != nil {
+		h.RespondError(c, "Failed to remove guardian", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Guardian removed successfully",
+	})
+}
+
+// QRCode handles streaming a student's attendance QR code as a PNG image
+//
+//	@Summary		Get a student's attendance QR code
+//	@Description	Returns a PNG QR code encoding the tenant and student number, to be scanned with POST /attendance/scan.
+//	@Tags			students
+//	@Produce		png
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"Student ID (UUID)"
+//	@Success		200	{file}	binary
+//	@Success		304	"Not modified"
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/students/{id}/qr [get]
+func (h *StudentHandler) QRCode(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid student ID format in QR code request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid student ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("student_id", id.String()).
+			Msg("Student QR code request without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Getting a student QR code requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	student, image, err := h.studentService.QRCode(serviceCtx, tenantID, id)
+	if err != nil {
+		h.RespondError(c, "Failed to get student QR code", err)
+		return
+	}
+
+	// The image only changes with the student number, so it is revalidated against the student's last update
+	if h.RespondNotModified(c, student.ID, student.UpdatedAt) {
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="student-%s.png"`, student.ID))
+	c.Data(http.StatusOK, "image/png", image)
+}
+
+// studentVersions returns the times the JSON of a student depends on: its own last update, today's date
+// since the derived age changes without any write, and the last update of every embedded relation. A
+// missing relation counts as the zero time so the positions stay fixed, and each guardian adds its link
+// and its parent, so linking or unlinking one changes the list.
+func studentVersions(c context.Context, student *model.Student) []time.Time {
+	versions := []time.Time{student.UpdatedAt, util.TodayFromContext(c)}
+
+	var user, class, parent time.Time
+	if student.TenantUser != nil && student.TenantUser.User != nil {
+		user = student.TenantUser.User.UpdatedAt
+	}
+	if student.Class != nil {
+		class = student.Class.UpdatedAt
+	}
+	if student.Parent != nil {
+		parent = student.Parent.UpdatedAt
+	}
+	versions = append(versions, user, class, parent)
+
+	for _, guardian := range student.Guardians {
+		var guardianParent time.Time
+		if guardian.Parent != nil {
+			guardianParent = guardian.Parent.UpdatedAt
+		}
+		versions = append(versions, guardian.CreatedAt, guardian.UpdatedAt, guardianParent)
+	}
+	return versions
+}
+
+// respondStudentList writes a page of students limited to the requested fields
+func (h *StudentHandler) respondStudentList(c *gin.Context, students []model.Student, meta *dto.PaginationMeta, fields []string) {
+	data, err := util.SelectFields(students, fields)
+	if err != nil {
+		h.GetLogger(c).Error().
+			Err(err).
+			Strs("fields", fields).
+			Msg("Failed to select student fields")
+		h.RespondError(c, "Failed to retrieve students", err)
+		return
+	}
+
+	h.RespondPaginated(c, "Students retrieved successfully", data, *meta)
+}
+
+// parseStudentQueryParams reads pagination and the optional student list filters from the query string
+func (h *StudentHandler) parseStudentQueryParams(c *gin.Context) (dto.StudentQueryParams, error) {
+	params := dto.StudentQueryParams{
+		QueryParams:      util.ParsePaginationParams(c),
+		EnrollmentStatus: c.Query("enrollment_status"),
+		Status:           c.Query("status"),
+		Fields:           h.GetListQuery(c, "fields"),
+		Expand:           h.GetListQuery(c, "expand"),
+	}
+
+	if err := h.ParseChangeFilters(c, &params.QueryParams); err != nil {
+		return params, err
+	}
+
+	var err error
+	if params.ClassID, err = h.GetOptionalUUIDQuery(c, "class_id"); err != nil {
+		return params, fmt.Errorf("class_id must be a valid UUID")
+	}
+	if params.ParentID, err = h.GetOptionalUUIDQuery(c, "parent_id"); err != nil {
+		return params, fmt.Errorf("parent_id must be a valid UUID")
+	}
+	if params.EnrolledFrom, err = h.GetOptionalDateQuery(c, "enrolled_from"); err != nil {
+		return params, fmt.Errorf("enrolled_from must use the YYYY-MM-DD format")
+	}
+	if params.EnrolledTo, err = h.GetOptionalDateQuery(c, "enrolled_to"); err != nil {
+		return params, fmt.Errorf("enrolled_to must use the YYYY-MM-DD format")
+	}
+	if params.EnrolledFrom != nil && params.EnrolledTo != nil && params.EnrolledTo.Before(*params.EnrolledFrom) {
+		return params, fmt.Errorf("enrolled_to must not be before enrolled_from")
+	}
+	return params, nil
+}
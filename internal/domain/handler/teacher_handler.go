@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// TeacherHandler handles teacher related requests
+type TeacherHandler struct {
+	BaseHandler
+	teacherService service.TeacherService
+	validator      *validator.Validate
+}
+
+// NewTeacherHandler creates a new teacher handler
+func NewTeacherHandler(teacherService service.TeacherService, validator *validator.Validate, appCtx *util.AppContext) *TeacherHandler {
+	return &TeacherHandler{
+		BaseHandler:    NewBaseHandler(appCtx),
+		teacherService: teacherService,
+		validator:      validator,
+	}
+}
+
+// Schedule handles getting a teacher's weekly timetable with workload and conflicts
+func (h *TeacherHandler) Schedule(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid teacher ID format in schedule request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid teacher ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("teacher_id", id.String()).
+			Msg("Teacher schedule attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Getting a teacher schedule requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	schedule, err := h.teacherService.GetSchedule(serviceCtx, tenantID, id)
+	if err != nil {
+		h.RespondError(c, "Failed to get teacher schedule", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Teacher schedule retrieved successfully",
+		Data:    schedule,
+	})
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// TenantSettingHandler handles reading and updating the settings of the current tenant
+type TenantSettingHandler struct {
+	BaseHandler
+	tenantSettingService service.TenantSettingService
+	validator            *validator.Validate
+}
+
+// NewTenantSettingHandler creates a new tenant setting handler
+func NewTenantSettingHandler(tenantSettingService service.TenantSettingService, validator *validator.Validate, appCtx *util.AppContext) *TenantSettingHandler {
+	return &TenantSettingHandler{
+		BaseHandler:          NewBaseHandler(appCtx),
+		tenantSettingService: tenantSettingService,
+		validator:            validator,
+	}
+}
+
+// Get handles getting the settings of the current tenant
+//
+//	@Summary		Get the tenant settings
+//	@Description	Returns every known setting with the value in effect, and which of them the tenant has set
+//	@Tags			tenant
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Success		200	{object}	dto.Response{data=dto.TenantSettingsResponse}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/tenant/settings [get]
+func (h *TenantSettingHandler) Get(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Msg("Tenant settings request without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Getting the tenant settings requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	settings, err := h.tenantSettingService.Get(serviceCtx, tenantID)
+	if err != nil {
+		h.RespondError(c, "Failed to get tenant settings", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Tenant settings retrieved successfully",
+		Data:    settings,
+	})
+}
+
+// Update handles setting or resetting settings of the current tenant
+//
+//	@Summary		Update the tenant settings
+//	@Description	Sets the given settings; a null value resets a setting to its default. Unknown keys and values of the wrong type are rejected.
+//	@Tags			tenant
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			request	body	dto.UpdateTenantSettingsRequest	true	"Settings by key"
+//	@Success		200	{object}	dto.Response{data=dto.TenantSettingsResponse}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/tenant/settings [put]
+func (h *TenantSettingHandler) Update(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	var req dto.UpdateTenantSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to bind tenant settings request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Msg("Tenant settings request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Msg("Tenant settings update attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Updating the tenant settings requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	settings, err := h.tenantSettingService.Update(serviceCtx, tenantID, req)
+	if err != nil {
+		h.RespondError(c, "Failed to update tenant settings", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Tenant settings updated successfully",
+		Data:    settings,
+	})
+}
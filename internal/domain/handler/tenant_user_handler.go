@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// TenantUserHandler handles tenant membership related requests
+type TenantUserHandler struct {
+	BaseHandler
+	tenantUserService service.TenantUserService
+	validator         *validator.Validate
+}
+
+// NewTenantUserHandler creates a new tenant user handler
+func NewTenantUserHandler(tenantUserService service.TenantUserService, validator *validator.Validate, appCtx *util.AppContext) *TenantUserHandler {
+	return &TenantUserHandler{
+		BaseHandler:       NewBaseHandler(appCtx),
+		tenantUserService: tenantUserService,
+		validator:         validator,
+	}
+}
+
+// List handles listing the memberships of the current tenant
+func (h *TenantUserHandler) List(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	params := dto.TenantUserQueryParams{
+		QueryParams: util.ParsePaginationParams(c),
+	}
+
+	var err error
+	if params.RoleID, err = h.GetOptionalUUIDQuery(c, "role_id"); err == nil {
+		params.IsActive, err = h.GetOptionalBoolQuery(c, "is_active")
+	}
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Invalid filter in list tenant users request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid query parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Msg("List tenant users attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Listing tenant users requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	tenantUsers, meta, err := h.tenantUserService.List(serviceCtx, tenantID, params)
+	if err != nil {
+		h.RespondError(c, "Failed to retrieve tenant users", err)
+		return
+	}
+
+	h.RespondPaginated(c, "Tenant users retrieved successfully", tenantUsers, *meta)
+}
+
+// UpdateRoles handles assigning and revoking roles of a membership
+func (h *TenantUserHandler) UpdateRoles(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_user_id_param", idStr).
+			Msg("Invalid tenant user ID format in update roles request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid tenant user ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var req dto.UpdateTenantUserRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_user_id", id.String()).
+			Msg("Failed to bind update tenant user roles request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("tenant_user_id", id.String()).
+			Msg("Update tenant user roles request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("tenant_user_id", id.String()).
+			Msg("Update tenant user roles attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Updating tenant user roles requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	tenantUser, err := h.tenantUserService.UpdateRoles(serviceCtx, tenantID, id, req)
+	if err != nil {
+		h.RespondError(c, "Failed to update tenant user roles", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Tenant user roles updated successfully",
+		Data:    tenantUser,
+	})
+}
+
+// Activate handles reactivating a suspended membership
+func (h *TenantUserHandler) Activate(c *gin.Context) {
+	h.setActive(c, true)
+}
+
+// Deactivate handles suspending a membership without deleting the user
+func (h *TenantUserHandler) Deactivate(c *gin.Context) {
+	h.setActive(c, false)
+}
+
+// setActive flips the active flag of the membership in the path
+func (h *TenantUserHandler) setActive(c *gin.Context, active bool) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_user_id_param", idStr).
+			Bool("active", active).
+			Msg("Invalid tenant user ID format in activation request")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid tenant user ID format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Get tenant ID from middleware context
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == uuid.Nil {
+		logger.Error().
+			Str("tenant_user_id", id.String()).
+			Msg("Tenant user activation attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Changing tenant user activation requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	tenantUser, err := h.tenantUserService.SetActive(serviceCtx, tenantID, id, active)
+	if err != nil {
+		h.RespondError(c, "Failed to update tenant user", err)
+		return
+	}
+
+	message := "Tenant user deactivated successfully"
+	if active {
+		message = "Tenant user activated successfully"
+	}
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: message,
+		Data:    tenantUser,
+	})
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -28,6 +29,20 @@ func NewUserHandler(userService service.UserService, validator *validator.Valida
 }
 
 // Create handles user creation
+//
+//	@Summary		Create a user
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			request	body	dto.CreateUserRequest	true	"User details"
+//	@Success		201	{object}	dto.Response{data=model.User}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		409	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/users [post]
 func (h *UserHandler) Create(c *gin.Context) {
 	logger := h.GetLogger(c)
 
@@ -50,11 +65,7 @@ func (h *UserHandler) Create(c *gin.Context) {
 			Str("username", req.Username).
 			Str("email", req.Email).
 			Msg("Create user request validation failed")
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Validation failed",
-			Error:   err.Error(),
-		})
+		h.RespondValidationError(c, err)
 		return
 	}
 
@@ -75,11 +86,7 @@ func (h *UserHandler) Create(c *gin.Context) {
 	serviceCtx := h.CreateServiceContext(c)
 	user, err := h.userService.Create(serviceCtx, tenantID, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Failed to create user",
-			Error:   err.Error(),
-		})
+		h.RespondError(c, "Failed to create user", err)
 		return
 	}
 
@@ -91,6 +98,21 @@ func (h *UserHandler) Create(c *gin.Context) {
 }
 
 // GetByID handles getting user by ID
+//
+//	@Summary		Get a user
+//	@Tags			users
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"User ID (UUID)"
+//	@Param			If-None-Match	header	string	false	"ETag of a previously fetched user"
+//	@Success		200	{object}	dto.Response{data=model.User}
+//	@Success		304	"User unchanged since the given ETag"
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/users/{id} [get]
 func (h *UserHandler) GetByID(c *gin.Context) {
 	logger := h.GetLogger(c)
 
@@ -112,11 +134,11 @@ func (h *UserHandler) GetByID(c *gin.Context) {
 	serviceCtx := h.CreateServiceContext(c)
 	user, err := h.userService.GetByID(serviceCtx, id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, dto.Response{
-			Success: false,
-			Message: "User not found",
-			Error:   err.Error(),
-		})
+		h.RespondError(c, "User not found", err)
+		return
+	}
+
+	if h.RespondNotModified(c, user.ID, user.UpdatedAt) {
 		return
 	}
 
@@ -128,6 +150,22 @@ func (h *UserHandler) GetByID(c *gin.Context) {
 }
 
 // Update handles user update
+//
+//	@Summary		Update a user
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"User ID (UUID)"
+//	@Param			request	body	dto.UpdateUserRequest	true	"Fields to update"
+//	@Success		200	{object}	dto.Response{data=model.User}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Failure		409	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/users/{id} [put]
 func (h *UserHandler) Update(c *gin.Context) {
 	logger := h.GetLogger(c)
 
@@ -165,22 +203,14 @@ func (h *UserHandler) Update(c *gin.Context) {
 			Err(err).
 			Str("user_id", id.String()).
 			Msg("Update user request validation failed")
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Validation failed",
-			Error:   err.Error(),
-		})
+		h.RespondValidationError(c, err)
 		return
 	}
 
 	serviceCtx := h.CreateServiceContext(c)
 	user, err := h.userService.Update(serviceCtx, id, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Failed to update user",
-			Error:   err.Error(),
-		})
+		h.RespondError(c, "Failed to update user", err)
 		return
 	}
 
@@ -192,6 +222,19 @@ func (h *UserHandler) Update(c *gin.Context) {
 }
 
 // Delete handles user deletion
+//
+//	@Summary		Delete a user
+//	@Tags			users
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"User ID (UUID)"
+//	@Success		200	{object}	dto.Response
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/users/{id} [delete]
 func (h *UserHandler) Delete(c *gin.Context) {
 	logger := h.GetLogger(c)
 
@@ -213,11 +256,7 @@ func (h *UserHandler) Delete(c *gin.Context) {
 	serviceCtx := h.CreateServiceContext(c)
 	err = h.userService.Delete(serviceCtx, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Failed to delete user",
-			Error:   err.Error(),
-		})
+		h.RespondError(c, "Failed to delete user", err)
 		return
 	}
 
@@ -228,6 +267,19 @@ func (h *UserHandler) Delete(c *gin.Context) {
 }
 
 // BulkDelete handles bulk user deletion
+//
+//	@Summary		Delete several users
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			request	body	dto.BulkDeleteUserRequest	true	"User IDs"
+//	@Success		200	{object}	dto.Response
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/users [delete]
 func (h *UserHandler) BulkDelete(c *gin.Context) {
 	logger := h.GetLogger(c)
 
@@ -249,11 +301,11 @@ func (h *UserHandler) BulkDelete(c *gin.Context) {
 			Err(err).
 			Interface("user_ids", req.IDs).
 			Msg("Bulk delete user request validation failed")
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Validation failed",
-			Error:   err.Error(),
-		})
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	if !h.ValidateBulkSize(c, len(req.IDs)) {
 		return
 	}
 
@@ -274,29 +326,132 @@ func (h *UserHandler) BulkDelete(c *gin.Context) {
 	serviceCtx := h.CreateServiceContext(c)
 	err := h.userService.BulkDelete(serviceCtx, tenantID, req.IDs)
 	if err != nil {
+		h.RespondError(c, "Failed to bulk delete users", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Success: true,
+		Message: "Users bulk deleted successfully",
+	})
+}
+
+// ResetPassword handles an admin setting a temporary password for a user
+//
+//	@Summary		Reset a user's password
+//	@Description	Sets the given or a generated temporary password and requires the user to change it on their next login
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			id	path	string	true	"User ID (UUID)"
+//	@Param			request	body	dto.ResetUserPasswordRequest	true	"Temporary password, generated when omitted"
+//	@Success		200	{object}	dto.Response{data=dto.ResetUserPasswordResponse}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		404	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/users/{id}/reset-password [post]
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	logger := h.GetLogger(c)
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("id_param", idStr).
+			Msg("Invalid user ID format in reset password request")
 		c.JSON(http.StatusBadRequest, dto.Response{
 			Success: false,
-			Message: "Failed to bulk delete users",
+			Message: "Invalid user ID format",
 			Error:   err.Error(),
 		})
 		return
 	}
 
+	var req dto.ResetUserPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", id.String()).
+			Msg("Failed to bind reset password request JSON")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("user_id", id.String()).
+			Msg("Reset password request validation failed")
+		h.RespondValidationError(c, err)
+		return
+	}
+
+	// Get tenant ID from helper method
+	tenantID, exists := h.GetTenantIDAsUUID(c)
+	if !exists {
+		logger.Error().
+			Str("user_id", id.String()).
+			Msg("Reset password attempt without valid tenant ID")
+		c.JSON(http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Tenant ID required",
+			Error:   "Password reset requires a valid tenant context",
+		})
+		return
+	}
+
+	serviceCtx := h.CreateServiceContext(c)
+	result, err := h.userService.ResetPassword(serviceCtx, tenantID, id, req)
+	if err != nil {
+		h.RespondError(c, "Failed to reset password", err)
+		return
+	}
+
 	c.JSON(http.StatusOK, dto.Response{
 		Success: true,
-		Message: "Users bulk deleted successfully",
+		Message: "Password reset successfully",
+		Data:    result,
 	})
 }
 
 // List handles user listing with pagination
+//
+//	@Summary		List users
+//	@Tags			users
+//	@Produce		json
+//	@Param			X-Tenant-ID	header		string	false	"Tenant ID, defaults to the tenant selected in the token"
+//	@Param			page	query	int	false	"Page number"
+//	@Param			limit	query	int	false	"Page size (max 100)"
+//	@Param			search	query	string	false	"Search term"
+//	@Param			sort_by	query	string	false	"Sort field" Enums(username, full_name, email, created_at, updated_at, is_active)
+//	@Param			sort_dir	query	string	false	"Sort direction" Enums(asc, desc)
+//	@Param			created_after	query	string	false	"Only users created after this RFC3339 timestamp"
+//	@Param			updated_after	query	string	false	"Only users updated after this RFC3339 timestamp"
+//	@Param			role_id	query	string	false	"Filter by role ID"
+//	@Param			is_active	query	bool	false	"Filter by active status; false returns users whose account or membership is deactivated"
+//	@Success		200	{object}	dto.PaginatedResponse{data=[]model.User}
+//	@Failure		400	{object}	dto.Response
+//	@Failure		401	{object}	dto.Response
+//	@Failure		403	{object}	dto.Response
+//	@Failure		500	{object}	dto.Response
+//	@Security		BearerAuth
+//	@Router			/users [get]
 func (h *UserHandler) List(c *gin.Context) {
 	logger := h.GetLogger(c)
 
-	var params dto.UserQueryParams
-	if err := c.ShouldBindQuery(&params); err != nil {
+	params, err := h.parseUserQueryParams(c)
+	if err != nil {
 		logger.Error().
 			Err(err).
-			Msg("Failed to bind user list query parameters")
+			Msg("Failed to parse user list query parameters")
 		c.JSON(http.StatusBadRequest, dto.Response{
 			Success: false,
 			Message: "Invalid query parameters",
@@ -310,11 +465,7 @@ func (h *UserHandler) List(c *gin.Context) {
 			Err(err).
 			Interface("params", params).
 			Msg("User list query parameters validation failed")
-		c.JSON(http.StatusBadRequest, dto.Response{
-			Success: false,
-			Message: "Validation failed",
-			Error:   err.Error(),
-		})
+		h.RespondValidationError(c, err)
 		return
 	}
 
@@ -334,18 +485,28 @@ func (h *UserHandler) List(c *gin.Context) {
 	serviceCtx := h.CreateServiceContext(c)
 	users, meta, err := h.userService.List(serviceCtx, tenantID, params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Response{
-			Success: false,
-			Message: "Failed to retrieve users",
-			Error:   err.Error(),
-		})
+		h.RespondError(c, "Failed to retrieve users", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.PaginatedResponse{
-		Success: true,
-		Message: "Users retrieved successfully",
-		Data:    users,
-		Meta:    *meta,
-	})
+	h.RespondPaginated(c, "Users retrieved successfully", users, *meta)
+}
+
+// parseUserQueryParams reads pagination and the optional user list filters from the query string
+func (h *UserHandler) parseUserQueryParams(c *gin.Context) (dto.UserQueryParams, error) {
+	params := dto.UserQueryParams{
+		QueryParams: util.ParsePaginationParams(c),
+	}
+	if err := h.ParseChangeFilters(c, &params.QueryParams); err != nil {
+		return params, err
+	}
+
+	var err error
+	if params.RoleID, err = h.GetOptionalUUIDQuery(c, "role_id"); err != nil {
+		return params, fmt.Errorf("role_id must be a valid UUID")
+	}
+	if params.IsActive, err = h.GetOptionalBoolQuery(c, "is_active"); err != nil {
+		return params, fmt.Errorf("is_active must be a boolean")
+	}
+	return params, nil
 }
@@ -21,6 +21,15 @@ type AcademicYear struct {
 	StudentFees []StudentFee `gorm:"foreignKey:AcademicYearID;constraint:OnDelete:CASCADE" json:"student_fees,omitempty"`
 }
 
+// SemesterRange returns the dates of a semester, splitting the year into two halves of equal length
+func (a AcademicYear) SemesterRange(semester int) (time.Time, time.Time) {
+	middle := a.StartDate.AddDate(0, 0, int(a.EndDate.Sub(a.StartDate).Hours()/24/2))
+	if semester == 1 {
+		return a.StartDate, middle
+	}
+	return middle.AddDate(0, 0, 1), a.EndDate
+}
+
 // TableName returns the table name for AcademicYear
 func (AcademicYear) TableName() string {
 	return "academic_years"
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoleService is the role of requests authenticated with an API key instead of a user token
+const RoleService = "Service"
+
+// APIKeyScopes are the permissions an API key may be granted
+var APIKeyScopes = []string{PermissionStudentsRead, PermissionStudentsWrite, PermissionStudentsManage}
+
+// APIKey represents the api_keys table, the key of a server-to-server integration within a tenant
+type APIKey struct {
+	BaseModel
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	KeyPrefix  string     `gorm:"size:20;not null" json:"key_prefix"`
+	KeyHash    string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Scopes     []string   `gorm:"type:jsonb;serializer:json;not null" json:"scopes"`
+	CreatedBy  *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName returns the table name for APIKey
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// HasScope reports whether the key is granted the permission
+func (k *APIKey) HasScope(permission string) bool {
+	for _, scope := range k.Scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -22,7 +22,7 @@ type Attendance struct {
 	TenantID       uuid.UUID        `gorm:"type:uuid;not null;index" json:"tenant_id"`
 	StudentID      *uuid.UUID       `gorm:"type:uuid;index" json:"student_id,omitempty"`
 	ScheduleID     *uuid.UUID       `gorm:"type:uuid;index" json:"schedule_id,omitempty"`
-	Status         AttendanceStatus `gorm:"type:attendance_status_enum;default:'Present'" json:"status"`
+	Status         AttendanceStatus `gorm:"type:attendance_status_enum;default:'present'" json:"status"`
 	AttendanceDate time.Time        `gorm:"type:date;default:CURRENT_DATE" json:"attendance_date"`
 	Remarks        *string          `gorm:"type:text" json:"remarks,omitempty"`
 
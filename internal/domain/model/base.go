@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -24,6 +26,22 @@ const (
 	DaySunday    DayOfWeek = "minggu"
 )
 
+// weekdays maps Go weekdays to the day_of_week_enum values
+var weekdays = map[time.Weekday]DayOfWeek{
+	time.Monday:    DayMonday,
+	time.Tuesday:   DayTuesday,
+	time.Wednesday: DayWednesday,
+	time.Thursday:  DayThursday,
+	time.Friday:    DayFriday,
+	time.Saturday:  DaySaturday,
+	time.Sunday:    DaySunday,
+}
+
+// DayOfWeekFor returns the day of week of the calendar date of t
+func DayOfWeekFor(t time.Time) DayOfWeek {
+	return weekdays[t.Weekday()]
+}
+
 // BaseModel contains common fields for all models with tenant support
 type BaseModel struct {
 	ID       uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
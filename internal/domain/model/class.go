@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -12,6 +14,7 @@ type Class struct {
 	GradeLevel        *int       `json:"grade_level,omitempty"`
 	HomeroomTeacherID *uuid.UUID `gorm:"type:uuid;index" json:"homeroom_teacher_id,omitempty"`
 	AcademicYearID    *uuid.UUID `gorm:"type:uuid;index" json:"academic_year_id,omitempty"`
+	UpdatedAt         time.Time  `json:"updated_at"`
 
 	// Relationships
 	HomeroomTeacher *Teacher       `gorm:"foreignKey:HomeroomTeacherID;constraint:OnDelete:SET NULL" json:"homeroom_teacher,omitempty"`
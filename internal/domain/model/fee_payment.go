@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeePayment represents the fee_payments table, one installment paid toward a student fee
+type FeePayment struct {
+	BaseModel
+	StudentFeeID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"student_fee_id"`
+	Amount        float64    `gorm:"type:decimal(10,2);not null;check:amount > 0" json:"amount"`
+	PaymentDate   time.Time  `gorm:"type:date;not null" json:"payment_date"`
+	PaymentMethod string     `gorm:"size:50;not null" json:"payment_method"`
+	ReceiptNumber *string    `gorm:"size:50" json:"receipt_number,omitempty"`
+	Notes         *string    `gorm:"type:text" json:"notes,omitempty"`
+	RecordedBy    *uuid.UUID `gorm:"type:uuid" json:"recorded_by,omitempty"`
+	CreatedAt     time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName returns the table name for FeePayment
+func (FeePayment) TableName() string {
+	return "fee_payments"
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeeReceipt represents the fee_receipts table. It copies the details of a paid fee when the receipt is
+// issued and is never updated afterwards.
+type FeeReceipt struct {
+	BaseModel
+	StudentFeeID     uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"student_fee_id"`
+	ReceiptNumber    string     `gorm:"size:50;not null" json:"receipt_number"`
+	SchoolName       string     `gorm:"size:255;not null" json:"school_name"`
+	SchoolAddress    *string    `gorm:"type:text" json:"school_address,omitempty"`
+	SchoolPhone      *string    `gorm:"size:50" json:"school_phone,omitempty"`
+	StudentID        *uuid.UUID `gorm:"type:uuid" json:"student_id,omitempty"`
+	StudentNumber    string     `gorm:"size:50;not null" json:"student_number"`
+	StudentName      string     `gorm:"size:255;not null" json:"student_name"`
+	FeeTypeName      *string    `gorm:"size:100" json:"fee_type_name,omitempty"`
+	AcademicYearName *string    `gorm:"size:50" json:"academic_year_name,omitempty"`
+	Amount           float64    `gorm:"type:decimal(10,2);not null" json:"amount"`
+	PenaltyAmount    float64    `gorm:"type:decimal(10,2);not null;default:0" json:"penalty_amount"`
+	TotalPaid        float64    `gorm:"type:decimal(10,2);not null" json:"total_paid"`
+	PaymentMethod    *string    `gorm:"size:50" json:"payment_method,omitempty"`
+	PaymentDate      time.Time  `gorm:"type:date;not null" json:"payment_date"`
+	IssuedAt         time.Time  `gorm:"not null" json:"issued_at"`
+	IssuedBy         *uuid.UUID `gorm:"type:uuid" json:"issued_by,omitempty"`
+}
+
+// TableName returns the table name for FeeReceipt
+func (FeeReceipt) TableName() string {
+	return "fee_receipts"
+}
@@ -1,9 +1,20 @@
 package model
 
 import (
+	"math"
+	"time"
+
 	"github.com/google/uuid"
 )
 
+// FeePenaltyType is how a fee type charges for late payment
+type FeePenaltyType string
+
+const (
+	FeePenaltyFlat       FeePenaltyType = "flat"
+	FeePenaltyPercentage FeePenaltyType = "percentage"
+)
+
 // FeeType represents the fee_types table
 type FeeType struct {
 	BaseModel
@@ -13,11 +24,30 @@ type FeeType struct {
 	DefaultAmount *float64  `gorm:"type:decimal(10,2);default:0;check:default_amount >= 0" json:"default_amount,omitempty"`
 	IsMandatory   bool      `gorm:"default:true" json:"is_mandatory"`
 	IsActive      bool      `gorm:"default:true" json:"is_active"`
+	// PenaltyType is unset when late payment is not penalized; PenaltyValue is an amount or a percentage of the fee
+	PenaltyType  *FeePenaltyType `gorm:"size:20" json:"penalty_type,omitempty"`
+	PenaltyValue float64         `gorm:"type:decimal(10,2);not null;default:0" json:"penalty_value"`
 
 	// Relationships
 	StudentFees []StudentFee `gorm:"foreignKey:FeeTypeID;constraint:OnDelete:CASCADE" json:"student_fees,omitempty"`
 }
 
+// PenaltyFor returns the late penalty a fee of this type owes today: nothing until the due date has passed or
+// once the fee is paid, then the flat amount or percentage of the fee amount, rounded to cents.
+// The fee summaries compute the same in SQL.
+func (f FeeType) PenaltyFor(fee StudentFee, today time.Time) float64 {
+	if f.PenaltyType == nil || fee.Status == FeeStatusPaid || !fee.DueDate.Before(today) {
+		return 0
+	}
+	switch *f.PenaltyType {
+	case FeePenaltyFlat:
+		return f.PenaltyValue
+	case FeePenaltyPercentage:
+		return math.Round(fee.Amount*f.PenaltyValue) / 100
+	}
+	return 0
+}
+
 // TableName returns the table name for FeeType
 func (FeeType) TableName() string {
 	return "fee_types"
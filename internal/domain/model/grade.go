@@ -11,6 +11,7 @@ type Grade struct {
 	EnrollmentID *uuid.UUID `gorm:"type:uuid;index" json:"enrollment_id,omitempty"`
 	GradeType    string     `gorm:"size:50;check:grade_type IN ('Assignment','Midterm','Final','Other')" json:"grade_type"`
 	Score        *float64   `gorm:"type:decimal(5,2)" json:"score,omitempty"`
+	Semester     *int       `gorm:"type:smallint" json:"semester,omitempty"` // 1 or 2, nil when the grade counts for the whole year
 	Remarks      *string    `gorm:"type:text" json:"remarks,omitempty"`
 
 	// Relationships
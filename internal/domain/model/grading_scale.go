@@ -0,0 +1,145 @@
+package model
+
+import (
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// Grade types recorded in grades.grade_type
+const (
+	GradeTypeAssignment = "Assignment"
+	GradeTypeMidterm    = "Midterm"
+	GradeTypeFinal      = "Final"
+	GradeTypeOther      = "Other"
+)
+
+// GradeTypes lists the grade types in the order they are reported
+var GradeTypes = []string{GradeTypeAssignment, GradeTypeMidterm, GradeTypeFinal, GradeTypeOther}
+
+// GradingScaleBand represents the grading_scale_bands table
+type GradingScaleBand struct {
+	TenantID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	Letter     string    `gorm:"size:5;primaryKey" json:"letter"`
+	MinScore   float64   `gorm:"type:decimal(5,2);not null" json:"min_score"`
+	GradePoint *float64  `gorm:"type:decimal(4,2)" json:"grade_point,omitempty"` // nil counts the bands below it
+}
+
+// TableName returns the table name for GradingScaleBand
+func (GradingScaleBand) TableName() string {
+	return "grading_scale_bands"
+}
+
+// GradeTypeWeight represents the grade_type_weights table
+type GradeTypeWeight struct {
+	TenantID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	GradeType string    `gorm:"size:50;primaryKey" json:"grade_type"`
+	Weight    float64   `gorm:"type:decimal(5,2);not null" json:"weight"`
+}
+
+// TableName returns the table name for GradeTypeWeight
+func (GradeTypeWeight) TableName() string {
+	return "grade_type_weights"
+}
+
+// DefaultGradingScaleBands is the scale of tenants that have not configured one
+var DefaultGradingScaleBands = []GradingScaleBand{
+	{Letter: "A", MinScore: 85, GradePoint: gradePoint(4)},
+	{Letter: "B", MinScore: 70, GradePoint: gradePoint(3)},
+	{Letter: "C", MinScore: 55, GradePoint: gradePoint(2)},
+	{Letter: "D", MinScore: 40, GradePoint: gradePoint(1)},
+	{Letter: "E", MinScore: 0, GradePoint: gradePoint(0)},
+}
+
+// DefaultGradeTypeWeights are the final score weights of tenants that have not configured them
+var DefaultGradeTypeWeights = []GradeTypeWeight{
+	{GradeType: GradeTypeAssignment, Weight: 30},
+	{GradeType: GradeTypeMidterm, Weight: 30},
+	{GradeType: GradeTypeFinal, Weight: 40},
+	{GradeType: GradeTypeOther, Weight: 0},
+}
+
+// GradingScale is a tenant's letter grade bands, ordered by descending minimum score, and grade type weights
+type GradingScale struct {
+	Bands          []GradingScaleBand
+	Weights        []GradeTypeWeight
+	DefaultBands   bool
+	DefaultWeights bool
+}
+
+// NewGradingScale builds a scale from configured bands and weights, using the defaults for missing ones
+func NewGradingScale(bands []GradingScaleBand, weights []GradeTypeWeight) *GradingScale {
+	scale := &GradingScale{Bands: bands, Weights: weights}
+	if len(scale.Bands) == 0 {
+		scale.Bands, scale.DefaultBands = DefaultGradingScaleBands, true
+	}
+	if len(scale.Weights) == 0 {
+		scale.Weights, scale.DefaultWeights = DefaultGradeTypeWeights, true
+	}
+	sort.SliceStable(scale.Bands, func(i, j int) bool {
+		return scale.Bands[i].MinScore > scale.Bands[j].MinScore
+	})
+	return scale
+}
+
+// LetterFor returns the letter of the highest band the score reaches, or nil when it is below every band
+func (s *GradingScale) LetterFor(score *float64) *string {
+	if score == nil {
+		return nil
+	}
+	for _, band := range s.Bands {
+		if *score >= band.MinScore {
+			letter := band.Letter
+			return &letter
+		}
+	}
+	return nil
+}
+
+// GradePointFor returns the grade point of the highest band the score reaches, or nil when it is below every band
+func (s *GradingScale) GradePointFor(score *float64) *float64 {
+	if score == nil {
+		return nil
+	}
+	for i := range s.Bands {
+		if *score >= s.Bands[i].MinScore {
+			point := s.BandGradePoint(i)
+			return &point
+		}
+	}
+	return nil
+}
+
+// BandGradePoint returns the grade point of the i-th band. A band without one counts as many points as there are
+// bands below it.
+func (s *GradingScale) BandGradePoint(i int) float64 {
+	if point := s.Bands[i].GradePoint; point != nil {
+		return *point
+	}
+	return float64(len(s.Bands) - 1 - i)
+}
+
+// WeightedScore combines average scores per grade type into a final score. Only grade types with scores
+// count, so a student without a final exam yet is scored on the weights of the other types.
+func (s *GradingScale) WeightedScore(averages map[string]float64) *float64 {
+	var total, weights float64
+	for _, weight := range s.Weights {
+		average, ok := averages[weight.GradeType]
+		if !ok || weight.Weight <= 0 {
+			continue
+		}
+		total += average * weight.Weight
+		weights += weight.Weight
+	}
+	if weights == 0 {
+		return nil
+	}
+	score := math.Round(total/weights*100) / 100
+	return &score
+}
+
+// gradePoint returns a pointer to a grade point
+func gradePoint(point float64) *float64 {
+	return &point
+}
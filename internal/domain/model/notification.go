@@ -1,17 +1,52 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
+// Notification types, each with default delivery channels
+const (
+	NotificationTypeGeneral    = "general"
+	NotificationTypeAttendance = "attendance"
+	NotificationTypeGrade      = "grade"
+	NotificationTypeFee        = "fee"
+)
+
+// NotificationChannels are the channels a notification is delivered through besides the in-app list
+type NotificationChannels struct {
+	Email bool `json:"email"`
+	SMS   bool `json:"sms"`
+}
+
+// DefaultNotificationChannels are used for types a user has no preference for. SMS is reserved for
+// what parents need to know the same day, since every message costs money.
+var DefaultNotificationChannels = map[string]NotificationChannels{
+	NotificationTypeGeneral:    {Email: true},
+	NotificationTypeAttendance: {Email: true, SMS: true},
+	NotificationTypeGrade:      {Email: true},
+	NotificationTypeFee:        {Email: true, SMS: true},
+}
+
+// NotificationTypes lists the notification types in the order they are reported
+var NotificationTypes = []string{
+	NotificationTypeGeneral,
+	NotificationTypeAttendance,
+	NotificationTypeGrade,
+	NotificationTypeFee,
+}
+
 // Notification represents the notifications table
 type Notification struct {
 	BaseModel
-	TenantID uuid.UUID  `gorm:"type:uuid;not null;index" json:"tenant_id"`
-	UserID   *uuid.UUID `gorm:"type:uuid;index" json:"user_id,omitempty"`
-	Title    string     `gorm:"size:100" json:"title"`
-	Message  string     `gorm:"type:text" json:"message"`
-	IsRead   bool       `gorm:"default:false" json:"is_read"`
+	TenantID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"tenant_id"`
+	UserID    *uuid.UUID `gorm:"type:uuid;index" json:"user_id,omitempty"`
+	Type      string     `gorm:"size:50;not null;default:'general'" json:"type"`
+	Title     string     `gorm:"size:100" json:"title"`
+	Message   string     `gorm:"type:text" json:"message"`
+	IsRead    bool       `gorm:"default:false" json:"is_read"`
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
 
 	// Relationships
 	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
@@ -21,3 +56,17 @@ type Notification struct {
 func (Notification) TableName() string {
 	return "notifications"
 }
+
+// NotificationPreference represents the notification_preferences table
+type NotificationPreference struct {
+	UserID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	NotificationType string    `gorm:"size:50;primaryKey" json:"notification_type"`
+	Email            bool      `gorm:"not null" json:"email"`
+	SMS              bool      `gorm:"column:sms;not null" json:"sms"`
+	UpdatedAt        time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName returns the table name for NotificationPreference
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
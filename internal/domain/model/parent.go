@@ -15,6 +15,7 @@ type Parent struct {
 	Birthplace   *string    `gorm:"size:100" json:"birthplace,omitempty"`
 	Birthday     *time.Time `gorm:"type:date" json:"birthday,omitempty"`
 	Gender       *Gender    `gorm:"type:gender_enum" json:"gender,omitempty"`
+	UpdatedAt    time.Time  `json:"updated_at"`
 
 	// Relationships
 	Students []Student `gorm:"foreignKey:ParentID;constraint:OnDelete:SET NULL" json:"students,omitempty"`
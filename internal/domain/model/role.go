@@ -1,15 +1,10 @@
 package model
 
-import (
-	"github.com/google/uuid"
-)
-
-// Role represents the roles table
+// Role represents the roles table. Roles are global, every tenant grants the same ones to its members.
 type Role struct {
-	BaseModel
-	TenantID    uuid.UUID `gorm:"type:uuid;not null;index" json:"tenant_id"`
-	Name        string    `gorm:"size:50;not null" json:"name"`
-	Description *string   `gorm:"type:text" json:"description,omitempty"`
+	GlobalBaseModel
+	Name        string  `gorm:"size:50;not null;uniqueIndex" json:"name"`
+	Description *string `gorm:"type:text" json:"description,omitempty"`
 
 	// Relationships
 	TenantUserRoles []TenantUserRole `gorm:"foreignKey:RoleID;constraint:OnDelete:CASCADE" json:"tenant_user_roles,omitempty"`
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permissions checked by the routes
+const (
+	PermissionStudentsRead   = "students:read"
+	PermissionStudentsWrite  = "students:write"
+	PermissionStudentsManage = "students:manage"
+)
+
+// RoleFallbackPermissions are granted by role name regardless of role_permissions, keeping the access the
+// routes gave these roles before permissions existed. It will be removed once tenants manage their grants.
+var RoleFallbackPermissions = map[string][]string{
+	"Admin":     {PermissionStudentsRead, PermissionStudentsWrite, PermissionStudentsManage},
+	"Developer": {PermissionStudentsRead, PermissionStudentsWrite, PermissionStudentsManage},
+	"Teacher":   {PermissionStudentsRead, PermissionStudentsWrite},
+}
+
+// HasFallbackPermission reports whether the role name is granted the permission by RoleFallbackPermissions
+func HasFallbackPermission(role, permission string) bool {
+	for name, permissions := range RoleFallbackPermissions {
+		if !strings.EqualFold(name, role) {
+			continue
+		}
+		for _, granted := range permissions {
+			if granted == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RolePermission represents the role_permissions table, granting a permission to a role within a tenant
+type RolePermission struct {
+	TenantID   uuid.UUID `gorm:"type:uuid;not null;primaryKey" json:"tenant_id"`
+	RoleID     uuid.UUID `gorm:"type:uuid;not null;primaryKey" json:"role_id"`
+	Permission string    `gorm:"size:100;not null;primaryKey" json:"permission"`
+	CreatedAt  time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+
+	// Relationships
+	Role *Role `gorm:"foreignKey:RoleID;constraint:OnDelete:CASCADE" json:"role,omitempty"`
+}
+
+// TableName returns the table name for RolePermission
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
@@ -0,0 +1,15 @@
+package model
+
+import "github.com/google/uuid"
+
+// Sequence represents the sequences table, a named counter of a tenant for generated identifiers
+type Sequence struct {
+	TenantID     uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Name         string    `gorm:"size:100;primaryKey"`
+	CurrentValue int64     `gorm:"not null;default:0"`
+}
+
+// TableName returns the table name for Sequence
+func (Sequence) TableName() string {
+	return "sequences"
+}
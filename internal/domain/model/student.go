@@ -6,23 +6,41 @@ import (
 	"github.com/google/uuid"
 )
 
+// StudentStatus represents the student_status_enum lifecycle of a student
+type StudentStatus string
+
+const (
+	StudentActive    StudentStatus = "active"
+	StudentGraduated StudentStatus = "graduated"
+	StudentWithdrawn StudentStatus = "withdrawn"
+	StudentSuspended StudentStatus = "suspended"
+)
+
 // Student represents the students table
 type Student struct {
 	BaseModel
-	TenantID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"tenant_id"`
-	TenantUserID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"tenant_user_id"`
-	StudentNumber string     `gorm:"size:50;not null" json:"student_number"`
-	AdmissionDate time.Time  `gorm:"type:date;not null" json:"admission_date"`
-	ClassID       *uuid.UUID `gorm:"type:uuid;index" json:"class_id,omitempty"`
-	ParentID      *uuid.UUID `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+	TenantID        uuid.UUID     `gorm:"type:uuid;not null;index" json:"tenant_id"`
+	TenantUserID    uuid.UUID     `gorm:"type:uuid;not null;index" json:"tenant_user_id"`
+	StudentNumber   string        `gorm:"size:50;not null" json:"student_number"`
+	AdmissionDate   time.Time     `gorm:"type:date;not null" json:"admission_date"`
+	ClassID         *uuid.UUID    `gorm:"type:uuid;index" json:"class_id,omitempty"`
+	ParentID        *uuid.UUID    `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+	Status          StudentStatus `gorm:"type:student_status_enum;not null;default:active" json:"status"`
+	StatusChangedAt *time.Time    `json:"status_changed_at,omitempty"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+
+	// Age is derived from the user's date of birth when the student is loaded
+	Age *int `gorm:"-" json:"age,omitempty"`
 
 	// Relationships
-	TenantUser  *TenantUser  `gorm:"foreignKey:TenantUserID;constraint:OnDelete:CASCADE" json:"tenant_user,omitempty"`
-	Class       *Class       `gorm:"foreignKey:ClassID;constraint:OnDelete:SET NULL" json:"class,omitempty"`
-	Parent      *Parent      `gorm:"foreignKey:ParentID;constraint:OnDelete:SET NULL" json:"parent,omitempty"`
-	Enrollments []Enrollment `gorm:"foreignKey:StudentID;constraint:OnDelete:CASCADE" json:"enrollments,omitempty"`
-	Attendance  []Attendance `gorm:"foreignKey:StudentID;constraint:OnDelete:CASCADE" json:"attendance,omitempty"`
-	StudentFees []StudentFee `gorm:"foreignKey:StudentID;constraint:OnDelete:CASCADE" json:"student_fees,omitempty"`
+	TenantUser  *TenantUser       `gorm:"foreignKey:TenantUserID;constraint:OnDelete:CASCADE" json:"tenant_user,omitempty"`
+	Class       *Class            `gorm:"foreignKey:ClassID;constraint:OnDelete:SET NULL" json:"class,omitempty"`
+	Parent      *Parent           `gorm:"foreignKey:ParentID;constraint:OnDelete:SET NULL" json:"parent,omitempty"`
+	Guardians   []StudentGuardian `gorm:"foreignKey:StudentID;constraint:OnDelete:CASCADE" json:"guardians,omitempty"`
+	Enrollments []Enrollment      `gorm:"foreignKey:StudentID;constraint:OnDelete:CASCADE" json:"enrollments,omitempty"`
+	Attendance  []Attendance      `gorm:"foreignKey:StudentID;constraint:OnDelete:CASCADE" json:"attendance,omitempty"`
+	StudentFees []StudentFee      `gorm:"foreignKey:StudentID;constraint:OnDelete:CASCADE" json:"student_fees,omitempty"`
 }
 
 // TableName returns the table name for Student
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StudentGuardian represents the student_guardians table, a parent or guardian linked to a student.
+// The primary guardian is mirrored in Student.ParentID.
+type StudentGuardian struct {
+	TenantID     uuid.UUID `gorm:"type:uuid;not null;index" json:"-"`
+	StudentID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"student_id"`
+	ParentID     uuid.UUID `gorm:"type:uuid;primaryKey" json:"parent_id"`
+	Relationship *string   `gorm:"size:50" json:"relationship,omitempty"`
+	IsPrimary    bool      `gorm:"not null;default:false" json:"is_primary"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Relationships
+	Parent *Parent `gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE" json:"parent,omitempty"`
+}
+
+// TableName returns the table name for StudentGuardian
+func (StudentGuardian) TableName() string {
+	return "student_guardians"
+}
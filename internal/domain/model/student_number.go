@@ -0,0 +1,47 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StudentNumberFormat represents the student_number_formats table
+type StudentNumberFormat struct {
+	TenantID            uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	Prefix              string    `gorm:"size:20;not null;default:''" json:"prefix"`
+	IncludeAcademicYear bool      `gorm:"not null;default:true" json:"include_academic_year"`
+	SequenceDigits      int       `gorm:"not null;default:4" json:"sequence_digits"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for StudentNumberFormat
+func (StudentNumberFormat) TableName() string {
+	return "student_number_formats"
+}
+
+// DefaultStudentNumberFormat is the format of tenants that have not configured one, e.g. 20250001
+var DefaultStudentNumberFormat = StudentNumberFormat{IncludeAcademicYear: true, SequenceDigits: 4}
+
+// Period returns the academic year part of numbers generated in the year, empty when the format has none
+func (f StudentNumberFormat) Period(year int) string {
+	if !f.IncludeAcademicYear {
+		return ""
+	}
+	return strconv.Itoa(year)
+}
+
+// SequenceName returns the name of the sequence numbering the students of the period
+func (f StudentNumberFormat) SequenceName(period string) string {
+	if period == "" {
+		return "student_number"
+	}
+	return "student_number:" + period
+}
+
+// Format returns the student number with the sequence value of the period
+func (f StudentNumberFormat) Format(period string, sequence int64) string {
+	return fmt.Sprintf("%s%s%0*d", f.Prefix, period, f.SequenceDigits, sequence)
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Known tenant setting keys
+const (
+	TenantSettingSelfRegistration = "self_registration"            // Whether users may join the tenant by naming it at registration
+	TenantSettingDefaultRole      = "default_role"                 // Role name given to users registering into the tenant
+	TenantSettingTimezone         = "timezone"                     // IANA time zone of the school, e.g. Asia/Jakarta
+	TenantSettingAutoAbsent       = "attendance_auto_absent"       // Whether students without attendance are marked absent at the end of the day
+	TenantSettingAutoAbsentAfter  = "attendance_auto_absent_after" // Local time of day, "HH:MM", from which the day's attendance is closed
+	TenantSettingStudentMinAge    = "student_min_age"              // Youngest expected age in grade level 1, a year older per grade level
+	TenantSettingStudentMaxAge    = "student_max_age"              // Oldest expected age in grade level 1, a year older per grade level
+)
+
+// TenantSetting represents the tenant_settings table, one configured value of a tenant
+type TenantSetting struct {
+	TenantID  uuid.UUID       `gorm:"type:uuid;primaryKey" json:"-"`
+	Key       string          `gorm:"size:100;primaryKey" json:"key"`
+	Value     json.RawMessage `gorm:"type:jsonb;serializer:json;not null" json:"value"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// TableName returns the table name for TenantSetting
+func (TenantSetting) TableName() string {
+	return "tenant_settings"
+}
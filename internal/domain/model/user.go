@@ -6,19 +6,26 @@ import (
 
 // User represents the users table
 type User struct {
-	GlobalBaseModel            // Users table doesn't have tenant_id since it's a global table
-	Username        string     `gorm:"size:50;uniqueIndex;not null" json:"username"`
-	PasswordHash    string     `gorm:"size:255;not null" json:"-"`
-	Email           string     `gorm:"size:100;uniqueIndex" json:"email"`
-	FullName        string     `gorm:"size:100;not null" json:"full_name"`
-	Birthplace      *string    `gorm:"size:100" json:"birthplace,omitempty"`
-	Birthday        *time.Time `gorm:"type:date" json:"birthday,omitempty"`
-	Gender          *Gender    `gorm:"type:gender_enum" json:"gender,omitempty"`
-	DateOfBirth     *time.Time `gorm:"type:date" json:"date_of_birth,omitempty"`
-	Phone           *string    `gorm:"size:20" json:"phone,omitempty"`
-	Address         *string    `gorm:"type:text" json:"address,omitempty"`
-	IsActive        bool       `gorm:"default:true" json:"is_active"`
-	IsDeveloper     bool       `gorm:"default:true" json:"is_developer"`
+	GlobalBaseModel               // Users table doesn't have tenant_id since it's a global table
+	Username           string     `gorm:"size:50;uniqueIndex;not null" json:"username"`
+	PasswordHash       string     `gorm:"size:255;not null" json:"-"`
+	Email              string     `gorm:"size:100;uniqueIndex" json:"email"`
+	FullName           string     `gorm:"size:100;not null" json:"full_name"`
+	Birthplace         *string    `gorm:"size:100" json:"birthplace,omitempty"`
+	Birthday           *time.Time `gorm:"type:date" json:"birthday,omitempty"`
+	Gender             *Gender    `gorm:"type:gender_enum" json:"gender,omitempty"`
+	DateOfBirth        *time.Time `gorm:"type:text;serializer:encrypted" json:"date_of_birth,omitempty"` // Encrypted at rest
+	Phone              *string    `gorm:"type:text;serializer:encrypted" json:"phone,omitempty"`         // Encrypted at rest
+	PhoneIndex         *string    `gorm:"size:64;blindindex:Phone" json:"-"`                             // Blind index of Phone, for exact phone search
+	Address            *string    `gorm:"type:text;serializer:encrypted" json:"address,omitempty"`       // Encrypted at rest
+	IsActive           bool       `gorm:"default:true" json:"is_active"`
+	EmailVerified      bool       `gorm:"not null;default:false" json:"email_verified"`
+	EmailVerifiedAt    *time.Time `json:"email_verified_at,omitempty"`
+	MustChangePassword bool       `gorm:"not null;default:false" json:"must_change_password"` // Set by an admin password reset
+	IsDeveloper        bool       `gorm:"default:true" json:"is_developer"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	DeletedAt          *time.Time `json:"deleted_at,omitempty"` // Set when the user deleted their account and it was anonymized
 
 	// Relationships
 	TenantUsers   []TenantUser   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"tenant_users,omitempty"`
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// AcademicYearRepository interface defines academic year repository methods
+type AcademicYearRepository interface {
+	GetByID(c context.Context, id uuid.UUID) (*model.AcademicYear, error)
+	GetActive(c context.Context, tenantID uuid.UUID) (*model.AcademicYear, error)
+	GetNext(c context.Context, tenantID uuid.UUID, after time.Time) (*model.AcademicYear, error)
+}
+
+// academicYearRepository implements AcademicYearRepository
+type academicYearRepository struct {
+	*BaseRepository
+}
+
+// NewAcademicYearRepository creates a new academic year repository
+func NewAcademicYearRepository(db *database.DatabaseConnections) AcademicYearRepository {
+	return &academicYearRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *academicYearRepository) GetByID(c context.Context, id uuid.UUID) (*model.AcademicYear, error) {
+	repoCtx := r.WithContext(c)
+	var academicYear model.AcademicYear
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.First(&academicYear, id).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("academic year not found")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("academic_year_id", id.String()).
+			Msg("Database error while getting academic year by ID")
+		return nil, err
+	}
+	return &academicYear, nil
+}
+
+func (r *academicYearRepository) GetActive(c context.Context, tenantID uuid.UUID) (*model.AcademicYear, error) {
+	repoCtx := r.WithContext(c)
+
+	var academicYear model.AcademicYear
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Where("tenant_id = ? AND is_active = true", tenantID).
+			Order("start_date DESC").First(&academicYear).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("no active academic year found")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_active_academic_year").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return &academicYear, nil
+}
+
+// GetNext returns the earliest academic year that starts after the given date
+func (r *academicYearRepository) GetNext(c context.Context, tenantID uuid.UUID, after time.Time) (*model.AcademicYear, error) {
+	repoCtx := r.WithContext(c)
+
+	var academicYear model.AcademicYear
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Where("tenant_id = ? AND start_date > ?", tenantID, after).
+			Order("start_date ASC").First(&academicYear).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("next academic year not found")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_next_academic_year").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return &academicYear, nil
+}
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// AccountMembership holds the personal records of a user in one tenant
+type AccountMembership struct {
+	TenantUser    model.TenantUser
+	Roles         []string
+	Student       *model.Student
+	Teacher       *model.Teacher
+	Enrollments   []model.Enrollment
+	Grades        []model.Grade
+	Attendance    []model.Attendance
+	Fees          []model.StudentFee
+	Notifications []model.Notification
+}
+
+// AccountData holds every personal record kept about a user
+type AccountData struct {
+	User        model.User
+	Preferences []model.NotificationPreference
+	Memberships []AccountMembership
+}
+
+// AccountRepository interface defines the repository methods for a user's own account data
+type AccountRepository interface {
+	GetData(c context.Context, userID uuid.UUID) (*AccountData, error)
+	Anonymize(c context.Context, userID uuid.UUID, deletedAt time.Time) error
+}
+
+// accountRepository implements AccountRepository
+type accountRepository struct {
+	*BaseRepository
+}
+
+// NewAccountRepository creates a new account repository
+func NewAccountRepository(db *database.DatabaseConnections) AccountRepository {
+	return &accountRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// GetData loads the user with their notification preferences and, for every tenant membership, their
+// student or teacher record, enrollments, grades, attendance, fees and notifications
+func (r *accountRepository) GetData(c context.Context, userID uuid.UUID) (*AccountData, error) {
+	repoCtx := r.WithContext(c)
+
+	data := &AccountData{}
+	err := r.db.Read.WithContext(c).First(&data.User, userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("user not found")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_account_user").
+			Msg("Database query failed")
+		return nil, err
+	}
+	if err := r.db.Read.WithContext(c).Where("user_id = ?", userID).Order("notification_type").Find(&data.Preferences).Error; err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_account_notification_preferences").
+			Msg("Database query failed")
+		return nil, err
+	}
+
+	var tenantUsers []model.TenantUser
+	err = r.ReadAsUser(c, userID, func(db *gorm.DB) error {
+		return db.Preload("Tenant").Where("user_id = ?", userID).Order("created_at").Find(&tenantUsers).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_account_memberships").
+			Msg("Database query failed")
+		return nil, err
+	}
+
+	for _, tenantUser := range tenantUsers {
+		membership := AccountMembership{TenantUser: tenantUser}
+		err := r.ReadWithTenant(c, tenantUser.TenantID, func(db *gorm.DB) error {
+			return loadMembershipData(db, userID, &membership)
+		})
+		if err != nil {
+			repoCtx.logger.Error().
+				Err(err).
+				Str("operation", "get_account_membership_data").
+				Str("tenant_id", tenantUser.TenantID.String()).
+				Msg("Database query failed")
+			return nil, err
+		}
+		data.Memberships = append(data.Memberships, membership)
+	}
+	return data, nil
+}
+
+// loadMembershipData fills the records of the membership's tenant user
+func loadMembershipData(db *gorm.DB, userID uuid.UUID, membership *AccountMembership) error {
+	tenantUser := membership.TenantUser
+
+	err := db.Table("tenant_user_roles tur").
+		Joins("JOIN roles ro ON ro.id = tur.role_id").
+		Where("tur.tenant_user_id = ?", tenantUser.ID).
+		Order("ro.name").
+		Pluck("ro.name", &membership.Roles).Error
+	if err != nil {
+		return err
+	}
+
+	var teachers []model.Teacher
+	if err := db.Where("tenant_user_id = ?", tenantUser.ID).Limit(1).Find(&teachers).Error; err != nil {
+		return err
+	}
+	if len(teachers) > 0 {
+		membership.Teacher = &teachers[0]
+	}
+
+	var students []model.Student
+	if err := db.Preload("Class").Where("tenant_user_id = ?", tenantUser.ID).Limit(1).Find(&students).Error; err != nil {
+		return err
+	}
+	if len(students) > 0 {
+		student := &students[0]
+		membership.Student = student
+
+		err := db.Preload("ClassSubject.Class").Preload("ClassSubject.Subject").Preload("AcademicYear").
+			Where("student_id = ?", student.ID).
+			Find(&membership.Enrollments).Error
+		if err != nil {
+			return err
+		}
+		err = db.Preload("Enrollment.ClassSubject.Subject").
+			Joins("JOIN enrollments e ON e.id = grades.enrollment_id").
+			Where("e.student_id = ?", student.ID).
+			Find(&membership.Grades).Error
+		if err != nil {
+			return err
+		}
+		err = db.Where("student_id = ?", student.ID).
+			Order("attendance_date").
+			Find(&membership.Attendance).Error
+		if err != nil {
+			return err
+		}
+		err = db.Preload("FeeType").
+			Where("student_id = ?", student.ID).
+			Order("due_date").
+			Find(&membership.Fees).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return db.Where("tenant_id = ? AND user_id = ?", tenantUser.TenantID, userID).
+		Order("created_at").
+		Find(&membership.Notifications).Error
+}
+
+// Anonymize replaces the user's personal data with tombstones and deactivates the account and its memberships
+// in one transaction. Notifications and preferences are deleted; student, enrollment, grade, attendance and fee
+// rows are kept so academic records stay intact.
+func (r *accountRepository) Anonymize(c context.Context, userID uuid.UUID, deletedAt time.Time) error {
+	repoCtx := r.WithContext(c)
+
+	tombstone := "deleted-" + userID.String()
+	err := r.withTenantTransaction(c, r.db.Write, uuid.Nil, func(tx *gorm.DB) error {
+		var tenantIDs []uuid.UUID
+		if err := tx.Model(&model.TenantUser{}).Where("user_id = ?", userID).Pluck("tenant_id", &tenantIDs).Error; err != nil {
+			return err
+		}
+
+		for _, tenantID := range tenantIDs {
+			// Tenant tables are behind row level security
+			if err := tx.Exec("SELECT set_config('app.current_tenant', ?, true)", tenantID.String()).Error; err != nil {
+				return err
+			}
+			err := tx.Model(&model.Teacher{}).
+				Where("tenant_id = ? AND tenant_user_id IN (?)", tenantID,
+					tx.Model(&model.TenantUser{}).Select("id").Where("user_id = ?", userID)).
+				Updates(map[string]interface{}{"birthplace": nil, "birthday": nil, "gender": nil}).Error
+			if err != nil {
+				return fmt.Errorf("anonymize teacher: %w", err)
+			}
+			if err := tx.Where("tenant_id = ? AND user_id = ?", tenantID, userID).Delete(&model.Notification{}).Error; err != nil {
+				return fmt.Errorf("delete notifications: %w", err)
+			}
+			err = tx.Model(&model.TenantUser{}).
+				Where("tenant_id = ? AND user_id = ?", tenantID, userID).
+				Update("is_active", false).Error
+			if err != nil {
+				return fmt.Errorf("deactivate membership: %w", err)
+			}
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&model.NotificationPreference{}).Error; err != nil {
+			return fmt.Errorf("delete notification preferences: %w", err)
+		}
+
+		result := tx.Model(&model.User{}).Where("id = ? AND deleted_at IS NULL", userID).Updates(map[string]interface{}{
+			"username":          tombstone,
+			"email":             tombstone + "@deleted.invalid",
+			"full_name":         "Deleted User",
+			"password_hash":     "",
+			"birthplace":        nil,
+			"birthday":          nil,
+			"gender":            nil,
+			"date_of_birth":     nil,
+			"phone":             nil,
+			"phone_index":       nil,
+			"address":           nil,
+			"is_active":         false,
+			"email_verified":    false,
+			"email_verified_at": nil,
+			"deleted_at":        deletedAt,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("anonymize user: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return apperror.NotFound("user not found")
+		}
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, apperror.ErrNotFound) {
+			repoCtx.logger.Error().
+				Err(err).
+				Str("operation", "anonymize_user").
+				Msg("Database query failed")
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository interface defines API key repository methods
+type APIKeyRepository interface {
+	Create(c context.Context, apiKey *model.APIKey) error
+	GetByHash(c context.Context, keyHash string) (*model.APIKey, error)
+	ListByTenant(c context.Context, tenantID uuid.UUID) ([]model.APIKey, error)
+	Revoke(c context.Context, tenantID, id uuid.UUID, revokedAt time.Time) error
+	TouchLastUsed(c context.Context, tenantID, id uuid.UUID, usedAt time.Time) error
+}
+
+// apiKeyRepository implements APIKeyRepository
+type apiKeyRepository struct {
+	*BaseRepository
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *database.DatabaseConnections) APIKeyRepository {
+	return &apiKeyRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create inserts a new API key
+func (r *apiKeyRepository) Create(c context.Context, apiKey *model.APIKey) error {
+	repoCtx := r.WithContext(c)
+
+	err := r.WriteWithTenant(c, apiKey.TenantID, func(db *gorm.DB) error {
+		return db.Create(apiKey).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "create_api_key").
+			Msg("Database write operation failed")
+	}
+	return err
+}
+
+// GetByHash returns the unrevoked API key with the hash, whatever its tenant
+func (r *apiKeyRepository) GetByHash(c context.Context, keyHash string) (*model.APIKey, error) {
+	repoCtx := r.WithContext(c)
+
+	var apiKey model.APIKey
+	// The key's tenant is not known yet, api_keys has no tenant isolation policy for this lookup
+	err := r.ReadWithTenant(c, uuid.Nil, func(db *gorm.DB) error {
+		return db.Where("key_hash = ? AND revoked_at IS NULL", keyHash).First(&apiKey).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("api key not found")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_api_key_by_hash").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// ListByTenant returns every API key of the tenant, revoked ones included, newest first
+func (r *apiKeyRepository) ListByTenant(c context.Context, tenantID uuid.UUID) ([]model.APIKey, error) {
+	repoCtx := r.WithContext(c)
+
+	var apiKeys []model.APIKey
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Where("tenant_id = ?", tenantID).
+			Order("created_at DESC").
+			Find(&apiKeys).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "list_api_keys").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return apiKeys, nil
+}
+
+// Revoke marks the tenant's API key as revoked, so it no longer authenticates
+func (r *apiKeyRepository) Revoke(c context.Context, tenantID, id uuid.UUID, revokedAt time.Time) error {
+	repoCtx := r.WithContext(c)
+
+	err := r.WriteWithTenant(c, tenantID, func(db *gorm.DB) error {
+		result := db.Model(&model.APIKey{}).
+			Where("id = ? AND tenant_id = ? AND revoked_at IS NULL", id, tenantID).
+			Updates(map[string]interface{}{"revoked_at": revokedAt, "updated_at": revokedAt})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperror.NotFound("api key not found")
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "revoke_api_key").
+			Msg("Database write operation failed")
+	}
+	return err
+}
+
+// TouchLastUsed records when the API key was last used
+func (r *apiKeyRepository) TouchLastUsed(c context.Context, tenantID, id uuid.UUID, usedAt time.Time) error {
+	repoCtx := r.WithContext(c)
+
+	err := r.WriteWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Model(&model.APIKey{}).
+			Where("id = ? AND tenant_id = ?", id, tenantID).
+			UpdateColumn("last_used_at", usedAt).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "touch_api_key").
+			Msg("Database write operation failed")
+	}
+	return err
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"github.com/protocyber/kelasgo-api/internal/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AttendanceStatusCount holds the number of attendance records with a given status
+type AttendanceStatusCount struct {
+	Status model.AttendanceStatus
+	Count  int64
+}
+
+// attendanceSortColumns maps accepted sort fields to their columns
+var attendanceSortColumns = map[string]string{
+	"attendance_date": "attendance.attendance_date",
+	"status":          "attendance.status",
+}
+
+// AttendanceFilter holds the optional filters of an attendance listing; every set field narrows the result
+type AttendanceFilter struct {
+	StudentID  *uuid.UUID
+	ScheduleID *uuid.UUID
+	// ClassID matches attendance of the schedules of any class subject of the class
+	ClassID  *uuid.UUID
+	DateFrom *time.Time
+	DateTo   *time.Time
+	Status   *string
+	// SortBy is one of the attendanceSortColumns keys; the default is newest date first
+	SortBy   string
+	SortDesc bool
+}
+
+// AttendanceRepository interface defines attendance repository methods
+type AttendanceRepository interface {
+	Create(c context.Context, attendance *model.Attendance) error
+	RecordScan(c context.Context, attendance *model.Attendance) (bool, error)
+	CountByStatusForStudent(c context.Context, tenantID, studentID uuid.UUID, from, to time.Time) ([]AttendanceStatusCount, error)
+	List(c context.Context, tenantID uuid.UUID, offset, limit int, filter AttendanceFilter) ([]model.Attendance, int64, error)
+	MarkAbsent(c context.Context, tenantID uuid.UUID, date time.Time, remarks string) (int64, error)
+}
+
+// attendanceRepository implements AttendanceRepository
+type attendanceRepository struct {
+	*BaseRepository
+}
+
+// NewAttendanceRepository creates a new attendance repository
+func NewAttendanceRepository(db *database.DatabaseConnections) AttendanceRepository {
+	return &attendanceRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create records an attendance entry. Without a date it is recorded for today in the app timezone,
+// since the CURRENT_DATE column default follows the database server's timezone.
+func (r *attendanceRepository) Create(c context.Context, attendance *model.Attendance) error {
+	repoCtx := r.WithContext(c)
+
+	if attendance.AttendanceDate.IsZero() {
+		attendance.AttendanceDate = util.TodayFromContext(c)
+	}
+
+	err := r.WriteWithTenant(c, attendance.TenantID, func(db *gorm.DB) error {
+		return db.Create(attendance).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "create_attendance").
+			Msg("Database query failed")
+		return err
+	}
+	return nil
+}
+
+// RecordScan records the student present for the session of the attendance. An existing record of the
+// session is kept, except an absent one, which the scan proves wrong and turns present. The attendance is
+// filled with the record in effect, and recorded reports whether the scan wrote it.
+func (r *attendanceRepository) RecordScan(c context.Context, attendance *model.Attendance) (bool, error) {
+	repoCtx := r.WithContext(c)
+
+	var recorded bool
+	err := r.WriteWithTenant(c, attendance.TenantID, func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "student_id"}, {Name: "schedule_id"}, {Name: "attendance_date"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"status": model.AttendancePresent, "remarks": nil}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Eq{Column: clause.Column{Table: "attendance", Name: "status"}, Value: model.AttendanceAbsent},
+			}},
+		}).Create(attendance)
+		if result.Error != nil {
+			return result.Error
+		}
+		recorded = result.RowsAffected > 0
+		if recorded {
+			return nil
+		}
+
+		// The session already has a record that is not absent
+		return tx.Where("student_id = ? AND schedule_id = ? AND attendance_date = ?::date",
+			attendance.StudentID, attendance.ScheduleID, attendance.AttendanceDate.Format("2006-01-02")).
+			First(attendance).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "record_attendance_scan").
+			Msg("Database write operation failed")
+		return false, err
+	}
+	return recorded, nil
+}
+
+// CountByStatusForStudent aggregates a student's attendance records per status within the date range
+func (r *attendanceRepository) CountByStatusForStudent(c context.Context, tenantID, studentID uuid.UUID, from, to time.Time) ([]AttendanceStatusCount, error) {
+	repoCtx := r.WithContext(c)
+
+	var counts []AttendanceStatusCount
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Model(&model.Attendance{}).
+			Select("status, COUNT(*) AS count").
+			Where("tenant_id = ? AND student_id = ? AND attendance_date BETWEEN ? AND ?", tenantID, studentID, from, to).
+			Group("status").
+			Scan(&counts).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "count_attendance_by_status").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return counts, nil
+}
+
+// List returns a page of the tenant's attendance records with their students and schedule subjects
+func (r *attendanceRepository) List(c context.Context, tenantID uuid.UUID, offset, limit int, filter AttendanceFilter) ([]model.Attendance, int64, error) {
+	repoCtx := r.WithContext(c)
+
+	attendance := []model.Attendance{}
+	var total int64
+
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		query := db.Model(&model.Attendance{}).Where("attendance.tenant_id = ?", tenantID)
+
+		if filter.StudentID != nil {
+			query = query.Where("attendance.student_id = ?", *filter.StudentID)
+		}
+		if filter.ScheduleID != nil {
+			query = query.Where("attendance.schedule_id = ?", *filter.ScheduleID)
+		}
+		if filter.ClassID != nil {
+			query = query.Joins("JOIN schedules sc ON sc.id = attendance.schedule_id").
+				Joins("JOIN class_subjects cs ON cs.id = sc.class_subject_id").
+				Where("cs.class_id = ?", *filter.ClassID)
+		}
+		if filter.DateFrom != nil {
+			query = query.Where("attendance.attendance_date >= ?", *filter.DateFrom)
+		}
+		if filter.DateTo != nil {
+			query = query.Where("attendance.attendance_date <= ?", *filter.DateTo)
+		}
+		if filter.Status != nil {
+			query = query.Where("attendance.status = ?", *filter.Status)
+		}
+
+		if err := query.Count(&total).Error; err != nil {
+			repoCtx.logger.Error().
+				Err(err).
+				Str("operation", "count_attendance").
+				Msg("Database query failed")
+			return err
+		}
+		if int64(offset) >= total {
+			return nil
+		}
+
+		page := query.Scopes(r.Paginate(offset, limit))
+		if _, ok := attendanceSortColumns[filter.SortBy]; ok {
+			page = page.Scopes(r.Sort(attendanceSortColumns, filter.SortBy, filter.SortDesc, "attendance.id"))
+		} else {
+			page = page.Order("attendance.attendance_date DESC, attendance.id")
+		}
+		err := page.Preload("Student.TenantUser.User").Preload("Schedule.ClassSubject.Subject").
+			Find(&attendance).Error
+		if err != nil {
+			repoCtx.logger.Error().
+				Err(err).
+				Str("operation", "list_attendance").
+				Msg("Database query failed")
+		}
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return attendance, total, nil
+}
+
+// MarkAbsent records as absent every active student with a session on the date that has no attendance record
+// for it. A student has the sessions of their class's subjects and of the class subjects they are enrolled in.
+// Concurrent runs for the same tenant and date wait for each other, so no session is marked twice.
+func (r *attendanceRepository) MarkAbsent(c context.Context, tenantID uuid.UUID, date time.Time, remarks string) (int64, error) {
+	repoCtx := r.WithContext(c)
+
+	day := date.Format("2006-01-02")
+	var marked int64
+	err := r.WriteWithTenant(c, tenantID, func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "auto_absent:"+tenantID.String()+":"+day).Error; err != nil {
+			return err
+		}
+		result := tx.Exec(`
+			INSERT INTO attendance (tenant_id, student_id, schedule_id, status, attendance_date, remarks)
+			SELECT DISTINCT s.tenant_id, st.id, s.id, ?::attendance_status_enum, ?::date, ?
+			FROM schedules s
+			JOIN class_subjects cs ON cs.id = s.class_subject_id
+			JOIN students st ON st.tenant_id = s.tenant_id
+				AND (
+					st.class_id = cs.class_id
+					OR EXISTS (SELECT 1 FROM enrollments e WHERE e.student_id = st.id AND e.class_subject_id = cs.id)
+				)
+			WHERE s.tenant_id = ?
+				AND s.day_of_week = ?
+				AND st.status = ?
+				AND st.admission_date <= ?::date
+				AND NOT EXISTS (
+					SELECT 1 FROM attendance a
+					WHERE a.student_id = st.id AND a.schedule_id = s.id AND a.attendance_date = ?::date
+				)
+			ON CONFLICT (student_id, schedule_id, attendance_date) DO NOTHING`,
+			model.AttendanceAbsent, day, remarks, tenantID, model.DayOfWeekFor(date), model.StudentActive, day, day,
+		)
+		marked = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "mark_attendance_absent").
+			Msg("Database write operation failed")
+		return 0, err
+	}
+	return marked, nil
+}
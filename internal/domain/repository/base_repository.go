@@ -2,6 +2,10 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
@@ -9,18 +13,19 @@ import (
 	"gorm.io/gorm"
 )
 
+// txContextKey is the context key holding the transaction started by WithTransaction
+type txContextKey struct{}
+
 // BaseRepository provides common database operations with tenant context
 type BaseRepository struct {
 	db     *database.DatabaseConnections
-	helper *util.TenantHelper
 	logger *util.ContextLogger
 }
 
 // NewBaseRepository creates a new base repository
 func NewBaseRepository(db *database.DatabaseConnections) *BaseRepository {
 	return &BaseRepository{
-		db:     db,
-		helper: util.NewTenantHelper(db),
+		db: db,
 	}
 }
 
@@ -29,7 +34,6 @@ func (r *BaseRepository) WithContext(ctx context.Context) *BaseRepository {
 	// Create a copy of the repository with context logger
 	return &BaseRepository{
 		db:     r.db,
-		helper: r.helper,
 		logger: util.NewServiceLogger(ctx),
 	}
 }
@@ -43,32 +47,97 @@ func (r *BaseRepository) GetLogger() *util.ContextLogger {
 	return &util.ContextLogger{}
 }
 
-// SetTenantContext sets the tenant context for database operations
-func (r *BaseRepository) SetTenantContext(tenantID uuid.UUID) error {
-	if r.logger != nil {
-		r.logger.Debug().
-			Str("tenant_id", tenantID.String()).
-			Msg("Setting tenant context for repository operation")
+// WithTransaction runs fn inside a single write transaction scoped to the tenant and current user.
+// Repository writes made with the context passed to fn join the transaction, so everything is
+// rolled back when fn returns an error. Nested calls join the outer transaction.
+func (r *BaseRepository) WithTransaction(c context.Context, tenantID uuid.UUID, fn func(txCtx context.Context) error) error {
+	if _, ok := c.Value(txContextKey{}).(*gorm.DB); ok {
+		return fn(c)
 	}
-	return r.helper.SetTenantContext(tenantID)
+
+	return r.withTenantTransaction(c, r.db.Write, tenantID, func(tx *gorm.DB) error {
+		return fn(context.WithValue(c, txContextKey{}, tx))
+	})
 }
 
-// ClearTenantContext clears the tenant context
-func (r *BaseRepository) ClearTenantContext() error {
-	if r.logger != nil {
-		r.logger.Debug().Msg("Clearing tenant context for repository operation")
+// Writer returns the transaction bound to the context by WithTransaction, or the write connection
+func (r *BaseRepository) Writer(c context.Context) *gorm.DB {
+	if tx, ok := c.Value(txContextKey{}).(*gorm.DB); ok {
+		return tx
 	}
-	return r.helper.GetTenantContextManager().ClearTenantContext()
+	return r.db.Write
 }
 
-// ExecuteWithTenant executes a function with tenant context set
-func (r *BaseRepository) ExecuteWithTenant(tenantID uuid.UUID, fn func() error) error {
-	if r.logger != nil {
-		r.logger.Debug().
-			Str("tenant_id", tenantID.String()).
-			Msg("Executing repository operation with tenant context")
+// ReadWithTenant runs fn in a read-only transaction on the read connection with the tenant
+// context applied. Inside WithTransaction the transaction is used to read its own writes.
+func (r *BaseRepository) ReadWithTenant(c context.Context, tenantID uuid.UUID, fn func(db *gorm.DB) error) error {
+	if tx, ok := c.Value(txContextKey{}).(*gorm.DB); ok {
+		return fn(tx)
+	}
+	return r.withTenantTransaction(c, r.db.Read, tenantID, fn, &sql.TxOptions{ReadOnly: true})
+}
+
+// WriteWithTenant runs fn in a transaction on the write connection with the tenant context
+// applied, so everything fn writes is committed or rolled back together.
+// Inside WithTransaction the transaction is used instead.
+func (r *BaseRepository) WriteWithTenant(c context.Context, tenantID uuid.UUID, fn func(db *gorm.DB) error) error {
+	if tx, ok := c.Value(txContextKey{}).(*gorm.DB); ok {
+		return fn(tx)
 	}
-	return r.helper.ExecuteWithTenant(tenantID, fn)
+	return r.withTenantTransaction(c, r.db.Write, tenantID, fn)
+}
+
+// ReadAsUser runs fn in a read-only transaction scoped to the user, and to the tenant of the request when it
+// has one. The own_memberships policy shows the user's tenant_users rows of every tenant, so the tenants of a
+// user can be looked up before one is selected, e.g. at login.
+func (r *BaseRepository) ReadAsUser(c context.Context, userID uuid.UUID, fn func(db *gorm.DB) error) error {
+	if tx, ok := c.Value(txContextKey{}).(*gorm.DB); ok {
+		return fn(tx)
+	}
+	return r.withScopedTransaction(c, r.db.Read, contextTenantID(c), userID, fn, &sql.TxOptions{ReadOnly: true})
+}
+
+// withTenantTransaction runs fn in a transaction with the tenant and current user scoped to it
+func (r *BaseRepository) withTenantTransaction(c context.Context, db *gorm.DB, tenantID uuid.UUID, fn func(tx *gorm.DB) error, opts ...*sql.TxOptions) error {
+	userID, _ := util.GetUserIDAsUUID(c)
+	return r.withScopedTransaction(c, db, tenantID, userID, fn, opts...)
+}
+
+// withScopedTransaction runs fn in a transaction with the tenant and user scoped to it, leaving out the nil ones,
+// along with the word similarity threshold searches match with. The settings are transaction-local, so Postgres
+// discards them at commit or rollback and a pooled connection never carries one request's tenant into the next.
+func (r *BaseRepository) withScopedTransaction(c context.Context, db *gorm.DB, tenantID, userID uuid.UUID, fn func(tx *gorm.DB) error, opts ...*sql.TxOptions) error {
+	return db.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		// Scope tenant and user to this transaction so RLS and the audit trigger see them, all in one statement
+		var settings []string
+		var args []interface{}
+		if tenantID != uuid.Nil {
+			settings = append(settings, "set_config('app.current_tenant', ?, true)")
+			args = append(args, tenantID.String())
+		}
+		if userID != uuid.Nil {
+			settings = append(settings, "set_config('app.current_user', ?, true)")
+			args = append(args, userID.String())
+		}
+		if r.db.SearchSimilarityThreshold > 0 {
+			settings = append(settings, "set_config('pg_trgm.word_similarity_threshold', ?, true)")
+			args = append(args, strconv.FormatFloat(r.db.SearchSimilarityThreshold, 'f', -1, 64))
+		}
+		if len(settings) > 0 {
+			if err := tx.Exec("SELECT "+strings.Join(settings, ", "), args...).Error; err != nil {
+				return err
+			}
+		}
+
+		return fn(tx)
+	}, opts...)
+}
+
+// contextTenantID returns the tenant of the request the context belongs to, or uuid.Nil. Repository methods
+// that take no tenant ID scope their transaction to it, so RLS still applies to their queries.
+func contextTenantID(c context.Context) uuid.UUID {
+	tenantID, _ := util.GetTenantIDAsUUID(c)
+	return tenantID
 }
 
 // GetReadDB returns the read database connection
@@ -80,3 +149,62 @@ func (r *BaseRepository) GetReadDB() *gorm.DB {
 func (r *BaseRepository) GetWriteDB() *gorm.DB {
 	return r.db.Write
 }
+
+// defaultMaxPageSize bounds list queries when no maximum page size is configured
+const defaultMaxPageSize = 100
+
+// Paginate returns a scope applying the offset and limit of a list query. The limit is clamped to between 1
+// and the configured maximum page size, so no caller can load an unbounded number of rows.
+func (r *BaseRepository) Paginate(offset, limit int) func(db *gorm.DB) *gorm.DB {
+	maxPageSize := r.db.MaxPageSize
+	if maxPageSize <= 0 {
+		maxPageSize = defaultMaxPageSize
+	}
+	if limit < 1 || limit > maxPageSize {
+		limit = maxPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Offset(offset).Limit(limit)
+	}
+}
+
+// Sort returns a scope ordering a list query by the column the sort field maps to, with the tie-breaker column
+// appended so pages stay stable. Sort fields missing from the columns leave the order unspecified.
+func (r *BaseRepository) Sort(columns map[string]string, sortBy string, desc bool, tieBreaker string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		sortColumn, ok := columns[sortBy]
+		if !ok {
+			return db
+		}
+		sortDir := "ASC"
+		if desc {
+			sortDir = "DESC"
+		}
+		return db.Order(sortColumn + " " + sortDir + ", " + tieBreaker)
+	}
+}
+
+// SearchCondition builds an accent and case insensitive search over the given columns.
+// Each column matches on a substring, or on trigram word similarity when a threshold is configured,
+// so "Muhamad" still finds "Muhammad". The expressions are backed by the f_unaccent trigram indexes; the
+// <% operator can use them where the word_similarity function cannot, and matches with the threshold the
+// repository transactions set.
+func (r *BaseRepository) SearchCondition(search string, columns ...string) (string, []interface{}) {
+	conditions := make([]string, 0, len(columns))
+	args := make([]interface{}, 0, len(columns)*2)
+
+	for _, column := range columns {
+		condition := fmt.Sprintf("f_unaccent(%s) ILIKE f_unaccent(?)", column)
+		args = append(args, "%"+search+"%")
+		if r.db.SearchSimilarityThreshold > 0 {
+			condition += fmt.Sprintf(" OR f_unaccent(?) <%% f_unaccent(%s)", column)
+			args = append(args, search)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
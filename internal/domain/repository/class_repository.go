@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// ClassRepository interface defines class repository methods
+type ClassRepository interface {
+	GetByID(c context.Context, id uuid.UUID) (*model.Class, error)
+	GetByGradeLevel(c context.Context, tenantID uuid.UUID, gradeLevel int) ([]model.Class, error)
+	GetByHomeroomTeacher(c context.Context, tenantID, teacherID uuid.UUID) ([]model.Class, error)
+	UpdateHomeroomTeacher(c context.Context, tenantID, classID uuid.UUID, teacherID *uuid.UUID) error
+}
+
+// classRepository implements ClassRepository
+type classRepository struct {
+	*BaseRepository
+}
+
+// NewClassRepository creates a new class repository
+func NewClassRepository(db *database.DatabaseConnections) ClassRepository {
+	return &classRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *classRepository) GetByID(c context.Context, id uuid.UUID) (*model.Class, error) {
+	repoCtx := r.WithContext(c)
+	var class model.Class
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Preload("AcademicYear").First(&class, id).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("class not found")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("class_id", id.String()).
+			Msg("Database error while getting class by ID")
+		return nil, err
+	}
+	return &class, nil
+}
+
+func (r *classRepository) GetByGradeLevel(c context.Context, tenantID uuid.UUID, gradeLevel int) ([]model.Class, error) {
+	repoCtx := r.WithContext(c)
+
+	var classes []model.Class
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Preload("AcademicYear").
+			Where("tenant_id = ? AND grade_level = ?", tenantID, gradeLevel).
+			Find(&classes).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_classes_by_grade_level").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return classes, nil
+}
+
+// GetByHomeroomTeacher returns the tenant's classes the teacher is homeroom teacher of
+func (r *classRepository) GetByHomeroomTeacher(c context.Context, tenantID, teacherID uuid.UUID) ([]model.Class, error) {
+	repoCtx := r.WithContext(c)
+
+	var classes []model.Class
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Where("tenant_id = ? AND homeroom_teacher_id = ?", tenantID, teacherID).
+			Order("name").
+			Find(&classes).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_classes_by_homeroom_teacher").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return classes, nil
+}
+
+// UpdateHomeroomTeacher sets the homeroom teacher of the tenant's class, clearing it when teacherID is nil
+func (r *classRepository) UpdateHomeroomTeacher(c context.Context, tenantID, classID uuid.UUID, teacherID *uuid.UUID) error {
+	repoCtx := r.WithContext(c)
+
+	err := r.WriteWithTenant(c, tenantID, func(db *gorm.DB) error {
+		result := db.Model(&model.Class{}).
+			Where("id = ? AND tenant_id = ?", classID, tenantID).
+			Update("homeroom_teacher_id", teacherID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperror.NotFound("class not found")
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "update_class_homeroom_teacher").
+			Msg("Database write operation failed")
+	}
+	return err
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ClassSubjectRepository interface defines class subject repository methods
+type ClassSubjectRepository interface {
+	Create(c context.Context, classSubject *model.ClassSubject) error
+	BulkCreate(c context.Context, tenantID uuid.UUID, classSubjects []model.ClassSubject) ([]model.ClassSubject, error)
+	GetByID(c context.Context, id uuid.UUID) (*model.ClassSubject, error)
+	GetByClassAndSubject(c context.Context, tenantID, classID, subjectID uuid.UUID) (*model.ClassSubject, error)
+	Update(c context.Context, classSubject *model.ClassSubject) error
+	Delete(c context.Context, id uuid.UUID) error
+	List(c context.Context, tenantID uuid.UUID, offset, limit int, classID, subjectID, teacherID *uuid.UUID) ([]model.ClassSubject, int64, error)
+	GetAllByClass(c context.Context, tenantID, classID uuid.UUID) ([]model.ClassSubject, error)
+}
+
+// classSubjectRepository implements ClassSubjectRepository
+type classSubjectRepository struct {
+	*BaseRepository
+}
+
+// NewClassSubjectRepository creates a new class subject repository
+func NewClassSubjectRepository(db *database.DatabaseConnections) ClassSubjectRepository {
+	return &classSubjectRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *classSubjectRepository) Create(c context.Context, classSubject *model.ClassSubject) error {
+	repoCtx := r.WithContext(c)
+	err := r.WriteWithTenant(c, classSubject.TenantID, func(db *gorm.DB) error {
+		return db.Create(classSubject).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "create_class_subject").
+			Msg("Database write operation failed")
+	}
+	return err
+}
+
+// BulkCreate creates the class subjects in one transaction, so a failure leaves none of them behind. A class
+// subject whose subject is already assigned to the class is skipped, and the created ones are returned.
+func (r *classSubjectRepository) BulkCreate(c context.Context, tenantID uuid.UUID, classSubjects []model.ClassSubject) ([]model.ClassSubject, error) {
+	repoCtx := r.WithContext(c)
+	if len(classSubjects) == 0 {
+		return nil, nil
+	}
+
+	var created []model.ClassSubject
+	err := r.WriteWithTenant(c, tenantID, func(tx *gorm.DB) error {
+		// One row at a time, since a multi row insert skipping conflicts returns fewer IDs than rows and they
+		// could not be matched back
+		for i := range classSubjects {
+			result := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "class_id"}, {Name: "subject_id"}},
+				DoNothing: true,
+			}).Create(&classSubjects[i])
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				created = append(created, classSubjects[i])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "bulk_create_class_subjects").
+			Int("count", len(classSubjects)).
+			Msg("Database write operation failed")
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *classSubjectRepository) GetByID(c context.Context, id uuid.UUID) (*model.ClassSubject, error) {
+	repoCtx := r.WithContext(c)
+	var classSubject model.ClassSubject
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Preload("Class").Preload("Subject").Preload("Teacher.TenantUser.User").First(&classSubject, id).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("class subject not found")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("class_subject_id", id.String()).
+			Msg("Database error while getting class subject by ID")
+		return nil, err
+	}
+	return &classSubject, nil
+}
+
+func (r *classSubjectRepository) GetByClassAndSubject(c context.Context, tenantID, classID, subjectID uuid.UUID) (*model.ClassSubject, error) {
+	repoCtx := r.WithContext(c)
+
+	var classSubject model.ClassSubject
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Where("tenant_id = ? AND class_id = ? AND subject_id = ?", tenantID, classID, subjectID).
+			First(&classSubject).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("class subject not found")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_class_subject_by_class_and_subject").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return &classSubject, nil
+}
+
+func (r *classSubjectRepository) Update(c context.Context, classSubject *model.ClassSubject) error {
+	repoCtx := r.WithContext(c)
+	err := r.WriteWithTenant(c, classSubject.TenantID, func(db *gorm.DB) error {
+		return db.Omit("Class", "Subject", "Teacher").Save(classSubject).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "update_class_subject").
+			Msg("Database write operation failed")
+	}
+	return err
+}
+
+func (r *classSubjectRepository) Delete(c context.Context, id uuid.UUID) error {
+	repoCtx := r.WithContext(c)
+	err := r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Delete(&model.ClassSubject{}, id).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "delete_class_subject").
+			Msg("Database write operation failed")
+	}
+	return err
+}
+
+func (r *classSubjectRepository) List(c context.Context, tenantID uuid.UUID, offset, limit int, classID, subjectID, teacherID *uuid.UUID) ([]model.ClassSubject, int64, error) {
+	repoCtx := r.WithContext(c)
+
+	var classSubjects []model.ClassSubject
+	var total int64
+
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		query := db.Preload("Class").Preload("Subject").Preload("Teacher.TenantUser.User").
+			Where("tenant_id = ?", tenantID)
+
+		if classID != nil {
+			query = query.Where("class_id = ?", *classID)
+		}
+		if subjectID != nil {
+			query = query.Where("subject_id = ?", *subjectID)
+		}
+		if teacherID != nil {
+			query = query.Where("teacher_id = ?", *teacherID)
+		}
+
+		// Get total count
+		if err := query.Model(&model.ClassSubject{}).Count(&total).Error; err != nil {
+			repoCtx.logger.Error().
+				Err(err).
+				Str("operation", "count_class_subjects").
+				Msg("Database query failed")
+			return err
+		}
+
+		// Get paginated results
+		if err := query.Scopes(r.Paginate(offset, limit)).Find(&classSubjects).Error; err != nil {
+			repoCtx.logger.Error().
+				Err(err).
+				Str("operation", "list_class_subjects").
+				Msg("Database query failed")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return classSubjects, total, nil
+}
+
+// GetAllByClass returns every class subject of the class ordered by subject name
+func (r *classSubjectRepository) GetAllByClass(c context.Context, tenantID, classID uuid.UUID) ([]model.ClassSubject, error) {
+	repoCtx := r.WithContext(c)
+
+	var classSubjects []model.ClassSubject
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Preload("Subject").
+			Joins("LEFT JOIN subjects ON subjects.id = class_subjects.subject_id").
+			Where("class_subjects.tenant_id = ? AND class_subjects.class_id = ?", tenantID, classID).
+			Order("subjects.name ASC").
+			Find(&classSubjects).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_class_subjects_by_class").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return classSubjects, nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// DashboardScope narrows dashboard counts. TeacherUserID limits them to the classes a teacher is homeroom
+// teacher of or teaches a subject in; AcademicYearID limits classes to that academic year.
+type DashboardScope struct {
+	TenantID       uuid.UUID
+	TeacherUserID  *uuid.UUID
+	AcademicYearID *uuid.UUID
+}
+
+// DashboardCounts holds the headline counts of a tenant
+type DashboardCounts struct {
+	Students int64
+	Teachers int64
+	Classes  int64
+}
+
+// OutstandingFeeTotal holds the number and sum of unpaid, partial and overdue fees
+type OutstandingFeeTotal struct {
+	FeeCount    int64
+	TotalAmount float64
+}
+
+// DashboardRepository interface defines the aggregate queries of the dashboard
+type DashboardRepository interface {
+	GetCounts(c context.Context, scope DashboardScope) (*DashboardCounts, error)
+	GetOutstandingFees(c context.Context, tenantID uuid.UUID) (*OutstandingFeeTotal, error)
+	CountAttendanceByStatus(c context.Context, scope DashboardScope, date time.Time) ([]AttendanceStatusCount, error)
+	CountExpectedAttendance(c context.Context, scope DashboardScope, day model.DayOfWeek) (int64, error)
+}
+
+// dashboardRepository implements DashboardRepository
+type dashboardRepository struct {
+	*BaseRepository
+}
+
+// NewDashboardRepository creates a new dashboard repository
+func NewDashboardRepository(db *database.DatabaseConnections) DashboardRepository {
+	return &dashboardRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// teacherClassIDs selects the classes of the teacher with the user ID
+func teacherClassIDs(db *gorm.DB, userID uuid.UUID) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).Raw(`
+		SELECT c.id FROM classes c
+		JOIN teachers t ON t.id = c.homeroom_teacher_id
+		JOIN tenant_users tu ON tu.id = t.tenant_user_id
+		WHERE tu.user_id = ?
+		UNION
+		SELECT cs.class_id FROM class_subjects cs
+		JOIN teachers t ON t.id = cs.teacher_id
+		JOIN tenant_users tu ON tu.id = t.tenant_user_id
+		WHERE tu.user_id = ?`, userID, userID)
+}
+
+// GetCounts counts active students, teachers and classes in the scope. Teachers are only counted tenant wide.
+func (r *dashboardRepository) GetCounts(c context.Context, scope DashboardScope) (*DashboardCounts, error) {
+	repoCtx := r.WithContext(c)
+
+	counts := &DashboardCounts{}
+	err := r.ReadWithTenant(c, scope.TenantID, func(db *gorm.DB) error {
+		students := db.Model(&model.Student{}).Where("tenant_id = ? AND status = ?", scope.TenantID, model.StudentActive)
+		classes := db.Model(&model.Class{}).Where("tenant_id = ?", scope.TenantID)
+		if scope.AcademicYearID != nil {
+			classes = classes.Where("academic_year_id = ?", *scope.AcademicYearID)
+		}
+		if scope.TeacherUserID != nil {
+			students = students.Where("class_id IN (?)", teacherClassIDs(db, *scope.TeacherUserID))
+			classes = classes.Where("id IN (?)", teacherClassIDs(db, *scope.TeacherUserID))
+		} else if err := db.Model(&model.Teacher{}).Where("tenant_id = ?", scope.TenantID).Count(&counts.Teachers).Error; err != nil {
+			return err
+		}
+
+		if err := students.Count(&counts.Students).Error; err != nil {
+			return err
+		}
+		return classes.Count(&counts.Classes).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "count_dashboard_totals").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return counts, nil
+}
+
+// GetOutstandingFees sums the unpaid, partial and overdue fees of the tenant
+func (r *dashboardRepository) GetOutstandingFees(c context.Context, tenantID uuid.UUID) (*OutstandingFeeTotal, error) {
+	repoCtx := r.WithContext(c)
+
+	total := &OutstandingFeeTotal{}
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		outstanding := []model.FeeStatus{model.FeeStatusUnpaid, model.FeeStatusPartial, model.FeeStatusOverdue}
+		return db.Table("student_fees").
+			Select("COUNT(*) AS fee_count, COALESCE(SUM(amount), 0) AS total_amount").
+			Where("tenant_id = ? AND status IN ?", tenantID, outstanding).
+			Scan(total).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "sum_outstanding_fees").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return total, nil
+}
+
+// CountAttendanceByStatus counts the attendance records of the date in the scope per status
+func (r *dashboardRepository) CountAttendanceByStatus(c context.Context, scope DashboardScope, date time.Time) ([]AttendanceStatusCount, error) {
+	repoCtx := r.WithContext(c)
+
+	var counts []AttendanceStatusCount
+	err := r.ReadWithTenant(c, scope.TenantID, func(db *gorm.DB) error {
+		query := db.Table("attendance AS a").
+			Select("a.status, COUNT(*) AS count").
+			Where("a.tenant_id = ? AND a.attendance_date = ?", scope.TenantID, date)
+		if scope.TeacherUserID != nil {
+			query = query.
+				Joins("JOIN schedules s ON s.id = a.schedule_id").
+				Joins("JOIN class_subjects cs ON cs.id = s.class_subject_id").
+				Where("cs.class_id IN (?)", teacherClassIDs(db, *scope.TeacherUserID))
+		}
+		return query.Group("a.status").Scan(&counts).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "count_attendance_by_status_for_date").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return counts, nil
+}
+
+// CountExpectedAttendance counts the attendance records expected on a day of the week in the scope,
+// one per student of a class for each of the class's sessions that day
+func (r *dashboardRepository) CountExpectedAttendance(c context.Context, scope DashboardScope, day model.DayOfWeek) (int64, error) {
+	repoCtx := r.WithContext(c)
+
+	var count int64
+	err := r.ReadWithTenant(c, scope.TenantID, func(db *gorm.DB) error {
+		query := db.Table("schedules AS s").
+			Joins("JOIN class_subjects cs ON cs.id = s.class_subject_id").
+			Joins("JOIN students st ON st.class_id = cs.class_id").
+			Where("s.tenant_id = ? AND s.day_of_week = ?", scope.TenantID, day)
+		if scope.TeacherUserID != nil {
+			query = query.Where("cs.class_id IN (?)", teacherClassIDs(db, *scope.TeacherUserID))
+		}
+		return query.Count(&count).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "count_expected_attendance").
+			Msg("Database query failed")
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// isolationProbeTable is the tenant table read by the session isolation probes
+const isolationProbeTable = "tenant_users"
+
+// SessionIsolationProbe is the outcome of scoping one pooled connection to a tenant the way repositories do,
+// then reading from the same connection without a tenant
+type SessionIsolationProbe struct {
+	Pool         string
+	BackendPID   int
+	ScopedRows   int64  // Probe table rows visible inside the tenant transaction
+	ForeignRows  int64  // Of those, rows of other tenants
+	LeakedTenant string // app.current_tenant seen after the transaction ended
+	LeakedRows   int64  // Probe table rows visible after the transaction ended
+	Error        string // The query of the probe that failed, which fails the check
+}
+
+// Isolated reports whether the probe completed, the connection saw only the tenant's rows and kept no tenant
+// afterwards
+func (p SessionIsolationProbe) Isolated() bool {
+	return p.Error == "" && p.ForeignRows == 0 && p.LeakedTenant == "" && p.LeakedRows == 0
+}
+
+// SessionIsolationResult holds the probes of every pool
+type SessionIsolationResult struct {
+	Table      string
+	BypassRole bool // The database role skips row level security, so every probe sees all tenants
+	Probes     []SessionIsolationProbe
+}
+
+// DiagnosticsRepository interface defines the repository methods checking the database setup
+type DiagnosticsRepository interface {
+	CheckSessionIsolation(c context.Context, tenantID uuid.UUID, connections int) (*SessionIsolationResult, error)
+}
+
+// diagnosticsRepository implements DiagnosticsRepository
+type diagnosticsRepository struct {
+	*BaseRepository
+}
+
+// NewDiagnosticsRepository creates a new diagnostics repository
+func NewDiagnosticsRepository(db *database.DatabaseConnections) DiagnosticsRepository {
+	return &diagnosticsRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// CheckSessionIsolation probes the given number of distinct connections of the write and the read pool
+func (r *diagnosticsRepository) CheckSessionIsolation(c context.Context, tenantID uuid.UUID, connections int) (*SessionIsolationResult, error) {
+	repoCtx := r.WithContext(c)
+
+	report, err := r.db.CheckRowLevelSecurity(c)
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "check_row_level_security").
+			Msg("Database query failed")
+		return nil, err
+	}
+
+	result := &SessionIsolationResult{Table: isolationProbeTable, BypassRole: report.BypassRole}
+	pools := []struct {
+		name string
+		db   *gorm.DB
+		opts []*sql.TxOptions
+	}{
+		{"write", r.db.Write, nil},
+		{"read", r.db.Read, []*sql.TxOptions{{ReadOnly: true}}},
+	}
+	for _, pool := range pools {
+		probes, err := r.probeConnections(c, pool.db, tenantID, connections, pool.opts...)
+		if err != nil {
+			repoCtx.logger.Error().
+				Err(err).
+				Str("operation", "check_session_isolation").
+				Str("pool", pool.name).
+				Msg("Database query failed")
+			return nil, err
+		}
+		for i := range probes {
+			probes[i].Pool = pool.name
+		}
+		result.Probes = append(result.Probes, probes...)
+	}
+	return result, nil
+}
+
+// probeConnections probes count connections of the pool. Each connection stays checked out while the next
+// is taken, so the pool hands out a different one every time.
+func (r *diagnosticsRepository) probeConnections(c context.Context, db *gorm.DB, tenantID uuid.UUID, count int, opts ...*sql.TxOptions) ([]SessionIsolationProbe, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+
+	var probes []SessionIsolationProbe
+	err := db.WithContext(c).Connection(func(conn *gorm.DB) error {
+		probe, err := r.probeConnection(c, conn, tenantID, opts...)
+		if err != nil {
+			return err
+		}
+		rest, err := r.probeConnections(c, db, tenantID, count-1, opts...)
+		if err != nil {
+			return err
+		}
+		probes = append([]SessionIsolationProbe{*probe}, rest...)
+		return nil
+	})
+	return probes, err
+}
+
+// probeConnection reads the probe table in a tenant transaction on the connection, then reads it again on the
+// same connection without setting a tenant. A failing query is reported in the probe rather than as an error,
+// since a policy rejecting a connection without a tenant is a finding of the check.
+func (r *diagnosticsRepository) probeConnection(c context.Context, conn *gorm.DB, tenantID uuid.UUID, opts ...*sql.TxOptions) (*SessionIsolationProbe, error) {
+	repoCtx := r.WithContext(c)
+
+	probe := &SessionIsolationProbe{}
+	if err := conn.Raw("SELECT pg_backend_pid()").Scan(&probe.BackendPID).Error; err != nil {
+		return nil, err
+	}
+	if err := r.runProbe(c, conn, tenantID, probe, opts...); err != nil {
+		repoCtx.logger.Warn().
+			Err(err).
+			Str("operation", "probe_session_isolation").
+			Int("backend_pid", probe.BackendPID).
+			Msg("Session isolation probe failed")
+		probe.Error = err.Error()
+	}
+	return probe, nil
+}
+
+// runProbe runs the queries of a probe, filling it in
+func (r *diagnosticsRepository) runProbe(c context.Context, conn *gorm.DB, tenantID uuid.UUID, probe *SessionIsolationProbe, opts ...*sql.TxOptions) error {
+	err := r.withTenantTransaction(c, conn, tenantID, func(tx *gorm.DB) error {
+		if err := tx.Table(isolationProbeTable).Count(&probe.ScopedRows).Error; err != nil {
+			return err
+		}
+		return tx.Table(isolationProbeTable).Where("tenant_id <> ?", tenantID).Count(&probe.ForeignRows).Error
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	// The tenant is transaction-local, so nothing of it may be left on the connection now
+	if err := conn.Raw("SELECT COALESCE(current_setting('app.current_tenant', true), '')").Scan(&probe.LeakedTenant).Error; err != nil {
+		return err
+	}
+	return conn.Table(isolationProbeTable).Count(&probe.LeakedRows).Error
+}
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// EnrollmentRepository interface defines enrollment repository methods
+type EnrollmentRepository interface {
+	Create(c context.Context, enrollment *model.Enrollment) error
+	BulkCreate(c context.Context, tenantID uuid.UUID, enrollments []model.Enrollment) error
+	GetByID(c context.Context, id uuid.UUID) (*model.Enrollment, error)
+	Delete(c context.Context, id uuid.UUID) error
+	Exists(c context.Context, tenantID, studentID, classSubjectID uuid.UUID, academicYearID *uuid.UUID) (bool, error)
+	GetEnrolledStudentIDs(c context.Context, tenantID, classSubjectID uuid.UUID, academicYearID *uuid.UUID) ([]uuid.UUID, error)
+	ListByStudent(c context.Context, tenantID, studentID uuid.UUID, offset, limit int) ([]model.Enrollment, int64, error)
+	ListByClassSubject(c context.Context, tenantID, classSubjectID uuid.UUID, offset, limit int) ([]model.Enrollment, int64, error)
+	GetHistoryByStudent(c context.Context, tenantID, studentID uuid.UUID) ([]model.Enrollment, error)
+}
+
+// enrollmentRepository implements EnrollmentRepository
+type enrollmentRepository struct {
+	*BaseRepository
+}
+
+// NewEnrollmentRepository creates a new enrollment repository
+func NewEnrollmentRepository(db *database.DatabaseConnections) EnrollmentRepository {
+	return &enrollmentRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *enrollmentRepository) Create(c context.Context, enrollment *model.Enrollment) error {
+	repoCtx := r.WithContext(c)
+	err := r.WriteWithTenant(c, enrollment.TenantID, func(db *gorm.DB) error {
+		return db.Create(enrollment).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "create_enrollment").
+			Msg("Database write operation failed")
+	}
+	return err
+}
+
+// BulkCreate inserts all enrollments in a single transaction
+func (r *enrollmentRepository) BulkCreate(c context.Context, tenantID uuid.UUID, enrollments []model.Enrollment) error {
+	repoCtx := r.WithContext(c)
+	if len(enrollments) == 0 {
+		return nil
+	}
+
+	err := r.WriteWithTenant(c, tenantID, func(tx *gorm.DB) error {
+		return tx.CreateInBatches(&enrollments, 100).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "bulk_create_enrollments").
+			Int("count", len(enrollments)).
+			Msg("Database write operation failed")
+	}
+	return err
+}
+
+func (r *enrollmentRepository) GetByID(c context.Context, id uuid.UUID) (*model.Enrollment, error) {
+	repoCtx := r.WithContext(c)
+	var enrollment model.Enrollment
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Preload("Student.TenantUser.User").Preload("ClassSubject.Subject").Preload("AcademicYear").
+			First(&enrollment, id).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("enrollment not found")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("enrollment_id", id.String()).
+			Msg("Database error while getting enrollment by ID")
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+func (r *enrollmentRepository) Delete(c context.Context, id uuid.UUID) error {
+	repoCtx := r.WithContext(c)
+	err := r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Delete(&model.Enrollment{}, id).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "delete_enrollment").
+			Msg("Database write operation failed")
+	}
+	return err
+}
+
+// Exists reports whether the student is already enrolled in the class subject for the academic year
+func (r *enrollmentRepository) Exists(c context.Context, tenantID, studentID, classSubjectID uuid.UUID, academicYearID *uuid.UUID) (bool, error) {
+	repoCtx := r.WithContext(c)
+
+	var count int64
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		query := db.Model(&model.Enrollment{}).
+			Where("tenant_id = ? AND student_id = ? AND class_subject_id = ?", tenantID, studentID, classSubjectID)
+		return whereAcademicYear(query, academicYearID).Count(&count).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "check_enrollment_exists").
+			Msg("Database query failed")
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetEnrolledStudentIDs returns the students already enrolled in the class subject for the academic year
+func (r *enrollmentRepository) GetEnrolledStudentIDs(c context.Context, tenantID, classSubjectID uuid.UUID, academicYearID *uuid.UUID) ([]uuid.UUID, error) {
+	repoCtx := r.WithContext(c)
+
+	var studentIDs []uuid.UUID
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		query := db.Model(&model.Enrollment{}).
+			Where("tenant_id = ? AND class_subject_id = ? AND student_id IS NOT NULL", tenantID, classSubjectID)
+		return whereAcademicYear(query, academicYearID).Pluck("student_id", &studentIDs).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_enrolled_student_ids").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return studentIDs, nil
+}
+
+func (r *enrollmentRepository) ListByStudent(c context.Context, tenantID, studentID uuid.UUID, offset, limit int) ([]model.Enrollment, int64, error) {
+	repoCtx := r.WithContext(c)
+
+	var enrollments []model.Enrollment
+	var total int64
+
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		query := db.Preload("ClassSubject.Class").Preload("ClassSubject.Subject").Preload("AcademicYear").
+			Where("student_id = ? AND tenant_id = ?", studentID, tenantID)
+
+		// Get total count
+		if err := query.Model(&model.Enrollment{}).Count(&total).Error; err != nil {
+			repoCtx.logger.Error().
+				Err(err).
+				Str("operation", "count_enrollments_by_student").
+				Msg("Database query failed")
+			return err
+		}
+
+		// Get paginated results
+		if err := query.Scopes(r.Paginate(offset, limit)).Find(&enrollments).Error; err != nil {
+			repoCtx.logger.Error().
+				Err(err).
+				Str("operation", "list_enrollments_by_student").
+				Msg("Database query failed")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return enrollments, total, nil
+}
+
+func (r *enrollmentRepository) ListByClassSubject(c context.Context, tenantID, classSubjectID uuid.UUID, offset, limit int) ([]model.Enrollment, int64, error) {
+	repoCtx := r.WithContext(c)
+
+	var enrollments []model.Enrollment
+	var total int64
+
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		query := db.Preload("Student.TenantUser.User").Preload("AcademicYear").
+			Where("class_subject_id = ? AND tenant_id = ?", classSubjectID, tenantID)
+
+		// Get total count
+		if err := query.Model(&model.Enrollment{}).Count(&total).Error; err != nil {
+			repoCtx.logger.Error().
+				Err(err).
+				Str("operation", "count_enrollments_by_class_subject").
+				Msg("Database query failed")
+			return err
+		}
+
+		// Get paginated results
+		if err := query.Scopes(r.Paginate(offset, limit)).Find(&enrollments).Error; err != nil {
+			repoCtx.logger.Error().
+				Err(err).
+				Str("operation", "list_enrollments_by_class_subject").
+				Msg("Database query failed")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return enrollments, total, nil
+}
+
+// GetHistoryByStudent returns every enrollment of a student with its academic year, class, subject and teacher,
+// ordered by academic year. The year is joined into the query and each other relation is preloaded in one query
+// with only the columns the history shows.
+func (r *enrollmentRepository) GetHistoryByStudent(c context.Context, tenantID, studentID uuid.UUID) ([]model.Enrollment, error) {
+	repoCtx := r.WithContext(c)
+
+	var enrollments []model.Enrollment
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Joins("AcademicYear").
+			Preload("ClassSubject").
+			Preload("ClassSubject.Class", func(db *gorm.DB) *gorm.DB {
+				return db.Select("id", "tenant_id", "name", "grade_level")
+			}).
+			Preload("ClassSubject.Subject", func(db *gorm.DB) *gorm.DB {
+				return db.Select("id", "tenant_id", "name", "code", "credit")
+			}).
+			Preload("ClassSubject.Teacher", func(db *gorm.DB) *gorm.DB {
+				return db.Select("id", "tenant_id", "tenant_user_id")
+			}).
+			Preload("ClassSubject.Teacher.TenantUser", func(db *gorm.DB) *gorm.DB {
+				return db.Select("id", "tenant_id", "user_id")
+			}).
+			Preload("ClassSubject.Teacher.TenantUser.User", func(db *gorm.DB) *gorm.DB {
+				return db.Select("id", "full_name")
+			}).
+			Where("enrollments.tenant_id = ? AND enrollments.student_id = ?", tenantID, studentID).
+			Order(`"AcademicYear".start_date NULLS LAST, enrollments.id`).
+			Find(&enrollments).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_enrollment_history_by_student").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+// whereAcademicYear matches the academic year, treating a nil ID as an enrollment without a year
+func whereAcademicYear(query *gorm.DB, academicYearID *uuid.UUID) *gorm.DB {
+	if academicYearID == nil {
+		return query.Where("academic_year_id IS NULL")
+	}
+	return query.Where("academic_year_id = ?", *academicYearID)
+}
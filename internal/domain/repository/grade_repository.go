@@ -0,0 +1,346 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// GradebookEntry holds the aggregated grades of one type for one student in one class subject.
+// GradeType is nil for enrollments without grades.
+type GradebookEntry struct {
+	StudentID      uuid.UUID
+	ClassSubjectID uuid.UUID
+	GradeType      *string
+	AverageScore   *float64
+	GradeCount     int64
+}
+
+// StudentGradeEntry holds the aggregated grades of one type for a student in one class subject, with its subject
+type StudentGradeEntry struct {
+	GradebookEntry
+	SubjectName *string
+	SubjectCode *string
+}
+
+// TranscriptGradeEntry holds the aggregated grades of one type for a student in one class subject of an academic year
+type TranscriptGradeEntry struct {
+	StudentGradeEntry
+	AcademicYearID   *uuid.UUID
+	AcademicYearName *string
+	SubjectID        *uuid.UUID
+	Credit           *int
+}
+
+// ClassSubjectGradeEntry holds the aggregated grades of one type for a student enrolled in a class subject
+type ClassSubjectGradeEntry struct {
+	GradebookEntry
+	StudentNumber string
+	FullName      string
+}
+
+// EnrollmentGradeEntry holds the aggregated grades of one type for one enrollment
+type EnrollmentGradeEntry struct {
+	GradebookEntry
+	EnrollmentID uuid.UUID
+}
+
+// PendingGradeEntry holds how many enrolled students of a class subject have no score yet
+type PendingGradeEntry struct {
+	ClassSubjectID uuid.UUID
+	ClassName      *string
+	SubjectName    *string
+	Enrolled       int64
+	Ungraded       int64
+}
+
+// GradeRepository interface defines grade repository methods
+type GradeRepository interface {
+	GetClassGradebook(c context.Context, tenantID, classID, academicYearID uuid.UUID, gradeType string) ([]GradebookEntry, error)
+	GetStudentGrades(c context.Context, tenantID, studentID, academicYearID uuid.UUID, semester *int) ([]StudentGradeEntry, error)
+	GetPendingForTeacher(c context.Context, tenantID, teacherID, academicYearID uuid.UUID) ([]PendingGradeEntry, error)
+	GetStudentEnrollmentGrades(c context.Context, tenantID, studentID uuid.UUID) ([]EnrollmentGradeEntry, error)
+	GetStudentTranscriptGrades(c context.Context, tenantID, studentID uuid.UUID) ([]TranscriptGradeEntry, error)
+	GetClassSubjectGrades(c context.Context, tenantID, classSubjectID uuid.UUID, semester *int) ([]ClassSubjectGradeEntry, error)
+	GetGradingScale(c context.Context, tenantID uuid.UUID) (*model.GradingScale, error)
+	ReplaceGradingScale(c context.Context, tenantID uuid.UUID, bands []model.GradingScaleBand, weights []model.GradeTypeWeight) error
+	GetClassSubjectEnrollments(c context.Context, tenantID, classSubjectID uuid.UUID) ([]model.Enrollment, error)
+	SaveGrades(c context.Context, tenantID uuid.UUID, created, updated []model.Grade) error
+}
+
+// gradeRepository implements GradeRepository
+type gradeRepository struct {
+	*BaseRepository
+}
+
+// NewGradeRepository creates a new grade repository
+func NewGradeRepository(db *database.DatabaseConnections) GradeRepository {
+	return &gradeRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// GetClassGradebook aggregates grades per student, class subject and grade type of a class in one query,
+// joining enrollment -> class_subject -> grade. An empty gradeType includes every grade type.
+func (r *gradeRepository) GetClassGradebook(c context.Context, tenantID, classID, academicYearID uuid.UUID, gradeType string) ([]GradebookEntry, error) {
+	repoCtx := r.WithContext(c)
+
+	var entries []GradebookEntry
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Table("enrollments AS e").
+			Select("e.student_id, e.class_subject_id, g.grade_type, AVG(g.score) AS average_score, COUNT(g.score) AS grade_count").
+			Joins("JOIN class_subjects cs ON cs.id = e.class_subject_id").
+			Joins("LEFT JOIN grades g ON g.enrollment_id = e.id AND (? = '' OR g.grade_type = ?)", gradeType, gradeType).
+			Where("e.tenant_id = ? AND cs.class_id = ? AND e.academic_year_id = ? AND e.student_id IS NOT NULL", tenantID, classID, academicYearID).
+			Group("e.student_id, e.class_subject_id, g.grade_type").
+			Scan(&entries).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_class_gradebook").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetStudentGrades aggregates a student's grades per enrolled class subject and grade type in an academic year,
+// ordered by subject name. With a semester only grades of that semester are included.
+func (r *gradeRepository) GetStudentGrades(c context.Context, tenantID, studentID, academicYearID uuid.UUID, semester *int) ([]StudentGradeEntry, error) {
+	repoCtx := r.WithContext(c)
+
+	var entries []StudentGradeEntry
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Table("enrollments AS e").
+			Select("e.student_id, e.class_subject_id, sub.name AS subject_name, sub.code AS subject_code, g.grade_type, AVG(g.score) AS average_score, COUNT(g.score) AS grade_count").
+			Joins("JOIN class_subjects cs ON cs.id = e.class_subject_id").
+			Joins("LEFT JOIN subjects sub ON sub.id = cs.subject_id").
+			Joins("LEFT JOIN grades g ON g.enrollment_id = e.id AND (?::smallint IS NULL OR g.semester = ?)", semester, semester).
+			Where("e.tenant_id = ? AND e.student_id = ? AND e.academic_year_id = ?", tenantID, studentID, academicYearID).
+			Group("e.student_id, e.class_subject_id, sub.name, sub.code, g.grade_type").
+			Order("sub.name, e.class_subject_id").
+			Scan(&entries).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_student_grades").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetStudentEnrollmentGrades aggregates a student's grades per enrollment and grade type across every academic year.
+// Only enrollments with grades are returned.
+func (r *gradeRepository) GetStudentEnrollmentGrades(c context.Context, tenantID, studentID uuid.UUID) ([]EnrollmentGradeEntry, error) {
+	repoCtx := r.WithContext(c)
+
+	var entries []EnrollmentGradeEntry
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Table("enrollments AS e").
+			Select("e.id AS enrollment_id, e.student_id, e.class_subject_id, g.grade_type, AVG(g.score) AS average_score, COUNT(g.score) AS grade_count").
+			Joins("JOIN grades g ON g.enrollment_id = e.id").
+			Where("e.tenant_id = ? AND e.student_id = ?", tenantID, studentID).
+			Group("e.id, e.student_id, e.class_subject_id, g.grade_type").
+			Scan(&entries).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_student_enrollment_grades").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetStudentTranscriptGrades aggregates a student's grades per academic year, enrolled class subject and grade type
+// in one query, ordered by academic year start and subject name so the entries of a class subject are adjacent
+func (r *gradeRepository) GetStudentTranscriptGrades(c context.Context, tenantID, studentID uuid.UUID) ([]TranscriptGradeEntry, error) {
+	repoCtx := r.WithContext(c)
+
+	var entries []TranscriptGradeEntry
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Table("enrollments AS e").
+			Select("e.student_id, e.class_subject_id, e.academic_year_id, ay.name AS academic_year_name, cs.subject_id, "+
+				"sub.name AS subject_name, sub.code AS subject_code, sub.credit, g.grade_type, AVG(g.score) AS average_score, COUNT(g.score) AS grade_count").
+			Joins("JOIN class_subjects cs ON cs.id = e.class_subject_id").
+			Joins("LEFT JOIN subjects sub ON sub.id = cs.subject_id").
+			Joins("LEFT JOIN academic_years ay ON ay.id = e.academic_year_id").
+			Joins("LEFT JOIN grades g ON g.enrollment_id = e.id").
+			Where("e.tenant_id = ? AND e.student_id = ?", tenantID, studentID).
+			Group("e.student_id, e.class_subject_id, e.academic_year_id, ay.name, ay.start_date, cs.subject_id, sub.name, sub.code, sub.credit, g.grade_type").
+			Order("ay.start_date NULLS LAST, e.academic_year_id, sub.name, e.class_subject_id").
+			Scan(&entries).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_student_transcript_grades").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetClassSubjectGrades aggregates the grades of every student enrolled in a class subject per grade type in one
+// query, ordered by student name. With a semester only grades of that semester are included.
+func (r *gradeRepository) GetClassSubjectGrades(c context.Context, tenantID, classSubjectID uuid.UUID, semester *int) ([]ClassSubjectGradeEntry, error) {
+	repoCtx := r.WithContext(c)
+
+	var entries []ClassSubjectGradeEntry
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Table("enrollments AS e").
+			Select("e.student_id, e.class_subject_id, s.student_number, u.full_name, g.grade_type, AVG(g.score) AS average_score, COUNT(g.score) AS grade_count").
+			Joins("JOIN students s ON s.id = e.student_id").
+			Joins("JOIN tenant_users tu ON tu.id = s.tenant_user_id").
+			Joins("JOIN users u ON u.id = tu.user_id").
+			Joins("LEFT JOIN grades g ON g.enrollment_id = e.id AND (?::smallint IS NULL OR g.semester = ?)", semester, semester).
+			Where("e.tenant_id = ? AND e.class_subject_id = ?", tenantID, classSubjectID).
+			Group("e.student_id, e.class_subject_id, s.student_number, u.full_name, g.grade_type").
+			Order("u.full_name, s.student_number, e.student_id").
+			Scan(&entries).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_class_subject_grades").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetPendingForTeacher counts per class subject taught by the teacher the students enrolled in the academic year
+// and those without any score yet, returning only class subjects with ungraded students ordered by class and subject
+func (r *gradeRepository) GetPendingForTeacher(c context.Context, tenantID, teacherID, academicYearID uuid.UUID) ([]PendingGradeEntry, error) {
+	repoCtx := r.WithContext(c)
+
+	const ungraded = "COUNT(*) FILTER (WHERE NOT EXISTS (SELECT 1 FROM grades g WHERE g.enrollment_id = e.id AND g.score IS NOT NULL))"
+	var entries []PendingGradeEntry
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Table("class_subjects AS cs").
+			Select("cs.id AS class_subject_id, cl.name AS class_name, sub.name AS subject_name, COUNT(*) AS enrolled, "+ungraded+" AS ungraded").
+			Joins("JOIN enrollments e ON e.class_subject_id = cs.id AND e.academic_year_id = ? AND e.student_id IS NOT NULL", academicYearID).
+			Joins("LEFT JOIN classes cl ON cl.id = cs.class_id").
+			Joins("LEFT JOIN subjects sub ON sub.id = cs.subject_id").
+			Where("cs.tenant_id = ? AND cs.teacher_id = ?", tenantID, teacherID).
+			Group("cs.id, cl.name, sub.name").
+			Having(ungraded + " > 0").
+			Order("cl.name, sub.name").
+			Scan(&entries).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_pending_grades_for_teacher").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetClassSubjectEnrollments returns the student enrollments of a class subject with their grades
+func (r *gradeRepository) GetClassSubjectEnrollments(c context.Context, tenantID, classSubjectID uuid.UUID) ([]model.Enrollment, error) {
+	repoCtx := r.WithContext(c)
+
+	var enrollments []model.Enrollment
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Preload("Grades").
+			Where("tenant_id = ? AND class_subject_id = ? AND student_id IS NOT NULL", tenantID, classSubjectID).
+			Find(&enrollments).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_class_subject_enrollments").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return enrollments, nil
+}
+
+// SaveGrades inserts the created grades and updates the score and remarks of the updated ones in one transaction
+func (r *gradeRepository) SaveGrades(c context.Context, tenantID uuid.UUID, created, updated []model.Grade) error {
+	repoCtx := r.WithContext(c)
+
+	err := r.WriteWithTenant(c, tenantID, func(db *gorm.DB) error {
+		if len(created) > 0 {
+			if err := db.Create(&created).Error; err != nil {
+				return err
+			}
+		}
+		for i := range updated {
+			err := db.Model(&updated[i]).
+				Where("tenant_id = ?", tenantID).
+				Select("score", "remarks").
+				Updates(&updated[i]).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "save_grades").
+			Int("created", len(created)).
+			Int("updated", len(updated)).
+			Msg("Database write operation failed")
+	}
+	return err
+}
+
+// GetGradingScale returns the tenant's grading scale, using the defaults for bands or weights it has not configured
+func (r *gradeRepository) GetGradingScale(c context.Context, tenantID uuid.UUID) (*model.GradingScale, error) {
+	repoCtx := r.WithContext(c)
+
+	var bands []model.GradingScaleBand
+	var weights []model.GradeTypeWeight
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		if err := db.Where("tenant_id = ?", tenantID).Find(&bands).Error; err != nil {
+			return err
+		}
+		return db.Where("tenant_id = ?", tenantID).Find(&weights).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_grading_scale").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return model.NewGradingScale(bands, weights), nil
+}
+
+// ReplaceGradingScale replaces the tenant's bands and weights in one transaction
+func (r *gradeRepository) ReplaceGradingScale(c context.Context, tenantID uuid.UUID, bands []model.GradingScaleBand, weights []model.GradeTypeWeight) error {
+	repoCtx := r.WithContext(c)
+
+	err := r.WriteWithTenant(c, tenantID, func(db *gorm.DB) error {
+		if err := db.Where("tenant_id = ?", tenantID).Delete(&model.GradingScaleBand{}).Error; err != nil {
+			return err
+		}
+		if err := db.Where("tenant_id = ?", tenantID).Delete(&model.GradeTypeWeight{}).Error; err != nil {
+			return err
+		}
+		if err := db.Create(&bands).Error; err != nil {
+			return err
+		}
+		return db.Create(&weights).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "replace_grading_scale").
+			Msg("Database query failed")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationRepository interface defines notification repository methods
+type NotificationRepository interface {
+	Create(c context.Context, notification *model.Notification) error
+	GetPreferences(c context.Context, userID uuid.UUID) ([]model.NotificationPreference, error)
+	SavePreferences(c context.Context, preferences []model.NotificationPreference) error
+}
+
+// notificationRepository implements NotificationRepository
+type notificationRepository struct {
+	*BaseRepository
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *database.DatabaseConnections) NotificationRepository {
+	return &notificationRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create stores an in-app notification
+func (r *notificationRepository) Create(c context.Context, notification *model.Notification) error {
+	repoCtx := r.WithContext(c)
+
+	err := r.WriteWithTenant(c, notification.TenantID, func(db *gorm.DB) error {
+		return db.Create(notification).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "create_notification").
+			Msg("Database query failed")
+		return err
+	}
+	return nil
+}
+
+// GetPreferences returns the channel preferences the user has set, which are global to the user
+func (r *notificationRepository) GetPreferences(c context.Context, userID uuid.UUID) ([]model.NotificationPreference, error) {
+	repoCtx := r.WithContext(c)
+
+	var preferences []model.NotificationPreference
+	err := r.ReadWithTenant(c, uuid.Nil, func(db *gorm.DB) error {
+		return db.Where("user_id = ?", userID).Order("notification_type").Find(&preferences).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_notification_preferences").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return preferences, nil
+}
+
+// SavePreferences inserts or replaces channel preferences in one transaction
+func (r *notificationRepository) SavePreferences(c context.Context, preferences []model.NotificationPreference) error {
+	repoCtx := r.WithContext(c)
+
+	err := r.WriteWithTenant(c, uuid.Nil, func(db *gorm.DB) error {
+		return db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "notification_type"}},
+			DoUpdates: clause.AssignmentColumns([]string{"email", "sms", "updated_at"}),
+		}).Create(&preferences).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "save_notification_preferences").
+			Msg("Database query failed")
+		return err
+	}
+	return nil
+}
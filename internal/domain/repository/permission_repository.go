@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// PermissionRepository interface defines role permission repository methods
+type PermissionRepository interface {
+	HasPermission(c context.Context, tenantID, userID uuid.UUID, permission string) (bool, error)
+	ListForUser(c context.Context, tenantID, userID uuid.UUID) ([]string, error)
+}
+
+// permissionRepository implements PermissionRepository
+type permissionRepository struct {
+	*BaseRepository
+}
+
+// NewPermissionRepository creates a new permission repository
+func NewPermissionRepository(db *database.DatabaseConnections) PermissionRepository {
+	return &permissionRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// HasPermission reports whether any role of the user's active membership in the tenant is granted the permission
+func (r *permissionRepository) HasPermission(c context.Context, tenantID, userID uuid.UUID, permission string) (bool, error) {
+	repoCtx := r.WithContext(c)
+
+	var granted bool
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Raw(`SELECT EXISTS (
+				SELECT 1
+				FROM role_permissions rp
+				JOIN tenant_user_roles tur ON tur.role_id = rp.role_id
+				JOIN tenant_users tu ON tu.id = tur.tenant_user_id
+				WHERE rp.tenant_id = ? AND rp.permission = ?
+					AND tu.tenant_id = ? AND tu.user_id = ? AND tu.is_active = true
+			)`, tenantID, permission, tenantID, userID).
+			Scan(&granted).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "has_permission").
+			Str("permission", permission).
+			Msg("Database query failed")
+		return false, err
+	}
+	return granted, nil
+}
+
+// ListForUser returns the distinct permissions granted to the roles of the user's active membership in the tenant
+func (r *permissionRepository) ListForUser(c context.Context, tenantID, userID uuid.UUID) ([]string, error) {
+	repoCtx := r.WithContext(c)
+
+	permissions := []string{}
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Raw(`SELECT DISTINCT rp.permission
+			FROM role_permissions rp
+			JOIN tenant_user_roles tur ON tur.role_id = rp.role_id
+			JOIN tenant_users tu ON tu.id = tur.tenant_user_id
+			WHERE rp.tenant_id = ? AND tu.tenant_id = ? AND tu.user_id = ? AND tu.is_active = true
+			ORDER BY rp.permission`, tenantID, tenantID, userID).
+			Scan(&permissions).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "list_user_permissions").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return permissions, nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
 	"github.com/protocyber/kelasgo-api/internal/domain/model"
 	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
 	"gorm.io/gorm"
@@ -14,13 +15,14 @@ import (
 type RoleRepository interface {
 	Create(ctx context.Context, role *model.Role) error
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Role, error)
-	GetByName(ctx context.Context, name string, tenantID uuid.UUID) (*model.Role, error)
+	GetByName(ctx context.Context, name string) (*model.Role, error)
 	Update(ctx context.Context, role *model.Role) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context, tenantID uuid.UUID, offset, limit int, search string) ([]model.Role, int64, error)
+	List(ctx context.Context, offset, limit int, search string) ([]model.Role, int64, error)
 }
 
-// roleRepository implements RoleRepository
+// roleRepository implements RoleRepository. Roles are global, so its transactions are only scoped to the tenant
+// of the request for the audit trail.
 type roleRepository struct {
 	*BaseRepository
 }
@@ -34,10 +36,9 @@ func NewRoleRepository(db *database.DatabaseConnections) RoleRepository {
 
 func (r *roleRepository) Create(c context.Context, role *model.Role) error {
 	repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(role.TenantID); err != nil {
-		return err
-	}
-	err := r.db.Write.Create(role).Error
+	err := r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Create(role).Error
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
@@ -50,10 +51,12 @@ func (r *roleRepository) Create(c context.Context, role *model.Role) error {
 func (r *roleRepository) GetByID(c context.Context, id uuid.UUID) (*model.Role, error) {
 	repoCtx := r.WithContext(c)
 	var role model.Role
-	err := r.db.Read.First(&role, id).Error
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.First(&role, id).Error
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("role not found")
+			return nil, apperror.NotFound("role not found")
 		}
 		repoCtx.logger.Error().
 			Err(err).
@@ -64,17 +67,15 @@ func (r *roleRepository) GetByID(c context.Context, id uuid.UUID) (*model.Role,
 	return &role, nil
 }
 
-func (r *roleRepository) GetByName(c context.Context, name string, tenantID uuid.UUID) (*model.Role, error) {
+func (r *roleRepository) GetByName(c context.Context, name string) (*model.Role, error) {
 	// repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(tenantID); err != nil {
-		return nil, err
-	}
-
 	var role model.Role
-	err := r.db.Read.Where("name = ? AND tenant_id = ?", name, tenantID).First(&role).Error
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Where("name = ?", name).First(&role).Error
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("role not found")
+			return nil, apperror.NotFound("role not found")
 		}
 		return nil, err
 	}
@@ -83,39 +84,41 @@ func (r *roleRepository) GetByName(c context.Context, name string, tenantID uuid
 
 func (r *roleRepository) Update(c context.Context, role *model.Role) error {
 	// repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(role.TenantID); err != nil {
-		return err
-	}
-	return r.db.Write.Save(role).Error
+	return r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Save(role).Error
+	})
 }
 
 func (r *roleRepository) Delete(c context.Context, id uuid.UUID) error {
 	// repoCtx := r.WithContext(c)
-	return r.db.Write.Delete(&model.Role{}, id).Error
+	return r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Delete(&model.Role{}, id).Error
+	})
 }
 
-func (r *roleRepository) List(c context.Context, tenantID uuid.UUID, offset, limit int, search string) ([]model.Role, int64, error) {
+func (r *roleRepository) List(c context.Context, offset, limit int, search string) ([]model.Role, int64, error) {
 	// repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(tenantID); err != nil {
-		return nil, 0, err
-	}
-
 	var roles []model.Role
 	var total int64
 
-	query := r.db.Read.Model(&model.Role{}).Where("tenant_id = ?", tenantID)
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		query := db.Model(&model.Role{})
 
-	if search != "" {
-		query = query.Where("name ILIKE ? OR description ILIKE ?",
-			"%"+search+"%", "%"+search+"%")
-	}
+		if search != "" {
+			query = query.Where("name ILIKE ? OR description ILIKE ?",
+				"%"+search+"%", "%"+search+"%")
+		}
 
-	// Get total count
-	if err := query.Count(&total).Error; err != nil {
+		// Get total count
+		if err := query.Count(&total).Error; err != nil {
+			return err
+		}
+
+		// Get paginated results
+		return query.Scopes(r.Paginate(offset, limit)).Find(&roles).Error
+	})
+	if err != nil {
 		return nil, 0, err
 	}
-
-	// Get paginated results
-	err := query.Offset(offset).Limit(limit).Find(&roles).Error
-	return roles, total, err
+	return roles, total, nil
 }
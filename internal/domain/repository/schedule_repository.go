@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// TeacherScheduleEntry is a weekly schedule row of a class subject taught by a teacher. The day and
+// times are nil when the schedule has not been given them yet.
+type TeacherScheduleEntry struct {
+	ScheduleID     uuid.UUID
+	ClassSubjectID uuid.UUID
+	ClassID        *uuid.UUID
+	ClassName      *string
+	SubjectID      *uuid.UUID
+	SubjectName    *string
+	DayOfWeek      *model.DayOfWeek
+	StartTime      *string // HH:MM
+	EndTime        *string // HH:MM
+	Room           *string
+}
+
+// ScheduleRepository interface defines schedule repository methods
+type ScheduleRepository interface {
+	GetByID(c context.Context, tenantID, id uuid.UUID) (*model.Schedule, error)
+	IsScheduledForStudent(c context.Context, tenantID, scheduleID, studentID uuid.UUID) (bool, error)
+	CountSessionsForStudent(c context.Context, tenantID, studentID uuid.UUID, from, to time.Time) (int64, error)
+	ListForTeacher(c context.Context, tenantID, teacherID uuid.UUID) ([]TeacherScheduleEntry, error)
+}
+
+// scheduleRepository implements ScheduleRepository
+type scheduleRepository struct {
+	*BaseRepository
+}
+
+// NewScheduleRepository creates a new schedule repository
+func NewScheduleRepository(db *database.DatabaseConnections) ScheduleRepository {
+	return &scheduleRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// GetByID returns a schedule of the tenant
+func (r *scheduleRepository) GetByID(c context.Context, tenantID, id uuid.UUID) (*model.Schedule, error) {
+	repoCtx := r.WithContext(c)
+
+	var schedule model.Schedule
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Where("id = ? AND tenant_id = ?", id, tenantID).First(&schedule).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("schedule not found")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_schedule_by_id").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// IsScheduledForStudent reports whether the schedule belongs to a subject of the student's class
+// or to a class subject the student is enrolled in
+func (r *scheduleRepository) IsScheduledForStudent(c context.Context, tenantID, scheduleID, studentID uuid.UUID) (bool, error) {
+	repoCtx := r.WithContext(c)
+
+	var scheduled bool
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Raw(`SELECT EXISTS (
+				SELECT 1
+				FROM schedules s
+				JOIN class_subjects cs ON cs.id = s.class_subject_id
+				WHERE s.id = ? AND s.tenant_id = ?
+					AND (
+						cs.class_id = (SELECT class_id FROM students WHERE id = ?)
+						OR cs.id IN (SELECT class_subject_id FROM enrollments WHERE student_id = ?)
+					)
+			)`, scheduleID, tenantID, studentID, studentID).
+			Scan(&scheduled).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "is_scheduled_for_student").
+			Msg("Database query failed")
+		return false, err
+	}
+	return scheduled, nil
+}
+
+// CountSessionsForStudent counts the weekly schedule occurrences within the date range for the
+// subjects of the student's class and the class subjects the student is enrolled in
+func (r *scheduleRepository) CountSessionsForStudent(c context.Context, tenantID, studentID uuid.UUID, from, to time.Time) (int64, error) {
+	repoCtx := r.WithContext(c)
+
+	// day_of_week_enum is ordered senin..minggu, which matches ISO day numbers 1..7
+	var sessions int64
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Raw(`
+			SELECT COUNT(*)
+			FROM generate_series(?::date, ?::date, interval '1 day') AS d(day)
+			JOIN schedules s
+				ON array_position(enum_range(NULL::day_of_week_enum), s.day_of_week) = EXTRACT(ISODOW FROM d.day)
+			JOIN class_subjects cs ON cs.id = s.class_subject_id
+			WHERE s.tenant_id = ?
+				AND (
+					cs.class_id = (SELECT class_id FROM students WHERE id = ?)
+					OR cs.id IN (SELECT class_subject_id FROM enrollments WHERE student_id = ?)
+				)`,
+			from, to, tenantID, studentID, studentID,
+		).Scan(&sessions).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "count_student_scheduled_sessions").
+			Msg("Database query failed")
+		return 0, err
+	}
+	return sessions, nil
+}
+
+// ListForTeacher returns the weekly schedule of every class subject taught by the teacher,
+// ordered by day and start time
+func (r *scheduleRepository) ListForTeacher(c context.Context, tenantID, teacherID uuid.UUID) ([]TeacherScheduleEntry, error) {
+	repoCtx := r.WithContext(c)
+
+	var entries []TeacherScheduleEntry
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Raw(`
+			SELECT
+				s.id AS schedule_id,
+				cs.id AS class_subject_id,
+				cs.class_id,
+				cl.name AS class_name,
+				cs.subject_id,
+				sub.name AS subject_name,
+				s.day_of_week,
+				to_char(s.start_time, 'HH24:MI') AS start_time,
+				to_char(s.end_time, 'HH24:MI') AS end_time,
+				s.room
+			FROM schedules s
+			JOIN class_subjects cs ON cs.id = s.class_subject_id
+			LEFT JOIN classes cl ON cl.id = cs.class_id
+			LEFT JOIN subjects sub ON sub.id = cs.subject_id
+			WHERE s.tenant_id = ? AND cs.teacher_id = ?
+			ORDER BY array_position(enum_range(NULL::day_of_week_enum), s.day_of_week), s.start_time, s.end_time`,
+			tenantID, teacherID,
+		).Scan(&entries).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "list_teacher_schedule").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return entries, nil
+}
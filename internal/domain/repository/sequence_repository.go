@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SequenceRepository interface defines the repository methods for named per-tenant counters
+type SequenceRepository interface {
+	NextVal(c context.Context, tenantID uuid.UUID, name string) (int64, error)
+}
+
+// sequenceRepository implements SequenceRepository
+type sequenceRepository struct {
+	*BaseRepository
+}
+
+// NewSequenceRepository creates a new sequence repository
+func NewSequenceRepository(db *database.DatabaseConnections) SequenceRepository {
+	return &sequenceRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// NextVal increments the tenant's named sequence and returns the new value, starting at 1. The sequence row
+// stays locked until the transaction ends, so concurrent callers wait instead of getting the same value.
+// Called inside WithTransaction, the lock is held until the outer transaction commits, so an identifier
+// generated from the value can be inserted before anyone else takes the next one.
+func (r *sequenceRepository) NextVal(c context.Context, tenantID uuid.UUID, name string) (int64, error) {
+	repoCtx := r.WithContext(c)
+
+	sequence := model.Sequence{TenantID: tenantID, Name: name}
+	err := r.WriteWithTenant(c, tenantID, func(tx *gorm.DB) error {
+		// Create the row on first use so there is always a row to lock
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&sequence).Error; err != nil {
+			return err
+		}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("tenant_id = ? AND name = ?", tenantID, name).
+			First(&sequence).Error
+		if err != nil {
+			return err
+		}
+
+		sequence.CurrentValue++
+		return tx.Model(&model.Sequence{}).
+			Where("tenant_id = ? AND name = ?", tenantID, name).
+			Update("current_value", sequence.CurrentValue).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "next_sequence_value").
+			Str("sequence", name).
+			Msg("Database write operation failed")
+		return 0, err
+	}
+	return sequence.CurrentValue, nil
+}
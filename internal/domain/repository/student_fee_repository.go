@@ -0,0 +1,363 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FeeAggregate holds the aggregated student fees of one fee type and status
+type FeeAggregate struct {
+	FeeTypeID     *uuid.UUID
+	FeeTypeName   *string
+	Status        model.FeeStatus
+	FeeCount      int64
+	TotalAmount   float64
+	OverdueAmount float64
+	// PaidAmount is what has been paid toward fees that are not fully paid yet
+	PaidAmount    float64
+	PenaltyAmount float64
+}
+
+// OutstandingStudentFees holds the outstanding fee totals of one student
+type OutstandingStudentFees struct {
+	StudentID        uuid.UUID
+	StudentNumber    string
+	FullName         *string
+	FeeCount         int64
+	TotalOutstanding float64
+	TotalPenalty     float64
+	OldestDueDate    time.Time
+}
+
+// feePenaltySQL is the late penalty a fee of student_fees sf with fee_types ft owes on the date given as
+// its parameter, the same as model.FeeType.PenaltyFor
+const feePenaltySQL = `CASE WHEN sf.status <> 'paid' AND sf.due_date < ? THEN
+	CASE ft.penalty_type WHEN 'flat' THEN ft.penalty_value WHEN 'percentage' THEN ROUND(sf.amount * ft.penalty_value / 100, 2) ELSE 0 END
+	ELSE 0 END`
+
+// feePaidSQL is what has been paid toward a fee of student_fees sf
+const feePaidSQL = `COALESCE((SELECT SUM(fp.amount) FROM fee_payments fp WHERE fp.student_fee_id = sf.id), 0)`
+
+// outstandingFeeSortColumns maps accepted sort fields to their SQL expressions
+var outstandingFeeSortColumns = map[string]string{
+	"total_outstanding": "total_outstanding",
+	"oldest_due_date":   "oldest_due_date",
+	"student_number":    "s.student_number",
+	"full_name":         "u.full_name",
+}
+
+// StudentFeeRepository interface defines student fee repository methods
+type StudentFeeRepository interface {
+	AggregateByTypeAndStatus(c context.Context, tenantID uuid.UUID, today time.Time, academicYearID, feeTypeID, studentID *uuid.UUID) ([]FeeAggregate, error)
+	ListOutstandingByStudent(c context.Context, tenantID uuid.UUID, today time.Time, academicYearID, feeTypeID *uuid.UUID, offset, limit int, sortBy string, sortDesc bool) ([]OutstandingStudentFees, int64, error)
+	GetFeeType(c context.Context, tenantID, id uuid.UUID) (*model.FeeType, error)
+	UpdateFeeTypePenalty(c context.Context, feeType *model.FeeType) error
+	GetByID(c context.Context, tenantID, id uuid.UUID) (*model.StudentFee, error)
+	GetForUpdate(c context.Context, tenantID, id uuid.UUID) (*model.StudentFee, error)
+	ListPayments(c context.Context, tenantID, feeID uuid.UUID) ([]model.FeePayment, error)
+	AddPayment(c context.Context, fee *model.StudentFee, payment *model.FeePayment) error
+	GetReceipt(c context.Context, tenantID, feeID uuid.UUID) (*model.FeeReceipt, error)
+	CreateReceipt(c context.Context, receipt *model.FeeReceipt) error
+	WithTransaction(c context.Context, tenantID uuid.UUID, fn func(txCtx context.Context) error) error
+}
+
+// studentFeeRepository implements StudentFeeRepository
+type studentFeeRepository struct {
+	*BaseRepository
+}
+
+// NewStudentFeeRepository creates a new student fee repository
+func NewStudentFeeRepository(db *database.DatabaseConnections) StudentFeeRepository {
+	return &studentFeeRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// AggregateByTypeAndStatus sums student fees per fee type and status. A fee is overdue when it is
+// marked overdue or is still unpaid after its due date. Penalties are those owed today.
+func (r *studentFeeRepository) AggregateByTypeAndStatus(c context.Context, tenantID uuid.UUID, today time.Time, academicYearID, feeTypeID, studentID *uuid.UUID) ([]FeeAggregate, error) {
+	repoCtx := r.WithContext(c)
+
+	var aggregates []FeeAggregate
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		query := db.Table("student_fees AS sf").
+			Select(`sf.fee_type_id, ft.name AS fee_type_name, sf.status,
+				COUNT(*) AS fee_count,
+				COALESCE(SUM(sf.amount), 0) AS total_amount,
+				COALESCE(SUM(CASE WHEN sf.status = 'overdue' OR (sf.status <> 'paid' AND sf.due_date < CURRENT_DATE) THEN sf.amount ELSE 0 END), 0) AS overdue_amount,
+				COALESCE(SUM(CASE WHEN sf.status <> 'paid' THEN `+feePaidSQL+` END), 0) AS paid_amount,
+				COALESCE(SUM(`+feePenaltySQL+`), 0) AS penalty_amount`, today).
+			Joins("LEFT JOIN fee_types ft ON ft.id = sf.fee_type_id").
+			Where("sf.tenant_id = ?", tenantID)
+
+		if academicYearID != nil {
+			query = query.Where("sf.academic_year_id = ?", *academicYearID)
+		}
+		if feeTypeID != nil {
+			query = query.Where("sf.fee_type_id = ?", *feeTypeID)
+		}
+		if studentID != nil {
+			query = query.Where("sf.student_id = ?", *studentID)
+		}
+
+		return query.Group("sf.fee_type_id, ft.name, sf.status").
+			Order("ft.name ASC").
+			Scan(&aggregates).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "aggregate_student_fees").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return aggregates, nil
+}
+
+// ListOutstandingByStudent aggregates unpaid, partial, and overdue fees per student. The outstanding total is
+// what remains after payments plus the penalties owed today. Unknown sort fields fall back to the total
+// outstanding amount.
+func (r *studentFeeRepository) ListOutstandingByStudent(c context.Context, tenantID uuid.UUID, today time.Time, academicYearID, feeTypeID *uuid.UUID, offset, limit int, sortBy string, sortDesc bool) ([]OutstandingStudentFees, int64, error) {
+	repoCtx := r.WithContext(c)
+
+	var results []OutstandingStudentFees
+	var total int64
+
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		outstanding := []model.FeeStatus{model.FeeStatusUnpaid, model.FeeStatusPartial, model.FeeStatusOverdue}
+		query := db.Table("student_fees AS sf").
+			Joins("JOIN students s ON s.id = sf.student_id").
+			Where("sf.tenant_id = ? AND sf.status IN ?", tenantID, outstanding)
+
+		if academicYearID != nil {
+			query = query.Where("sf.academic_year_id = ?", *academicYearID)
+		}
+		if feeTypeID != nil {
+			query = query.Where("sf.fee_type_id = ?", *feeTypeID)
+		}
+
+		// Get total count of students with outstanding fees
+		if err := query.Session(&gorm.Session{}).Distinct("sf.student_id").Count(&total).Error; err != nil {
+			repoCtx.logger.Error().
+				Err(err).
+				Str("operation", "count_students_with_outstanding_fees").
+				Msg("Database query failed")
+			return err
+		}
+
+		sortColumn, ok := outstandingFeeSortColumns[sortBy]
+		if !ok {
+			sortColumn = outstandingFeeSortColumns["total_outstanding"]
+		}
+		sortDir := "ASC"
+		if sortDesc {
+			sortDir = "DESC"
+		}
+
+		// Get paginated results
+		err := query.
+			Select(`s.id AS student_id, s.student_number, u.full_name,
+				COUNT(sf.id) AS fee_count,
+				SUM(sf.amount - `+feePaidSQL+` + `+feePenaltySQL+`) AS total_outstanding,
+				SUM(`+feePenaltySQL+`) AS total_penalty,
+				MIN(sf.due_date) AS oldest_due_date`, today, today).
+			Joins("LEFT JOIN fee_types ft ON ft.id = sf.fee_type_id").
+			Joins("LEFT JOIN tenant_users tu ON tu.id = s.tenant_user_id").
+			Joins("LEFT JOIN users u ON u.id = tu.user_id").
+			Group("s.id, s.student_number, u.full_name").
+			Order(sortColumn + " " + sortDir + ", s.id").
+			Scopes(r.Paginate(offset, limit)).
+			Scan(&results).Error
+		if err != nil {
+			repoCtx.logger.Error().
+				Err(err).
+				Str("operation", "list_outstanding_fees_by_student").
+				Msg("Database query failed")
+		}
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return results, total, nil
+}
+
+// GetByID returns the tenant's fee with its student, fee type and academic year
+func (r *studentFeeRepository) GetByID(c context.Context, tenantID, id uuid.UUID) (*model.StudentFee, error) {
+	repoCtx := r.WithContext(c)
+
+	var fee model.StudentFee
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Preload("Student.TenantUser.User").Preload("FeeType").Preload("AcademicYear").
+			Where("id = ? AND tenant_id = ?", id, tenantID).
+			First(&fee).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("fee not found")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_student_fee").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return &fee, nil
+}
+
+// GetFeeType returns the tenant's fee type
+func (r *studentFeeRepository) GetFeeType(c context.Context, tenantID, id uuid.UUID) (*model.FeeType, error) {
+	repoCtx := r.WithContext(c)
+
+	var feeType model.FeeType
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Where("id = ? AND tenant_id = ?", id, tenantID).First(&feeType).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("fee type not found")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_fee_type").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return &feeType, nil
+}
+
+// UpdateFeeTypePenalty saves the late penalty of the fee type
+func (r *studentFeeRepository) UpdateFeeTypePenalty(c context.Context, feeType *model.FeeType) error {
+	repoCtx := r.WithContext(c)
+
+	err := r.WriteWithTenant(c, feeType.TenantID, func(db *gorm.DB) error {
+		return db.Model(&model.FeeType{}).
+			Where("id = ? AND tenant_id = ?", feeType.ID, feeType.TenantID).
+			Updates(map[string]interface{}{"penalty_type": feeType.PenaltyType, "penalty_value": feeType.PenaltyValue}).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "update_fee_type_penalty").
+			Msg("Database write operation failed")
+	}
+	return err
+}
+
+// GetForUpdate returns the tenant's fee and locks it until the transaction ends, so payments toward it are
+// recorded one at a time. It is meant to be called inside WithTransaction.
+func (r *studentFeeRepository) GetForUpdate(c context.Context, tenantID, id uuid.UUID) (*model.StudentFee, error) {
+	repoCtx := r.WithContext(c)
+
+	var fee model.StudentFee
+	err := r.WriteWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("FeeType").
+			Where("id = ? AND tenant_id = ?", id, tenantID).
+			First(&fee).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("fee not found")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "lock_student_fee").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return &fee, nil
+}
+
+// ListPayments returns the payments made toward the fee, oldest first
+func (r *studentFeeRepository) ListPayments(c context.Context, tenantID, feeID uuid.UUID) ([]model.FeePayment, error) {
+	repoCtx := r.WithContext(c)
+
+	var payments []model.FeePayment
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Where("student_fee_id = ? AND tenant_id = ?", feeID, tenantID).
+			Order("payment_date, created_at").
+			Find(&payments).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "list_fee_payments").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return payments, nil
+}
+
+// AddPayment stores the payment and the fee's new status, payment date and method together
+func (r *studentFeeRepository) AddPayment(c context.Context, fee *model.StudentFee, payment *model.FeePayment) error {
+	repoCtx := r.WithContext(c)
+
+	err := r.WriteWithTenant(c, fee.TenantID, func(db *gorm.DB) error {
+		if err := db.Create(payment).Error; err != nil {
+			return err
+		}
+		return db.Model(&model.StudentFee{}).
+			Where("id = ? AND tenant_id = ?", fee.ID, fee.TenantID).
+			Updates(map[string]interface{}{
+				"status":         fee.Status,
+				"payment_date":   fee.PaymentDate,
+				"payment_method": fee.PaymentMethod,
+			}).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "add_fee_payment").
+			Msg("Database write operation failed")
+	}
+	return err
+}
+
+// GetReceipt returns the receipt issued for the fee, or nil when none has been issued
+func (r *studentFeeRepository) GetReceipt(c context.Context, tenantID, feeID uuid.UUID) (*model.FeeReceipt, error) {
+	repoCtx := r.WithContext(c)
+
+	var receipts []model.FeeReceipt
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Where("student_fee_id = ? AND tenant_id = ?", feeID, tenantID).Limit(1).Find(&receipts).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_fee_receipt").
+			Msg("Database query failed")
+		return nil, err
+	}
+	if len(receipts) == 0 {
+		return nil, nil
+	}
+	return &receipts[0], nil
+}
+
+// CreateReceipt stores an issued receipt. A second receipt for the same fee is a conflict.
+func (r *studentFeeRepository) CreateReceipt(c context.Context, receipt *model.FeeReceipt) error {
+	repoCtx := r.WithContext(c)
+
+	err := r.WriteWithTenant(c, receipt.TenantID, func(db *gorm.DB) error {
+		return db.Create(receipt).Error
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return apperror.Conflict("a receipt has already been issued for this fee")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "create_fee_receipt").
+			Msg("Database write operation failed")
+	}
+	return err
+}
@@ -3,13 +3,70 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
 	"github.com/protocyber/kelasgo-api/internal/domain/model"
 	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// maxStudentNumberAttempts bounds how many suffixed student numbers a transfer tries
+const maxStudentNumberAttempts = 20
+
+// studentPreloads maps the expandable relations of a student to their preload paths
+var studentPreloads = map[string]string{
+	"tenant_user": "TenantUser.User",
+	"class":       "Class",
+	"parent":      "Parent",
+	"guardians":   "Guardians.Parent",
+}
+
+// studentSortColumns maps accepted sort fields to their columns
+var studentSortColumns = map[string]string{
+	"student_number": "students.student_number",
+	"admission_date": "students.admission_date",
+	"created_at":     "students.created_at",
+	"updated_at":     "students.updated_at",
+}
+
+// StudentFilter holds the optional filters of a student listing; every set field narrows the result,
+// except Expand which names the relations to preload
+type StudentFilter struct {
+	Expand        []string
+	IDs           []uuid.UUID
+	Search        string
+	ClassID       *uuid.UUID
+	ParentID      *uuid.UUID
+	AdmissionFrom *time.Time
+	AdmissionTo   *time.Time
+	// EnrollmentStatus is "enrolled" or "not_enrolled" depending on existing enrollment records
+	EnrollmentStatus string
+	// Status is a model.StudentStatus; empty matches every status
+	Status       string
+	CreatedAfter *time.Time
+	UpdatedAfter *time.Time
+	// SortBy is one of the studentSortColumns keys; unknown values leave the order unspecified
+	SortBy   string
+	SortDesc bool
+}
+
+// StudentTransfer describes a copy of a student into another tenant
+type StudentTransfer struct {
+	Source              *model.Student
+	DestinationTenantID uuid.UUID
+	StudentNumber       string
+	AdmissionDate       time.Time
+	// TenantUserID reuses an existing tenant user of the destination tenant when set
+	TenantUserID     *uuid.UUID
+	RoleIDs          []uuid.UUID
+	DeactivateSource bool
+}
+
 // StudentRepository interface defines student repository methods
 type StudentRepository interface {
 	Create(c context.Context, student *model.Student) error
@@ -17,11 +74,23 @@ type StudentRepository interface {
 	GetByStudentNumber(c context.Context, studentNumber string, tenantID uuid.UUID) (*model.Student, error)
 	GetByTenantUserID(c context.Context, tenantUserID uuid.UUID) (*model.Student, error)
 	Update(c context.Context, student *model.Student) error
+	UpdateStatus(c context.Context, tenantID, id uuid.UUID, status model.StudentStatus, changedAt time.Time) error
 	Delete(c context.Context, id uuid.UUID) error
 	BulkDelete(c context.Context, ids []uuid.UUID) error
-	List(c context.Context, tenantID uuid.UUID, offset, limit int, search string) ([]model.Student, int64, error)
-	GetByClass(c context.Context, tenantID, classID uuid.UUID, offset, limit int) ([]model.Student, int64, error)
-	GetByParent(c context.Context, tenantID, parentID uuid.UUID, offset, limit int) ([]model.Student, int64, error)
+	GetByIDs(c context.Context, tenantID uuid.UUID, ids []uuid.UUID) ([]model.Student, error)
+	List(c context.Context, tenantID uuid.UUID, offset, limit int, filter StudentFilter) ([]model.Student, int64, error)
+	GetIDsByClass(c context.Context, tenantID, classID uuid.UUID) ([]uuid.UUID, error)
+	GetAllByClass(c context.Context, tenantID, classID uuid.UUID) ([]model.Student, error)
+	GetAllByParentUser(c context.Context, tenantID, userID uuid.UUID) ([]model.Student, error)
+	GetGuardians(c context.Context, tenantID, studentID uuid.UUID) ([]model.StudentGuardian, error)
+	SaveGuardian(c context.Context, guardian *model.StudentGuardian) error
+	RemoveGuardian(c context.Context, tenantID, studentID, parentID uuid.UUID) error
+	ReassignClass(c context.Context, tenantID, fromClassID, toClassID uuid.UUID) (int64, error)
+	BulkUpdateClass(c context.Context, tenantID uuid.UUID, ids []uuid.UUID, classID uuid.UUID) (int64, error)
+	TransferToTenant(c context.Context, transfer StudentTransfer) (*model.Student, error)
+	GetNumberFormat(c context.Context, tenantID uuid.UUID) (*model.StudentNumberFormat, error)
+	SaveNumberFormat(c context.Context, format *model.StudentNumberFormat) error
+	WithTransaction(c context.Context, tenantID uuid.UUID, fn func(txCtx context.Context) error) error
 }
 
 // studentRepository implements StudentRepository
@@ -38,10 +107,12 @@ func NewStudentRepository(db *database.DatabaseConnections) StudentRepository {
 
 func (r *studentRepository) Create(c context.Context, student *model.Student) error {
 	repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(student.TenantID); err != nil {
-		return err
-	}
-	err := r.db.Write.Create(student).Error
+	err := r.WriteWithTenant(c, student.TenantID, func(db *gorm.DB) error {
+		if err := db.Create(student).Error; err != nil {
+			return err
+		}
+		return savePrimaryParent(db, student)
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
@@ -54,10 +125,14 @@ func (r *studentRepository) Create(c context.Context, student *model.Student) er
 func (r *studentRepository) GetByID(c context.Context, id uuid.UUID) (*model.Student, error) {
 	repoCtx := r.WithContext(c)
 	var student model.Student
-	err := r.db.Read.Preload("TenantUser.User").Preload("Class").Preload("Parent").First(&student, id).Error
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Preload("TenantUser.User").Preload("Class").Preload("Parent").
+			Preload("Guardians", orderGuardians).Preload("Guardians.Parent").
+			First(&student, id).Error
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("student not found")
+			return nil, apperror.NotFound("student not found")
 		}
 		repoCtx.logger.Error().
 			Err(err).
@@ -70,16 +145,15 @@ func (r *studentRepository) GetByID(c context.Context, id uuid.UUID) (*model.Stu
 
 func (r *studentRepository) GetByStudentNumber(c context.Context, studentNumber string, tenantID uuid.UUID) (*model.Student, error) {
 	repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(tenantID); err != nil {
-		return nil, err
-	}
 
 	var student model.Student
-	err := r.db.Read.Preload("TenantUser.User").Preload("Class").Preload("Parent").
-		Where("student_number = ? AND tenant_id = ?", studentNumber, tenantID).First(&student).Error
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Preload("TenantUser.User").Preload("Class").Preload("Parent").
+			Where("student_number = ? AND tenant_id = ?", studentNumber, tenantID).First(&student).Error
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("student not found")
+			return nil, apperror.NotFound("student not found")
 		}
 		repoCtx.logger.Error().
 			Err(err).
@@ -93,11 +167,13 @@ func (r *studentRepository) GetByStudentNumber(c context.Context, studentNumber
 func (r *studentRepository) GetByTenantUserID(c context.Context, tenantUserID uuid.UUID) (*model.Student, error) {
 	repoCtx := r.WithContext(c)
 	var student model.Student
-	err := r.db.Read.Preload("TenantUser.User").Preload("Class").Preload("Parent").
-		Where("tenant_user_id = ?", tenantUserID).First(&student).Error
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Preload("TenantUser.User").Preload("Class").Preload("Parent").
+			Where("tenant_user_id = ?", tenantUserID).First(&student).Error
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("student not found")
+			return nil, apperror.NotFound("student not found")
 		}
 		repoCtx.logger.Error().
 			Err(err).
@@ -110,10 +186,12 @@ func (r *studentRepository) GetByTenantUserID(c context.Context, tenantUserID uu
 
 func (r *studentRepository) Update(c context.Context, student *model.Student) error {
 	repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(student.TenantID); err != nil {
-		return err
-	}
-	err := r.db.Write.Save(student).Error
+	err := r.WriteWithTenant(c, student.TenantID, func(db *gorm.DB) error {
+		if err := db.Save(student).Error; err != nil {
+			return err
+		}
+		return savePrimaryParent(db, student)
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
@@ -123,9 +201,35 @@ func (r *studentRepository) Update(c context.Context, student *model.Student) er
 	return err
 }
 
+// UpdateStatus sets the student's lifecycle status and when it changed; enrollments, grades and attendance are left as they are
+func (r *studentRepository) UpdateStatus(c context.Context, tenantID, id uuid.UUID, status model.StudentStatus, changedAt time.Time) error {
+	repoCtx := r.WithContext(c)
+	err := r.WriteWithTenant(c, tenantID, func(db *gorm.DB) error {
+		result := db.Model(&model.Student{}).
+			Where("id = ? AND tenant_id = ?", id, tenantID).
+			Updates(map[string]interface{}{"status": status, "status_changed_at": changedAt})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperror.NotFound("student not found")
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "update_student_status").
+			Msg("Database write operation failed")
+	}
+	return err
+}
+
 func (r *studentRepository) Delete(c context.Context, id uuid.UUID) error {
 	repoCtx := r.WithContext(c)
-	err := r.db.Write.Delete(&model.Student{}, id).Error
+	err := r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Delete(&model.Student{}, id).Error
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
@@ -141,7 +245,9 @@ func (r *studentRepository) BulkDelete(c context.Context, ids []uuid.UUID) error
 		return nil
 	}
 
-	err := r.db.Write.Where("id IN (?)", ids).Delete(&model.Student{}).Error
+	err := r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Where("id IN (?)", ids).Delete(&model.Student{}).Error
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
@@ -152,105 +258,496 @@ func (r *studentRepository) BulkDelete(c context.Context, ids []uuid.UUID) error
 	return err
 }
 
-func (r *studentRepository) List(c context.Context, tenantID uuid.UUID, offset, limit int, search string) ([]model.Student, int64, error) {
+func (r *studentRepository) List(c context.Context, tenantID uuid.UUID, offset, limit int, filter StudentFilter) ([]model.Student, int64, error) {
 	repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(tenantID); err != nil {
-		return nil, 0, err
-	}
 
-	var students []model.Student
+	students := []model.Student{}
 	var total int64
 
-	query := r.db.Read.Preload("TenantUser.User").Preload("Class").Preload("Parent").
-		Where("students.tenant_id = ?", tenantID)
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		query := db.Where("students.tenant_id = ?", tenantID)
 
-	if search != "" {
-		query = query.Joins("JOIN tenant_users ON tenant_users.id = students.tenant_user_id").
-			Joins("JOIN users ON users.id = tenant_users.user_id").
-			Where("users.full_name ILIKE ? OR students.student_number ILIKE ?",
-				"%"+search+"%", "%"+search+"%")
+		if len(filter.IDs) > 0 {
+			query = query.Where("students.id IN ?", filter.IDs)
+		}
+		if filter.Search != "" {
+			condition, args := r.SearchCondition(filter.Search, "users.full_name", "students.student_number")
+			query = query.Joins("JOIN tenant_users ON tenant_users.id = students.tenant_user_id").
+				Joins("JOIN users ON users.id = tenant_users.user_id").
+				Where(condition, args...)
+		}
+		if filter.ClassID != nil {
+			query = query.Where("students.class_id = ?", *filter.ClassID)
+		}
+		if filter.ParentID != nil {
+			query = query.Where("EXISTS (SELECT 1 FROM student_guardians sg WHERE sg.student_id = students.id AND sg.parent_id = ?)", *filter.ParentID)
+		}
+		if filter.Status != "" {
+			query = query.Where("students.status = ?", filter.Status)
+		}
+		switch {
+		case filter.AdmissionFrom != nil && filter.AdmissionTo != nil:
+			query = query.Where("students.admission_date BETWEEN ? AND ?", *filter.AdmissionFrom, *filter.AdmissionTo)
+		case filter.AdmissionFrom != nil:
+			query = query.Where("students.admission_date >= ?", *filter.AdmissionFrom)
+		case filter.AdmissionTo != nil:
+			query = query.Where("students.admission_date <= ?", *filter.AdmissionTo)
+		}
+		switch filter.EnrollmentStatus {
+		case "enrolled":
+			query = query.Where("EXISTS (SELECT 1 FROM enrollments WHERE enrollments.student_id = students.id)")
+		case "not_enrolled":
+			query = query.Where("NOT EXISTS (SELECT 1 FROM enrollments WHERE enrollments.student_id = students.id)")
+		}
+		if filter.CreatedAfter != nil {
+			query = query.Where("students.created_at > ?", *filter.CreatedAfter)
+		}
+		if filter.UpdatedAfter != nil {
+			query = query.Where("students.updated_at > ?", *filter.UpdatedAfter)
+		}
+
+		// Get total count
+		if err := query.Model(&model.Student{}).Count(&total).Error; err != nil {
+			repoCtx.logger.Error().
+				Err(err).
+				Str("operation", "count_students").
+				Msg("Database query failed")
+			return err
+		}
+		if int64(offset) >= total {
+			return nil
+		}
+
+		// Get paginated results, eager loading only the expanded relations with one query per relation
+		page := query.Scopes(r.Paginate(offset, limit), r.Sort(studentSortColumns, filter.SortBy, filter.SortDesc, "students.id"))
+		for _, relation := range filter.Expand {
+			if preload, ok := studentPreloads[relation]; ok {
+				page = page.Preload(preload)
+			}
+		}
+		err := page.Find(&students).Error
+		if err != nil {
+			repoCtx.logger.Error().
+				Err(err).
+				Str("operation", "list_students").
+				Msg("Database query failed")
+		}
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
 	}
+	return students, total, nil
+}
+
+// GetByIDs returns the tenant's students among the given IDs in one query. IDs of other tenants or of
+// students that don't exist are left out.
+func (r *studentRepository) GetByIDs(c context.Context, tenantID uuid.UUID, ids []uuid.UUID) ([]model.Student, error) {
+	repoCtx := r.WithContext(c)
 
-	// Get total count
-	if err := query.Model(&model.Student{}).Count(&total).Error; err != nil {
+	var students []model.Student
+	if len(ids) == 0 {
+		return students, nil
+	}
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Where("id IN ? AND tenant_id = ?", ids, tenantID).Find(&students).Error
+	})
+	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
-			Str("operation", "count_students").
+			Str("operation", "get_students_by_ids").
 			Msg("Database query failed")
-		return nil, 0, err
+		return nil, err
 	}
+	return students, nil
+}
+
+// GetIDsByClass returns the IDs of every active student in the class
+func (r *studentRepository) GetIDsByClass(c context.Context, tenantID, classID uuid.UUID) ([]uuid.UUID, error) {
+	repoCtx := r.WithContext(c)
 
-	// Get paginated results
-	err := query.Offset(offset).Limit(limit).Find(&students).Error
+	var ids []uuid.UUID
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Model(&model.Student{}).
+			Where("class_id = ? AND tenant_id = ? AND status = ?", classID, tenantID, model.StudentActive).
+			Pluck("id", &ids).Error
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
-			Str("operation", "list_students").
+			Str("operation", "get_student_ids_by_class").
 			Msg("Database query failed")
+		return nil, err
 	}
-	return students, total, err
+	return ids, nil
 }
 
-func (r *studentRepository) GetByClass(c context.Context, tenantID, classID uuid.UUID, offset, limit int) ([]model.Student, int64, error) {
+// GetAllByClass returns every student in the class ordered by student number
+func (r *studentRepository) GetAllByClass(c context.Context, tenantID, classID uuid.UUID) ([]model.Student, error) {
 	repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(tenantID); err != nil {
-		return nil, 0, err
-	}
 
 	var students []model.Student
-	var total int64
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Preload("TenantUser.User").
+			Where("class_id = ? AND tenant_id = ?", classID, tenantID).
+			Order("student_number ASC").
+			Find(&students).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_all_students_by_class").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return students, nil
+}
 
-	query := r.db.Read.Preload("TenantUser.User").Preload("Class").Preload("Parent").
-		Where("class_id = ? AND tenant_id = ?", classID, tenantID)
+// GetAllByParentUser returns the students having a guardian whose parent record has the verified email of
+// the user, ordered by student number
+func (r *studentRepository) GetAllByParentUser(c context.Context, tenantID, userID uuid.UUID) ([]model.Student, error) {
+	repoCtx := r.WithContext(c)
 
-	// Get total count
-	if err := query.Model(&model.Student{}).Count(&total).Error; err != nil {
+	var students []model.Student
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Preload("TenantUser.User").Preload("Class").
+			Where("students.tenant_id = ?", tenantID).
+			Where(`EXISTS (SELECT 1 FROM student_guardians sg
+				JOIN parents p ON p.id = sg.parent_id
+				JOIN users u ON LOWER(u.email) = LOWER(p.email) AND u.email_verified
+				WHERE sg.student_id = students.id AND u.id = ?)`, userID).
+			Order("students.student_number ASC").
+			Find(&students).Error
+	})
+	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
-			Str("operation", "count_students_by_class").
+			Str("operation", "get_all_students_by_parent_user").
 			Msg("Database query failed")
-		return nil, 0, err
+		return nil, err
 	}
+	return students, nil
+}
 
-	// Get paginated results
-	err := query.Offset(offset).Limit(limit).Find(&students).Error
+// GetGuardians returns the guardians of the student with their parent records, the primary one first
+func (r *studentRepository) GetGuardians(c context.Context, tenantID, studentID uuid.UUID) ([]model.StudentGuardian, error) {
+	repoCtx := r.WithContext(c)
+
+	guardians := []model.StudentGuardian{}
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Scopes(orderGuardians).Preload("Parent").
+			Where("student_id = ? AND tenant_id = ?", studentID, tenantID).
+			Find(&guardians).Error
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
-			Str("operation", "get_students_by_class").
+			Str("operation", "get_student_guardians").
 			Msg("Database query failed")
+		return nil, err
 	}
-	return students, total, err
+	return guardians, nil
 }
 
-func (r *studentRepository) GetByParent(c context.Context, tenantID, parentID uuid.UUID, offset, limit int) ([]model.Student, int64, error) {
+// SaveGuardian links the parent to the student or updates the existing link. A primary guardian replaces the
+// previous one, and the first guardian of a student always becomes primary.
+func (r *studentRepository) SaveGuardian(c context.Context, guardian *model.StudentGuardian) error {
 	repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(tenantID); err != nil {
-		return nil, 0, err
+
+	err := r.WriteWithTenant(c, guardian.TenantID, func(tx *gorm.DB) error {
+		var parents int64
+		if err := tx.Model(&model.Parent{}).Where("id = ? AND tenant_id = ?", guardian.ParentID, guardian.TenantID).Count(&parents).Error; err != nil {
+			return err
+		}
+		if parents == 0 {
+			return apperror.NotFound("parent not found")
+		}
+		return saveGuardian(tx, guardian, "relationship", "is_primary")
+	})
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "save_student_guardian").
+			Msg("Database write operation failed")
 	}
+	return err
+}
 
-	var students []model.Student
-	var total int64
+// RemoveGuardian unlinks the parent from the student. When it was the primary guardian, the guardian linked
+// first after it becomes primary.
+func (r *studentRepository) RemoveGuardian(c context.Context, tenantID, studentID, parentID uuid.UUID) error {
+	repoCtx := r.WithContext(c)
+
+	err := r.WriteWithTenant(c, tenantID, func(tx *gorm.DB) error {
+		result := tx.Where("student_id = ? AND parent_id = ? AND tenant_id = ?", studentID, parentID, tenantID).
+			Delete(&model.StudentGuardian{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperror.NotFound("guardian not found")
+		}
+		return syncPrimaryGuardian(tx, studentID)
+	})
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "remove_student_guardian").
+			Msg("Database write operation failed")
+	}
+	return err
+}
+
+// savePrimaryParent makes the student's parent its primary guardian, keeping the other guardians linked,
+// and reloads the guardians of the student
+func savePrimaryParent(tx *gorm.DB, student *model.Student) error {
+	if student.ParentID == nil {
+		return nil
+	}
+	guardian := &model.StudentGuardian{
+		TenantID:  student.TenantID,
+		StudentID: student.ID,
+		ParentID:  *student.ParentID,
+		IsPrimary: true,
+	}
+	if err := saveGuardian(tx, guardian, "is_primary"); err != nil {
+		return err
+	}
+	return tx.Scopes(orderGuardians).Preload("Parent").Where("student_id = ?", student.ID).Find(&student.Guardians).Error
+}
+
+// saveGuardian inserts the guardian or updates the columns of the existing link, then restores the single
+// primary guardian of the student
+func saveGuardian(tx *gorm.DB, guardian *model.StudentGuardian, updateColumns ...string) error {
+	if guardian.IsPrimary {
+		err := tx.Model(&model.StudentGuardian{}).
+			Where("student_id = ? AND parent_id <> ? AND is_primary", guardian.StudentID, guardian.ParentID).
+			Update("is_primary", false).Error
+		if err != nil {
+			return err
+		}
+	}
+	err := tx.Omit("Parent").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "student_id"}, {Name: "parent_id"}},
+		DoUpdates: clause.AssignmentColumns(updateColumns),
+	}).Create(guardian).Error
+	if err != nil {
+		return err
+	}
+	return syncPrimaryGuardian(tx, guardian.StudentID)
+}
+
+// syncPrimaryGuardian promotes the earliest linked guardian when the student has guardians but no primary one,
+// and copies the primary guardian to the student's parent ID, clearing it when no guardian is left
+func syncPrimaryGuardian(tx *gorm.DB, studentID uuid.UUID) error {
+	var guardians []model.StudentGuardian
+	if err := tx.Scopes(orderGuardians).Where("student_id = ?", studentID).Limit(1).Find(&guardians).Error; err != nil {
+		return err
+	}
+
+	var parentID *uuid.UUID
+	if len(guardians) > 0 {
+		primary := guardians[0]
+		if !primary.IsPrimary {
+			err := tx.Model(&model.StudentGuardian{}).
+				Where("student_id = ? AND parent_id = ?", studentID, primary.ParentID).
+				Update("is_primary", true).Error
+			if err != nil {
+				return err
+			}
+		}
+		parentID = &primary.ParentID
+	}
+	return tx.Model(&model.Student{}).
+		Where("id = ? AND parent_id IS DISTINCT FROM ?", studentID, parentID).
+		Update("parent_id", parentID).Error
+}
 
-	query := r.db.Read.Preload("TenantUser.User").Preload("Class").Preload("Parent").
-		Where("parent_id = ? AND tenant_id = ?", parentID, tenantID)
+// orderGuardians orders guardians with the primary one first, then by when they were linked
+func orderGuardians(db *gorm.DB) *gorm.DB {
+	return db.Order("is_primary DESC, created_at, parent_id")
+}
 
-	// Get total count
-	if err := query.Model(&model.Student{}).Count(&total).Error; err != nil {
+// BulkUpdateClass moves the given students of the tenant to the class in a single statement
+func (r *studentRepository) BulkUpdateClass(c context.Context, tenantID uuid.UUID, ids []uuid.UUID, classID uuid.UUID) (int64, error) {
+	repoCtx := r.WithContext(c)
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var updated int64
+	err := r.WriteWithTenant(c, tenantID, func(tx *gorm.DB) error {
+		result := tx.Model(&model.Student{}).
+			Where("id IN ? AND tenant_id = ?", ids, tenantID).
+			Update("class_id", classID)
+		updated = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
-			Str("operation", "count_students_by_parent").
-			Msg("Database query failed")
-		return nil, 0, err
+			Str("operation", "bulk_update_students_class").
+			Str("class_id", classID.String()).
+			Int("count", len(ids)).
+			Msg("Database write operation failed")
+		return 0, err
+	}
+	return updated, nil
+}
+
+// ReassignClass moves every student in the source class to the target class in a single transaction
+func (r *studentRepository) ReassignClass(c context.Context, tenantID, fromClassID, toClassID uuid.UUID) (int64, error) {
+	repoCtx := r.WithContext(c)
+
+	var moved int64
+	err := r.WriteWithTenant(c, tenantID, func(tx *gorm.DB) error {
+		result := tx.Model(&model.Student{}).
+			Where("class_id = ? AND tenant_id = ?", fromClassID, tenantID).
+			Update("class_id", toClassID)
+		if result.Error != nil {
+			return result.Error
+		}
+		moved = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "reassign_students_class").
+			Str("from_class_id", fromClassID.String()).
+			Str("to_class_id", toClassID.String()).
+			Msg("Database write operation failed")
+		return 0, err
+	}
+	return moved, nil
+}
+
+// TransferToTenant copies a student and its tenant user into the destination tenant in a single
+// transaction. When the student number is already taken, a numeric suffix is appended.
+func (r *studentRepository) TransferToTenant(c context.Context, transfer StudentTransfer) (*model.Student, error) {
+	repoCtx := r.WithContext(c)
+	source := transfer.Source
+
+	var student *model.Student
+	// Write the new records under the destination tenant so RLS allows them
+	err := r.WriteWithTenant(c, transfer.DestinationTenantID, func(tx *gorm.DB) error {
+		var tenantUserID uuid.UUID
+		if transfer.TenantUserID != nil {
+			tenantUserID = *transfer.TenantUserID
+			if err := tx.Model(&model.TenantUser{}).Where("id = ?", tenantUserID).Update("is_active", true).Error; err != nil {
+				return err
+			}
+		} else {
+			tenantUser := &model.TenantUser{
+				TenantID: transfer.DestinationTenantID,
+				UserID:   source.TenantUser.UserID,
+				IsActive: true,
+			}
+			if err := tx.Create(tenantUser).Error; err != nil {
+				return err
+			}
+			tenantUserID = tenantUser.ID
+		}
+
+		for _, roleID := range transfer.RoleIDs {
+			tenantUserRole := &model.TenantUserRole{TenantUserID: tenantUserID, RoleID: roleID}
+			if err := tx.Where(tenantUserRole).FirstOrCreate(tenantUserRole).Error; err != nil {
+				return err
+			}
+		}
+
+		created, err := createStudentWithUniqueNumber(tx, &model.Student{
+			TenantID:      transfer.DestinationTenantID,
+			TenantUserID:  tenantUserID,
+			StudentNumber: transfer.StudentNumber,
+			AdmissionDate: transfer.AdmissionDate,
+		})
+		if err != nil {
+			return err
+		}
+		student = created
+
+		if transfer.DeactivateSource {
+			if err := tx.Exec("SELECT set_config('app.current_tenant', ?, true)", source.TenantID.String()).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&model.TenantUser{}).Where("id = ?", source.TenantUserID).Update("is_active", false).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "transfer_student_to_tenant").
+			Str("student_id", source.ID.String()).
+			Str("destination_tenant_id", transfer.DestinationTenantID.String()).
+			Msg("Database write operation failed")
+		return nil, err
+	}
+	return student, nil
+}
+
+// createStudentWithUniqueNumber inserts the student, retrying with a suffixed student number inside
+// a savepoint whenever the number collides with an existing one
+func createStudentWithUniqueNumber(tx *gorm.DB, student *model.Student) (*model.Student, error) {
+	baseNumber := student.StudentNumber
+	for attempt := 1; attempt <= maxStudentNumberAttempts; attempt++ {
+		err := tx.Transaction(func(sp *gorm.DB) error {
+			return sp.Create(student).Error
+		})
+		if err == nil {
+			return student, nil
+		}
+		if !isUniqueViolation(err) {
+			return nil, err
+		}
+		student.StudentNumber = fmt.Sprintf("%s-%d", baseNumber, attempt+1)
 	}
+	return nil, errors.New("could not find an available student number")
+}
+
+// GetNumberFormat returns the tenant's student number format, or nil when the tenant has not configured one
+func (r *studentRepository) GetNumberFormat(c context.Context, tenantID uuid.UUID) (*model.StudentNumberFormat, error) {
+	repoCtx := r.WithContext(c)
 
-	// Get paginated results
-	err := query.Offset(offset).Limit(limit).Find(&students).Error
+	var formats []model.StudentNumberFormat
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Where("tenant_id = ?", tenantID).Limit(1).Find(&formats).Error
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
-			Str("operation", "get_students_by_parent").
+			Str("operation", "get_student_number_format").
 			Msg("Database query failed")
+		return nil, err
+	}
+	if len(formats) == 0 {
+		return nil, nil
 	}
-	return students, total, err
+	return &formats[0], nil
+}
+
+// SaveNumberFormat creates or replaces the tenant's student number format. Counters are kept, so changing
+// the format continues the sequence of the period.
+func (r *studentRepository) SaveNumberFormat(c context.Context, format *model.StudentNumberFormat) error {
+	repoCtx := r.WithContext(c)
+
+	err := r.WriteWithTenant(c, format.TenantID, func(db *gorm.DB) error {
+		return db.Save(format).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "save_student_number_format").
+			Msg("Database write operation failed")
+	}
+	return err
+}
+
+// isUniqueViolation reports whether the error is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
 }
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestStudentListQueryCount(t *testing.T) {
+	tests := []struct {
+		name   string
+		expand []string
+		offset int
+		want   int
+	}{
+		{name: "no relations", want: 2},
+		{name: "class and parent", expand: []string{"class", "parent"}, want: 4},
+		{name: "tenant user", expand: []string{"tenant_user"}, want: 4},
+		{name: "guardians", expand: []string{"guardians"}, want: 4},
+		{name: "every relation", expand: []string{"tenant_user", "class", "parent", "guardians"}, want: 8},
+		{name: "page out of range", expand: []string{"class"}, offset: 100, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The count must not grow with the page, which would be an N+1 query
+			for _, students := range []int{1, 10} {
+				repo, queries := newQueryCountingStudentRepository(t, students)
+				got, _, err := repo.List(context.Background(), uuid.New(), tt.offset, 20, StudentFilter{Expand: tt.expand})
+				if err != nil {
+					t.Fatalf("List() error = %v", err)
+				}
+				if tt.offset == 0 && len(got) != students {
+					t.Fatalf("List() returned %d students, want %d", len(got), students)
+				}
+				if *queries != tt.want {
+					t.Errorf("List() of %d students ran %d queries, want %d", students, *queries, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// newQueryCountingStudentRepository returns a student repository over a fake database holding the given
+// number of students, and the number of queries it ran, counted by a GORM callback
+func newQueryCountingStudentRepository(t *testing.T, students int) (StudentRepository, *int) {
+	t.Helper()
+
+	cipher, err := database.NewFieldCipher("test")
+	if err != nil {
+		t.Fatalf("NewFieldCipher() error = %v", err)
+	}
+	database.RegisterEncryptedSerializer(cipher)
+
+	name := fmt.Sprintf("fake-%s", uuid.NewString())
+	sql.Register(name, fakeDriver{students: students})
+	conn, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+
+	var mu sync.Mutex
+	queries := 0
+	err = db.Callback().Query().Before("gorm:query").Register("test:count_queries", func(*gorm.DB) {
+		mu.Lock()
+		defer mu.Unlock()
+		queries++
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	repo := NewStudentRepository(&database.DatabaseConnections{Write: db, Read: db, UserFieldCipher: cipher})
+	return repo, &queries
+}
+
+// fakeDriver is a database/sql driver answering the queries of a student listing with made up rows
+type fakeDriver struct {
+	students int
+}
+
+func (d fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{students: d.students}, nil
+}
+
+type fakeConn struct {
+	students int
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("fake driver: prepared statements are not supported")
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+func (c *fakeConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+func (c *fakeConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+// tablePattern finds the table a query reads
+var tablePattern = regexp.MustCompile(`FROM "(\w+)"`)
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	match := tablePattern.FindStringSubmatch(query)
+	if match == nil {
+		return nil, fmt.Errorf("fake driver: unexpected query %q", query)
+	}
+	now := time.Now()
+
+	if strings.Contains(query, "count(") {
+		return &fakeRows{columns: []string{"count"}, values: [][]driver.Value{{int64(c.students)}}}, nil
+	}
+
+	rows := &fakeRows{}
+	switch match[1] {
+	case "students":
+		rows.columns = []string{"id", "tenant_id", "tenant_user_id", "student_number", "admission_date", "class_id", "parent_id", "status", "created_at", "updated_at"}
+		for i := 0; i < c.students; i++ {
+			rows.values = append(rows.values, []driver.Value{
+				uuid.NewString(), uuid.NewString(), uuid.NewString(), fmt.Sprintf("S%03d", i), now,
+				uuid.NewString(), uuid.NewString(), "active", now, now,
+			})
+		}
+	case "tenant_users":
+		rows.columns = []string{"id", "user_id"}
+		for _, arg := range args {
+			rows.values = append(rows.values, []driver.Value{arg.Value, uuid.NewString()})
+		}
+	case "student_guardians":
+		rows.columns = []string{"student_id", "parent_id", "is_primary"}
+		for _, arg := range args {
+			rows.values = append(rows.values, []driver.Value{arg.Value, uuid.NewString(), true})
+		}
+	default:
+		// A relation looked up by the IDs in the query
+		rows.columns = []string{"id"}
+		for _, arg := range args {
+			rows.values = append(rows.values, []driver.Value{arg.Value})
+		}
+	}
+	return rows, nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
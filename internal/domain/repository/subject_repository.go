@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// SubjectRepository interface defines subject repository methods
+type SubjectRepository interface {
+	GetByID(c context.Context, id uuid.UUID) (*model.Subject, error)
+}
+
+// subjectRepository implements SubjectRepository
+type subjectRepository struct {
+	*BaseRepository
+}
+
+// NewSubjectRepository creates a new subject repository
+func NewSubjectRepository(db *database.DatabaseConnections) SubjectRepository {
+	return &subjectRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *subjectRepository) GetByID(c context.Context, id uuid.UUID) (*model.Subject, error) {
+	repoCtx := r.WithContext(c)
+	var subject model.Subject
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.First(&subject, id).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("subject not found")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("subject_id", id.String()).
+			Msg("Database error while getting subject by ID")
+		return nil, err
+	}
+	return &subject, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// TeacherRepository interface defines teacher repository methods
+type TeacherRepository interface {
+	GetByID(c context.Context, id uuid.UUID) (*model.Teacher, error)
+	GetByUserID(c context.Context, tenantID, userID uuid.UUID) (*model.Teacher, error)
+}
+
+// teacherRepository implements TeacherRepository
+type teacherRepository struct {
+	*BaseRepository
+}
+
+// NewTeacherRepository creates a new teacher repository
+func NewTeacherRepository(db *database.DatabaseConnections) TeacherRepository {
+	return &teacherRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *teacherRepository) GetByID(c context.Context, id uuid.UUID) (*model.Teacher, error) {
+	repoCtx := r.WithContext(c)
+	var teacher model.Teacher
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Preload("TenantUser.User").First(&teacher, id).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("teacher not found")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("teacher_id", id.String()).
+			Msg("Database error while getting teacher by ID")
+		return nil, err
+	}
+	return &teacher, nil
+}
+
+// GetByUserID returns the teacher record of a user in the tenant
+func (r *teacherRepository) GetByUserID(c context.Context, tenantID, userID uuid.UUID) (*model.Teacher, error) {
+	repoCtx := r.WithContext(c)
+
+	var teacher model.Teacher
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Joins("JOIN tenant_users tu ON tu.id = teachers.tenant_user_id").
+			Where("teachers.tenant_id = ? AND tu.user_id = ?", tenantID, userID).
+			First(&teacher).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("teacher not found")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "get_teacher_by_user_id").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return &teacher, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// TenantRepository interface defines tenant repository methods
+type TenantRepository interface {
+	GetByID(c context.Context, id uuid.UUID) (*model.Tenant, error)
+	ListActive(c context.Context) ([]model.Tenant, error)
+}
+
+// tenantRepository implements TenantRepository
+type tenantRepository struct {
+	*BaseRepository
+}
+
+// NewTenantRepository creates a new tenant repository
+func NewTenantRepository(db *database.DatabaseConnections) TenantRepository {
+	return &tenantRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *tenantRepository) GetByID(c context.Context, id uuid.UUID) (*model.Tenant, error) {
+	repoCtx := r.WithContext(c)
+	var tenant model.Tenant
+	err := r.db.Read.WithContext(c).First(&tenant, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("tenant not found")
+		}
+		repoCtx.logger.Error().
+			Err(err).
+			Str("tenant_id", id.String()).
+			Msg("Database error while getting tenant by ID")
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// ListActive returns the tenants with an active or trial subscription, ordered by name
+func (r *tenantRepository) ListActive(c context.Context) ([]model.Tenant, error) {
+	repoCtx := r.WithContext(c)
+
+	var tenants []model.Tenant
+	err := r.db.Read.WithContext(c).
+		Where("subscription_status IN ?", []model.SubscriptionStatus{model.SubscriptionStatusActive, model.SubscriptionStatusTrial}).
+		Order("name").
+		Find(&tenants).Error
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "list_active_tenants").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return tenants, nil
+}
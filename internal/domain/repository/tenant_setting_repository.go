@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TenantSettingRepository interface defines tenant setting repository methods
+type TenantSettingRepository interface {
+	List(c context.Context, tenantID uuid.UUID) ([]model.TenantSetting, error)
+	Update(c context.Context, tenantID uuid.UUID, settings []model.TenantSetting, removedKeys []string) error
+}
+
+// tenantSettingRepository implements TenantSettingRepository
+type tenantSettingRepository struct {
+	*BaseRepository
+}
+
+// NewTenantSettingRepository creates a new tenant setting repository
+func NewTenantSettingRepository(db *database.DatabaseConnections) TenantSettingRepository {
+	return &tenantSettingRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// List returns the settings the tenant has configured
+func (r *tenantSettingRepository) List(c context.Context, tenantID uuid.UUID) ([]model.TenantSetting, error) {
+	repoCtx := r.WithContext(c)
+
+	var settings []model.TenantSetting
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Where("tenant_id = ?", tenantID).Order("key").Find(&settings).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "list_tenant_settings").
+			Msg("Database query failed")
+		return nil, err
+	}
+	return settings, nil
+}
+
+// Update inserts or replaces the settings and deletes the removed keys, so they fall back to their defaults,
+// in one transaction
+func (r *tenantSettingRepository) Update(c context.Context, tenantID uuid.UUID, settings []model.TenantSetting, removedKeys []string) error {
+	repoCtx := r.WithContext(c)
+
+	err := r.WriteWithTenant(c, tenantID, func(db *gorm.DB) error {
+		if len(settings) > 0 {
+			err := db.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "key"}},
+				DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+			}).Create(&settings).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(removedKeys) > 0 {
+			return db.Where("tenant_id = ? AND key IN ?", tenantID, removedKeys).Delete(&model.TenantSetting{}).Error
+		}
+		return nil
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "update_tenant_settings").
+			Msg("Database write operation failed")
+	}
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
 	"github.com/protocyber/kelasgo-api/internal/domain/model"
 	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
 	"gorm.io/gorm"
@@ -16,6 +17,7 @@ type TenantUserRepository interface {
 	GetByID(c context.Context, id uuid.UUID) (*model.TenantUser, error)
 	GetByTenantAndUser(c context.Context, tenantID, userID uuid.UUID) (*model.TenantUser, error)
 	GetByTenant(c context.Context, tenantID uuid.UUID, offset, limit int) ([]model.TenantUser, int64, error)
+	List(c context.Context, tenantID uuid.UUID, offset, limit int, filter TenantUserFilter) ([]model.TenantUser, int64, error)
 	GetByUser(c context.Context, userID uuid.UUID, offset, limit int) ([]model.TenantUser, int64, error)
 	Update(c context.Context, tenantUser *model.TenantUser) error
 	Delete(c context.Context, id uuid.UUID) error
@@ -24,6 +26,13 @@ type TenantUserRepository interface {
 	DeactivateUser(c context.Context, tenantID, userID uuid.UUID) error
 }
 
+// TenantUserFilter holds the optional filters of a tenant membership listing
+type TenantUserFilter struct {
+	Search   string
+	RoleID   *uuid.UUID
+	IsActive *bool
+}
+
 // tenantUserRepository implements TenantUserRepository
 type tenantUserRepository struct {
 	*BaseRepository
@@ -38,10 +47,9 @@ func NewTenantUserRepository(db *database.DatabaseConnections) TenantUserReposit
 
 func (r *tenantUserRepository) Create(c context.Context, tenantUser *model.TenantUser) error {
 	repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(tenantUser.TenantID); err != nil {
-		return err
-	}
-	err := r.db.Write.Create(tenantUser).Error
+	err := r.WriteWithTenant(c, tenantUser.TenantID, func(db *gorm.DB) error {
+		return db.Create(tenantUser).Error
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
@@ -53,10 +61,12 @@ func (r *tenantUserRepository) Create(c context.Context, tenantUser *model.Tenan
 
 func (r *tenantUserRepository) GetByID(c context.Context, id uuid.UUID) (*model.TenantUser, error) {
 	var tenantUser model.TenantUser
-	err := r.db.Read.Preload("User").Preload("Teacher").Preload("Student").First(&tenantUser, id).Error
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Preload("User").Preload("Teacher").Preload("Student").First(&tenantUser, id).Error
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("tenant user not found")
+			return nil, apperror.NotFound("tenant user not found")
 		}
 		return nil, err
 	}
@@ -64,16 +74,14 @@ func (r *tenantUserRepository) GetByID(c context.Context, id uuid.UUID) (*model.
 }
 
 func (r *tenantUserRepository) GetByTenantAndUser(c context.Context, tenantID, userID uuid.UUID) (*model.TenantUser, error) {
-	if err := r.SetTenantContext(tenantID); err != nil {
-		return nil, err
-	}
-
 	var tenantUser model.TenantUser
-	err := r.db.Read.Preload("User").Preload("Teacher").Preload("Student").
-		Where("tenant_id = ? AND user_id = ?", tenantID, userID).First(&tenantUser).Error
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Preload("User").Preload("Teacher").Preload("Student").
+			Where("tenant_id = ? AND user_id = ?", tenantID, userID).First(&tenantUser).Error
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("tenant user not found")
+			return nil, apperror.NotFound("tenant user not found")
 		}
 		return nil, err
 	}
@@ -81,39 +89,90 @@ func (r *tenantUserRepository) GetByTenantAndUser(c context.Context, tenantID, u
 }
 
 func (r *tenantUserRepository) GetByTenant(c context.Context, tenantID uuid.UUID, offset, limit int) ([]model.TenantUser, int64, error) {
-	if err := r.SetTenantContext(tenantID); err != nil {
-		return nil, 0, err
-	}
-
 	var tenantUsers []model.TenantUser
 	var count int64
 
-	query := r.db.Read.Model(&model.TenantUser{}).Where("tenant_id = ?", tenantID)
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		query := db.Model(&model.TenantUser{}).Where("tenant_id = ?", tenantID)
 
-	if err := query.Count(&count).Error; err != nil {
-		return nil, 0, err
-	}
+		if err := query.Count(&count).Error; err != nil {
+			return err
+		}
 
-	if err := query.Preload("User").Preload("Teacher").Preload("Student").
-		Offset(offset).Limit(limit).Find(&tenantUsers).Error; err != nil {
+		return query.Preload("User").Preload("Teacher").Preload("Student").
+			Scopes(r.Paginate(offset, limit)).Find(&tenantUsers).Error
+	})
+	if err != nil {
 		return nil, 0, err
 	}
 
 	return tenantUsers, count, nil
 }
 
-func (r *tenantUserRepository) GetByUser(c context.Context, userID uuid.UUID, offset, limit int) ([]model.TenantUser, int64, error) {
+// List returns the memberships of a tenant with their user and roles
+func (r *tenantUserRepository) List(c context.Context, tenantID uuid.UUID, offset, limit int, filter TenantUserFilter) ([]model.TenantUser, int64, error) {
+	repoCtx := r.WithContext(c)
+
 	var tenantUsers []model.TenantUser
-	var count int64
+	var total int64
+
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		query := db.Model(&model.TenantUser{}).Where("tenant_users.tenant_id = ?", tenantID)
+
+		if filter.Search != "" {
+			condition, args := r.SearchCondition(filter.Search, "users.full_name", "users.username", "users.email")
+			query = query.Joins("JOIN users ON users.id = tenant_users.user_id").Where(condition, args...)
+		}
+		if filter.RoleID != nil {
+			query = query.Where("EXISTS (SELECT 1 FROM tenant_user_roles WHERE tenant_user_roles.tenant_user_id = tenant_users.id AND tenant_user_roles.role_id = ?)", *filter.RoleID)
+		}
+		if filter.IsActive != nil {
+			query = query.Where("tenant_users.is_active = ?", *filter.IsActive)
+		}
 
-	query := r.db.Read.Model(&model.TenantUser{}).Where("user_id = ?", userID)
+		// Get total count
+		if err := query.Count(&total).Error; err != nil {
+			repoCtx.logger.Error().
+				Err(err).
+				Str("operation", "count_tenant_users").
+				Msg("Database query failed")
+			return err
+		}
 
-	if err := query.Count(&count).Error; err != nil {
+		// Get paginated results
+		err := query.Preload("User").Preload("TenantUserRoles.Role").
+			Order("tenant_users.created_at DESC").
+			Scopes(r.Paginate(offset, limit)).Find(&tenantUsers).Error
+		if err != nil {
+			repoCtx.logger.Error().
+				Err(err).
+				Str("operation", "list_tenant_users").
+				Msg("Database query failed")
+		}
+		return err
+	})
+	if err != nil {
 		return nil, 0, err
 	}
+	return tenantUsers, total, nil
+}
+
+func (r *tenantUserRepository) GetByUser(c context.Context, userID uuid.UUID, offset, limit int) ([]model.TenantUser, int64, error) {
+	var tenantUsers []model.TenantUser
+	var count int64
+
+	// The memberships of every tenant, the teacher and student records only of the request's tenant
+	err := r.ReadAsUser(c, userID, func(db *gorm.DB) error {
+		query := db.Model(&model.TenantUser{}).Where("user_id = ?", userID)
 
-	if err := query.Preload("User").Preload("Teacher").Preload("Student").
-		Offset(offset).Limit(limit).Find(&tenantUsers).Error; err != nil {
+		if err := query.Count(&count).Error; err != nil {
+			return err
+		}
+
+		return query.Preload("User").Preload("Teacher").Preload("Student").
+			Scopes(r.Paginate(offset, limit)).Find(&tenantUsers).Error
+	})
+	if err != nil {
 		return nil, 0, err
 	}
 
@@ -122,10 +181,9 @@ func (r *tenantUserRepository) GetByUser(c context.Context, userID uuid.UUID, of
 
 func (r *tenantUserRepository) Update(c context.Context, tenantUser *model.TenantUser) error {
 	repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(tenantUser.TenantID); err != nil {
-		return err
-	}
-	err := r.db.Write.Save(tenantUser).Error
+	err := r.WriteWithTenant(c, tenantUser.TenantID, func(db *gorm.DB) error {
+		return db.Save(tenantUser).Error
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
@@ -137,7 +195,9 @@ func (r *tenantUserRepository) Update(c context.Context, tenantUser *model.Tenan
 
 func (r *tenantUserRepository) Delete(c context.Context, id uuid.UUID) error {
 	// repoCtx := r.WithContext(c)
-	return r.db.Write.Delete(&model.TenantUser{}, id).Error
+	return r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Delete(&model.TenantUser{}, id).Error
+	})
 }
 
 func (r *tenantUserRepository) BulkDelete(c context.Context, ids []uuid.UUID) error {
@@ -146,7 +206,9 @@ func (r *tenantUserRepository) BulkDelete(c context.Context, ids []uuid.UUID) er
 		return nil
 	}
 
-	err := r.db.Write.Where("id IN (?)", ids).Delete(&model.TenantUser{}).Error
+	err := r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Where("id IN (?)", ids).Delete(&model.TenantUser{}).Error
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
@@ -158,23 +220,35 @@ func (r *tenantUserRepository) BulkDelete(c context.Context, ids []uuid.UUID) er
 }
 
 func (r *tenantUserRepository) ActivateUser(c context.Context, tenantID, userID uuid.UUID) error {
-	// repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(tenantID); err != nil {
-		return err
-	}
+	repoCtx := r.WithContext(c)
 
-	return r.db.Write.Model(&model.TenantUser{}).
-		Where("tenant_id = ? AND user_id = ?", tenantID, userID).
-		Update("is_active", true).Error
+	err := r.WriteWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Model(&model.TenantUser{}).
+			Where("tenant_id = ? AND user_id = ?", tenantID, userID).
+			Update("is_active", true).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "activate_tenant_user").
+			Msg("Database write operation failed")
+	}
+	return err
 }
 
 func (r *tenantUserRepository) DeactivateUser(c context.Context, tenantID, userID uuid.UUID) error {
-	// repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(tenantID); err != nil {
-		return err
-	}
+	repoCtx := r.WithContext(c)
 
-	return r.db.Write.Model(&model.TenantUser{}).
-		Where("tenant_id = ? AND user_id = ?", tenantID, userID).
-		Update("is_active", false).Error
+	err := r.WriteWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Model(&model.TenantUser{}).
+			Where("tenant_id = ? AND user_id = ?", tenantID, userID).
+			Update("is_active", false).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "deactivate_tenant_user").
+			Msg("Database write operation failed")
+	}
+	return err
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
 	"github.com/protocyber/kelasgo-api/internal/domain/model"
 	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
 	"gorm.io/gorm"
@@ -18,6 +19,7 @@ type TenantUserRoleRepository interface {
 	GetTenantUsersByRole(c context.Context, roleID uuid.UUID) ([]model.TenantUserRole, error)
 	Delete(c context.Context, tenantUserID, roleID uuid.UUID) error
 	DeleteAllTenantUserRoles(c context.Context, tenantUserID uuid.UUID) error
+	UpdateRoles(c context.Context, tenantID, tenantUserID uuid.UUID, assign, revoke []uuid.UUID) error
 }
 
 // tenantUserRoleRepository implements TenantUserRoleRepository
@@ -34,7 +36,9 @@ func NewTenantUserRoleRepository(db *database.DatabaseConnections) TenantUserRol
 
 func (r *tenantUserRoleRepository) Create(c context.Context, tenantUserRole *model.TenantUserRole) error {
 	repoCtx := r.WithContext(c)
-	err := r.db.Write.Create(tenantUserRole).Error
+	err := r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Create(tenantUserRole).Error
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
@@ -46,11 +50,13 @@ func (r *tenantUserRoleRepository) Create(c context.Context, tenantUserRole *mod
 
 func (r *tenantUserRoleRepository) GetByTenantUserAndRole(c context.Context, tenantUserID, roleID uuid.UUID) (*model.TenantUserRole, error) {
 	var tenantUserRole model.TenantUserRole
-	err := r.db.Read.Preload("TenantUser").Preload("Role").
-		Where("tenant_user_id = ? AND role_id = ?", tenantUserID, roleID).First(&tenantUserRole).Error
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Preload("TenantUser").Preload("Role").
+			Where("tenant_user_id = ? AND role_id = ?", tenantUserID, roleID).First(&tenantUserRole).Error
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("tenant user role not found")
+			return nil, apperror.NotFound("tenant user role not found")
 		}
 		return nil, err
 	}
@@ -59,7 +65,9 @@ func (r *tenantUserRoleRepository) GetByTenantUserAndRole(c context.Context, ten
 
 func (r *tenantUserRoleRepository) GetRolesByTenantUser(c context.Context, tenantUserID uuid.UUID) ([]model.TenantUserRole, error) {
 	var tenantUserRoles []model.TenantUserRole
-	err := r.db.Read.Preload("Role").Where("tenant_user_id = ?", tenantUserID).Find(&tenantUserRoles).Error
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Preload("Role").Where("tenant_user_id = ?", tenantUserID).Find(&tenantUserRoles).Error
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +76,9 @@ func (r *tenantUserRoleRepository) GetRolesByTenantUser(c context.Context, tenan
 
 func (r *tenantUserRoleRepository) GetTenantUsersByRole(c context.Context, roleID uuid.UUID) ([]model.TenantUserRole, error) {
 	var tenantUserRoles []model.TenantUserRole
-	err := r.db.Read.Preload("TenantUser").Where("role_id = ?", roleID).Find(&tenantUserRoles).Error
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Preload("TenantUser").Where("role_id = ?", roleID).Find(&tenantUserRoles).Error
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -77,12 +87,16 @@ func (r *tenantUserRoleRepository) GetTenantUsersByRole(c context.Context, roleI
 
 func (r *tenantUserRoleRepository) Delete(c context.Context, tenantUserID, roleID uuid.UUID) error {
 	// repoCtx := r.WithContext(c)
-	return r.db.Write.Where("tenant_user_id = ? AND role_id = ?", tenantUserID, roleID).Delete(&model.TenantUserRole{}).Error
+	return r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Where("tenant_user_id = ? AND role_id = ?", tenantUserID, roleID).Delete(&model.TenantUserRole{}).Error
+	})
 }
 
 func (r *tenantUserRoleRepository) DeleteAllTenantUserRoles(c context.Context, tenantUserID uuid.UUID) error {
 	repoCtx := r.WithContext(c)
-	err := r.db.Write.Where("tenant_user_id = ?", tenantUserID).Delete(&model.TenantUserRole{}).Error
+	err := r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Where("tenant_user_id = ?", tenantUserID).Delete(&model.TenantUserRole{}).Error
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
@@ -91,3 +105,33 @@ func (r *tenantUserRoleRepository) DeleteAllTenantUserRoles(c context.Context, t
 	}
 	return err
 }
+
+// UpdateRoles assigns and revokes roles of a tenant user in a single transaction.
+// Assigning a role the user already has and revoking one it doesn't have are no-ops.
+func (r *tenantUserRoleRepository) UpdateRoles(c context.Context, tenantID, tenantUserID uuid.UUID, assign, revoke []uuid.UUID) error {
+	repoCtx := r.WithContext(c)
+
+	err := r.WriteWithTenant(c, tenantID, func(tx *gorm.DB) error {
+		for _, roleID := range assign {
+			tenantUserRole := model.TenantUserRole{TenantUserID: tenantUserID, RoleID: roleID}
+			if err := tx.Where(&tenantUserRole).FirstOrCreate(&tenantUserRole).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(revoke) > 0 {
+			if err := tx.Where("tenant_user_id = ? AND role_id IN ?", tenantUserID, revoke).
+				Delete(&model.TenantUserRole{}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "update_tenant_user_roles").
+			Msg("Database write operation failed")
+	}
+	return err
+}
@@ -3,13 +3,54 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
 	"github.com/protocyber/kelasgo-api/internal/domain/model"
 	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
+	"github.com/protocyber/kelasgo-api/internal/util"
 	"gorm.io/gorm"
 )
 
+// userSortColumns maps accepted sort fields to their columns
+var userSortColumns = map[string]string{
+	"username":   "users.username",
+	"full_name":  "users.full_name",
+	"email":      "users.email",
+	"created_at": "users.created_at",
+	"updated_at": "users.updated_at",
+	"is_active":  "users.is_active",
+}
+
+// tenantUserSortColumns are the userSortColumns of a listing joined with tenant_users, where a user only
+// counts as active when their membership in the tenant is active too
+var tenantUserSortColumns = func() map[string]string {
+	columns := make(map[string]string, len(userSortColumns))
+	for field, column := range userSortColumns {
+		columns[field] = column
+	}
+	columns["is_active"] = "(users.is_active AND tenant_users.is_active)"
+	return columns
+}()
+
+// defaultUserSort is the sort field of a user listing without one, newest first
+const defaultUserSort = "created_at"
+
+// UserFilter holds the optional filters of a tenant user listing. They combine, so a listing can be narrowed
+// by search, role and active status at once.
+type UserFilter struct {
+	Search       string
+	RoleID       *uuid.UUID
+	IsActive     *bool // Active means both the account and the membership in the tenant are active
+	CreatedAfter *time.Time
+	UpdatedAfter *time.Time
+	// SortBy is one of the userSortColumns keys; empty sorts by creation time descending and unknown values
+	// leave the order unspecified
+	SortBy   string
+	SortDesc bool
+}
+
 // UserRepository interface defines user repository methods
 type UserRepository interface {
 	Create(c context.Context, user *model.User) error
@@ -21,12 +62,17 @@ type UserRepository interface {
 	GetByEmailAndTenant(c context.Context, email string, tenantID uuid.UUID) (*model.User, error)
 	GetUserTenants(c context.Context, userID uuid.UUID) ([]model.TenantUser, error) // Get all tenants for a user
 	Update(c context.Context, user *model.User) error
+	UpdatePasswordHash(c context.Context, id uuid.UUID, passwordHash string) error
+	ResetPassword(c context.Context, id uuid.UUID, passwordHash string) error
+	MustChangePassword(c context.Context, id uuid.UUID) (bool, error)
+	MarkEmailVerified(c context.Context, id uuid.UUID, verifiedAt time.Time) error
 	Delete(c context.Context, id uuid.UUID) error
 	BulkDelete(c context.Context, ids []uuid.UUID) error
-	List(c context.Context, offset, limit int, search string) ([]model.User, int64, error)
-	GetUsersByTenant(c context.Context, tenantID uuid.UUID, offset, limit int, search string) ([]model.User, int64, error)
+	GetByIDs(c context.Context, tenantID uuid.UUID, ids []uuid.UUID) ([]model.User, error)
+	List(c context.Context, offset, limit int, search, sortBy string, sortDesc bool) ([]model.User, int64, error)
+	GetUsersByTenant(c context.Context, tenantID uuid.UUID, offset, limit int, filter UserFilter) ([]model.User, int64, error)
 	GetUsersByRole(c context.Context, roleID uuid.UUID, offset, limit int) ([]model.User, int64, error)
-	GetByRole(c context.Context, tenantID uuid.UUID, roleID uuid.UUID, offset, limit int) ([]model.User, int64, error)
+	WithTransaction(c context.Context, tenantID uuid.UUID, fn func(txCtx context.Context) error) error
 }
 
 // userRepository implements UserRepository
@@ -43,7 +89,9 @@ func NewUserRepository(db *database.DatabaseConnections) UserRepository {
 
 func (r *userRepository) Create(c context.Context, user *model.User) error {
 	repoCtx := r.WithContext(c)
-	err := r.db.Write.Create(user).Error
+	err := r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Create(user).Error
+	})
 	if err != nil {
 		repoCtx.GetLogger().Error().
 			Err(err).
@@ -55,11 +103,12 @@ func (r *userRepository) Create(c context.Context, user *model.User) error {
 
 func (r *userRepository) GetByID(c context.Context, id uuid.UUID) (*model.User, error) {
 	repoCtx := r.WithContext(c)
-	var user model.User
-	err := r.db.Read.Preload("TenantUsers").First(&user, id).Error
+	user, err := r.findWithTenantUsers(c, func(db *gorm.DB) *gorm.DB {
+		return db.Where("users.id = ?", id)
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, apperror.NotFound("user not found")
 		}
 		repoCtx.GetLogger().Error().
 			Err(err).
@@ -67,40 +116,63 @@ func (r *userRepository) GetByID(c context.Context, id uuid.UUID) (*model.User,
 			Msg("Database error while getting user by ID")
 		return nil, err
 	}
-	return &user, nil
+	return user, nil
 }
 
 func (r *userRepository) GetByUsername(c context.Context, username string) (*model.User, error) {
-	var user model.User
-	err := r.db.Read.Preload("TenantUsers").Where("username = ?", username).First(&user).Error
+	user, err := r.findWithTenantUsers(c, func(db *gorm.DB) *gorm.DB {
+		return db.Where("username = ?", username)
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, apperror.NotFound("user not found")
 		}
 		return nil, err
 	}
-	return &user, nil
+	return user, nil
 }
 
 func (r *userRepository) GetByEmail(c context.Context, email string) (*model.User, error) {
-	var user model.User
-	err := r.db.Read.Preload("TenantUsers").Where("email = ?", email).First(&user).Error
+	user, err := r.findWithTenantUsers(c, func(db *gorm.DB) *gorm.DB {
+		return db.Where("email = ?", email)
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, apperror.NotFound("user not found")
 		}
 		return nil, err
 	}
+	return user, nil
+}
+
+// findWithTenantUsers returns the first user the scope matches with their memberships. Memberships are read
+// as the user, so those of every tenant are loaded and not only the ones of the request's tenant.
+func (r *userRepository) findWithTenantUsers(c context.Context, scope func(db *gorm.DB) *gorm.DB) (*model.User, error) {
+	var user model.User
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return scope(db).First(&user).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = r.ReadAsUser(c, user.ID, func(db *gorm.DB) error {
+		return db.Where("user_id = ?", user.ID).Find(&user.TenantUsers).Error
+	})
+	if err != nil {
+		return nil, err
+	}
 	return &user, nil
 }
 
 func (r *userRepository) GetByEmailGlobal(c context.Context, email string) (*model.User, error) {
 	repoCtx := r.WithContext(c)
 	var user model.User
-	err := r.db.Read.Where("email = ?", email).First(&user).Error
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Where("email = ?", email).First(&user).Error
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, apperror.NotFound("user not found")
 		}
 		repoCtx.logger.Error().
 			Err(err).
@@ -114,7 +186,9 @@ func (r *userRepository) GetByEmailGlobal(c context.Context, email string) (*mod
 func (r *userRepository) GetUserTenants(c context.Context, userID uuid.UUID) ([]model.TenantUser, error) {
 	repoCtx := r.WithContext(c)
 	var tenantUsers []model.TenantUser
-	err := r.db.Read.Preload("Tenant").Where("user_id = ? AND is_active = true", userID).Find(&tenantUsers).Error
+	err := r.ReadAsUser(c, userID, func(db *gorm.DB) error {
+		return db.Preload("Tenant").Where("user_id = ? AND is_active = true", userID).Find(&tenantUsers).Error
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
@@ -127,7 +201,9 @@ func (r *userRepository) GetUserTenants(c context.Context, userID uuid.UUID) ([]
 
 func (r *userRepository) Update(c context.Context, user *model.User) error {
 	repoCtx := r.WithContext(c)
-	err := r.db.Write.Save(user).Error
+	err := r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Save(user).Error
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
@@ -137,9 +213,82 @@ func (r *userRepository) Update(c context.Context, user *model.User) error {
 	return err
 }
 
+// UpdatePasswordHash replaces only the password hash of the user
+func (r *userRepository) UpdatePasswordHash(c context.Context, id uuid.UUID, passwordHash string) error {
+	repoCtx := r.WithContext(c)
+	err := r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Model(&model.User{}).Where("id = ?", id).Update("password_hash", passwordHash).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "update_user_password_hash").
+			Msg("Database write operation failed")
+	}
+	return err
+}
+
+// ResetPassword replaces the password hash and requires the user to change the password on their next login
+func (r *userRepository) ResetPassword(c context.Context, id uuid.UUID, passwordHash string) error {
+	repoCtx := r.WithContext(c)
+	err := r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Model(&model.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"password_hash":        passwordHash,
+			"must_change_password": true,
+		}).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "reset_user_password").
+			Msg("Database write operation failed")
+	}
+	return err
+}
+
+// MustChangePassword reports whether the user's password was reset by an admin and not changed since
+func (r *userRepository) MustChangePassword(c context.Context, id uuid.UUID) (bool, error) {
+	repoCtx := r.WithContext(c)
+	var mustChange []bool
+	err := r.ReadAsUser(c, id, func(db *gorm.DB) error {
+		return db.Model(&model.User{}).Where("id = ?", id).Pluck("must_change_password", &mustChange).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("user_id", id.String()).
+			Msg("Database error while getting password change flag")
+		return false, err
+	}
+	if len(mustChange) == 0 {
+		return false, apperror.NotFound("user not found")
+	}
+	return mustChange[0], nil
+}
+
+// MarkEmailVerified flags the user's email address as verified
+func (r *userRepository) MarkEmailVerified(c context.Context, id uuid.UUID, verifiedAt time.Time) error {
+	repoCtx := r.WithContext(c)
+	err := r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Model(&model.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"email_verified":    true,
+			"email_verified_at": verifiedAt,
+		}).Error
+	})
+	if err != nil {
+		repoCtx.logger.Error().
+			Err(err).
+			Str("operation", "mark_user_email_verified").
+			Msg("Database write operation failed")
+	}
+	return err
+}
+
 func (r *userRepository) Delete(c context.Context, id uuid.UUID) error {
 	repoCtx := r.WithContext(c)
-	err := r.db.Write.Delete(&model.User{}, id).Error
+	err := r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Delete(&model.User{}, id).Error
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
@@ -155,7 +304,9 @@ func (r *userRepository) BulkDelete(c context.Context, ids []uuid.UUID) error {
 		return nil
 	}
 
-	err := r.db.Write.Where("id IN (?)", ids).Delete(&model.User{}).Error
+	err := r.WriteWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		return db.Where("id IN (?)", ids).Delete(&model.User{}).Error
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
@@ -166,64 +317,83 @@ func (r *userRepository) BulkDelete(c context.Context, ids []uuid.UUID) error {
 	return err
 }
 
-func (r *userRepository) List(c context.Context, offset, limit int, search string) ([]model.User, int64, error) {
+func (r *userRepository) List(c context.Context, offset, limit int, search, sortBy string, sortDesc bool) ([]model.User, int64, error) {
 	repoCtx := r.WithContext(c)
 	var users []model.User
 	var total int64
 
-	query := r.db.Read.Preload("TenantUsers")
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		query := db.Preload("TenantUsers")
 
-	if search != "" {
-		query = query.Where("full_name ILIKE ? OR username ILIKE ? OR email ILIKE ?",
-			"%"+search+"%", "%"+search+"%", "%"+search+"%")
-	}
+		if search != "" {
+			condition, args := r.searchCondition(search, "")
+			query = query.Where(condition, args...)
+		}
 
-	// Get total count
-	if err := query.Model(&model.User{}).Count(&total).Error; err != nil {
+		// Get total count
+		if err := query.Model(&model.User{}).Count(&total).Error; err != nil {
+			return err
+		}
+
+		// Get paginated results
+		return r.orderUsers(query, userSortColumns, sortBy, sortDesc).Scopes(r.Paginate(offset, limit)).Find(&users).Error
+	})
+	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
-			Str("operation", "count_users").
+			Str("operation", "list_users").
 			Msg("Database query failed")
-		return nil, 0, err
 	}
+	return users, total, err
+}
 
-	// Get paginated results
-	err := query.Offset(offset).Limit(limit).Find(&users).Error
+// GetByIDs returns the users among the given IDs that are members of the tenant, in one query. IDs of users
+// outside the tenant or that don't exist are left out.
+func (r *userRepository) GetByIDs(c context.Context, tenantID uuid.UUID, ids []uuid.UUID) ([]model.User, error) {
+	repoCtx := r.WithContext(c)
+
+	var users []model.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Where("users.id IN ?", ids).
+			Where("EXISTS (SELECT 1 FROM tenant_users WHERE tenant_users.user_id = users.id AND tenant_users.tenant_id = ?)", tenantID).
+			Find(&users).Error
+	})
 	if err != nil {
 		repoCtx.logger.Error().
 			Err(err).
-			Str("operation", "list_users").
+			Str("operation", "get_users_by_ids").
 			Msg("Database query failed")
+		return nil, err
 	}
-	return users, total, err
+	return users, nil
 }
 
-func (r *userRepository) GetUsersByTenant(c context.Context, tenantID uuid.UUID, offset, limit int, search string) ([]model.User, int64, error) {
+func (r *userRepository) GetUsersByTenant(c context.Context, tenantID uuid.UUID, offset, limit int, filter UserFilter) ([]model.User, int64, error) {
 	// repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(tenantID); err != nil {
-		return nil, 0, err
-	}
-
 	var users []model.User
 	var total int64
 
-	query := r.db.Read.Preload("TenantUsers").
-		Joins("JOIN tenant_users ON users.id = tenant_users.user_id").
-		Where("tenant_users.tenant_id = ?", tenantID)
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		query := db.Preload("TenantUsers").
+			Joins("JOIN tenant_users ON users.id = tenant_users.user_id").
+			Where("tenant_users.tenant_id = ?", tenantID)
+		query = r.applyFilter(query, filter)
 
-	if search != "" {
-		query = query.Where("users.full_name ILIKE ? OR users.username ILIKE ? OR users.email ILIKE ?",
-			"%"+search+"%", "%"+search+"%", "%"+search+"%")
-	}
+		// Get total count
+		if err := query.Model(&model.User{}).Count(&total).Error; err != nil {
+			return err
+		}
 
-	// Get total count
-	if err := query.Model(&model.User{}).Count(&total).Error; err != nil {
+		// Get paginated results
+		return r.orderUsers(query, tenantUserSortColumns, filter.SortBy, filter.SortDesc).Scopes(r.Paginate(offset, limit)).Find(&users).Error
+	})
+	if err != nil {
 		return nil, 0, err
 	}
-
-	// Get paginated results
-	err := query.Offset(offset).Limit(limit).Find(&users).Error
-	return users, total, err
+	return users, total, nil
 }
 
 func (r *userRepository) GetUsersByRole(c context.Context, roleID uuid.UUID, offset, limit int) ([]model.User, int64, error) {
@@ -231,36 +401,35 @@ func (r *userRepository) GetUsersByRole(c context.Context, roleID uuid.UUID, off
 	var users []model.User
 	var total int64
 
-	query := r.db.Read.Preload("TenantUsers").
-		Joins("JOIN tenant_users ON users.id = tenant_users.user_id").
-		Joins("JOIN tenant_user_roles ON tenant_users.id = tenant_user_roles.tenant_user_id").
-		Where("tenant_user_roles.role_id = ? AND tenant_users.is_active = true", roleID)
+	err := r.ReadWithTenant(c, contextTenantID(c), func(db *gorm.DB) error {
+		query := db.Preload("TenantUsers").
+			Joins("JOIN tenant_users ON users.id = tenant_users.user_id").
+			Joins("JOIN tenant_user_roles ON tenant_users.id = tenant_user_roles.tenant_user_id").
+			Where("tenant_user_roles.role_id = ? AND tenant_users.is_active = true", roleID)
 
-	// Get total count
-	if err := query.Model(&model.User{}).Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
+		// Get total count
+		if err := query.Model(&model.User{}).Count(&total).Error; err != nil {
+			return err
+		}
 
-	// Get paginated results
-	err := query.Offset(offset).Limit(limit).Find(&users).Error
+		// Get paginated results
+		return query.Scopes(r.Paginate(offset, limit)).Find(&users).Error
+	})
 	return users, total, err
 }
 
 func (r *userRepository) GetByUsernameAndTenant(c context.Context, username string, tenantID uuid.UUID) (*model.User, error) {
 	repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(tenantID); err != nil {
-		return nil, err
-	}
-
 	var user model.User
-	err := r.db.Read.Preload("TenantUsers").
-		Joins("JOIN tenant_users ON users.id = tenant_users.user_id").
-		Where("users.username = ? AND tenant_users.tenant_id = ? AND tenant_users.is_active = true", username, tenantID).
-		First(&user).Error
-
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Preload("TenantUsers").
+			Joins("JOIN tenant_users ON users.id = tenant_users.user_id").
+			Where("users.username = ? AND tenant_users.tenant_id = ? AND tenant_users.is_active = true", username, tenantID).
+			First(&user).Error
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, apperror.NotFound("user not found")
 		}
 		repoCtx.logger.Error().
 			Err(err).
@@ -273,45 +442,62 @@ func (r *userRepository) GetByUsernameAndTenant(c context.Context, username stri
 
 func (r *userRepository) GetByEmailAndTenant(c context.Context, email string, tenantID uuid.UUID) (*model.User, error) {
 	// repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(tenantID); err != nil {
-		return nil, err
-	}
-
 	var user model.User
-	err := r.db.Read.Preload("TenantUsers").
-		Joins("JOIN tenant_users ON users.id = tenant_users.user_id").
-		Where("users.email = ? AND tenant_users.tenant_id = ? AND tenant_users.is_active = true", email, tenantID).
-		First(&user).Error
-
+	err := r.ReadWithTenant(c, tenantID, func(db *gorm.DB) error {
+		return db.Preload("TenantUsers").
+			Joins("JOIN tenant_users ON users.id = tenant_users.user_id").
+			Where("users.email = ? AND tenant_users.tenant_id = ? AND tenant_users.is_active = true", email, tenantID).
+			First(&user).Error
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, apperror.NotFound("user not found")
 		}
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) GetByRole(c context.Context, tenantID uuid.UUID, roleID uuid.UUID, offset, limit int) ([]model.User, int64, error) {
-	// repoCtx := r.WithContext(c)
-	if err := r.SetTenantContext(tenantID); err != nil {
-		return nil, 0, err
+// applyFilter narrows a users query joined with tenant_users by the search term, role, active status and the
+// change timestamps
+func (r *userRepository) applyFilter(query *gorm.DB, filter UserFilter) *gorm.DB {
+	if filter.Search != "" {
+		condition, args := r.searchCondition(filter.Search, "users.")
+		query = query.Where(condition, args...)
 	}
+	if filter.RoleID != nil {
+		query = query.Where("EXISTS (SELECT 1 FROM tenant_user_roles WHERE tenant_user_roles.tenant_user_id = tenant_users.id AND tenant_user_roles.role_id = ?)",
+			*filter.RoleID)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("(users.is_active AND tenant_users.is_active) = ?", *filter.IsActive)
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("users.created_at > ?", *filter.CreatedAfter)
+	}
+	if filter.UpdatedAfter != nil {
+		query = query.Where("users.updated_at > ?", *filter.UpdatedAfter)
+	}
+	return query
+}
 
-	var users []model.User
-	var total int64
-
-	query := r.db.Read.Preload("TenantUsers").
-		Joins("JOIN tenant_users ON users.id = tenant_users.user_id").
-		Joins("JOIN tenant_user_roles ON tenant_users.id = tenant_user_roles.tenant_user_id").
-		Where("tenant_users.tenant_id = ? AND tenant_user_roles.role_id = ? AND tenant_users.is_active = true", tenantID, roleID)
-
-	// Get total count
-	if err := query.Model(&model.User{}).Count(&total).Error; err != nil {
-		return nil, 0, err
+// searchCondition matches the search term on the name, username and email columns with the given prefix, and
+// on the phone number when the term is a valid one. Phones are encrypted, so they only match in full, through
+// the blind index of the normalized number.
+func (r *userRepository) searchCondition(search, prefix string) (string, []interface{}) {
+	condition, args := r.SearchCondition(search, prefix+"full_name", prefix+"username", prefix+"email")
+	if phone, ok := util.NormalizeIndonesianPhone(search); ok {
+		condition = "(" + condition + " OR " + prefix + "phone_index = ?)"
+		args = append(args, r.db.UserFieldCipher.BlindIndex(phone))
 	}
+	return condition, args
+}
 
-	// Get paginated results
-	err := query.Offset(offset).Limit(limit).Find(&users).Error
-	return users, total, err
+// orderUsers applies the requested sort, or the newest users first when none is requested, using the user ID
+// as a tie-breaker for stable pages
+func (r *userRepository) orderUsers(query *gorm.DB, columns map[string]string, sortBy string, sortDesc bool) *gorm.DB {
+	if sortBy == "" {
+		sortBy, sortDesc = defaultUserSort, true
+	}
+	return query.Scopes(r.Sort(columns, sortBy, sortDesc, "users.id"))
 }
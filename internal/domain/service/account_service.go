@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/config"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// AccountService interface defines the service methods for a user's own account data
+type AccountService interface {
+	Export(c context.Context, userID uuid.UUID) (*dto.AccountExportResponse, error)
+	Delete(c context.Context, userID uuid.UUID, req dto.DeleteAccountRequest) error
+}
+
+// accountService implements AccountService
+type accountService struct {
+	accountRepo    repository.AccountRepository
+	userRepo       repository.UserRepository
+	passwordHasher *util.PasswordHasher
+	authConfig     *config.AuthConfig
+}
+
+// NewAccountService creates a new account service
+func NewAccountService(
+	accountRepo repository.AccountRepository,
+	userRepo repository.UserRepository,
+	passwordHasher *util.PasswordHasher,
+	authConfig *config.AuthConfig,
+) AccountService {
+	return &accountService{
+		accountRepo:    accountRepo,
+		userRepo:       userRepo,
+		passwordHasher: passwordHasher,
+		authConfig:     authConfig,
+	}
+}
+
+// Export collects every personal record kept about the user across their tenants
+func (s *accountService) Export(c context.Context, userID uuid.UUID) (*dto.AccountExportResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	data, err := s.accountRepo.GetData(c, userID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil, err
+		}
+		logger.Error().
+			Err(err).
+			Str("user_id", userID.String()).
+			Msg("Failed to collect account data for export")
+		return nil, apperror.Internal("failed to export account data")
+	}
+
+	user := data.User
+	export := &dto.AccountExportResponse{
+		ExportedAt: util.NowFromContext(c),
+		Profile: dto.AccountExportProfile{
+			ID:              user.ID,
+			Username:        user.Username,
+			Email:           user.Email,
+			FullName:        user.FullName,
+			Birthplace:      user.Birthplace,
+			Birthday:        user.Birthday,
+			Gender:          (*string)(user.Gender),
+			DateOfBirth:     user.DateOfBirth,
+			Phone:           user.Phone,
+			Address:         user.Address,
+			IsActive:        user.IsActive,
+			EmailVerified:   user.EmailVerified,
+			EmailVerifiedAt: user.EmailVerifiedAt,
+			CreatedAt:       user.CreatedAt,
+			UpdatedAt:       user.UpdatedAt,
+		},
+		NotificationPreferences: make([]dto.NotificationPreferenceResponse, 0, len(data.Preferences)),
+		Memberships:             make([]dto.AccountExportMembership, 0, len(data.Memberships)),
+	}
+	for _, preference := range data.Preferences {
+		export.NotificationPreferences = append(export.NotificationPreferences, dto.NotificationPreferenceResponse{
+			Type:  preference.NotificationType,
+			Email: preference.Email,
+			SMS:   preference.SMS,
+		})
+	}
+	for _, membership := range data.Memberships {
+		export.Memberships = append(export.Memberships, exportMembership(membership))
+	}
+
+	logger.Info().
+		Str("user_id", userID.String()).
+		Int("memberships", len(export.Memberships)).
+		Msg("Account data exported")
+
+	return export, nil
+}
+
+// Delete anonymizes the user's account after checking their password. Academic records stay, pointing to
+// the anonymized user.
+func (s *accountService) Delete(c context.Context, userID uuid.UUID, req dto.DeleteAccountRequest) error {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	if !s.authConfig.AllowAccountDeletion {
+		return apperror.Forbidden("account deletion is disabled, contact your school administrator")
+	}
+
+	user, err := s.userRepo.GetByID(c, userID)
+	if err != nil || user.DeletedAt != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", userID.String()).
+			Msg("User not found during account deletion")
+		return apperror.NotFound("user not found")
+	}
+
+	if !s.passwordHasher.Check(req.Password, user.PasswordHash) {
+		logger.Warn().
+			Str("user_id", userID.String()).
+			Msg("Incorrect password during account deletion")
+		return apperror.Validation("password is incorrect")
+	}
+
+	if err := s.accountRepo.Anonymize(c, userID, util.NowFromContext(c)); err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return err
+		}
+		logger.Error().
+			Err(err).
+			Str("user_id", userID.String()).
+			Msg("Failed to anonymize account")
+		return apperror.Internal("failed to delete account")
+	}
+
+	logger.Info().
+		Str("user_id", userID.String()).
+		Msg("Account deleted and anonymized")
+
+	return nil
+}
+
+// exportMembership converts the records of one tenant membership
+func exportMembership(membership repository.AccountMembership) dto.AccountExportMembership {
+	tenantUser := membership.TenantUser
+	export := dto.AccountExportMembership{
+		TenantID:      tenantUser.TenantID,
+		IsActive:      tenantUser.IsActive,
+		JoinedAt:      tenantUser.CreatedAt,
+		Roles:         membership.Roles,
+		Enrollments:   make([]dto.AccountExportEnrollment, 0, len(membership.Enrollments)),
+		Grades:        make([]dto.AccountExportGrade, 0, len(membership.Grades)),
+		Attendance:    make([]dto.AccountExportAttendance, 0, len(membership.Attendance)),
+		Fees:          make([]dto.AccountExportFee, 0, len(membership.Fees)),
+		Notifications: make([]dto.AccountExportNotification, 0, len(membership.Notifications)),
+	}
+	if tenantUser.Tenant != nil {
+		export.TenantName = tenantUser.Tenant.Name
+	}
+	if export.Roles == nil {
+		export.Roles = []string{}
+	}
+
+	if student := membership.Student; student != nil {
+		export.Student = &dto.AccountExportStudent{
+			ID:            student.ID,
+			StudentNumber: student.StudentNumber,
+			AdmissionDate: student.AdmissionDate,
+			ClassID:       student.ClassID,
+			ParentID:      student.ParentID,
+		}
+		if student.Class != nil {
+			export.Student.ClassName = &student.Class.Name
+		}
+	}
+	if teacher := membership.Teacher; teacher != nil {
+		export.Teacher = &dto.AccountExportTeacher{
+			ID:             teacher.ID,
+			EmployeeNumber: teacher.EmployeeNumber,
+			HireDate:       teacher.HireDate,
+			Qualification:  teacher.Qualification,
+			Position:       teacher.Position,
+			Birthplace:     teacher.Birthplace,
+			Birthday:       teacher.Birthday,
+			Gender:         (*string)(teacher.Gender),
+		}
+	}
+
+	for _, enrollment := range membership.Enrollments {
+		item := dto.AccountExportEnrollment{
+			ID:             enrollment.ID,
+			ClassSubjectID: enrollment.ClassSubjectID,
+		}
+		if classSubject := enrollment.ClassSubject; classSubject != nil {
+			if classSubject.Class != nil {
+				item.ClassName = &classSubject.Class.Name
+			}
+			if classSubject.Subject != nil {
+				item.SubjectName = &classSubject.Subject.Name
+			}
+		}
+		if enrollment.AcademicYear != nil {
+			item.AcademicYear = &enrollment.AcademicYear.Name
+		}
+		export.Enrollments = append(export.Enrollments, item)
+	}
+	for _, grade := range membership.Grades {
+		item := dto.AccountExportGrade{
+			ID:           grade.ID,
+			EnrollmentID: grade.EnrollmentID,
+			GradeType:    grade.GradeType,
+			Score:        grade.Score,
+			Semester:     grade.Semester,
+			Remarks:      grade.Remarks,
+		}
+		if grade.Enrollment != nil && grade.Enrollment.ClassSubject != nil && grade.Enrollment.ClassSubject.Subject != nil {
+			item.SubjectName = &grade.Enrollment.ClassSubject.Subject.Name
+		}
+		export.Grades = append(export.Grades, item)
+	}
+	for _, attendance := range membership.Attendance {
+		export.Attendance = append(export.Attendance, dto.AccountExportAttendance{
+			ID:             attendance.ID,
+			ScheduleID:     attendance.ScheduleID,
+			AttendanceDate: attendance.AttendanceDate.Format("2006-01-02"),
+			Status:         string(attendance.Status),
+			Remarks:        attendance.Remarks,
+		})
+	}
+	for _, fee := range membership.Fees {
+		item := dto.AccountExportFee{
+			ID:             fee.ID,
+			Amount:         fee.Amount,
+			DueDate:        fee.DueDate.Format("2006-01-02"),
+			Status:         string(fee.Status),
+			PaymentMethod:  fee.PaymentMethod,
+			Notes:          fee.Notes,
+			AcademicYearID: fee.AcademicYearID,
+		}
+		if fee.FeeType != nil {
+			item.FeeTypeName = &fee.FeeType.Name
+		}
+		if fee.PaymentDate != nil {
+			paymentDate := fee.PaymentDate.Format("2006-01-02")
+			item.PaymentDate = &paymentDate
+		}
+		export.Fees = append(export.Fees, item)
+	}
+	for _, notification := range membership.Notifications {
+		export.Notifications = append(export.Notifications, dto.AccountExportNotification{
+			ID:        notification.ID,
+			Type:      notification.Type,
+			Title:     notification.Title,
+			Message:   notification.Message,
+			IsRead:    notification.IsRead,
+			CreatedAt: notification.CreatedAt,
+		})
+	}
+	return export
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// APIKeyService interface defines API key service methods
+type APIKeyService interface {
+	Create(c context.Context, tenantID, createdBy uuid.UUID, req dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error)
+	List(c context.Context, tenantID uuid.UUID) ([]dto.APIKeyResponse, error)
+	Revoke(c context.Context, tenantID, id uuid.UUID) error
+	Authenticate(c context.Context, key string) (*model.APIKey, error)
+}
+
+// apiKeyService implements APIKeyService
+type apiKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Create generates a new API key for the tenant. The key is returned once; only its hash is stored.
+func (s *apiKeyService) Create(c context.Context, tenantID, createdBy uuid.UUID, req dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	key, prefix, hash, err := util.GenerateAPIKey()
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to generate API key")
+		return nil, apperror.Internal("failed to create api key")
+	}
+
+	// Duplicate scopes are dropped
+	seen := make(map[string]bool, len(req.Scopes))
+	scopes := make([]string, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	apiKey := &model.APIKey{
+		BaseModel: model.BaseModel{TenantID: tenantID},
+		Name:      req.Name,
+		KeyPrefix: prefix,
+		KeyHash:   hash,
+		Scopes:    scopes,
+	}
+	if createdBy != uuid.Nil {
+		apiKey.CreatedBy = &createdBy
+	}
+	if err := s.apiKeyRepo.Create(c, apiKey); err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to create API key")
+		return nil, apperror.Internal("failed to create api key")
+	}
+
+	logger.Info().
+		Str("api_key_id", apiKey.ID.String()).
+		Str("key_prefix", apiKey.KeyPrefix).
+		Strs("scopes", apiKey.Scopes).
+		Msg("API key created")
+
+	return &dto.CreateAPIKeyResponse{
+		APIKeyResponse: apiKeyResponse(apiKey),
+		Key:            key,
+	}, nil
+}
+
+// List returns the tenant's API keys, revoked ones included
+func (s *apiKeyService) List(c context.Context, tenantID uuid.UUID) ([]dto.APIKeyResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	apiKeys, err := s.apiKeyRepo.ListByTenant(c, tenantID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to list API keys")
+		return nil, apperror.Internal("failed to list api keys")
+	}
+
+	responses := make([]dto.APIKeyResponse, 0, len(apiKeys))
+	for i := range apiKeys {
+		responses = append(responses, apiKeyResponse(&apiKeys[i]))
+	}
+	return responses, nil
+}
+
+// Revoke stops the tenant's API key from authenticating
+func (s *apiKeyService) Revoke(c context.Context, tenantID, id uuid.UUID) error {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	if err := s.apiKeyRepo.Revoke(c, tenantID, id, util.NowFromContext(c)); err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return err
+		}
+		logger.Error().
+			Err(err).
+			Str("api_key_id", id.String()).
+			Msg("Failed to revoke API key")
+		return apperror.Internal("failed to revoke api key")
+	}
+
+	logger.Info().
+		Str("api_key_id", id.String()).
+		Msg("API key revoked")
+	return nil
+}
+
+// Authenticate returns the unrevoked API key matching the key and records its use
+func (s *apiKeyService) Authenticate(c context.Context, key string) (*model.APIKey, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	apiKey, err := s.apiKeyRepo.GetByHash(c, util.HashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil, apperror.Unauthorized("invalid api key")
+		}
+		return nil, apperror.Internal("failed to authenticate api key")
+	}
+
+	// Usage tracking is informational, so failing to record it doesn't reject the request
+	if err := s.apiKeyRepo.TouchLastUsed(c, apiKey.TenantID, apiKey.ID, util.NowFromContext(c)); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("api_key_id", apiKey.ID.String()).
+			Msg("Failed to record API key use")
+	}
+	return apiKey, nil
+}
+
+// apiKeyResponse converts an API key to its response without the key
+func apiKeyResponse(apiKey *model.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		KeyPrefix:  apiKey.KeyPrefix,
+		Scopes:     apiKey.Scopes,
+		CreatedBy:  apiKey.CreatedBy,
+		LastUsedAt: apiKey.LastUsedAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
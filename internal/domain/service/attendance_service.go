@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// AttendanceService interface defines attendance service methods
+type AttendanceService interface {
+	GetStudentSummary(c context.Context, tenantID, studentID uuid.UUID, dateFrom, dateTo *time.Time) (*dto.AttendanceSummaryResponse, error)
+	Scan(c context.Context, tenantID uuid.UUID, req dto.AttendanceScanRequest) (*dto.AttendanceScanResponse, error)
+	List(c context.Context, tenantID uuid.UUID, params dto.AttendanceQueryParams) ([]model.Attendance, *dto.PaginationMeta, error)
+	MarkAbsent(c context.Context, tenantID uuid.UUID, date time.Time) (int64, error)
+}
+
+// attendanceService implements AttendanceService
+type attendanceService struct {
+	attendanceRepo   repository.AttendanceRepository
+	scheduleRepo     repository.ScheduleRepository
+	studentRepo      repository.StudentRepository
+	academicYearRepo repository.AcademicYearRepository
+}
+
+// NewAttendanceService creates a new attendance service
+func NewAttendanceService(
+	attendanceRepo repository.AttendanceRepository,
+	scheduleRepo repository.ScheduleRepository,
+	studentRepo repository.StudentRepository,
+	academicYearRepo repository.AcademicYearRepository,
+) AttendanceService {
+	return &attendanceService{
+		attendanceRepo:   attendanceRepo,
+		scheduleRepo:     scheduleRepo,
+		studentRepo:      studentRepo,
+		academicYearRepo: academicYearRepo,
+	}
+}
+
+// autoAbsentRemarks marks the attendance records created by MarkAbsent
+const autoAbsentRemarks = "Marked absent automatically, no attendance was recorded"
+
+// attendanceStatuses lists every status in the order it is reported
+var attendanceStatuses = []model.AttendanceStatus{
+	model.AttendancePresent,
+	model.AttendanceAbsent,
+	model.AttendanceLate,
+	model.AttendanceExcused,
+}
+
+func (s *attendanceService) GetStudentSummary(c context.Context, tenantID, studentID uuid.UUID, dateFrom, dateTo *time.Time) (*dto.AttendanceSummaryResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	student, err := s.studentRepo.GetByID(c, studentID)
+	if err != nil || student.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("student_id", studentID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Student not found in tenant for attendance summary")
+		return nil, apperror.NotFound("student not found")
+	}
+
+	from, to, err := s.resolveDateRange(c, tenantID, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.attendanceRepo.CountByStatusForStudent(c, tenantID, studentID, from, to)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("student_id", studentID.String()).
+			Msg("Failed to aggregate student attendance")
+		return nil, apperror.Internal("failed to get attendance summary")
+	}
+
+	scheduled, err := s.scheduleRepo.CountSessionsForStudent(c, tenantID, studentID, from, to)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("student_id", studentID.String()).
+			Msg("Failed to count scheduled sessions for student")
+		return nil, apperror.Internal("failed to get attendance summary")
+	}
+
+	countByStatus := make(map[model.AttendanceStatus]int64, len(counts))
+	var recorded int64
+	for _, count := range counts {
+		countByStatus[count.Status] += count.Count
+		recorded += count.Count
+	}
+
+	// Use scheduled sessions as the denominator, unless more sessions were recorded than scheduled
+	denominator := scheduled
+	if recorded > denominator {
+		denominator = recorded
+	}
+
+	statuses := make([]dto.AttendanceStatusSummary, 0, len(attendanceStatuses))
+	for _, status := range attendanceStatuses {
+		summary := dto.AttendanceStatusSummary{
+			Status: string(status),
+			Count:  countByStatus[status],
+		}
+		if denominator > 0 {
+			summary.Percentage = math.Round(float64(summary.Count)/float64(denominator)*10000) / 100
+		}
+		statuses = append(statuses, summary)
+	}
+
+	return &dto.AttendanceSummaryResponse{
+		StudentID:         studentID,
+		DateFrom:          from.Format("2006-01-02"),
+		DateTo:            to.Format("2006-01-02"),
+		ScheduledSessions: scheduled,
+		RecordedSessions:  recorded,
+		Statuses:          statuses,
+	}, nil
+}
+
+// List returns a page of the tenant's attendance records; the filters combine, so a class and a single date
+// give the attendance of the whole class that day across its schedules
+func (s *attendanceService) List(c context.Context, tenantID uuid.UUID, params dto.AttendanceQueryParams) ([]model.Attendance, *dto.PaginationMeta, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	// Set defaults
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	attendance, total, err := s.attendanceRepo.List(c, tenantID, offset, params.Limit, repository.AttendanceFilter{
+		StudentID:  params.StudentID,
+		ScheduleID: params.ScheduleID,
+		ClassID:    params.ClassID,
+		DateFrom:   params.DateFrom,
+		DateTo:     params.DateTo,
+		Status:     params.Status,
+		SortBy:     params.SortBy,
+		SortDesc:   params.SortDir == "desc",
+	})
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Interface("params", params).
+			Msg("Failed to list attendance")
+		return nil, nil, apperror.Internal("failed to list attendance")
+	}
+
+	meta := &dto.PaginationMeta{
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalRows:  total,
+		TotalPages: int(math.Ceil(float64(total) / float64(params.Limit))),
+	}
+	return attendance, meta, nil
+}
+
+// MarkAbsent marks the tenant's students absent from their sessions on the date that have no attendance record,
+// returning how many records were created. Running it again for the same date marks nothing new.
+func (s *attendanceService) MarkAbsent(c context.Context, tenantID uuid.UUID, date time.Time) (int64, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	marked, err := s.attendanceRepo.MarkAbsent(c, tenantID, date, autoAbsentRemarks)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Str("date", date.Format("2006-01-02")).
+			Msg("Failed to mark students without attendance absent")
+		return 0, apperror.Internal("failed to mark absent students")
+	}
+	return marked, nil
+}
+
+// Scan records a student as present for today's session of a schedule from a scanned QR code payload.
+// Scanning the same student twice for a session returns the existing record.
+func (s *attendanceService) Scan(c context.Context, tenantID uuid.UUID, req dto.AttendanceScanRequest) (*dto.AttendanceScanResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	payloadTenantID, studentNumber, err := util.ParseStudentQRPayload(req.Payload)
+	if err != nil {
+		return nil, apperror.Validation("invalid QR code")
+	}
+	if payloadTenantID != tenantID {
+		logger.Warn().
+			Str("tenant_id", tenantID.String()).
+			Str("payload_tenant_id", payloadTenantID.String()).
+			Msg("Scanned QR code belongs to another tenant")
+		return nil, apperror.Forbidden("QR code belongs to another tenant")
+	}
+
+	student, err := s.studentRepo.GetByStudentNumber(c, studentNumber, tenantID)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("student_number", studentNumber).
+			Msg("Scanned student not found in tenant")
+		return nil, apperror.NotFound("student not found")
+	}
+
+	schedule, err := s.scheduleRepo.GetByID(c, tenantID, req.ScheduleID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil, err
+		}
+		return nil, apperror.Internal("failed to record attendance")
+	}
+
+	today := util.TodayFromContext(c)
+	if schedule.DayOfWeek != model.DayOfWeekFor(today) {
+		return nil, apperror.Validation("schedule is not held today")
+	}
+
+	scheduled, err := s.scheduleRepo.IsScheduledForStudent(c, tenantID, schedule.ID, student.ID)
+	if err != nil {
+		return nil, apperror.Internal("failed to record attendance")
+	}
+	if !scheduled {
+		return nil, apperror.Validation("student is not scheduled for this session")
+	}
+
+	response := &dto.AttendanceScanResponse{
+		StudentID:      student.ID,
+		StudentNumber:  student.StudentNumber,
+		ScheduleID:     schedule.ID,
+		AttendanceDate: today.Format("2006-01-02"),
+	}
+
+	// Repeated or concurrent scans find the record of the first one, and a scan overrides an absent record
+	attendance := &model.Attendance{
+		TenantID:       tenantID,
+		StudentID:      &student.ID,
+		ScheduleID:     &schedule.ID,
+		Status:         model.AttendancePresent,
+		AttendanceDate: today,
+	}
+	recorded, err := s.attendanceRepo.RecordScan(c, attendance)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("student_id", student.ID.String()).
+			Str("schedule_id", schedule.ID.String()).
+			Msg("Failed to record scanned attendance")
+		return nil, apperror.Internal("failed to record attendance")
+	}
+	if !recorded {
+		response.AttendanceID = attendance.ID
+		response.Status = string(attendance.Status)
+		response.AlreadyRecorded = true
+		return response, nil
+	}
+
+	logger.Info().
+		Str("attendance_id", attendance.ID.String()).
+		Str("student_id", student.ID.String()).
+		Str("schedule_id", schedule.ID.String()).
+		Msg("Attendance recorded from QR scan")
+
+	response.AttendanceID = attendance.ID
+	response.Status = string(attendance.Status)
+	return response, nil
+}
+
+// resolveDateRange fills a missing range from the active academic year, ending no later than today
+func (s *attendanceService) resolveDateRange(c context.Context, tenantID uuid.UUID, dateFrom, dateTo *time.Time) (time.Time, time.Time, error) {
+	today := util.TodayFromContext(c)
+
+	to := today
+	if dateTo != nil {
+		to = *dateTo
+	}
+
+	var from time.Time
+	if dateFrom != nil {
+		from = *dateFrom
+	} else {
+		academicYear, err := s.academicYearRepo.GetActive(c, tenantID)
+		if err != nil {
+			return time.Time{}, time.Time{}, apperror.Validation("date_from is required when there is no active academic year")
+		}
+		from = academicYear.StartDate
+		if dateTo == nil && academicYear.EndDate.Before(to) {
+			to = academicYear.EndDate
+		}
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, apperror.Validation("date_to must not be before date_from")
+	}
+	return from, to, nil
+}
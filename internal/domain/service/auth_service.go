@@ -3,11 +3,19 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/config"
 	"github.com/protocyber/kelasgo-api/internal/domain/dto"
 	"github.com/protocyber/kelasgo-api/internal/domain/model"
 	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/mail"
 	"github.com/protocyber/kelasgo-api/internal/util"
 )
 
@@ -17,8 +25,13 @@ type AuthService interface {
 	Register(c context.Context, req dto.RegisterRequest) (*model.User, error)
 	SelectTenant(c context.Context, userID uuid.UUID, req dto.TenantSelectionRequest) (*dto.TenantSelectionResponse, error)
 	GetUserTenants(c context.Context, userID uuid.UUID) ([]model.TenantUser, error)
+	GetMe(c context.Context, userID, tenantID uuid.UUID) (*dto.MeResponse, error)
+	GetPermissions(c context.Context, userID, tenantID uuid.UUID, tokenRole string) (*dto.PermissionsResponse, error)
 	ChangePassword(c context.Context, userID uuid.UUID, req dto.ChangePasswordRequest) error
 	ValidateToken(c context.Context, token string) (*dto.TokenClaims, error)
+	VerifyEmail(c context.Context, token string) error
+	ResendVerification(c context.Context, req dto.ResendVerificationRequest) error
+	CheckAvailability(c context.Context, req dto.CheckAvailabilityRequest) (*dto.AvailabilityResponse, error)
 }
 
 // authService implements AuthService
@@ -27,7 +40,12 @@ type authService struct {
 	roleRepo           repository.RoleRepository
 	tenantUserRepo     repository.TenantUserRepository
 	tenantUserRoleRepo repository.TenantUserRoleRepository
+	permissionRepo     repository.PermissionRepository
 	jwtService         *util.JWTService
+	passwordHasher     *util.PasswordHasher
+	mailer             *mail.Mailer
+	tenantSettings     TenantSettingService
+	authConfig         *config.AuthConfig
 }
 
 // NewAuthService creates a new auth service
@@ -36,14 +54,24 @@ func NewAuthService(
 	roleRepo repository.RoleRepository,
 	tenantUserRepo repository.TenantUserRepository,
 	tenantUserRoleRepo repository.TenantUserRoleRepository,
+	permissionRepo repository.PermissionRepository,
 	jwtService *util.JWTService,
+	passwordHasher *util.PasswordHasher,
+	mailer *mail.Mailer,
+	tenantSettings TenantSettingService,
+	authConfig *config.AuthConfig,
 ) AuthService {
 	return &authService{
 		userRepo:           userRepo,
 		roleRepo:           roleRepo,
 		tenantUserRepo:     tenantUserRepo,
 		tenantUserRoleRepo: tenantUserRoleRepo,
+		permissionRepo:     permissionRepo,
 		jwtService:         jwtService,
+		passwordHasher:     passwordHasher,
+		mailer:             mailer,
+		tenantSettings:     tenantSettings,
+		authConfig:         authConfig,
 	}
 }
 
@@ -51,32 +79,55 @@ func (s *authService) Login(c context.Context, req dto.LoginRequest) (*dto.Login
 	// Create context logger for service
 	logger := util.NewServiceLogger(c)
 
-	// Get user by email globally (no tenant context needed)
-	user, err := s.userRepo.GetByEmailGlobal(c, req.Email)
+	identifier := req.GetIdentifier()
+
+	// Get user by username or email globally (no tenant context needed)
+	user, err := s.findUserByIdentifier(c, identifier)
 	if err != nil {
 		logger.Error().
 			Err(err).
-			Str("email", req.Email).
+			Str("identifier", identifier).
 			Msg("User not found during login attempt")
-		return nil, errors.New("invalid email or password")
+		return nil, apperror.Unauthorized("invalid username/email or password")
 	}
 
 	// Check if user is active
 	if !user.IsActive {
 		logger.Warn().
 			Str("user_id", user.ID.String()).
-			Str("email", req.Email).
+			Str("identifier", identifier).
 			Msg("Login attempt for deactivated user")
-		return nil, errors.New("user account is deactivated")
+		return nil, apperror.Forbidden("user account is deactivated")
 	}
 
 	// Check password
-	if !util.CheckPassword(req.Password, user.PasswordHash) {
+	if !s.passwordHasher.Check(req.Password, user.PasswordHash) {
 		logger.Warn().
 			Str("user_id", user.ID.String()).
-			Str("email", req.Email).
+			Str("identifier", identifier).
 			Msg("Invalid password during login attempt")
-		return nil, errors.New("invalid email or password")
+		return nil, apperror.Unauthorized("invalid username/email or password")
+	}
+
+	// Reject unverified accounts only after the password matched so the check doesn't leak account existence
+	if s.authConfig.RequireEmailVerification && !user.EmailVerified {
+		logger.Warn().
+			Str("user_id", user.ID.String()).
+			Str("identifier", identifier).
+			Msg("Login attempt with unverified email")
+		return nil, apperror.Forbidden("email address is not verified")
+	}
+
+	// Upgrade hashes created with a lower bcrypt cost while the plain password is at hand
+	if s.passwordHasher.NeedsRehash(user.PasswordHash) {
+		s.rehashPassword(c, user, req.Password)
+	}
+
+	// Single-school deployments skip the select-tenant step when there is nothing to choose
+	if s.authConfig.SingleTenantMode {
+		if response := s.loginIntoOnlyTenant(c, user); response != nil {
+			return response, nil
+		}
 	}
 
 	// Generate JWT token without tenant context (user can select tenant later)
@@ -85,15 +136,17 @@ func (s *authService) Login(c context.Context, req dto.LoginRequest) (*dto.Login
 		uuid.Nil, // No tenant selected yet
 		user.Username,
 		user.Email,
-		"", // No role yet
+		"",  // No role yet
+		nil, // Role expiry overrides apply once a tenant is selected
+		user.MustChangePassword,
 	)
 	if err != nil {
 		logger.Error().
 			Err(err).
 			Str("user_id", user.ID.String()).
-			Str("email", req.Email).
+			Str("identifier", identifier).
 			Msg("Failed to generate JWT token during login")
-		return nil, errors.New("failed to generate token")
+		return nil, apperror.Internal("failed to generate token")
 	}
 
 	// TODO: Implement refresh token logic
@@ -104,16 +157,95 @@ func (s *authService) Login(c context.Context, req dto.LoginRequest) (*dto.Login
 		RefreshToken: refreshToken,
 		ExpiresAt:    expiresAt,
 		User: dto.UserInfo{
-			ID:       user.ID,
-			TenantID: nil, // No tenant selected yet
-			Username: user.Username,
-			Email:    user.Email,
-			FullName: user.FullName,
-			Role:     "", // No role yet
+			ID:                 user.ID,
+			TenantID:           nil, // No tenant selected yet
+			Username:           user.Username,
+			Email:              user.Email,
+			FullName:           user.FullName,
+			Role:               "", // No role yet
+			MustChangePassword: user.MustChangePassword,
 		},
 	}, nil
 }
 
+// loginIntoOnlyTenant returns a tenant-scoped login for a user with exactly one active membership. It returns nil
+// when the user has none or several, or the tenant can't be selected, leaving the two-step login to the caller.
+func (s *authService) loginIntoOnlyTenant(c context.Context, user *model.User) *dto.LoginResponse {
+	logger := util.NewServiceLogger(c)
+
+	tenantUsers, err := s.userRepo.GetUserTenants(c, user.ID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", user.ID.String()).
+			Msg("Failed to get user tenants during single tenant login")
+		return nil
+	}
+	if len(tenantUsers) != 1 {
+		return nil
+	}
+
+	selection, err := s.SelectTenant(c, user.ID, dto.TenantSelectionRequest{TenantID: tenantUsers[0].TenantID.String()})
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("user_id", user.ID.String()).
+			Str("tenant_id", tenantUsers[0].TenantID.String()).
+			Msg("Failed to select the only tenant during login")
+		return nil
+	}
+
+	return &dto.LoginResponse{
+		Token:        selection.Token,
+		RefreshToken: selection.RefreshToken,
+		ExpiresAt:    selection.ExpiresAt,
+		User:         selection.User,
+	}
+}
+
+// findUserByIdentifier looks up a user by email or username, trying the most likely match first
+func (s *authService) findUserByIdentifier(c context.Context, identifier string) (*model.User, error) {
+	if strings.Contains(identifier, "@") {
+		if user, err := s.userRepo.GetByEmailGlobal(c, identifier); err == nil {
+			return user, nil
+		}
+		return s.userRepo.GetByUsername(c, identifier)
+	}
+
+	if user, err := s.userRepo.GetByUsername(c, identifier); err == nil {
+		return user, nil
+	}
+	return s.userRepo.GetByEmailGlobal(c, identifier)
+}
+
+// rehashPassword stores a new hash of the password with the configured cost.
+// Failures are only logged since the login itself already succeeded.
+func (s *authService) rehashPassword(c context.Context, user *model.User, password string) {
+	logger := util.NewServiceLogger(c)
+
+	hashedPassword, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", user.ID.String()).
+			Msg("Failed to rehash password during login")
+		return
+	}
+
+	if err := s.userRepo.UpdatePasswordHash(c, user.ID, hashedPassword); err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", user.ID.String()).
+			Msg("Failed to store rehashed password during login")
+		return
+	}
+	user.PasswordHash = hashedPassword
+
+	logger.Info().
+		Str("user_id", user.ID.String()).
+		Msg("Password rehashed with the configured bcrypt cost")
+}
+
 func (s *authService) Register(c context.Context, req dto.RegisterRequest) (*model.User, error) {
 	// Create context logger for service
 	logger := util.NewServiceLogger(c)
@@ -124,7 +256,7 @@ func (s *authService) Register(c context.Context, req dto.RegisterRequest) (*mod
 		logger.Warn().
 			Str("username", req.Username).
 			Msg("Registration attempt with existing username")
-		return nil, errors.New("username already exists")
+		return nil, apperror.Conflict("username already exists")
 	}
 
 	// Check if email already exists globally
@@ -133,42 +265,137 @@ func (s *authService) Register(c context.Context, req dto.RegisterRequest) (*mod
 		logger.Warn().
 			Str("email", req.Email).
 			Msg("Registration attempt with existing email")
-		return nil, errors.New("email already exists")
+		return nil, apperror.Conflict("email already exists")
 	}
 
 	// Hash password
-	hashedPassword, err := util.HashPassword(req.Password)
+	hashedPassword, err := s.passwordHasher.Hash(req.Password)
 	if err != nil {
 		logger.Error().
 			Err(err).
 			Str("email", req.Email).
 			Msg("Failed to hash password during registration")
-		return nil, errors.New("failed to hash password")
+		return nil, apperror.Internal("failed to hash password")
+	}
+
+	// A missing phone is stored as NULL, like the other write paths do
+	var phone *string
+	if req.Phone != "" {
+		phone = util.NormalizeIndonesianPhonePtr(&req.Phone)
 	}
 
-	// Create user without tenant context
 	user := &model.User{
 		Username:     req.Username,
 		PasswordHash: hashedPassword,
 		Email:        req.Email,
 		FullName:     req.FullName,
-		Phone:        &req.Phone,
+		Phone:        phone,
 		IsActive:     true,
 	}
 
-	err = s.userRepo.Create(c, user)
-	if err != nil {
+	if req.TenantID == "" {
+		// Create user without tenant context
+		err = s.userRepo.Create(c, user)
+		if err != nil {
+			logger.Error().
+				Err(err).
+				Str("email", req.Email).
+				Str("username", req.Username).
+				Msg("Failed to create user during registration")
+			return nil, apperror.Internal("failed to create user")
+		}
+	} else if err := s.registerIntoTenant(c, user, req.TenantID); err != nil {
+		return nil, err
+	}
+
+	// The account exists at this point, a failed email can be retried through resend verification
+	if err := s.sendVerificationEmail(c, user); err != nil {
 		logger.Error().
 			Err(err).
-			Str("email", req.Email).
-			Str("username", req.Username).
-			Msg("Failed to create user during registration")
-		return nil, errors.New("failed to create user")
+			Str("user_id", user.ID.String()).
+			Msg("Failed to send verification email during registration")
 	}
 
 	return user, nil
 }
 
+// registerIntoTenant creates the user as a member of the tenant, assigning the tenant's default role. Only
+// tenants with self-registration enabled can be joined, knowing a tenant's ID is not enough to become a member.
+func (s *authService) registerIntoTenant(c context.Context, user *model.User, tenantIDStr string) error {
+	logger := util.NewServiceLogger(c)
+
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return apperror.Validation("invalid tenant ID format")
+	}
+	if !s.tenantSettings.SelfRegistration(c, tenantID) {
+		logger.Warn().
+			Str("tenant_id", tenantID.String()).
+			Str("username", user.Username).
+			Msg("Registration into a tenant without self-registration")
+		return apperror.Forbidden("tenant does not accept registrations")
+	}
+	defaultRole := s.tenantSettings.DefaultRole(c, tenantID)
+
+	return s.userRepo.WithTransaction(c, tenantID, func(txCtx context.Context) error {
+		// Resolve the role first so a misconfigured tenant does not leave a member without a role
+		var role *model.Role
+		if defaultRole != "" {
+			role, err = s.roleRepo.GetByName(txCtx, defaultRole)
+			if err != nil {
+				logger.Error().
+					Err(err).
+					Str("tenant_id", tenantID.String()).
+					Str("role", defaultRole).
+					Msg("Default registration role not found")
+				return apperror.Validation("tenant does not accept registrations")
+			}
+		}
+
+		if err := s.userRepo.Create(txCtx, user); err != nil {
+			logger.Error().
+				Err(err).
+				Str("email", user.Email).
+				Str("username", user.Username).
+				Msg("Failed to create user during registration")
+			return apperror.Internal("failed to create user")
+		}
+
+		tenantUser := &model.TenantUser{
+			TenantID: tenantID,
+			UserID:   user.ID,
+			IsActive: true,
+		}
+		if err := s.tenantUserRepo.Create(txCtx, tenantUser); err != nil {
+			logger.Error().
+				Err(err).
+				Str("user_id", user.ID.String()).
+				Str("tenant_id", tenantID.String()).
+				Msg("Failed to create tenant-user relationship during registration")
+			return apperror.Internal("failed to create tenant-user relationship")
+		}
+
+		if role == nil {
+			return nil
+		}
+
+		tenantUserRole := &model.TenantUserRole{
+			TenantUserID: tenantUser.ID,
+			RoleID:       role.ID,
+		}
+		if err := s.tenantUserRoleRepo.Create(txCtx, tenantUserRole); err != nil {
+			logger.Error().
+				Err(err).
+				Str("tenant_user_id", tenantUser.ID.String()).
+				Str("role_id", role.ID.String()).
+				Msg("Failed to assign default role during registration")
+			return apperror.Internal("failed to assign default role")
+		}
+
+		return nil
+	})
+}
+
 func (s *authService) SelectTenant(c context.Context, userID uuid.UUID, req dto.TenantSelectionRequest) (*dto.TenantSelectionResponse, error) {
 	// Create context logger for service
 	logger := util.NewServiceLogger(c)
@@ -181,7 +408,7 @@ func (s *authService) SelectTenant(c context.Context, userID uuid.UUID, req dto.
 			Str("tenant_id", req.TenantID).
 			Str("user_id", userID.String()).
 			Msg("Failed to parse tenant ID during tenant selection")
-		return nil, errors.New("invalid tenant ID format")
+		return nil, apperror.Validation("invalid tenant ID format")
 	}
 
 	// Get user
@@ -191,7 +418,7 @@ func (s *authService) SelectTenant(c context.Context, userID uuid.UUID, req dto.
 			Err(err).
 			Str("user_id", userID.String()).
 			Msg("User not found during tenant selection")
-		return nil, errors.New("user not found")
+		return nil, apperror.NotFound("user not found")
 	}
 
 	// Check if user belongs to this tenant
@@ -202,15 +429,30 @@ func (s *authService) SelectTenant(c context.Context, userID uuid.UUID, req dto.
 			Str("user_id", userID.String()).
 			Str("tenant_id", tenantID.String()).
 			Msg("User not authorized for this tenant")
-		return nil, errors.New("user not authorized for this tenant")
+		return nil, apperror.Forbidden("user not authorized for this tenant")
+	}
+
+	// Suspended memberships can't be used to enter the tenant
+	if !tenantUser.IsActive {
+		logger.Warn().
+			Str("user_id", userID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Tenant selection attempt with deactivated membership")
+		return nil, apperror.Forbidden("user membership in this tenant is deactivated")
 	}
 
 	// Get role name from TenantUserRoles
 	roleName := ""
+	var roleNames []string
 	tenantUserRoles, err := s.tenantUserRoleRepo.GetRolesByTenantUser(c, tenantUser.ID)
 	if err == nil && len(tenantUserRoles) > 0 && tenantUserRoles[0].Role != nil {
 		roleName = tenantUserRoles[0].Role.Name
 	}
+	for _, tenantUserRole := range tenantUserRoles {
+		if tenantUserRole.Role != nil {
+			roleNames = append(roleNames, tenantUserRole.Role.Name)
+		}
+	}
 
 	// Generate JWT token with tenant context
 	token, expiresAt, err := s.jwtService.GenerateToken(
@@ -219,6 +461,8 @@ func (s *authService) SelectTenant(c context.Context, userID uuid.UUID, req dto.
 		user.Username,
 		user.Email,
 		roleName,
+		roleNames, // The shortest expiry of the user's roles applies
+		user.MustChangePassword,
 	)
 	if err != nil {
 		logger.Error().
@@ -226,7 +470,7 @@ func (s *authService) SelectTenant(c context.Context, userID uuid.UUID, req dto.
 			Str("user_id", user.ID.String()).
 			Str("tenant_id", tenantID.String()).
 			Msg("Failed to generate JWT token during tenant selection")
-		return nil, errors.New("failed to generate token")
+		return nil, apperror.Internal("failed to generate token")
 	}
 
 	// TODO: Implement refresh token logic
@@ -237,12 +481,13 @@ func (s *authService) SelectTenant(c context.Context, userID uuid.UUID, req dto.
 		RefreshToken: refreshToken,
 		ExpiresAt:    expiresAt,
 		User: dto.UserInfo{
-			ID:       user.ID,
-			TenantID: &tenantID,
-			Username: user.Username,
-			Email:    user.Email,
-			FullName: user.FullName,
-			Role:     roleName,
+			ID:                 user.ID,
+			TenantID:           &tenantID,
+			Username:           user.Username,
+			Email:              user.Email,
+			FullName:           user.FullName,
+			Role:               roleName,
+			MustChangePassword: user.MustChangePassword,
 		},
 	}, nil
 }
@@ -257,11 +502,163 @@ func (s *authService) GetUserTenants(c context.Context, userID uuid.UUID) ([]mod
 			Err(err).
 			Str("user_id", userID.String()).
 			Msg("Failed to get user tenants")
-		return nil, errors.New("failed to get user tenants")
+		return nil, apperror.Internal("failed to get user tenants")
 	}
 	return tenantUsers, nil
 }
 
+func (s *authService) GetMe(c context.Context, userID, tenantID uuid.UUID) (*dto.MeResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	user, err := s.userRepo.GetByID(c, userID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", userID.String()).
+			Msg("User not found while loading profile")
+		return nil, apperror.NotFound("user not found")
+	}
+
+	tenantUsers, err := s.userRepo.GetUserTenants(c, userID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", userID.String()).
+			Msg("Failed to get user tenants while loading profile")
+		return nil, apperror.Internal("failed to get user tenants")
+	}
+
+	me := &dto.MeResponse{
+		ID:            user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		FullName:      user.FullName,
+		Phone:         user.Phone,
+		IsActive:      user.IsActive,
+		EmailVerified: user.EmailVerified,
+		Roles:         []string{},
+		Tenants:       make([]dto.UserTenantInfo, 0, len(tenantUsers)),
+	}
+
+	for _, tenantUser := range tenantUsers {
+		info := dto.UserTenantInfo{
+			TenantID:     tenantUser.TenantID,
+			TenantUserID: tenantUser.ID,
+			IsActive:     tenantUser.IsActive,
+		}
+		if tenantUser.Tenant != nil {
+			info.TenantName = tenantUser.Tenant.Name
+		}
+		me.Tenants = append(me.Tenants, info)
+	}
+
+	// Roles are only meaningful when a tenant has been selected
+	if tenantID == uuid.Nil {
+		return me, nil
+	}
+	me.TenantID = &tenantID
+
+	tenantUser, err := s.tenantUserRepo.GetByTenantAndUser(c, tenantID, userID)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("user_id", userID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Selected tenant membership not found while loading profile")
+		return me, nil
+	}
+
+	tenantUserRoles, err := s.tenantUserRoleRepo.GetRolesByTenantUser(c, tenantUser.ID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_user_id", tenantUser.ID.String()).
+			Msg("Failed to get roles while loading profile")
+		return nil, apperror.Internal("failed to get user roles")
+	}
+
+	for _, tenantUserRole := range tenantUserRoles {
+		if tenantUserRole.Role != nil {
+			me.Roles = append(me.Roles, tenantUserRole.Role.Name)
+		}
+	}
+	if len(me.Roles) > 0 {
+		me.Role = me.Roles[0]
+	}
+
+	return me, nil
+}
+
+// GetPermissions returns the roles of the user in the tenant and the permissions they grant, including the
+// fallback permissions of the token role that the permission middleware honors
+func (s *authService) GetPermissions(c context.Context, userID, tenantID uuid.UUID, tokenRole string) (*dto.PermissionsResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	tenantUser, err := s.tenantUserRepo.GetByTenantAndUser(c, tenantID, userID)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("user_id", userID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Selected tenant membership not found while loading permissions")
+		return nil, apperror.Forbidden("user not authorized for this tenant")
+	}
+
+	tenantUserRoles, err := s.tenantUserRoleRepo.GetRolesByTenantUser(c, tenantUser.ID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_user_id", tenantUser.ID.String()).
+			Msg("Failed to get roles while loading permissions")
+		return nil, apperror.Internal("failed to get user roles")
+	}
+
+	granted, err := s.permissionRepo.ListForUser(c, tenantID, userID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", userID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to get permissions")
+		return nil, apperror.Internal("failed to get user permissions")
+	}
+
+	response := &dto.PermissionsResponse{
+		TenantID:    tenantID,
+		Roles:       []string{},
+		Permissions: []string{},
+	}
+	for _, tenantUserRole := range tenantUserRoles {
+		if tenantUserRole.Role != nil {
+			response.Roles = append(response.Roles, tenantUserRole.Role.Name)
+		}
+	}
+
+	// Inactive memberships have no grants, so they don't get fallback permissions either
+	permissions := make(map[string]bool, len(granted))
+	for _, permission := range granted {
+		permissions[permission] = true
+	}
+	if tenantUser.IsActive {
+		for role, rolePermissions := range model.RoleFallbackPermissions {
+			if !strings.EqualFold(role, tokenRole) {
+				continue
+			}
+			for _, permission := range rolePermissions {
+				permissions[permission] = true
+			}
+		}
+	}
+	for permission := range permissions {
+		response.Permissions = append(response.Permissions, permission)
+	}
+	sort.Strings(response.Permissions)
+
+	return response, nil
+}
+
 func (s *authService) ChangePassword(c context.Context, userID uuid.UUID, req dto.ChangePasswordRequest) error {
 	// Create context logger for service
 	logger := util.NewServiceLogger(c)
@@ -273,30 +670,31 @@ func (s *authService) ChangePassword(c context.Context, userID uuid.UUID, req dt
 			Err(err).
 			Str("user_id", userID.String()).
 			Msg("User not found during password change")
-		return errors.New("user not found")
+		return apperror.NotFound("user not found")
 	}
 
 	// Check current password
-	if !util.CheckPassword(req.CurrentPassword, user.PasswordHash) {
+	if !s.passwordHasher.Check(req.CurrentPassword, user.PasswordHash) {
 		logger.Warn().
 			Str("user_id", userID.String()).
 			Str("username", user.Username).
 			Msg("Incorrect current password during password change")
-		return errors.New("current password is incorrect")
+		return apperror.Validation("current password is incorrect")
 	}
 
 	// Hash new password
-	hashedPassword, err := util.HashPassword(req.NewPassword)
+	hashedPassword, err := s.passwordHasher.Hash(req.NewPassword)
 	if err != nil {
 		logger.Error().
 			Err(err).
 			Str("user_id", userID.String()).
 			Msg("Failed to hash new password during password change")
-		return errors.New("failed to hash new password")
+		return apperror.Internal("failed to hash new password")
 	}
 
-	// Update password
+	// Update password, which also satisfies a password change required by an admin reset
 	user.PasswordHash = hashedPassword
+	user.MustChangePassword = false
 
 	err = s.userRepo.Update(c, user)
 	if err != nil {
@@ -304,7 +702,7 @@ func (s *authService) ChangePassword(c context.Context, userID uuid.UUID, req dt
 			Err(err).
 			Str("user_id", userID.String()).
 			Msg("Failed to update password in database")
-		return errors.New("failed to update password")
+		return apperror.Internal("failed to update password")
 	}
 
 	return nil
@@ -320,7 +718,7 @@ func (s *authService) ValidateToken(c context.Context, token string) (*dto.Token
 		logger.Error().
 			Err(err).
 			Msg("Token validation failed")
-		return nil, errors.New("invalid token")
+		return nil, apperror.Unauthorized("invalid token")
 	}
 
 	// Convert JWT claims to DTO claims
@@ -338,3 +736,129 @@ func (s *authService) ValidateToken(c context.Context, token string) (*dto.Token
 
 	return tokenClaims, nil
 }
+
+// VerifyEmail marks the email address in the verification token as verified
+func (s *authService) VerifyEmail(c context.Context, token string) error {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	claims, err := s.jwtService.ValidateEmailVerificationToken(token)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Msg("Invalid email verification token")
+		return apperror.Validation("invalid or expired verification token")
+	}
+
+	user, err := s.userRepo.GetByID(c, claims.UserID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", claims.UserID.String()).
+			Msg("User not found during email verification")
+		return apperror.Validation("invalid or expired verification token")
+	}
+
+	// A token issued for a previous address must not verify the current one
+	if !strings.EqualFold(user.Email, claims.Email) {
+		logger.Warn().
+			Str("user_id", user.ID.String()).
+			Msg("Email verification token does not match the current email")
+		return apperror.Validation("invalid or expired verification token")
+	}
+
+	if user.EmailVerified {
+		return nil
+	}
+
+	if err := s.userRepo.MarkEmailVerified(c, user.ID, time.Now()); err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", user.ID.String()).
+			Msg("Failed to mark email as verified")
+		return apperror.Internal("failed to verify email")
+	}
+
+	return nil
+}
+
+// ResendVerification sends a new verification email. Unknown or already verified addresses
+// are ignored silently so the endpoint can't be used to discover accounts.
+func (s *authService) ResendVerification(c context.Context, req dto.ResendVerificationRequest) error {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	user, err := s.userRepo.GetByEmailGlobal(c, req.Email)
+	if err != nil {
+		logger.Info().
+			Str("email", req.Email).
+			Msg("Verification resend requested for unknown email")
+		return nil
+	}
+
+	if user.EmailVerified {
+		logger.Info().
+			Str("user_id", user.ID.String()).
+			Msg("Verification resend requested for already verified email")
+		return nil
+	}
+
+	if err := s.sendVerificationEmail(c, user); err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", user.ID.String()).
+			Msg("Failed to resend verification email")
+		return apperror.Internal("failed to send verification email")
+	}
+
+	return nil
+}
+
+// CheckAvailability reports whether a username and email could be registered. Both are unique across
+// tenants, as Register checks them. Only availability is returned, nothing about the account holding them.
+func (s *authService) CheckAvailability(c context.Context, req dto.CheckAvailabilityRequest) (*dto.AvailabilityResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	response := &dto.AvailabilityResponse{}
+	if req.Username != "" {
+		_, err := s.userRepo.GetByUsername(c, req.Username)
+		if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+			logger.Error().
+				Err(err).
+				Msg("Failed to check username availability")
+			return nil, apperror.Internal("failed to check availability")
+		}
+		available := err != nil
+		response.Username = &available
+	}
+	if req.Email != "" {
+		_, err := s.userRepo.GetByEmailGlobal(c, req.Email)
+		if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+			logger.Error().
+				Err(err).
+				Msg("Failed to check email availability")
+			return nil, apperror.Internal("failed to check availability")
+		}
+		available := err != nil
+		response.Email = &available
+	}
+	return response, nil
+}
+
+// sendVerificationEmail queues an email containing a verification link for the user's current address
+func (s *authService) sendVerificationEmail(c context.Context, user *model.User) error {
+	token, err := s.jwtService.GenerateEmailVerificationToken(user.ID, user.Email, s.authConfig.EmailVerificationExpireTime)
+	if err != nil {
+		return err
+	}
+
+	link := s.authConfig.EmailVerificationURL + "?token=" + url.QueryEscape(token)
+	return s.mailer.Enqueue(mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\n"+
+			"The link expires in %d hours. If you did not create an account, you can ignore this email.\n",
+			user.FullName, link, s.authConfig.EmailVerificationExpireTime),
+	})
+}
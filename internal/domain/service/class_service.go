@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// ClassService interface defines class service methods
+type ClassService interface {
+	Promote(c context.Context, tenantID, classID uuid.UUID, req dto.PromoteClassRequest) (*dto.PromoteClassResponse, error)
+	Gradebook(c context.Context, tenantID, classID uuid.UUID, params dto.GradebookQueryParams) (*dto.GradebookResponse, error)
+	AssignHomeroomTeacher(c context.Context, tenantID, classID uuid.UUID, req dto.AssignHomeroomTeacherRequest) (*dto.HomeroomTeacherResponse, error)
+}
+
+// classService implements ClassService
+type classService struct {
+	classRepo        repository.ClassRepository
+	studentRepo      repository.StudentRepository
+	academicYearRepo repository.AcademicYearRepository
+	classSubjectRepo repository.ClassSubjectRepository
+	gradeRepo        repository.GradeRepository
+	teacherRepo      repository.TeacherRepository
+}
+
+// NewClassService creates a new class service
+func NewClassService(
+	classRepo repository.ClassRepository,
+	studentRepo repository.StudentRepository,
+	academicYearRepo repository.AcademicYearRepository,
+	classSubjectRepo repository.ClassSubjectRepository,
+	gradeRepo repository.GradeRepository,
+	teacherRepo repository.TeacherRepository,
+) ClassService {
+	return &classService{
+		classRepo:        classRepo,
+		studentRepo:      studentRepo,
+		academicYearRepo: academicYearRepo,
+		classSubjectRepo: classSubjectRepo,
+		gradeRepo:        gradeRepo,
+		teacherRepo:      teacherRepo,
+	}
+}
+
+func (s *classService) Promote(c context.Context, tenantID, classID uuid.UUID, req dto.PromoteClassRequest) (*dto.PromoteClassResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	sourceClass, err := s.classRepo.GetByID(c, classID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_id", classID.String()).
+			Msg("Source class not found during promotion")
+		return nil, apperror.NotFound("source class not found")
+	}
+	if sourceClass.TenantID != tenantID {
+		logger.Warn().
+			Str("class_id", classID.String()).
+			Str("expected_tenant", tenantID.String()).
+			Str("actual_tenant", sourceClass.TenantID.String()).
+			Msg("Source class does not belong to the specified tenant")
+		return nil, apperror.NotFound("source class not found")
+	}
+
+	targetClass, err := s.resolveTargetClass(c, tenantID, sourceClass, req)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("class_id", classID.String()).
+			Msg("Failed to resolve target class for promotion")
+		return nil, err
+	}
+
+	if targetClass.ID == sourceClass.ID {
+		return nil, apperror.Validation("target class must be different from source class")
+	}
+	if targetClass.TenantID != tenantID {
+		logger.Warn().
+			Str("target_class_id", targetClass.ID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Target class does not belong to the specified tenant")
+		return nil, apperror.NotFound("target class not found")
+	}
+
+	if req.RequireConsecutiveYear {
+		if err := s.validateConsecutiveYears(c, tenantID, sourceClass, targetClass); err != nil {
+			logger.Warn().
+				Err(err).
+				Str("class_id", sourceClass.ID.String()).
+				Str("target_class_id", targetClass.ID.String()).
+				Msg("Promotion rejected: academic years are not consecutive")
+			return nil, err
+		}
+	}
+
+	moved, err := s.studentRepo.ReassignClass(c, tenantID, sourceClass.ID, targetClass.ID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_id", sourceClass.ID.String()).
+			Str("target_class_id", targetClass.ID.String()).
+			Msg("Failed to reassign students during promotion")
+		return nil, apperror.Internal("failed to promote class")
+	}
+
+	logger.Info().
+		Str("class_id", sourceClass.ID.String()).
+		Str("target_class_id", targetClass.ID.String()).
+		Int64("students_moved", moved).
+		Msg("Class promoted")
+
+	return &dto.PromoteClassResponse{
+		SourceClassID: sourceClass.ID,
+		TargetClassID: targetClass.ID,
+		StudentsMoved: moved,
+	}, nil
+}
+
+// AssignHomeroomTeacher sets or clears the homeroom teacher of the class. A teacher may be homeroom teacher
+// of several classes; the others are listed in the response so the caller can warn about it.
+func (s *classService) AssignHomeroomTeacher(c context.Context, tenantID, classID uuid.UUID, req dto.AssignHomeroomTeacherRequest) (*dto.HomeroomTeacherResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	class, err := s.classRepo.GetByID(c, classID)
+	if err != nil || class.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("class_id", classID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Class not found in tenant for homeroom teacher assignment")
+		return nil, apperror.NotFound("class not found")
+	}
+
+	response := &dto.HomeroomTeacherResponse{
+		ID:                class.ID,
+		Name:              class.Name,
+		GradeLevel:        class.GradeLevel,
+		AcademicYearID:    class.AcademicYearID,
+		HomeroomTeacherID: req.TeacherID,
+	}
+	if req.TeacherID != nil {
+		teacher, err := s.teacherRepo.GetByID(c, *req.TeacherID)
+		if err != nil || teacher.TenantID != tenantID {
+			logger.Warn().
+				Err(err).
+				Str("teacher_id", req.TeacherID.String()).
+				Str("tenant_id", tenantID.String()).
+				Msg("Teacher not found in tenant for homeroom teacher assignment")
+			return nil, apperror.NotFound("teacher not found")
+		}
+		if teacher.TenantUser != nil && teacher.TenantUser.User != nil {
+			response.HomeroomTeacherName = &teacher.TenantUser.User.FullName
+		}
+
+		homeroomClasses, err := s.classRepo.GetByHomeroomTeacher(c, tenantID, teacher.ID)
+		if err != nil {
+			logger.Error().
+				Err(err).
+				Str("teacher_id", teacher.ID.String()).
+				Msg("Failed to get homeroom classes of teacher")
+			return nil, apperror.Internal("failed to assign homeroom teacher")
+		}
+		for _, homeroomClass := range homeroomClasses {
+			if homeroomClass.ID != class.ID {
+				response.OtherHomeroomClasses = append(response.OtherHomeroomClasses, dto.HomeroomClass{
+					ID:   homeroomClass.ID,
+					Name: homeroomClass.Name,
+				})
+			}
+		}
+	}
+
+	if err := s.classRepo.UpdateHomeroomTeacher(c, tenantID, class.ID, req.TeacherID); err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil, err
+		}
+		logger.Error().
+			Err(err).
+			Str("class_id", class.ID.String()).
+			Msg("Failed to update homeroom teacher")
+		return nil, apperror.Internal("failed to assign homeroom teacher")
+	}
+
+	event := logger.Info().
+		Str("class_id", class.ID.String()).
+		Int("other_homeroom_classes", len(response.OtherHomeroomClasses))
+	if req.TeacherID != nil {
+		event = event.Str("teacher_id", req.TeacherID.String())
+	}
+	event.Msg("Homeroom teacher assigned")
+
+	return response, nil
+}
+
+func (s *classService) Gradebook(c context.Context, tenantID, classID uuid.UUID, params dto.GradebookQueryParams) (*dto.GradebookResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	class, err := s.classRepo.GetByID(c, classID)
+	if err != nil || class.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("class_id", classID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Class not found in tenant for gradebook")
+		return nil, apperror.NotFound("class not found")
+	}
+
+	academicYear, err := s.academicYearRepo.GetActive(c, tenantID)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("No active academic year for gradebook")
+		return nil, apperror.Validation("no active academic year found")
+	}
+
+	students, err := s.studentRepo.GetAllByClass(c, tenantID, classID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_id", classID.String()).
+			Msg("Failed to get students for gradebook")
+		return nil, apperror.Internal("failed to build gradebook")
+	}
+
+	classSubjects, err := s.classSubjectRepo.GetAllByClass(c, tenantID, classID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_id", classID.String()).
+			Msg("Failed to get class subjects for gradebook")
+		return nil, apperror.Internal("failed to build gradebook")
+	}
+
+	entries, err := s.gradeRepo.GetClassGradebook(c, tenantID, classID, academicYear.ID, params.GradeType)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_id", classID.String()).
+			Msg("Failed to aggregate grades for gradebook")
+		return nil, apperror.Internal("failed to build gradebook")
+	}
+
+	scale, err := s.gradeRepo.GetGradingScale(c, tenantID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to get grading scale for gradebook")
+		return nil, apperror.Internal("failed to build gradebook")
+	}
+
+	// Index aggregated grades by student and class subject
+	type cellKey struct {
+		studentID      uuid.UUID
+		classSubjectID uuid.UUID
+	}
+	entriesByCell := make(map[cellKey][]repository.GradebookEntry, len(entries))
+	for _, entry := range entries {
+		key := cellKey{entry.StudentID, entry.ClassSubjectID}
+		entriesByCell[key] = append(entriesByCell[key], entry)
+	}
+
+	subjects := make([]dto.GradebookSubject, 0, len(classSubjects))
+	for _, classSubject := range classSubjects {
+		subject := dto.GradebookSubject{
+			ClassSubjectID: classSubject.ID,
+			SubjectID:      classSubject.SubjectID,
+		}
+		if classSubject.Subject != nil {
+			subject.Name = classSubject.Subject.Name
+			subject.Code = classSubject.Subject.Code
+		}
+		subjects = append(subjects, subject)
+	}
+
+	rows := make([]dto.GradebookRow, 0, len(students))
+	for _, student := range students {
+		row := dto.GradebookRow{
+			StudentID:     student.ID,
+			StudentNumber: student.StudentNumber,
+			Grades:        make([]dto.GradebookCell, 0, len(subjects)),
+		}
+		if student.TenantUser != nil && student.TenantUser.User != nil {
+			row.FullName = student.TenantUser.User.FullName
+		}
+		for _, subject := range subjects {
+			cell := gradebookCell(scale, entriesByCell[cellKey{student.ID, subject.ClassSubjectID}], params.GradeType == "")
+			cell.ClassSubjectID = subject.ClassSubjectID
+			row.Grades = append(row.Grades, cell)
+		}
+		rows = append(rows, row)
+	}
+
+	return &dto.GradebookResponse{
+		ClassID:        classID,
+		AcademicYearID: academicYear.ID,
+		GradeType:      params.GradeType,
+		Subjects:       subjects,
+		Rows:           rows,
+	}, nil
+}
+
+// gradebookCell combines the per grade type aggregates of a cell into its average score, and its weighted
+// final score when weighted is set, with the letter grade of the score shown
+func gradebookCell(scale *model.GradingScale, entries []repository.GradebookEntry, weighted bool) dto.GradebookCell {
+	var cell dto.GradebookCell
+	var total float64
+	averages := make(map[string]float64, len(entries))
+	for _, entry := range entries {
+		if entry.GradeType == nil || entry.AverageScore == nil {
+			continue
+		}
+		averages[*entry.GradeType] = *entry.AverageScore
+		total += *entry.AverageScore * float64(entry.GradeCount)
+		cell.GradeCount += entry.GradeCount
+	}
+	if cell.GradeCount == 0 {
+		return cell
+	}
+
+	average := math.Round(total/float64(cell.GradeCount)*100) / 100
+	cell.AverageScore = &average
+	if weighted {
+		cell.FinalScore = scale.WeightedScore(averages)
+		cell.LetterGrade = scale.LetterFor(cell.FinalScore)
+	} else {
+		cell.LetterGrade = scale.LetterFor(cell.AverageScore)
+	}
+	return cell
+}
+
+// resolveTargetClass returns the explicit target class or finds the single class at the incremented grade level
+func (s *classService) resolveTargetClass(c context.Context, tenantID uuid.UUID, sourceClass *model.Class, req dto.PromoteClassRequest) (*model.Class, error) {
+	if req.TargetClassID != nil {
+		targetClass, err := s.classRepo.GetByID(c, *req.TargetClassID)
+		if err != nil {
+			return nil, apperror.NotFound("target class not found")
+		}
+		return targetClass, nil
+	}
+
+	if req.GradeIncrement == nil {
+		return nil, apperror.Validation("either target_class_id or grade_increment is required")
+	}
+	if sourceClass.GradeLevel == nil {
+		return nil, apperror.Validation("source class has no grade level; specify target_class_id")
+	}
+
+	candidates, err := s.classRepo.GetByGradeLevel(c, tenantID, *sourceClass.GradeLevel+*req.GradeIncrement)
+	if err != nil {
+		return nil, apperror.Internal("failed to find target class")
+	}
+
+	// Prefer classes in the year following the source class when years are known
+	if len(candidates) > 1 && sourceClass.AcademicYear != nil {
+		nextYear, err := s.academicYearRepo.GetNext(c, tenantID, sourceClass.AcademicYear.StartDate)
+		if err == nil {
+			var inNextYear []model.Class
+			for _, candidate := range candidates {
+				if candidate.AcademicYearID != nil && *candidate.AcademicYearID == nextYear.ID {
+					inNextYear = append(inNextYear, candidate)
+				}
+			}
+			candidates = inNextYear
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, apperror.Validation("no class found at the target grade level")
+	case 1:
+		return &candidates[0], nil
+	default:
+		return nil, apperror.Validation("multiple classes found at the target grade level; specify target_class_id")
+	}
+}
+
+// validateConsecutiveYears ensures the target class belongs to the academic year right after the source class
+func (s *classService) validateConsecutiveYears(c context.Context, tenantID uuid.UUID, sourceClass, targetClass *model.Class) error {
+	if sourceClass.AcademicYear == nil || targetClass.AcademicYearID == nil {
+		return apperror.Validation("both classes must have an academic year to verify consecutive years")
+	}
+
+	nextYear, err := s.academicYearRepo.GetNext(c, tenantID, sourceClass.AcademicYear.StartDate)
+	if err != nil {
+		return apperror.Validation("no academic year found after the source class year")
+	}
+	if nextYear.ID != *targetClass.AcademicYearID {
+		return apperror.Validation("target class is not in the academic year following the source class")
+	}
+	return nil
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// ClassSubjectService interface defines class subject service methods
+type ClassSubjectService interface {
+	Create(c context.Context, tenantID uuid.UUID, req dto.CreateClassSubjectRequest) (*model.ClassSubject, error)
+	AssignToTeacher(c context.Context, tenantID, teacherID uuid.UUID, req dto.AssignTeacherClassSubjectsRequest) (*dto.AssignTeacherClassSubjectsResponse, error)
+	GetByID(c context.Context, tenantID, id uuid.UUID) (*model.ClassSubject, error)
+	Update(c context.Context, tenantID, id uuid.UUID, req dto.UpdateClassSubjectRequest) (*model.ClassSubject, error)
+	Delete(c context.Context, tenantID, id uuid.UUID) error
+	List(c context.Context, tenantID uuid.UUID, params dto.ClassSubjectQueryParams) ([]model.ClassSubject, *dto.PaginationMeta, error)
+	GetByTeacher(c context.Context, tenantID, teacherID uuid.UUID, params dto.QueryParams) ([]model.ClassSubject, *dto.PaginationMeta, error)
+}
+
+// classSubjectService implements ClassSubjectService
+type classSubjectService struct {
+	classSubjectRepo repository.ClassSubjectRepository
+	classRepo        repository.ClassRepository
+	subjectRepo      repository.SubjectRepository
+	teacherRepo      repository.TeacherRepository
+}
+
+// NewClassSubjectService creates a new class subject service
+func NewClassSubjectService(
+	classSubjectRepo repository.ClassSubjectRepository,
+	classRepo repository.ClassRepository,
+	subjectRepo repository.SubjectRepository,
+	teacherRepo repository.TeacherRepository,
+) ClassSubjectService {
+	return &classSubjectService{
+		classSubjectRepo: classSubjectRepo,
+		classRepo:        classRepo,
+		subjectRepo:      subjectRepo,
+		teacherRepo:      teacherRepo,
+	}
+}
+
+func (s *classSubjectService) Create(c context.Context, tenantID uuid.UUID, req dto.CreateClassSubjectRequest) (*model.ClassSubject, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	if err := s.validateReferences(c, tenantID, req.ClassID, req.SubjectID, req.TeacherID); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Class subject creation rejected due to invalid references")
+		return nil, err
+	}
+
+	// Prevent assigning the same subject to the same class twice
+	existing, err := s.classSubjectRepo.GetByClassAndSubject(c, tenantID, *req.ClassID, *req.SubjectID)
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		logger.Error().
+			Err(err).
+			Str("class_id", req.ClassID.String()).
+			Str("subject_id", req.SubjectID.String()).
+			Msg("Failed to check existing class subject")
+		return nil, apperror.Internal("failed to create class subject")
+	}
+	if existing != nil {
+		logger.Warn().
+			Str("class_id", req.ClassID.String()).
+			Str("subject_id", req.SubjectID.String()).
+			Msg("Class subject creation attempt for already assigned subject")
+		return nil, apperror.Conflict("subject is already assigned to this class")
+	}
+
+	classSubject := &model.ClassSubject{
+		TenantID:  tenantID,
+		ClassID:   req.ClassID,
+		SubjectID: req.SubjectID,
+		TeacherID: req.TeacherID,
+	}
+
+	err = s.classSubjectRepo.Create(c, classSubject)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_id", req.ClassID.String()).
+			Str("subject_id", req.SubjectID.String()).
+			Msg("Failed to create class subject in database")
+		return nil, apperror.Internal("failed to create class subject")
+	}
+
+	return classSubject, nil
+}
+
+// AssignToTeacher creates a class subject taught by the teacher for every class and subject pair in one
+// transaction. Pairs whose subject is already assigned to the class, to any teacher, are skipped.
+func (s *classSubjectService) AssignToTeacher(c context.Context, tenantID, teacherID uuid.UUID, req dto.AssignTeacherClassSubjectsRequest) (*dto.AssignTeacherClassSubjectsResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	if err := s.validateReferences(c, tenantID, nil, nil, &teacherID); err != nil {
+		return nil, err
+	}
+
+	response := &dto.AssignTeacherClassSubjectsResponse{
+		TeacherID: teacherID,
+		Created:   []dto.AssignedClassSubject{},
+		Skipped:   []dto.TeacherClassSubjectItem{},
+	}
+
+	// Validate every class and subject once, even when they appear in several pairs
+	validClasses := make(map[uuid.UUID]bool)
+	validSubjects := make(map[uuid.UUID]bool)
+	seen := make(map[dto.TeacherClassSubjectItem]bool, len(req.Assignments))
+	var classSubjects []model.ClassSubject
+	for _, assignment := range req.Assignments {
+		if !validClasses[assignment.ClassID] {
+			if err := s.validateReferences(c, tenantID, &assignment.ClassID, nil, nil); err != nil {
+				if errors.Is(err, apperror.ErrNotFound) {
+					return nil, apperror.NotFound(fmt.Sprintf("class %s not found", assignment.ClassID))
+				}
+				return nil, err
+			}
+			validClasses[assignment.ClassID] = true
+		}
+		if !validSubjects[assignment.SubjectID] {
+			if err := s.validateReferences(c, tenantID, nil, &assignment.SubjectID, nil); err != nil {
+				if errors.Is(err, apperror.ErrNotFound) {
+					return nil, apperror.NotFound(fmt.Sprintf("subject %s not found", assignment.SubjectID))
+				}
+				return nil, err
+			}
+			validSubjects[assignment.SubjectID] = true
+		}
+
+		if seen[assignment] {
+			response.Skipped = append(response.Skipped, assignment)
+			continue
+		}
+		seen[assignment] = true
+
+		classID, subjectID := assignment.ClassID, assignment.SubjectID
+		classSubjects = append(classSubjects, model.ClassSubject{
+			TenantID:  tenantID,
+			ClassID:   &classID,
+			SubjectID: &subjectID,
+			TeacherID: &teacherID,
+		})
+	}
+
+	created, err := s.classSubjectRepo.BulkCreate(c, tenantID, classSubjects)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("teacher_id", teacherID.String()).
+			Int("count", len(classSubjects)).
+			Msg("Failed to bulk create class subjects in database")
+		return nil, apperror.Internal("failed to assign subjects to teacher")
+	}
+
+	// Pairs the insert skipped were already assigned to the class
+	createdPairs := make(map[dto.TeacherClassSubjectItem]bool, len(created))
+	for _, classSubject := range created {
+		createdPairs[dto.TeacherClassSubjectItem{ClassID: *classSubject.ClassID, SubjectID: *classSubject.SubjectID}] = true
+		response.Created = append(response.Created, dto.AssignedClassSubject{
+			ID:        classSubject.ID,
+			ClassID:   *classSubject.ClassID,
+			SubjectID: *classSubject.SubjectID,
+		})
+	}
+	for _, classSubject := range classSubjects {
+		pair := dto.TeacherClassSubjectItem{ClassID: *classSubject.ClassID, SubjectID: *classSubject.SubjectID}
+		if !createdPairs[pair] {
+			response.Skipped = append(response.Skipped, pair)
+		}
+	}
+	response.CreatedCount = len(response.Created)
+	response.SkippedCount = len(response.Skipped)
+
+	logger.Info().
+		Str("teacher_id", teacherID.String()).
+		Int("created", response.CreatedCount).
+		Int("skipped", response.SkippedCount).
+		Msg("Subjects assigned to teacher")
+
+	return response, nil
+}
+
+func (s *classSubjectService) GetByID(c context.Context, tenantID, id uuid.UUID) (*model.ClassSubject, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	classSubject, err := s.classSubjectRepo.GetByID(c, id)
+	if err != nil || classSubject.TenantID != tenantID {
+		logger.Error().
+			Err(err).
+			Str("class_subject_id", id.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to get class subject by ID")
+		return nil, apperror.NotFound("class subject not found")
+	}
+	return classSubject, nil
+}
+
+func (s *classSubjectService) Update(c context.Context, tenantID, id uuid.UUID, req dto.UpdateClassSubjectRequest) (*model.ClassSubject, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	classSubject, err := s.GetByID(c, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateReferences(c, tenantID, req.ClassID, req.SubjectID, req.TeacherID); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("class_subject_id", id.String()).
+			Msg("Class subject update rejected due to invalid references")
+		return nil, err
+	}
+
+	// Update fields
+	if req.ClassID != nil {
+		classSubject.ClassID = req.ClassID
+	}
+	if req.SubjectID != nil {
+		classSubject.SubjectID = req.SubjectID
+	}
+	if req.TeacherID != nil {
+		classSubject.TeacherID = req.TeacherID
+	}
+
+	// Check the new class/subject pair is not already taken by another assignment
+	if classSubject.ClassID != nil && classSubject.SubjectID != nil {
+		existing, err := s.classSubjectRepo.GetByClassAndSubject(c, tenantID, *classSubject.ClassID, *classSubject.SubjectID)
+		if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+			logger.Error().
+				Err(err).
+				Str("class_subject_id", id.String()).
+				Msg("Failed to check existing class subject")
+			return nil, apperror.Internal("failed to update class subject")
+		}
+		if existing != nil && existing.ID != id {
+			logger.Warn().
+				Str("class_subject_id", id.String()).
+				Str("class_id", classSubject.ClassID.String()).
+				Str("subject_id", classSubject.SubjectID.String()).
+				Msg("Class subject update attempt for already assigned subject")
+			return nil, apperror.Conflict("subject is already assigned to this class")
+		}
+	}
+
+	err = s.classSubjectRepo.Update(c, classSubject)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_subject_id", id.String()).
+			Msg("Failed to update class subject in database")
+		return nil, apperror.Internal("failed to update class subject")
+	}
+
+	return classSubject, nil
+}
+
+func (s *classSubjectService) Delete(c context.Context, tenantID, id uuid.UUID) error {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	if _, err := s.GetByID(c, tenantID, id); err != nil {
+		return err
+	}
+
+	err := s.classSubjectRepo.Delete(c, id)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_subject_id", id.String()).
+			Msg("Failed to delete class subject from database")
+		return apperror.Internal("failed to delete class subject")
+	}
+
+	return nil
+}
+
+func (s *classSubjectService) List(c context.Context, tenantID uuid.UUID, params dto.ClassSubjectQueryParams) ([]model.ClassSubject, *dto.PaginationMeta, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	// Set defaults
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	classSubjects, total, err := s.classSubjectRepo.List(c, tenantID, offset, params.Limit, params.ClassID, params.SubjectID, params.TeacherID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Interface("params", params).
+			Msg("Failed to list class subjects")
+		return nil, nil, err
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(params.Limit)))
+
+	meta := &dto.PaginationMeta{
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalRows:  total,
+		TotalPages: totalPages,
+	}
+
+	return classSubjects, meta, nil
+}
+
+func (s *classSubjectService) GetByTeacher(c context.Context, tenantID, teacherID uuid.UUID, params dto.QueryParams) ([]model.ClassSubject, *dto.PaginationMeta, error) {
+	return s.List(c, tenantID, dto.ClassSubjectQueryParams{
+		QueryParams: params,
+		TeacherID:   &teacherID,
+	})
+}
+
+// validateReferences ensures the given class, subject, and teacher all belong to the tenant
+func (s *classSubjectService) validateReferences(c context.Context, tenantID uuid.UUID, classID, subjectID, teacherID *uuid.UUID) error {
+	if classID != nil {
+		class, err := s.classRepo.GetByID(c, *classID)
+		if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+			return apperror.Internal("failed to load class")
+		}
+		if err != nil || class.TenantID != tenantID {
+			return apperror.NotFound("class not found")
+		}
+	}
+	if subjectID != nil {
+		subject, err := s.subjectRepo.GetByID(c, *subjectID)
+		if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+			return apperror.Internal("failed to load subject")
+		}
+		if err != nil || subject.TenantID != tenantID {
+			return apperror.NotFound("subject not found")
+		}
+	}
+	if teacherID != nil {
+		teacher, err := s.teacherRepo.GetByID(c, *teacherID)
+		if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+			return apperror.Internal("failed to load teacher")
+		}
+		if err != nil || teacher.TenantID != tenantID {
+			return apperror.NotFound("teacher not found")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// dashboardClassLimit caps the class subjects listed on a teacher's dashboard
+const dashboardClassLimit = 100
+
+// DashboardService interface defines dashboard service methods
+type DashboardService interface {
+	Get(c context.Context, tenantID, userID uuid.UUID, role string) (*dto.DashboardResponse, error)
+	GetSummary(c context.Context, tenantID, userID uuid.UUID, role string) (*dto.DashboardSummaryResponse, error)
+}
+
+// dashboardService implements DashboardService
+type dashboardService struct {
+	dashboardRepo       repository.DashboardRepository
+	academicYearRepo    repository.AcademicYearRepository
+	teacherRepo         repository.TeacherRepository
+	teacherService      TeacherService
+	classSubjectService ClassSubjectService
+	gradeService        GradeService
+	studentService      StudentService
+	attendanceService   AttendanceService
+	feeService          FeeService
+}
+
+// NewDashboardService creates a new dashboard service
+func NewDashboardService(
+	dashboardRepo repository.DashboardRepository,
+	academicYearRepo repository.AcademicYearRepository,
+	teacherRepo repository.TeacherRepository,
+	teacherService TeacherService,
+	classSubjectService ClassSubjectService,
+	gradeService GradeService,
+	studentService StudentService,
+	attendanceService AttendanceService,
+	feeService FeeService,
+) DashboardService {
+	return &dashboardService{
+		dashboardRepo:       dashboardRepo,
+		academicYearRepo:    academicYearRepo,
+		teacherRepo:         teacherRepo,
+		teacherService:      teacherService,
+		classSubjectService: classSubjectService,
+		gradeService:        gradeService,
+		studentService:      studentService,
+		attendanceService:   attendanceService,
+		feeService:          feeService,
+	}
+}
+
+// Get returns the dashboard variant of the caller's role: the summary for Admin, Developer and Staff,
+// the scoped summary with classes, today's schedule and pending grades for a Teacher, and the children's
+// attendance and fees for a Parent
+func (s *dashboardService) Get(c context.Context, tenantID, userID uuid.UUID, role string) (*dto.DashboardResponse, error) {
+	switch {
+	case strings.EqualFold(role, "Teacher"):
+		summary, err := s.GetSummary(c, tenantID, userID, role)
+		if err != nil {
+			return nil, err
+		}
+		teacher, err := s.getTeacherDashboard(c, tenantID, userID)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.DashboardResponse{Role: "teacher", Summary: summary, Teacher: teacher}, nil
+
+	case strings.EqualFold(role, "Parent"):
+		parent, err := s.getParentDashboard(c, tenantID, userID)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.DashboardResponse{Role: "parent", Parent: parent}, nil
+
+	case strings.EqualFold(role, "Admin"), strings.EqualFold(role, "Developer"), strings.EqualFold(role, "Staff"):
+		summary, err := s.GetSummary(c, tenantID, userID, role)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.DashboardResponse{Role: "admin", Summary: summary}, nil
+	}
+	return nil, apperror.Forbidden("no dashboard for this role")
+}
+
+// GetSummary returns the landing screen numbers of the tenant. Teachers get the numbers of their own
+// classes without the teacher count and outstanding fees; every other role gets the tenant wide numbers.
+func (s *dashboardService) GetSummary(c context.Context, tenantID, userID uuid.UUID, role string) (*dto.DashboardSummaryResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	scope := repository.DashboardScope{TenantID: tenantID}
+	response := &dto.DashboardSummaryResponse{Scope: "tenant"}
+	if strings.EqualFold(role, "Teacher") {
+		scope.TeacherUserID = &userID
+		response.Scope = "teacher"
+	}
+
+	academicYear, err := s.academicYearRepo.GetActive(c, tenantID)
+	switch {
+	case err == nil:
+		scope.AcademicYearID = &academicYear.ID
+		response.ActiveAcademicYear = &dto.DashboardAcademicYear{
+			ID:        academicYear.ID,
+			Name:      academicYear.Name,
+			StartDate: academicYear.StartDate.Format("2006-01-02"),
+			EndDate:   academicYear.EndDate.Format("2006-01-02"),
+		}
+	case !errors.Is(err, apperror.ErrNotFound):
+		return nil, apperror.Internal("failed to get dashboard summary")
+	}
+
+	counts, err := s.dashboardRepo.GetCounts(c, scope)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to count dashboard totals")
+		return nil, apperror.Internal("failed to get dashboard summary")
+	}
+	response.TotalStudents = counts.Students
+	response.TotalClasses = counts.Classes
+
+	if scope.TeacherUserID == nil {
+		response.TotalTeachers = &counts.Teachers
+
+		fees, err := s.dashboardRepo.GetOutstandingFees(c, tenantID)
+		if err != nil {
+			logger.Error().
+				Err(err).
+				Str("tenant_id", tenantID.String()).
+				Msg("Failed to sum outstanding fees")
+			return nil, apperror.Internal("failed to get dashboard summary")
+		}
+		response.OutstandingFees = &dto.DashboardOutstandingFees{
+			Count:       fees.FeeCount,
+			TotalAmount: fees.TotalAmount,
+		}
+	}
+
+	attendance, err := s.getTodayAttendance(c, scope)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to aggregate today's attendance")
+		return nil, apperror.Internal("failed to get dashboard summary")
+	}
+	response.Attendance = *attendance
+
+	return response, nil
+}
+
+// getTodayAttendance counts today's attendance in the scope against the sessions scheduled today
+func (s *dashboardService) getTodayAttendance(c context.Context, scope repository.DashboardScope) (*dto.DashboardAttendance, error) {
+	today := util.TodayFromContext(c)
+
+	counts, err := s.dashboardRepo.CountAttendanceByStatus(c, scope, today)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := s.dashboardRepo.CountExpectedAttendance(c, scope, model.DayOfWeekFor(today))
+	if err != nil {
+		return nil, err
+	}
+
+	attendance := &dto.DashboardAttendance{
+		Date:     today.Format("2006-01-02"),
+		Expected: expected,
+	}
+	for _, count := range counts {
+		attendance.Recorded += count.Count
+		switch count.Status {
+		case model.AttendancePresent:
+			attendance.Present += count.Count
+		case model.AttendanceLate:
+			attendance.Late += count.Count
+		case model.AttendanceAbsent:
+			attendance.Absent += count.Count
+		case model.AttendanceExcused:
+			attendance.Excused += count.Count
+		}
+	}
+	if attendance.Recorded > 0 {
+		rate := math.Round(float64(attendance.Present+attendance.Late)/float64(attendance.Recorded)*10000) / 100
+		attendance.Rate = &rate
+	}
+	return attendance, nil
+}
+
+// getTeacherDashboard collects the class subjects, today's timetable and pending grades of the user's teacher record
+func (s *dashboardService) getTeacherDashboard(c context.Context, tenantID, userID uuid.UUID) (*dto.TeacherDashboard, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	teacher, err := s.teacherRepo.GetByUserID(c, tenantID, userID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			logger.Warn().
+				Str("user_id", userID.String()).
+				Str("tenant_id", tenantID.String()).
+				Msg("Teacher dashboard requested without a teacher record")
+			return nil, apperror.NotFound("teacher profile not found")
+		}
+		return nil, apperror.Internal("failed to get dashboard")
+	}
+
+	dashboard := &dto.TeacherDashboard{
+		TeacherID:     teacher.ID,
+		Classes:       []dto.TeacherDashboardClass{},
+		TodaySchedule: []dto.TeacherScheduleSlot{},
+		PendingGrades: []dto.PendingGradeEntry{},
+	}
+
+	classSubjects, _, err := s.classSubjectService.GetByTeacher(c, tenantID, teacher.ID, dto.QueryParams{Page: 1, Limit: dashboardClassLimit})
+	if err != nil {
+		return nil, apperror.Internal("failed to get dashboard")
+	}
+	for _, classSubject := range classSubjects {
+		class := dto.TeacherDashboardClass{
+			ClassSubjectID: classSubject.ID,
+			ClassID:        classSubject.ClassID,
+			SubjectID:      classSubject.SubjectID,
+		}
+		if classSubject.Class != nil {
+			class.ClassName = &classSubject.Class.Name
+		}
+		if classSubject.Subject != nil {
+			class.SubjectName = &classSubject.Subject.Name
+		}
+		dashboard.Classes = append(dashboard.Classes, class)
+	}
+
+	schedule, err := s.teacherService.GetSchedule(c, tenantID, teacher.ID)
+	if err != nil {
+		return nil, err
+	}
+	today := string(model.DayOfWeekFor(util.TodayFromContext(c)))
+	for _, slot := range schedule.Slots {
+		if slot.DayOfWeek == today {
+			dashboard.TodaySchedule = append(dashboard.TodaySchedule, slot)
+		}
+	}
+
+	academicYear, err := s.academicYearRepo.GetActive(c, tenantID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return dashboard, nil
+		}
+		return nil, apperror.Internal("failed to get dashboard")
+	}
+	dashboard.PendingGrades, err = s.gradeService.GetPendingForTeacher(c, tenantID, teacher.ID, academicYear.ID)
+	if err != nil {
+		return nil, err
+	}
+	return dashboard, nil
+}
+
+// getParentDashboard collects the attendance and fee totals of each child of the parent user
+func (s *dashboardService) getParentDashboard(c context.Context, tenantID, userID uuid.UUID) (*dto.ParentDashboard, error) {
+	students, err := s.studentService.GetChildren(c, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard := &dto.ParentDashboard{Children: make([]dto.ParentDashboardChild, 0, len(students))}
+	for _, student := range students {
+		child := dto.ParentDashboardChild{
+			StudentID:     student.ID,
+			StudentNumber: student.StudentNumber,
+			ClassID:       student.ClassID,
+		}
+		if student.TenantUser != nil && student.TenantUser.User != nil {
+			child.FullName = student.TenantUser.User.FullName
+		}
+		if student.Class != nil {
+			child.ClassName = &student.Class.Name
+		}
+
+		// Without an active academic year there is no default range to summarize
+		child.Attendance, err = s.attendanceService.GetStudentSummary(c, tenantID, student.ID, nil, nil)
+		if err != nil && !errors.Is(err, apperror.ErrValidation) {
+			return nil, err
+		}
+
+		fees, err := s.feeService.Summary(c, tenantID, dto.FeeQueryParams{StudentID: &student.ID})
+		if err != nil {
+			return nil, err
+		}
+		child.Fees = fees.Totals
+
+		dashboard.Children = append(dashboard.Children, child)
+	}
+	return dashboard, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// sessionIsolationConnections is the number of connections probed per pool
+const sessionIsolationConnections = 2
+
+// DiagnosticsService interface defines the service methods checking the database setup
+type DiagnosticsService interface {
+	CheckSessionIsolation(c context.Context, tenantID uuid.UUID) (*dto.SessionIsolationResponse, error)
+}
+
+// diagnosticsService implements DiagnosticsService
+type diagnosticsService struct {
+	diagnosticsRepo repository.DiagnosticsRepository
+}
+
+// NewDiagnosticsService creates a new diagnostics service
+func NewDiagnosticsService(diagnosticsRepo repository.DiagnosticsRepository) DiagnosticsService {
+	return &diagnosticsService{
+		diagnosticsRepo: diagnosticsRepo,
+	}
+}
+
+// CheckSessionIsolation checks that the tenant set for a transaction neither shows other tenants' rows nor
+// stays on the pooled connection once the transaction ends
+func (s *diagnosticsService) CheckSessionIsolation(c context.Context, tenantID uuid.UUID) (*dto.SessionIsolationResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	result, err := s.diagnosticsRepo.CheckSessionIsolation(c, tenantID, sessionIsolationConnections)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to check session isolation")
+		return nil, apperror.Internal("failed to check session isolation")
+	}
+
+	response := &dto.SessionIsolationResponse{
+		TenantID:   tenantID,
+		Table:      result.Table,
+		BypassRole: result.BypassRole,
+		Isolated:   !result.BypassRole,
+		Probes:     make([]dto.SessionIsolationProbeResponse, 0, len(result.Probes)),
+	}
+	for _, probe := range result.Probes {
+		isolated := probe.Isolated()
+		response.Isolated = response.Isolated && isolated
+		response.Probes = append(response.Probes, dto.SessionIsolationProbeResponse{
+			Pool:         probe.Pool,
+			BackendPID:   probe.BackendPID,
+			ScopedRows:   probe.ScopedRows,
+			ForeignRows:  probe.ForeignRows,
+			LeakedTenant: probe.LeakedTenant,
+			LeakedRows:   probe.LeakedRows,
+			Error:        probe.Error,
+			Isolated:     isolated,
+		})
+	}
+
+	if !response.Isolated {
+		logger.Warn().
+			Str("tenant_id", tenantID.String()).
+			Bool("bypass_role", result.BypassRole).
+			Msg("Tenant session isolation check failed")
+	}
+
+	return response, nil
+}
@@ -0,0 +1,450 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// EnrollmentService interface defines enrollment service methods
+type EnrollmentService interface {
+	Create(c context.Context, tenantID uuid.UUID, req dto.CreateEnrollmentRequest) (*model.Enrollment, error)
+	BulkEnroll(c context.Context, tenantID uuid.UUID, req dto.BulkEnrollRequest) (*dto.BulkEnrollResponse, error)
+	Delete(c context.Context, tenantID, id uuid.UUID) error
+	ListByStudent(c context.Context, tenantID, studentID uuid.UUID, params dto.QueryParams) ([]model.Enrollment, *dto.PaginationMeta, error)
+	ListByClassSubject(c context.Context, tenantID, classSubjectID uuid.UUID, params dto.QueryParams) ([]model.Enrollment, *dto.PaginationMeta, error)
+	History(c context.Context, tenantID, studentID uuid.UUID) (*dto.EnrollmentHistoryResponse, error)
+}
+
+// enrollmentService implements EnrollmentService
+type enrollmentService struct {
+	enrollmentRepo   repository.EnrollmentRepository
+	classSubjectRepo repository.ClassSubjectRepository
+	studentRepo      repository.StudentRepository
+	academicYearRepo repository.AcademicYearRepository
+	gradeRepo        repository.GradeRepository
+}
+
+// NewEnrollmentService creates a new enrollment service
+func NewEnrollmentService(
+	enrollmentRepo repository.EnrollmentRepository,
+	classSubjectRepo repository.ClassSubjectRepository,
+	studentRepo repository.StudentRepository,
+	academicYearRepo repository.AcademicYearRepository,
+	gradeRepo repository.GradeRepository,
+) EnrollmentService {
+	return &enrollmentService{
+		enrollmentRepo:   enrollmentRepo,
+		classSubjectRepo: classSubjectRepo,
+		studentRepo:      studentRepo,
+		academicYearRepo: academicYearRepo,
+		gradeRepo:        gradeRepo,
+	}
+}
+
+func (s *enrollmentService) Create(c context.Context, tenantID uuid.UUID, req dto.CreateEnrollmentRequest) (*model.Enrollment, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	student, err := s.studentRepo.GetByID(c, *req.StudentID)
+	if err != nil || student.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("student_id", req.StudentID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Student not found in tenant during enrollment creation")
+		return nil, apperror.NotFound("student not found")
+	}
+
+	classSubject, err := s.getClassSubject(c, tenantID, *req.ClassSubjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	academicYearID, err := s.resolveAcademicYear(c, tenantID, classSubject, req.AcademicYearID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prevent enrolling the same student twice in the same class subject and year
+	exists, err := s.enrollmentRepo.Exists(c, tenantID, student.ID, classSubject.ID, academicYearID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("student_id", student.ID.String()).
+			Str("class_subject_id", classSubject.ID.String()).
+			Msg("Failed to check existing enrollment")
+		return nil, apperror.Internal("failed to create enrollment")
+	}
+	if exists {
+		logger.Warn().
+			Str("student_id", student.ID.String()).
+			Str("class_subject_id", classSubject.ID.String()).
+			Msg("Enrollment creation attempt for already enrolled student")
+		return nil, apperror.Conflict("student is already enrolled in this class subject for the academic year")
+	}
+
+	enrollment := &model.Enrollment{
+		TenantID:       tenantID,
+		StudentID:      &student.ID,
+		ClassSubjectID: &classSubject.ID,
+		AcademicYearID: academicYearID,
+	}
+
+	err = s.enrollmentRepo.Create(c, enrollment)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("student_id", student.ID.String()).
+			Str("class_subject_id", classSubject.ID.String()).
+			Msg("Failed to create enrollment in database")
+		return nil, apperror.Internal("failed to create enrollment")
+	}
+
+	return enrollment, nil
+}
+
+func (s *enrollmentService) BulkEnroll(c context.Context, tenantID uuid.UUID, req dto.BulkEnrollRequest) (*dto.BulkEnrollResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	classSubject, err := s.getClassSubject(c, tenantID, req.ClassSubjectID)
+	if err != nil {
+		return nil, err
+	}
+	if classSubject.ClassID == nil {
+		return nil, apperror.Validation("class subject is not linked to a class")
+	}
+
+	academicYearID, err := s.resolveAcademicYear(c, tenantID, classSubject, req.AcademicYearID)
+	if err != nil {
+		return nil, err
+	}
+
+	studentIDs, err := s.studentRepo.GetIDsByClass(c, tenantID, *classSubject.ClassID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_id", classSubject.ClassID.String()).
+			Msg("Failed to get students of class for bulk enrollment")
+		return nil, apperror.Internal("failed to enroll students")
+	}
+
+	enrolledIDs, err := s.enrollmentRepo.GetEnrolledStudentIDs(c, tenantID, classSubject.ID, academicYearID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_subject_id", classSubject.ID.String()).
+			Msg("Failed to get already enrolled students for bulk enrollment")
+		return nil, apperror.Internal("failed to enroll students")
+	}
+
+	alreadyEnrolled := make(map[uuid.UUID]bool, len(enrolledIDs))
+	for _, id := range enrolledIDs {
+		alreadyEnrolled[id] = true
+	}
+
+	var enrollments []model.Enrollment
+	for _, studentID := range studentIDs {
+		if alreadyEnrolled[studentID] {
+			continue
+		}
+		enrollments = append(enrollments, model.Enrollment{
+			TenantID:       tenantID,
+			StudentID:      &studentID,
+			ClassSubjectID: &classSubject.ID,
+			AcademicYearID: academicYearID,
+		})
+	}
+
+	if err := s.enrollmentRepo.BulkCreate(c, tenantID, enrollments); err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_subject_id", classSubject.ID.String()).
+			Int("count", len(enrollments)).
+			Msg("Failed to bulk create enrollments in database")
+		return nil, apperror.Internal("failed to enroll students")
+	}
+
+	logger.Info().
+		Str("class_subject_id", classSubject.ID.String()).
+		Int("enrolled", len(enrollments)).
+		Int("skipped", len(studentIDs)-len(enrollments)).
+		Msg("Class students enrolled")
+
+	return &dto.BulkEnrollResponse{
+		ClassSubjectID: classSubject.ID,
+		AcademicYearID: academicYearID,
+		Enrolled:       len(enrollments),
+		Skipped:        len(studentIDs) - len(enrollments),
+	}, nil
+}
+
+func (s *enrollmentService) Delete(c context.Context, tenantID, id uuid.UUID) error {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	enrollment, err := s.enrollmentRepo.GetByID(c, id)
+	if err != nil || enrollment.TenantID != tenantID {
+		logger.Error().
+			Err(err).
+			Str("enrollment_id", id.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Enrollment not found during delete")
+		return apperror.NotFound("enrollment not found")
+	}
+
+	err = s.enrollmentRepo.Delete(c, id)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("enrollment_id", id.String()).
+			Msg("Failed to delete enrollment from database")
+		return apperror.Internal("failed to delete enrollment")
+	}
+
+	return nil
+}
+
+func (s *enrollmentService) ListByStudent(c context.Context, tenantID, studentID uuid.UUID, params dto.QueryParams) ([]model.Enrollment, *dto.PaginationMeta, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	// Set defaults
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	enrollments, total, err := s.enrollmentRepo.ListByStudent(c, tenantID, studentID, offset, params.Limit)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Str("student_id", studentID.String()).
+			Interface("params", params).
+			Msg("Failed to get enrollments by student")
+		return nil, nil, err
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(params.Limit)))
+
+	meta := &dto.PaginationMeta{
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalRows:  total,
+		TotalPages: totalPages,
+	}
+
+	return enrollments, meta, nil
+}
+
+func (s *enrollmentService) ListByClassSubject(c context.Context, tenantID, classSubjectID uuid.UUID, params dto.QueryParams) ([]model.Enrollment, *dto.PaginationMeta, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	// Set defaults
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	enrollments, total, err := s.enrollmentRepo.ListByClassSubject(c, tenantID, classSubjectID, offset, params.Limit)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Str("class_subject_id", classSubjectID.String()).
+			Interface("params", params).
+			Msg("Failed to get enrollments by class subject")
+		return nil, nil, err
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(params.Limit)))
+
+	meta := &dto.PaginationMeta{
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalRows:  total,
+		TotalPages: totalPages,
+	}
+
+	return enrollments, meta, nil
+}
+
+// History returns every enrollment of the student across academic years, oldest year first and by subject name
+// within a year, each with a summary of its grades
+func (s *enrollmentService) History(c context.Context, tenantID, studentID uuid.UUID) (*dto.EnrollmentHistoryResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	student, err := s.studentRepo.GetByID(c, studentID)
+	if err != nil || student.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("student_id", studentID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Student not found in tenant for enrollment history")
+		return nil, apperror.NotFound("student not found")
+	}
+
+	enrollments, err := s.enrollmentRepo.GetHistoryByStudent(c, tenantID, studentID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("student_id", studentID.String()).
+			Msg("Failed to get enrollment history")
+		return nil, apperror.Internal("failed to get enrollment history")
+	}
+	entries, err := s.gradeRepo.GetStudentEnrollmentGrades(c, tenantID, studentID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("student_id", studentID.String()).
+			Msg("Failed to aggregate grades for enrollment history")
+		return nil, apperror.Internal("failed to get enrollment history")
+	}
+	scale, err := s.gradeRepo.GetGradingScale(c, tenantID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to get grading scale for enrollment history")
+		return nil, apperror.Internal("failed to get enrollment history")
+	}
+
+	entriesByEnrollment := make(map[uuid.UUID][]repository.GradebookEntry)
+	for _, entry := range entries {
+		entriesByEnrollment[entry.EnrollmentID] = append(entriesByEnrollment[entry.EnrollmentID], entry.GradebookEntry)
+	}
+
+	// Enrollments arrive ordered by academic year, which the subject order within a year keeps
+	yearOrder := make(map[uuid.UUID]int)
+	items := make([]dto.EnrollmentHistoryItem, 0, len(enrollments))
+	for _, enrollment := range enrollments {
+		var yearID uuid.UUID
+		if enrollment.AcademicYearID != nil {
+			yearID = *enrollment.AcademicYearID
+		}
+		if _, ok := yearOrder[yearID]; !ok {
+			yearOrder[yearID] = len(yearOrder)
+		}
+
+		item := dto.EnrollmentHistoryItem{
+			EnrollmentID:   enrollment.ID,
+			AcademicYearID: enrollment.AcademicYearID,
+			ClassSubjectID: enrollment.ClassSubjectID,
+			Grades:         enrollmentGradeSummary(scale, entriesByEnrollment[enrollment.ID]),
+		}
+		if enrollment.AcademicYear != nil {
+			item.AcademicYearName = enrollment.AcademicYear.Name
+		}
+		if classSubject := enrollment.ClassSubject; classSubject != nil {
+			item.ClassID = classSubject.ClassID
+			item.SubjectID = classSubject.SubjectID
+			item.TeacherID = classSubject.TeacherID
+			if classSubject.Class != nil {
+				item.ClassName = classSubject.Class.Name
+			}
+			if classSubject.Subject != nil {
+				item.SubjectName = classSubject.Subject.Name
+				item.SubjectCode = classSubject.Subject.Code
+				item.Credit = classSubject.Subject.Credit
+			}
+			if teacher := classSubject.Teacher; teacher != nil && teacher.TenantUser != nil && teacher.TenantUser.User != nil {
+				item.TeacherName = teacher.TenantUser.User.FullName
+			}
+		}
+		items = append(items, item)
+	}
+
+	yearOf := func(item dto.EnrollmentHistoryItem) int {
+		if item.AcademicYearID == nil {
+			return yearOrder[uuid.Nil]
+		}
+		return yearOrder[*item.AcademicYearID]
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if yearOf(items[i]) != yearOf(items[j]) {
+			return yearOf(items[i]) < yearOf(items[j])
+		}
+		return items[i].SubjectName < items[j].SubjectName
+	})
+
+	return &dto.EnrollmentHistoryResponse{
+		StudentID:   studentID,
+		Enrollments: items,
+	}, nil
+}
+
+// enrollmentGradeSummary combines the per grade type aggregates of an enrollment into its average and weighted
+// final score with the letter grade, listing the grade types in their usual order
+func enrollmentGradeSummary(scale *model.GradingScale, entries []repository.GradebookEntry) dto.EnrollmentGradeSummary {
+	cell := gradebookCell(scale, entries, true)
+	summary := dto.EnrollmentGradeSummary{
+		AverageScore: cell.AverageScore,
+		FinalScore:   cell.FinalScore,
+		LetterGrade:  cell.LetterGrade,
+		GradeCount:   cell.GradeCount,
+		GradeTypes:   []dto.EnrollmentGradeTypeSummary{},
+	}
+	for _, gradeType := range model.GradeTypes {
+		for _, entry := range entries {
+			if entry.GradeType != nil && *entry.GradeType == gradeType && entry.GradeCount > 0 {
+				summary.GradeTypes = append(summary.GradeTypes, dto.EnrollmentGradeTypeSummary{
+					GradeType:    gradeType,
+					AverageScore: entry.AverageScore,
+					GradeCount:   entry.GradeCount,
+				})
+			}
+		}
+	}
+	return summary
+}
+
+// getClassSubject loads the class subject and ensures it belongs to the tenant
+func (s *enrollmentService) getClassSubject(c context.Context, tenantID, id uuid.UUID) (*model.ClassSubject, error) {
+	logger := util.NewServiceLogger(c)
+
+	classSubject, err := s.classSubjectRepo.GetByID(c, id)
+	if err != nil || classSubject.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("class_subject_id", id.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Class subject not found in tenant")
+		return nil, apperror.NotFound("class subject not found")
+	}
+	return classSubject, nil
+}
+
+// resolveAcademicYear returns the requested academic year or falls back to the year of the class subject's class
+func (s *enrollmentService) resolveAcademicYear(c context.Context, tenantID uuid.UUID, classSubject *model.ClassSubject, requested *uuid.UUID) (*uuid.UUID, error) {
+	if requested == nil {
+		if classSubject.Class != nil {
+			return classSubject.Class.AcademicYearID, nil
+		}
+		return nil, nil
+	}
+
+	academicYear, err := s.academicYearRepo.GetByID(c, *requested)
+	if err != nil || academicYear.TenantID != tenantID {
+		return nil, apperror.NotFound("academic year not found")
+	}
+	return &academicYear.ID, nil
+}
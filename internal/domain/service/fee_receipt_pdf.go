@@ -0,0 +1,57 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/pkg/pdf"
+)
+
+// renderFeeReceipt renders a fee receipt as a one page PDF document, laid out like the report card
+func renderFeeReceipt(receipt *model.FeeReceipt) []byte {
+	w := &reportCardWriter{doc: pdf.New("Receipt " + receipt.ReceiptNumber)}
+	w.newPage()
+
+	w.text(reportMargin, pdf.HelveticaBold, 12, receipt.SchoolName)
+	w.next(reportLineHeight)
+	for _, line := range []*string{receipt.SchoolAddress, receipt.SchoolPhone} {
+		if line != nil && *line != "" {
+			w.text(reportMargin, pdf.Helvetica, reportFontSize, *line)
+			w.next(reportLineHeight)
+		}
+	}
+	w.next(reportLineHeight)
+	w.text(reportMargin, pdf.HelveticaBold, 16, "PAYMENT RECEIPT")
+	w.next(reportLineHeight * 1.5)
+	w.text(reportMargin, pdf.Helvetica, reportFontSize, "No. "+receipt.ReceiptNumber)
+	w.next(reportLineHeight * 2)
+
+	for _, field := range [][2]string{
+		{"Student", receipt.StudentName},
+		{"Student number", receipt.StudentNumber},
+		{"Fee", optionalText(receipt.FeeTypeName)},
+		{"Academic year", optionalText(receipt.AcademicYearName)},
+		{"Fee amount", fmt.Sprintf("%.2f", receipt.Amount)},
+		{"Late penalty", fmt.Sprintf("%.2f", receipt.PenaltyAmount)},
+		{"Amount paid", fmt.Sprintf("%.2f", receipt.TotalPaid)},
+		{"Payment method", optionalText(receipt.PaymentMethod)},
+		{"Payment date", receipt.PaymentDate.Format("2 January 2006")},
+	} {
+		w.text(reportMargin, pdf.HelveticaBold, reportFontSize, field[0])
+		w.text(reportMargin+100, pdf.Helvetica, reportFontSize, field[1])
+		w.next(reportLineHeight)
+	}
+	w.line()
+
+	w.next(reportLineHeight)
+	w.text(reportMargin, pdf.Helvetica, 8, "Issued on "+receipt.IssuedAt.Format("2 January 2006 15:04 MST"))
+	return w.doc.Bytes()
+}
+
+// optionalText returns the text, or a dash when it is not set
+func optionalText(text *string) string {
+	if text == nil || *text == "" {
+		return "-"
+	}
+	return *text
+}
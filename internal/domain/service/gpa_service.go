@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// GPAService interface defines grade point average service methods
+type GPAService interface {
+	Get(c context.Context, tenantID, studentID uuid.UUID, params dto.GPAQueryParams) (*dto.GPAResponse, error)
+}
+
+// gpaService implements GPAService
+type gpaService struct {
+	studentRepo      repository.StudentRepository
+	academicYearRepo repository.AcademicYearRepository
+	gradeRepo        repository.GradeRepository
+}
+
+// NewGPAService creates a new GPA service
+func NewGPAService(
+	studentRepo repository.StudentRepository,
+	academicYearRepo repository.AcademicYearRepository,
+	gradeRepo repository.GradeRepository,
+) GPAService {
+	return &gpaService{
+		studentRepo:      studentRepo,
+		academicYearRepo: academicYearRepo,
+		gradeRepo:        gradeRepo,
+	}
+}
+
+// Get computes the student's credit weighted GPA within the requested or active academic year and over every year.
+// The final score of each enrolled subject is mapped to a grade point by the tenant's grading scale.
+func (s *gpaService) Get(c context.Context, tenantID, studentID uuid.UUID, params dto.GPAQueryParams) (*dto.GPAResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	student, err := s.studentRepo.GetByID(c, studentID)
+	if err != nil || student.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("student_id", studentID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Student not found in tenant for GPA")
+		return nil, apperror.NotFound("student not found")
+	}
+
+	academicYear, err := s.resolveAcademicYear(c, tenantID, params.AcademicYearID)
+	if err != nil {
+		return nil, err
+	}
+
+	scale, err := s.gradeRepo.GetGradingScale(c, tenantID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to get grading scale for GPA")
+		return nil, apperror.Internal("failed to compute GPA")
+	}
+	entries, err := s.gradeRepo.GetStudentTranscriptGrades(c, tenantID, studentID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("student_id", studentID.String()).
+			Msg("Failed to aggregate grades for GPA")
+		return nil, apperror.Internal("failed to compute GPA")
+	}
+
+	var allSubjects, yearSubjects []dto.GPASubject
+	forEachTranscriptSubject(entries, func(entry repository.TranscriptGradeEntry, cellEntries []repository.GradebookEntry) {
+		subject := gpaSubject(scale, transcriptSubject(scale, entry, cellEntries))
+		allSubjects = append(allSubjects, subject)
+		if academicYear != nil && entry.AcademicYearID != nil && *entry.AcademicYearID == academicYear.ID {
+			yearSubjects = append(yearSubjects, subject)
+		}
+	})
+
+	response := &dto.GPAResponse{
+		StudentID:  studentID,
+		Cumulative: gradePointAverage(allSubjects),
+	}
+	if len(scale.Bands) > 0 {
+		response.MaxGradePoint = scale.BandGradePoint(0)
+	}
+	if academicYear != nil {
+		response.AcademicYear = &dto.GPAYear{
+			GPASummary:     gradePointAverage(yearSubjects),
+			AcademicYearID: academicYear.ID,
+			AcademicYear:   academicYear.Name,
+			Subjects:       yearSubjects,
+		}
+		if response.AcademicYear.Subjects == nil {
+			response.AcademicYear.Subjects = []dto.GPASubject{}
+		}
+	}
+	return response, nil
+}
+
+// resolveAcademicYear returns the requested academic year of the tenant, or the active one. Without a request
+// and without an active year it returns nil, leaving only the cumulative GPA.
+func (s *gpaService) resolveAcademicYear(c context.Context, tenantID uuid.UUID, academicYearID *uuid.UUID) (*model.AcademicYear, error) {
+	if academicYearID == nil {
+		academicYear, err := s.academicYearRepo.GetActive(c, tenantID)
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				return nil, nil
+			}
+			util.NewServiceLogger(c).Error().
+				Err(err).
+				Str("tenant_id", tenantID.String()).
+				Msg("Failed to get active academic year for GPA")
+			return nil, apperror.Internal("failed to compute GPA")
+		}
+		return academicYear, nil
+	}
+
+	academicYear, err := s.academicYearRepo.GetByID(c, *academicYearID)
+	if err != nil || academicYear.TenantID != tenantID {
+		return nil, apperror.NotFound("academic year not found")
+	}
+	return academicYear, nil
+}
+
+// gpaSubject maps the final score of a subject to its grade point. It counts toward the GPA when it has both
+// a grade point and credits.
+func gpaSubject(scale *model.GradingScale, subject dto.TranscriptSubject) dto.GPASubject {
+	gradePoint := scale.GradePointFor(subject.FinalScore)
+	return dto.GPASubject{
+		ClassSubjectID: subject.ClassSubjectID,
+		SubjectID:      subject.SubjectID,
+		Name:           subject.Name,
+		Code:           subject.Code,
+		Credit:         subject.Credit,
+		FinalScore:     subject.FinalScore,
+		LetterGrade:    subject.LetterGrade,
+		GradePoint:     gradePoint,
+		Counted:        gradePoint != nil && subject.Credit > 0,
+	}
+}
+
+// gradePointAverage weighs the grade point of every counted subject by its credits
+func gradePointAverage(subjects []dto.GPASubject) dto.GPASummary {
+	var summary dto.GPASummary
+	var total float64
+	for _, subject := range subjects {
+		if !subject.Counted {
+			continue
+		}
+		total += *subject.GradePoint * float64(subject.Credit)
+		summary.Credits += subject.Credit
+		summary.SubjectsCounted++
+	}
+	if summary.Credits > 0 {
+		gpa := math.Round(total/float64(summary.Credits)*100) / 100
+		summary.GPA = &gpa
+	}
+	return summary
+}
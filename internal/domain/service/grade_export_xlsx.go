@@ -0,0 +1,66 @@
+package service
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/pkg/xlsx"
+)
+
+// gradeExportColumnWidths are the widths in characters of the student number and name columns; grade columns
+// use the default width
+var gradeExportColumnWidths = []float64{16, 32}
+
+// writeGradeExportExcel writes a grade export as a workbook with the class subject details on top, then one row
+// per student with a column per grade type followed by the average, final score and letter grade
+func writeGradeExportExcel(w io.Writer, export *dto.GradeExport) error {
+	sheetName := export.SubjectName
+	if export.ClassName != "" {
+		sheetName = fmt.Sprintf("%s %s", export.ClassName, export.SubjectName)
+	}
+	sheet, err := xlsx.New(w, sheetName, gradeExportColumnWidths...)
+	if err != nil {
+		return err
+	}
+
+	period := "Full year"
+	if export.Semester != nil {
+		period = fmt.Sprintf("Semester %d", *export.Semester)
+	}
+	for _, field := range [][2]string{
+		{"Class", export.ClassName},
+		{"Subject", fmt.Sprintf("%s (%s)", export.SubjectName, export.SubjectCode)},
+		{"Teacher", export.TeacherName},
+		{"Period", period},
+		{"Generated", export.GeneratedAt.Format("2 January 2006 15:04 MST")},
+	} {
+		if err := sheet.WriteRow(xlsx.Normal, field[0], field[1]); err != nil {
+			return err
+		}
+	}
+	if err := sheet.WriteRow(xlsx.Normal); err != nil {
+		return err
+	}
+
+	header := []interface{}{"Student number", "Name"}
+	for _, gradeType := range export.GradeTypes {
+		header = append(header, gradeType)
+	}
+	header = append(header, "Grades", "Average", "Final score", "Letter")
+	if err := sheet.WriteRow(xlsx.Bold, header...); err != nil {
+		return err
+	}
+
+	for _, row := range export.Rows {
+		values := []interface{}{row.StudentNumber, row.FullName}
+		for _, average := range row.GradeTypeAverages {
+			values = append(values, average)
+		}
+		values = append(values, row.GradeCount, row.AverageScore, row.FinalScore, row.LetterGrade)
+		if err := sheet.WriteRow(xlsx.Normal, values...); err != nil {
+			return err
+		}
+	}
+	return sheet.Close()
+}
@@ -0,0 +1,409 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// GradeService interface defines grade service methods
+type GradeService interface {
+	GetGradingScale(c context.Context, tenantID uuid.UUID) (*dto.GradingScaleResponse, error)
+	UpdateGradingScale(c context.Context, tenantID uuid.UUID, req dto.UpdateGradingScaleRequest) (*dto.GradingScaleResponse, error)
+	GetPendingForTeacher(c context.Context, tenantID, teacherID, academicYearID uuid.UUID) ([]dto.PendingGradeEntry, error)
+	BulkSave(c context.Context, tenantID, classSubjectID uuid.UUID, req dto.BulkGradeRequest) (*dto.BulkGradeResponse, error)
+	Export(c context.Context, tenantID, classSubjectID uuid.UUID, params dto.GradeExportQueryParams) (*dto.GradeExport, error)
+	WriteExcel(w io.Writer, export *dto.GradeExport) error
+}
+
+// gradeService implements GradeService
+type gradeService struct {
+	gradeRepo        repository.GradeRepository
+	classSubjectRepo repository.ClassSubjectRepository
+}
+
+// NewGradeService creates a new grade service
+func NewGradeService(gradeRepo repository.GradeRepository, classSubjectRepo repository.ClassSubjectRepository) GradeService {
+	return &gradeService{
+		gradeRepo:        gradeRepo,
+		classSubjectRepo: classSubjectRepo,
+	}
+}
+
+func (s *gradeService) GetGradingScale(c context.Context, tenantID uuid.UUID) (*dto.GradingScaleResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	scale, err := s.gradeRepo.GetGradingScale(c, tenantID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to get grading scale")
+		return nil, apperror.Internal("failed to get grading scale")
+	}
+	return gradingScaleResponse(scale), nil
+}
+
+// GetPendingForTeacher lists the teacher's class subjects with enrolled students that have no score yet
+func (s *gradeService) GetPendingForTeacher(c context.Context, tenantID, teacherID, academicYearID uuid.UUID) ([]dto.PendingGradeEntry, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	entries, err := s.gradeRepo.GetPendingForTeacher(c, tenantID, teacherID, academicYearID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("teacher_id", teacherID.String()).
+			Msg("Failed to count pending grades for teacher")
+		return nil, apperror.Internal("failed to get pending grades")
+	}
+
+	pending := make([]dto.PendingGradeEntry, 0, len(entries))
+	for _, entry := range entries {
+		pending = append(pending, dto.PendingGradeEntry{
+			ClassSubjectID:   entry.ClassSubjectID,
+			ClassName:        entry.ClassName,
+			SubjectName:      entry.SubjectName,
+			EnrolledStudents: entry.Enrolled,
+			UngradedStudents: entry.Ungraded,
+		})
+	}
+	return pending, nil
+}
+
+func (s *gradeService) UpdateGradingScale(c context.Context, tenantID uuid.UUID, req dto.UpdateGradingScaleRequest) (*dto.GradingScaleResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	bands := make([]model.GradingScaleBand, 0, len(req.Bands))
+	letters := make(map[string]bool, len(req.Bands))
+	coversZero := false
+	withPoints := 0
+	for _, band := range req.Bands {
+		letter := strings.TrimSpace(band.Letter)
+		if letter == "" {
+			return nil, apperror.Validation("band letters must not be blank")
+		}
+		if letters[strings.ToUpper(letter)] {
+			return nil, apperror.Validation(fmt.Sprintf("letter %s is used by more than one band", letter))
+		}
+		letters[strings.ToUpper(letter)] = true
+		coversZero = coversZero || *band.MinScore == 0
+		if band.GradePoint != nil {
+			withPoints++
+		}
+		bands = append(bands, model.GradingScaleBand{TenantID: tenantID, Letter: letter, MinScore: *band.MinScore, GradePoint: band.GradePoint})
+	}
+	// Every score must map to a letter
+	if !coversZero {
+		return nil, apperror.Validation("one band must have a min_score of 0")
+	}
+	// Points counted from the band position don't mix with configured ones
+	if withPoints > 0 && withPoints < len(bands) {
+		return nil, apperror.Validation("grade_point must be set on every band or on none")
+	}
+
+	weights := make([]model.GradeTypeWeight, 0, len(req.Weights))
+	gradeTypes := make(map[string]bool, len(req.Weights))
+	var totalWeight float64
+	for _, weight := range req.Weights {
+		if gradeTypes[weight.GradeType] {
+			return nil, apperror.Validation(fmt.Sprintf("grade type %s is weighted more than once", weight.GradeType))
+		}
+		gradeTypes[weight.GradeType] = true
+		totalWeight += *weight.Weight
+		weights = append(weights, model.GradeTypeWeight{TenantID: tenantID, GradeType: weight.GradeType, Weight: *weight.Weight})
+	}
+	if totalWeight == 0 {
+		return nil, apperror.Validation("at least one grade type must have a positive weight")
+	}
+
+	if err := s.gradeRepo.ReplaceGradingScale(c, tenantID, bands, weights); err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to update grading scale")
+		return nil, apperror.Internal("failed to update grading scale")
+	}
+
+	logger.Info().
+		Str("tenant_id", tenantID.String()).
+		Int("bands", len(bands)).
+		Int("weights", len(weights)).
+		Msg("Grading scale updated")
+
+	return gradingScaleResponse(model.NewGradingScale(bands, weights)), nil
+}
+
+// gradingScaleResponse converts a grading scale to its response
+func gradingScaleResponse(scale *model.GradingScale) *dto.GradingScaleResponse {
+	response := &dto.GradingScaleResponse{
+		Bands:          make([]dto.GradingScaleBand, 0, len(scale.Bands)),
+		Weights:        make([]dto.GradeTypeWeight, 0, len(scale.Weights)),
+		DefaultBands:   scale.DefaultBands,
+		DefaultWeights: scale.DefaultWeights,
+	}
+	for i, band := range scale.Bands {
+		minScore := band.MinScore
+		gradePoint := scale.BandGradePoint(i)
+		response.Bands = append(response.Bands, dto.GradingScaleBand{Letter: band.Letter, MinScore: &minScore, GradePoint: &gradePoint})
+	}
+	for _, weight := range scale.Weights {
+		value := weight.Weight
+		response.Weights = append(response.Weights, dto.GradeTypeWeight{GradeType: weight.GradeType, Weight: &value})
+	}
+	return response
+}
+
+// gradeKey identifies the grade an entry replaces: one grade per enrollment, grade type and semester
+type gradeKey struct {
+	enrollmentID uuid.UUID
+	gradeType    string
+	semester     int // 0 when the grade counts for the whole year
+}
+
+// newGradeKey returns the key of a grade
+func newGradeKey(enrollmentID uuid.UUID, gradeType string, semester *int) gradeKey {
+	key := gradeKey{enrollmentID: enrollmentID, gradeType: gradeType}
+	if semester != nil {
+		key.semester = *semester
+	}
+	return key
+}
+
+// BulkSave enters the grades of a class subject's students, creating a grade per enrollment, grade type and
+// semester or replacing the score and remarks of the existing one. Entries whose student isn't enrolled in the
+// class subject are reported as failed, the others are saved together in one transaction.
+func (s *gradeService) BulkSave(c context.Context, tenantID, classSubjectID uuid.UUID, req dto.BulkGradeRequest) (*dto.BulkGradeResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	classSubject, err := s.classSubjectRepo.GetByID(c, classSubjectID)
+	if err != nil || classSubject.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("class_subject_id", classSubjectID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Class subject not found in tenant")
+		return nil, apperror.NotFound("class subject not found")
+	}
+
+	enrollments, err := s.gradeRepo.GetClassSubjectEnrollments(c, tenantID, classSubjectID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_subject_id", classSubjectID.String()).
+			Msg("Failed to get enrollments for bulk grade entry")
+		return nil, apperror.Internal("failed to save grades")
+	}
+
+	enrolled := make(map[uuid.UUID]bool, len(enrollments))
+	byStudent := make(map[uuid.UUID][]uuid.UUID, len(enrollments))
+	existing := make(map[gradeKey][]model.Grade)
+	for _, enrollment := range enrollments {
+		enrolled[enrollment.ID] = true
+		byStudent[*enrollment.StudentID] = append(byStudent[*enrollment.StudentID], enrollment.ID)
+		for _, grade := range enrollment.Grades {
+			key := newGradeKey(enrollment.ID, grade.GradeType, grade.Semester)
+			existing[key] = append(existing[key], grade)
+		}
+	}
+
+	response := &dto.BulkGradeResponse{
+		ClassSubjectID: classSubjectID,
+		Results:        make([]dto.BulkGradeResult, len(req.Grades)),
+	}
+	var created, updated []model.Grade
+	var createdIndexes, updatedIndexes []int
+	seen := make(map[gradeKey]int, len(req.Grades))
+	for i, entry := range req.Grades {
+		result := &response.Results[i]
+		result.Index = i
+
+		enrollmentID, failure := resolveGradeEnrollment(entry, enrolled, byStudent)
+		if failure != "" {
+			result.Error = failure
+			continue
+		}
+		result.EnrollmentID = &enrollmentID
+
+		key := newGradeKey(enrollmentID, entry.GradeType, entry.Semester)
+		if first, ok := seen[key]; ok {
+			result.Error = fmt.Sprintf("duplicates entry %d", first)
+			continue
+		}
+		seen[key] = i
+
+		switch grades := existing[key]; len(grades) {
+		case 0:
+			created = append(created, model.Grade{
+				TenantID:     tenantID,
+				EnrollmentID: &enrollmentID,
+				GradeType:    entry.GradeType,
+				Score:        entry.Score,
+				Semester:     entry.Semester,
+				Remarks:      entry.Remarks,
+			})
+			createdIndexes = append(createdIndexes, i)
+		case 1:
+			grade := grades[0]
+			grade.Score = entry.Score
+			grade.Remarks = entry.Remarks
+			updated = append(updated, grade)
+			updatedIndexes = append(updatedIndexes, i)
+		default:
+			result.Error = "the student has several grades of this type, edit them individually"
+		}
+	}
+
+	if err := s.gradeRepo.SaveGrades(c, tenantID, created, updated); err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_subject_id", classSubjectID.String()).
+			Msg("Failed to save grades in database")
+		return nil, apperror.Internal("failed to save grades")
+	}
+
+	for i, index := range createdIndexes {
+		response.Results[index].GradeID = &created[i].ID
+		response.Results[index].Created = true
+		response.Results[index].Success = true
+	}
+	for i, index := range updatedIndexes {
+		response.Results[index].GradeID = &updated[i].ID
+		response.Results[index].Success = true
+	}
+	response.Saved = len(created) + len(updated)
+	response.Failed = len(req.Grades) - response.Saved
+
+	logger.Info().
+		Str("class_subject_id", classSubjectID.String()).
+		Int("created", len(created)).
+		Int("updated", len(updated)).
+		Int("failed", response.Failed).
+		Msg("Grades entered in bulk")
+	return response, nil
+}
+
+// resolveGradeEnrollment returns the class subject enrollment a bulk grade entry is for, or why it has none
+func resolveGradeEnrollment(entry dto.BulkGradeEntry, enrolled map[uuid.UUID]bool, byStudent map[uuid.UUID][]uuid.UUID) (uuid.UUID, string) {
+	if entry.EnrollmentID != nil {
+		if !enrolled[*entry.EnrollmentID] {
+			return uuid.Nil, "enrollment not found in this class subject"
+		}
+		return *entry.EnrollmentID, ""
+	}
+
+	switch enrollmentIDs := byStudent[*entry.StudentID]; len(enrollmentIDs) {
+	case 0:
+		return uuid.Nil, "student is not enrolled in this class subject"
+	case 1:
+		return enrollmentIDs[0], ""
+	default:
+		return uuid.Nil, "student has several enrollments in this class subject, use enrollment_id"
+	}
+}
+
+// Export collects the grade sheet of a class subject: per enrolled student the average of each grade type, the
+// overall average and the weighted final score with its letter grade on the tenant's grading scale
+func (s *gradeService) Export(c context.Context, tenantID, classSubjectID uuid.UUID, params dto.GradeExportQueryParams) (*dto.GradeExport, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	classSubject, err := s.classSubjectRepo.GetByID(c, classSubjectID)
+	if err != nil || classSubject.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("class_subject_id", classSubjectID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Class subject not found in tenant for grade export")
+		return nil, apperror.NotFound("class subject not found")
+	}
+
+	scale, err := s.gradeRepo.GetGradingScale(c, tenantID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to get grading scale for grade export")
+		return nil, apperror.Internal("failed to export grades")
+	}
+	entries, err := s.gradeRepo.GetClassSubjectGrades(c, tenantID, classSubjectID, params.Semester)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("class_subject_id", classSubjectID.String()).
+			Msg("Failed to aggregate grades for grade export")
+		return nil, apperror.Internal("failed to export grades")
+	}
+
+	export := &dto.GradeExport{
+		ClassSubjectID: classSubjectID,
+		Semester:       params.Semester,
+		GradeTypes:     model.GradeTypes,
+		Rows:           []dto.GradeExportRow{},
+		GeneratedAt:    util.NowFromContext(c),
+	}
+	if classSubject.Class != nil {
+		export.ClassName = classSubject.Class.Name
+	}
+	if classSubject.Subject != nil {
+		export.SubjectName = classSubject.Subject.Name
+		export.SubjectCode = classSubject.Subject.Code
+	}
+	if teacher := classSubject.Teacher; teacher != nil && teacher.TenantUser != nil && teacher.TenantUser.User != nil {
+		export.TeacherName = teacher.TenantUser.User.FullName
+	}
+
+	// Entries arrive ordered by student, one per grade type
+	var cellEntries []repository.GradebookEntry
+	for i, entry := range entries {
+		cellEntries = append(cellEntries, entry.GradebookEntry)
+		if i+1 < len(entries) && entries[i+1].StudentID == entry.StudentID {
+			continue
+		}
+
+		cell := gradebookCell(scale, cellEntries, true)
+		row := dto.GradeExportRow{
+			StudentID:         entry.StudentID,
+			StudentNumber:     entry.StudentNumber,
+			FullName:          entry.FullName,
+			GradeTypeAverages: make([]*float64, len(export.GradeTypes)),
+			AverageScore:      cell.AverageScore,
+			FinalScore:        cell.FinalScore,
+			LetterGrade:       cell.LetterGrade,
+			GradeCount:        cell.GradeCount,
+		}
+		for _, cellEntry := range cellEntries {
+			for j, gradeType := range export.GradeTypes {
+				if cellEntry.GradeType != nil && *cellEntry.GradeType == gradeType && cellEntry.AverageScore != nil {
+					average := math.Round(*cellEntry.AverageScore*100) / 100
+					row.GradeTypeAverages[j] = &average
+				}
+			}
+		}
+		export.Rows = append(export.Rows, row)
+		cellEntries = nil
+	}
+
+	logger.Info().
+		Str("class_subject_id", classSubjectID.String()).
+		Int("students", len(export.Rows)).
+		Msg("Grades exported")
+	return export, nil
+}
+
+// WriteExcel streams a grade export to w as an Excel workbook
+func (s *gradeService) WriteExcel(w io.Writer, export *dto.GradeExport) error {
+	return writeGradeExportExcel(w, export)
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/mail"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/sms"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// smsMaxLength keeps text messages within two SMS segments
+const smsMaxLength = 300
+
+// NotificationService interface defines notification service methods
+type NotificationService interface {
+	Send(c context.Context, tenantID uuid.UUID, req dto.CreateNotificationRequest) (*dto.NotificationDeliveryResponse, error)
+	GetPreferences(c context.Context, userID uuid.UUID) ([]dto.NotificationPreferenceResponse, error)
+	UpdatePreferences(c context.Context, userID uuid.UUID, req dto.UpdateNotificationPreferencesRequest) ([]dto.NotificationPreferenceResponse, error)
+}
+
+// notificationService implements NotificationService
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+	userRepo         repository.UserRepository
+	tenantUserRepo   repository.TenantUserRepository
+	mailer           *mail.Mailer
+	smsDispatcher    *sms.Dispatcher
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(
+	notificationRepo repository.NotificationRepository,
+	userRepo repository.UserRepository,
+	tenantUserRepo repository.TenantUserRepository,
+	mailer *mail.Mailer,
+	smsDispatcher *sms.Dispatcher,
+) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		tenantUserRepo:   tenantUserRepo,
+		mailer:           mailer,
+		smsDispatcher:    smsDispatcher,
+	}
+}
+
+// Send stores an in-app notification for a member of the tenant and queues it by email and SMS
+// according to the user's preferences for its type
+func (s *notificationService) Send(c context.Context, tenantID uuid.UUID, req dto.CreateNotificationRequest) (*dto.NotificationDeliveryResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	userID := *req.UserID
+	if _, err := s.tenantUserRepo.GetByTenantAndUser(c, tenantID, userID); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("user_id", userID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Notification recipient is not a member of the tenant")
+		return nil, apperror.NotFound("user not found")
+	}
+	user, err := s.userRepo.GetByID(c, userID)
+	if err != nil {
+		return nil, apperror.NotFound("user not found")
+	}
+
+	notificationType := req.Type
+	if notificationType == "" {
+		notificationType = model.NotificationTypeGeneral
+	}
+
+	notification := &model.Notification{
+		TenantID: tenantID,
+		UserID:   &userID,
+		Type:     notificationType,
+		Title:    req.Title,
+		Message:  req.Message,
+	}
+	if err := s.notificationRepo.Create(c, notification); err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", userID.String()).
+			Msg("Failed to store notification")
+		return nil, apperror.Internal("failed to send notification")
+	}
+
+	channels, err := s.channelsFor(c, userID, notificationType)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", userID.String()).
+			Msg("Failed to get notification preferences")
+		return nil, apperror.Internal("failed to send notification")
+	}
+
+	response := &dto.NotificationDeliveryResponse{
+		NotificationID: notification.ID,
+		Type:           notificationType,
+	}
+
+	// Delivery is best effort: the notification is stored and listed in-app even if a queue is full
+	if channels.Email && user.Email != "" {
+		err := s.mailer.Enqueue(mail.Message{To: user.Email, Subject: req.Title, Body: req.Message})
+		if err != nil {
+			logger.Warn().
+				Err(err).
+				Str("notification_id", notification.ID.String()).
+				Msg("Failed to queue notification email")
+		}
+		response.EmailQueued = err == nil
+	}
+	if channels.SMS && user.Phone != nil && *user.Phone != "" {
+		err := s.smsDispatcher.Enqueue(sms.Message{To: *user.Phone, Body: truncate(req.Title+": "+req.Message, smsMaxLength)})
+		if err != nil {
+			logger.Warn().
+				Err(err).
+				Str("notification_id", notification.ID.String()).
+				Msg("Failed to queue notification SMS")
+		}
+		response.SMSQueued = err == nil
+	}
+
+	logger.Info().
+		Str("notification_id", notification.ID.String()).
+		Str("type", notificationType).
+		Bool("email_queued", response.EmailQueued).
+		Bool("sms_queued", response.SMSQueued).
+		Msg("Notification sent")
+
+	return response, nil
+}
+
+func (s *notificationService) GetPreferences(c context.Context, userID uuid.UUID) ([]dto.NotificationPreferenceResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	preferences, err := s.notificationRepo.GetPreferences(c, userID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", userID.String()).
+			Msg("Failed to get notification preferences")
+		return nil, apperror.Internal("failed to get notification preferences")
+	}
+	return notificationPreferenceResponses(preferences), nil
+}
+
+func (s *notificationService) UpdatePreferences(c context.Context, userID uuid.UUID, req dto.UpdateNotificationPreferencesRequest) ([]dto.NotificationPreferenceResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	preferences := make([]model.NotificationPreference, 0, len(req.Preferences))
+	seen := make(map[string]bool, len(req.Preferences))
+	for _, preference := range req.Preferences {
+		if seen[preference.Type] {
+			return nil, apperror.Validation("each notification type may only be listed once")
+		}
+		seen[preference.Type] = true
+		preferences = append(preferences, model.NotificationPreference{
+			UserID:           userID,
+			NotificationType: preference.Type,
+			Email:            *preference.Email,
+			SMS:              *preference.SMS,
+		})
+	}
+
+	if err := s.notificationRepo.SavePreferences(c, preferences); err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", userID.String()).
+			Msg("Failed to save notification preferences")
+		return nil, apperror.Internal("failed to update notification preferences")
+	}
+
+	return s.GetPreferences(c, userID)
+}
+
+// channelsFor returns the channels the user chose for the notification type, or the type's defaults
+func (s *notificationService) channelsFor(c context.Context, userID uuid.UUID, notificationType string) (model.NotificationChannels, error) {
+	preferences, err := s.notificationRepo.GetPreferences(c, userID)
+	if err != nil {
+		return model.NotificationChannels{}, err
+	}
+	for _, preference := range preferences {
+		if preference.NotificationType == notificationType {
+			return model.NotificationChannels{Email: preference.Email, SMS: preference.SMS}, nil
+		}
+	}
+	return model.DefaultNotificationChannels[notificationType], nil
+}
+
+// notificationPreferenceResponses lists the effective channels of every notification type
+func notificationPreferenceResponses(preferences []model.NotificationPreference) []dto.NotificationPreferenceResponse {
+	byType := make(map[string]model.NotificationPreference, len(preferences))
+	for _, preference := range preferences {
+		byType[preference.NotificationType] = preference
+	}
+
+	responses := make([]dto.NotificationPreferenceResponse, 0, len(model.NotificationTypes))
+	for _, notificationType := range model.NotificationTypes {
+		if preference, ok := byType[notificationType]; ok {
+			responses = append(responses, dto.NotificationPreferenceResponse{Type: notificationType, Email: preference.Email, SMS: preference.SMS})
+			continue
+		}
+		channels := model.DefaultNotificationChannels[notificationType]
+		responses = append(responses, dto.NotificationPreferenceResponse{Type: notificationType, Email: channels.Email, SMS: channels.SMS, IsDefault: true})
+	}
+	return responses
+}
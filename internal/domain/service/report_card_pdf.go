@@ -0,0 +1,177 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/pkg/pdf"
+)
+
+// Report card layout in points
+const (
+	reportMargin     = 50.0
+	reportLineHeight = 16.0
+	reportFontSize   = 10.0
+)
+
+// reportColumn is a table column: its title, x position and the characters that fit in it
+type reportColumn struct {
+	title    string
+	x        float64
+	maxChars int
+}
+
+// reportColumns are the columns of the report card subject table
+var reportColumns = []reportColumn{
+	{"Subject", reportMargin, 36},
+	{"Code", 250, 12},
+	{"Grades", 320, 10},
+	{"Average", 380, 12},
+	{"Final", 450, 10},
+	{"Letter", 510, 6},
+}
+
+// reportCardWriter lays out text top to bottom, starting a new page when the current one is full
+type reportCardWriter struct {
+	doc     *pdf.Document
+	page    *pdf.Page
+	y       float64
+	columns []reportColumn
+}
+
+// renderReportCard renders a report card as a PDF document
+func renderReportCard(card *dto.ReportCard) []byte {
+	w := &reportCardWriter{doc: pdf.New("Report card " + card.StudentNumber), columns: reportColumns}
+	w.newPage()
+
+	w.text(reportMargin, pdf.HelveticaBold, 16, "REPORT CARD")
+	w.next(reportLineHeight * 1.5)
+	w.text(reportMargin, pdf.HelveticaBold, 12, card.SchoolName)
+	w.next(reportLineHeight * 2)
+
+	period := card.AcademicYear
+	if card.Semester != nil {
+		period = fmt.Sprintf("%s, semester %d", card.AcademicYear, *card.Semester)
+	}
+	for _, field := range [][2]string{
+		{"Name", card.FullName},
+		{"Student number", card.StudentNumber},
+		{"Class", card.ClassName},
+		{"Academic year", period},
+	} {
+		w.text(reportMargin, pdf.HelveticaBold, reportFontSize, field[0])
+		w.text(reportMargin+100, pdf.Helvetica, reportFontSize, field[1])
+		w.next(reportLineHeight)
+	}
+	w.next(reportLineHeight)
+
+	w.header()
+	if len(card.Subjects) == 0 {
+		w.text(reportMargin, pdf.Helvetica, reportFontSize, "No enrolled subjects")
+		w.next(reportLineHeight)
+	}
+	for _, subject := range card.Subjects {
+		if w.full(reportLineHeight) {
+			w.newPage()
+			w.header()
+		}
+		w.row(pdf.Helvetica, subject.Name, subject.Code, fmt.Sprint(subject.GradeCount),
+			formatScore(subject.AverageScore), formatScore(subject.FinalScore), formatLetter(subject.LetterGrade))
+	}
+	w.line()
+	w.row(pdf.HelveticaBold, "Overall", "", "", "", formatScore(card.OverallScore), formatLetter(card.OverallLetter))
+	w.next(reportLineHeight)
+
+	if w.full(reportLineHeight * 8) {
+		w.newPage()
+	}
+	w.text(reportMargin, pdf.HelveticaBold, 12, "Attendance")
+	w.next(reportLineHeight * 1.5)
+	if card.Attendance == nil {
+		w.text(reportMargin, pdf.Helvetica, reportFontSize, "No attendance recorded for this period yet")
+		w.next(reportLineHeight)
+	} else {
+		w.text(reportMargin, pdf.Helvetica, reportFontSize, fmt.Sprintf("%s to %s: %d scheduled sessions, %d recorded",
+			card.Attendance.DateFrom, card.Attendance.DateTo, card.Attendance.ScheduledSessions, card.Attendance.RecordedSessions))
+		w.next(reportLineHeight)
+		for _, status := range card.Attendance.Statuses {
+			w.text(reportMargin, pdf.Helvetica, reportFontSize, strings.ToUpper(status.Status[:1])+status.Status[1:])
+			w.text(reportMargin+100, pdf.Helvetica, reportFontSize, fmt.Sprintf("%d (%.2f%%)", status.Count, status.Percentage))
+			w.next(reportLineHeight)
+		}
+	}
+
+	w.next(reportLineHeight)
+	w.text(reportMargin, pdf.Helvetica, 8, "Generated on "+card.GeneratedAt.Format("2 January 2006 15:04 MST"))
+	return w.doc.Bytes()
+}
+
+// newPage starts a new page at the top margin
+func (w *reportCardWriter) newPage() {
+	w.page = w.doc.AddPage()
+	w.y = pdf.PageHeight - reportMargin
+}
+
+// full reports whether the current page has no room left for the given height
+func (w *reportCardWriter) full(height float64) bool {
+	return w.y-height < reportMargin
+}
+
+// next moves down by the given height
+func (w *reportCardWriter) next(height float64) {
+	w.y -= height
+}
+
+// text draws text on the current line
+func (w *reportCardWriter) text(x float64, font pdf.Font, size float64, text string) {
+	w.page.Text(x, w.y, font, size, text)
+}
+
+// line draws a rule below the current line
+func (w *reportCardWriter) line() {
+	w.page.Line(reportMargin, w.y+reportLineHeight-4, pdf.PageWidth-reportMargin, w.y+reportLineHeight-4, 0.5)
+}
+
+// row draws one value per table column and moves to the next line
+func (w *reportCardWriter) row(font pdf.Font, values ...string) {
+	for i, value := range values {
+		w.text(w.columns[i].x, font, reportFontSize, truncate(value, w.columns[i].maxChars))
+	}
+	w.next(reportLineHeight)
+}
+
+// header draws the table header
+func (w *reportCardWriter) header() {
+	titles := make([]string, len(w.columns))
+	for i, column := range w.columns {
+		titles[i] = column.title
+	}
+	w.row(pdf.HelveticaBold, titles...)
+	w.line()
+}
+
+// formatScore formats an optional score with two decimals
+func formatScore(score *float64) string {
+	if score == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", *score)
+}
+
+// formatLetter formats an optional letter grade
+func formatLetter(letter *string) string {
+	if letter == nil {
+		return "-"
+	}
+	return *letter
+}
+
+// truncate shortens text to at most n characters so it fits its column
+func truncate(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n-3]) + "..."
+}
@@ -0,0 +1,419 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// ReportCardService interface defines report card service methods
+type ReportCardService interface {
+	GeneratePDF(c context.Context, tenantID, studentID uuid.UUID, params dto.ReportCardQueryParams) (*dto.ReportCard, []byte, error)
+	Transcript(c context.Context, tenantID, studentID uuid.UUID) (*dto.Transcript, error)
+	TranscriptPDF(c context.Context, tenantID, studentID uuid.UUID) (*dto.Transcript, []byte, error)
+}
+
+// reportCardService implements ReportCardService
+type reportCardService struct {
+	studentRepo       repository.StudentRepository
+	tenantRepo        repository.TenantRepository
+	academicYearRepo  repository.AcademicYearRepository
+	gradeRepo         repository.GradeRepository
+	attendanceService AttendanceService
+}
+
+// NewReportCardService creates a new report card service
+func NewReportCardService(
+	studentRepo repository.StudentRepository,
+	tenantRepo repository.TenantRepository,
+	academicYearRepo repository.AcademicYearRepository,
+	gradeRepo repository.GradeRepository,
+	attendanceService AttendanceService,
+) ReportCardService {
+	return &reportCardService{
+		studentRepo:       studentRepo,
+		tenantRepo:        tenantRepo,
+		academicYearRepo:  academicYearRepo,
+		gradeRepo:         gradeRepo,
+		attendanceService: attendanceService,
+	}
+}
+
+// GeneratePDF assembles the student's report card for the academic year, the active one by default, and renders it
+func (s *reportCardService) GeneratePDF(c context.Context, tenantID, studentID uuid.UUID, params dto.ReportCardQueryParams) (*dto.ReportCard, []byte, error) {
+	card, err := s.build(c, tenantID, studentID, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return card, renderReportCard(card), nil
+}
+
+// build collects the profile, grades and attendance of a report card
+func (s *reportCardService) build(c context.Context, tenantID, studentID uuid.UUID, params dto.ReportCardQueryParams) (*dto.ReportCard, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	student, err := s.studentRepo.GetByID(c, studentID)
+	if err != nil || student.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("student_id", studentID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Student not found in tenant for report card")
+		return nil, apperror.NotFound("student not found")
+	}
+
+	academicYear, err := s.resolveAcademicYear(c, tenantID, params.AcademicYearID)
+	if err != nil {
+		return nil, err
+	}
+
+	tenant, err := s.tenantRepo.GetByID(c, tenantID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to get tenant for report card")
+		return nil, apperror.Internal("failed to generate report card")
+	}
+
+	card := &dto.ReportCard{
+		SchoolName:    tenant.Name,
+		StudentID:     student.ID,
+		StudentNumber: student.StudentNumber,
+		AcademicYear:  academicYear.Name,
+		Semester:      params.Semester,
+		GeneratedAt:   util.NowFromContext(c),
+	}
+	if student.TenantUser != nil && student.TenantUser.User != nil {
+		card.FullName = student.TenantUser.User.FullName
+	}
+	if student.Class != nil {
+		card.ClassName = student.Class.Name
+	}
+
+	scale, err := s.gradeRepo.GetGradingScale(c, tenantID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to get grading scale for report card")
+		return nil, apperror.Internal("failed to generate report card")
+	}
+	if card.Subjects, err = s.subjectResults(c, scale, tenantID, studentID, academicYear.ID, params.Semester); err != nil {
+		return nil, err
+	}
+	card.OverallScore = overallScore(card.Subjects)
+	card.OverallLetter = scale.LetterFor(card.OverallScore)
+
+	// Attendance covers the year or semester up to today; there is none yet for a period in the future
+	from, to := academicYear.StartDate, academicYear.EndDate
+	if params.Semester != nil {
+		from, to = academicYear.SemesterRange(*params.Semester)
+	}
+	if today := util.TodayFromContext(c); today.Before(to) {
+		to = today
+	}
+	if !to.Before(from) {
+		card.Attendance, err = s.attendanceService.GetStudentSummary(c, tenantID, studentID, &from, &to)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return card, nil
+}
+
+// resolveAcademicYear returns the requested academic year of the tenant, or the active one
+func (s *reportCardService) resolveAcademicYear(c context.Context, tenantID uuid.UUID, academicYearID *uuid.UUID) (*model.AcademicYear, error) {
+	if academicYearID == nil {
+		academicYear, err := s.academicYearRepo.GetActive(c, tenantID)
+		if err != nil {
+			return nil, apperror.Validation("no active academic year found; specify academic_year_id")
+		}
+		return academicYear, nil
+	}
+
+	academicYear, err := s.academicYearRepo.GetByID(c, *academicYearID)
+	if err != nil || academicYear.TenantID != tenantID {
+		return nil, apperror.NotFound("academic year not found")
+	}
+	return academicYear, nil
+}
+
+// subjectResults computes the weighted final score and letter grade of each enrolled subject like the gradebook does
+func (s *reportCardService) subjectResults(c context.Context, scale *model.GradingScale, tenantID, studentID, academicYearID uuid.UUID, semester *int) ([]dto.ReportCardSubject, error) {
+	entries, err := s.gradeRepo.GetStudentGrades(c, tenantID, studentID, academicYearID, semester)
+	if err != nil {
+		util.NewServiceLogger(c).Error().
+			Err(err).
+			Str("student_id", studentID.String()).
+			Msg("Failed to aggregate grades for report card")
+		return nil, apperror.Internal("failed to generate report card")
+	}
+
+	// Entries arrive ordered by subject, one per grade type
+	subjects := []dto.ReportCardSubject{}
+	var cellEntries []repository.GradebookEntry
+	flush := func(entry repository.StudentGradeEntry) {
+		cell := gradebookCell(scale, cellEntries, true)
+		subject := dto.ReportCardSubject{
+			ClassSubjectID: entry.ClassSubjectID,
+			AverageScore:   cell.AverageScore,
+			FinalScore:     cell.FinalScore,
+			LetterGrade:    cell.LetterGrade,
+			GradeCount:     cell.GradeCount,
+		}
+		if entry.SubjectName != nil {
+			subject.Name = *entry.SubjectName
+		}
+		if entry.SubjectCode != nil {
+			subject.Code = *entry.SubjectCode
+		}
+		subjects = append(subjects, subject)
+		cellEntries = nil
+	}
+	for i, entry := range entries {
+		cellEntries = append(cellEntries, entry.GradebookEntry)
+		if i+1 == len(entries) || entries[i+1].ClassSubjectID != entry.ClassSubjectID {
+			flush(entry)
+		}
+	}
+	return subjects, nil
+}
+
+// overallScore averages the final scores of the subjects that have one
+func overallScore(subjects []dto.ReportCardSubject) *float64 {
+	var total float64
+	var count int
+	for _, subject := range subjects {
+		if subject.FinalScore != nil {
+			total += *subject.FinalScore
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	score := math.Round(total/float64(count)*100) / 100
+	return &score
+}
+
+// Transcript assembles the student's cumulative academic record: the final result of every enrolled class subject
+// per academic year, the credit weighted average of each year and of the whole record, and a summary per subject
+func (s *reportCardService) Transcript(c context.Context, tenantID, studentID uuid.UUID) (*dto.Transcript, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	student, err := s.studentRepo.GetByID(c, studentID)
+	if err != nil || student.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("student_id", studentID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Student not found in tenant for transcript")
+		return nil, apperror.NotFound("student not found")
+	}
+
+	tenant, err := s.tenantRepo.GetByID(c, tenantID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to get tenant for transcript")
+		return nil, apperror.Internal("failed to generate transcript")
+	}
+	scale, err := s.gradeRepo.GetGradingScale(c, tenantID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to get grading scale for transcript")
+		return nil, apperror.Internal("failed to generate transcript")
+	}
+	entries, err := s.gradeRepo.GetStudentTranscriptGrades(c, tenantID, studentID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("student_id", studentID.String()).
+			Msg("Failed to aggregate grades for transcript")
+		return nil, apperror.Internal("failed to generate transcript")
+	}
+
+	transcript := &dto.Transcript{
+		SchoolName:    tenant.Name,
+		StudentID:     student.ID,
+		StudentNumber: student.StudentNumber,
+		Status:        string(student.Status),
+		AdmissionDate: student.AdmissionDate,
+		Years:         []dto.TranscriptYear{},
+		GeneratedAt:   util.NowFromContext(c),
+	}
+	if student.TenantUser != nil && student.TenantUser.User != nil {
+		transcript.FullName = student.TenantUser.User.FullName
+	}
+
+	forEachTranscriptSubject(entries, func(entry repository.TranscriptGradeEntry, cellEntries []repository.GradebookEntry) {
+		years := transcript.Years
+		if len(years) == 0 || !sameOptionalID(years[len(years)-1].AcademicYearID, entry.AcademicYearID) {
+			year := dto.TranscriptYear{AcademicYearID: entry.AcademicYearID}
+			if entry.AcademicYearName != nil {
+				year.AcademicYear = *entry.AcademicYearName
+			}
+			transcript.Years = append(transcript.Years, year)
+		}
+		year := &transcript.Years[len(transcript.Years)-1]
+		year.Subjects = append(year.Subjects, transcriptSubject(scale, entry, cellEntries))
+	})
+
+	var allSubjects []dto.TranscriptSubject
+	for i := range transcript.Years {
+		year := &transcript.Years[i]
+		year.WeightedAverage, year.Credits = creditWeightedAverage(year.Subjects)
+		year.LetterGrade = scale.LetterFor(year.WeightedAverage)
+		allSubjects = append(allSubjects, year.Subjects...)
+	}
+	transcript.CumulativeAverage, transcript.TotalCredits = creditWeightedAverage(allSubjects)
+	transcript.CumulativeLetter = scale.LetterFor(transcript.CumulativeAverage)
+	transcript.Subjects = transcriptSubjectSummaries(scale, allSubjects)
+
+	return transcript, nil
+}
+
+// TranscriptPDF assembles the student's transcript and renders it
+func (s *reportCardService) TranscriptPDF(c context.Context, tenantID, studentID uuid.UUID) (*dto.Transcript, []byte, error) {
+	transcript, err := s.Transcript(c, tenantID, studentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return transcript, renderTranscript(transcript), nil
+}
+
+// forEachTranscriptSubject calls fn once per class subject and academic year of the transcript entries with the
+// per grade type aggregates of it. Entries arrive ordered by academic year and subject, one per grade type.
+func forEachTranscriptSubject(entries []repository.TranscriptGradeEntry, fn func(entry repository.TranscriptGradeEntry, cellEntries []repository.GradebookEntry)) {
+	var cellEntries []repository.GradebookEntry
+	for i, entry := range entries {
+		cellEntries = append(cellEntries, entry.GradebookEntry)
+		if i+1 < len(entries) && entries[i+1].ClassSubjectID == entry.ClassSubjectID &&
+			sameOptionalID(entries[i+1].AcademicYearID, entry.AcademicYearID) {
+			continue
+		}
+		fn(entry, cellEntries)
+		cellEntries = nil
+	}
+}
+
+// transcriptSubject computes the final result of a class subject from its per grade type aggregates
+func transcriptSubject(scale *model.GradingScale, entry repository.TranscriptGradeEntry, entries []repository.GradebookEntry) dto.TranscriptSubject {
+	cell := gradebookCell(scale, entries, true)
+	subject := dto.TranscriptSubject{
+		ClassSubjectID: entry.ClassSubjectID,
+		SubjectID:      entry.SubjectID,
+		AverageScore:   cell.AverageScore,
+		FinalScore:     cell.FinalScore,
+		LetterGrade:    cell.LetterGrade,
+		GradeCount:     cell.GradeCount,
+	}
+	if entry.SubjectName != nil {
+		subject.Name = *entry.SubjectName
+	}
+	if entry.SubjectCode != nil {
+		subject.Code = *entry.SubjectCode
+	}
+	if entry.Credit != nil {
+		subject.Credit = *entry.Credit
+	}
+	return subject
+}
+
+// creditWeightedAverage averages the final scores of the subjects that have one weighted by their credits, and
+// returns the credits counted. When none of them has credits, every subject weighs the same.
+func creditWeightedAverage(subjects []dto.TranscriptSubject) (*float64, int) {
+	var total, plainTotal float64
+	var credits, count int
+	for _, subject := range subjects {
+		if subject.FinalScore == nil {
+			continue
+		}
+		plainTotal += *subject.FinalScore
+		count++
+		if subject.Credit > 0 {
+			total += *subject.FinalScore * float64(subject.Credit)
+			credits += subject.Credit
+		}
+	}
+
+	var average float64
+	switch {
+	case credits > 0:
+		average = total / float64(credits)
+	case count > 0:
+		average = plainTotal / float64(count)
+	default:
+		return nil, 0
+	}
+	average = math.Round(average*100) / 100
+	return &average, credits
+}
+
+// transcriptSubjectSummaries combines the yearly results of each subject into the average of its final scores,
+// in the order subjects were first taken
+func transcriptSubjectSummaries(scale *model.GradingScale, subjects []dto.TranscriptSubject) []dto.TranscriptSubjectSummary {
+	type summaryTotal struct {
+		total float64
+		count int
+	}
+
+	summaries := []dto.TranscriptSubjectSummary{}
+	totals := []summaryTotal{}
+	indexes := make(map[uuid.UUID]int)
+	for _, subject := range subjects {
+		// Class subjects without a subject are summarized on their own
+		key := subject.ClassSubjectID
+		if subject.SubjectID != nil {
+			key = *subject.SubjectID
+		}
+		i, ok := indexes[key]
+		if !ok {
+			i = len(summaries)
+			indexes[key] = i
+			summaries = append(summaries, dto.TranscriptSubjectSummary{
+				SubjectID: subject.SubjectID,
+				Name:      subject.Name,
+				Code:      subject.Code,
+			})
+			totals = append(totals, summaryTotal{})
+		}
+
+		summaries[i].Years++
+		if subject.FinalScore != nil {
+			summaries[i].Credits += subject.Credit
+			totals[i].total += *subject.FinalScore
+			totals[i].count++
+		}
+	}
+
+	for i := range summaries {
+		if totals[i].count > 0 {
+			score := math.Round(totals[i].total/float64(totals[i].count)*100) / 100
+			summaries[i].FinalScore = &score
+			summaries[i].LetterGrade = scale.LetterFor(&score)
+		}
+	}
+	return summaries
+}
+
+// sameOptionalID reports whether two optional IDs are equal
+func sameOptionalID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
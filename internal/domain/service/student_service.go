@@ -3,13 +3,19 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
 	"github.com/protocyber/kelasgo-api/internal/domain/dto"
 	"github.com/protocyber/kelasgo-api/internal/domain/model"
 	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/event"
 	"github.com/protocyber/kelasgo-api/internal/util"
+	"github.com/protocyber/kelasgo-api/pkg/qrcode"
 )
 
 // StudentService interface defines student service methods
@@ -19,28 +25,64 @@ type StudentService interface {
 	Update(c context.Context, id uuid.UUID, req dto.UpdateStudentRequest) (*model.Student, error)
 	Delete(c context.Context, id uuid.UUID) error
 	BulkDelete(c context.Context, tenantID uuid.UUID, ids []uuid.UUID) error
+	BulkAssignClass(c context.Context, tenantID uuid.UUID, req dto.BulkAssignClassRequest) (*dto.BulkAssignClassResponse, error)
 	List(c context.Context, tenantID uuid.UUID, params dto.StudentQueryParams) ([]model.Student, *dto.PaginationMeta, error)
-	GetByClass(c context.Context, tenantID, classID uuid.UUID, params dto.QueryParams) ([]model.Student, *dto.PaginationMeta, error)
-	GetByParent(c context.Context, tenantID, parentID uuid.UUID, params dto.QueryParams) ([]model.Student, *dto.PaginationMeta, error)
+	GetByClass(c context.Context, tenantID, classID uuid.UUID, params dto.StudentQueryParams) ([]model.Student, *dto.PaginationMeta, error)
+	GetByParent(c context.Context, tenantID, parentID uuid.UUID, params dto.StudentQueryParams) ([]model.Student, *dto.PaginationMeta, error)
+	GetChildren(c context.Context, tenantID, userID uuid.UUID) ([]model.Student, error)
+	Transfer(c context.Context, tenantID, id, userID uuid.UUID, req dto.TransferStudentRequest) (*dto.TransferStudentResponse, error)
+	ChangeStatus(c context.Context, tenantID, id uuid.UUID, req dto.ChangeStudentStatusRequest) (*model.Student, error)
+	AddGuardian(c context.Context, tenantID, id uuid.UUID, req dto.AddStudentGuardianRequest) ([]model.StudentGuardian, error)
+	RemoveGuardian(c context.Context, tenantID, id, parentID uuid.UUID) error
+	GetNumberFormat(c context.Context, tenantID uuid.UUID) (*dto.StudentNumberFormatResponse, error)
+	UpdateNumberFormat(c context.Context, tenantID uuid.UUID, req dto.UpdateStudentNumberFormatRequest) (*dto.StudentNumberFormatResponse, error)
+	QRCode(c context.Context, tenantID, id uuid.UUID) (*model.Student, []byte, error)
 }
 
 // studentService implements StudentService
 type studentService struct {
-	studentRepo    repository.StudentRepository
-	tenantUserRepo repository.TenantUserRepository
+	studentRepo        repository.StudentRepository
+	tenantUserRepo     repository.TenantUserRepository
+	tenantUserRoleRepo repository.TenantUserRoleRepository
+	settingService     TenantSettingService
+	classRepo          repository.ClassRepository
+	academicYearRepo   repository.AcademicYearRepository
+	sequenceRepo       repository.SequenceRepository
+	events             event.Publisher
 }
 
 // NewStudentService creates a new student service
 func NewStudentService(
 	studentRepo repository.StudentRepository,
 	tenantUserRepo repository.TenantUserRepository,
+	tenantUserRoleRepo repository.TenantUserRoleRepository,
+	settingService TenantSettingService,
+	classRepo repository.ClassRepository,
+	academicYearRepo repository.AcademicYearRepository,
+	sequenceRepo repository.SequenceRepository,
+	events event.Publisher,
 ) StudentService {
 	return &studentService{
-		studentRepo:    studentRepo,
-		tenantUserRepo: tenantUserRepo,
+		studentRepo:        studentRepo,
+		tenantUserRepo:     tenantUserRepo,
+		tenantUserRoleRepo: tenantUserRoleRepo,
+		settingService:     settingService,
+		classRepo:          classRepo,
+		academicYearRepo:   academicYearRepo,
+		sequenceRepo:       sequenceRepo,
+		events:             events,
 	}
 }
 
+// maxGeneratedNumberAttempts bounds how many taken student numbers generation skips
+const maxGeneratedNumberAttempts = 20
+
+// studentQRScale is the number of pixels per module in student QR code images
+const studentQRScale = 8
+
+// transferAdminRoles lists the roles allowed to receive transferred students in a tenant
+var transferAdminRoles = []string{"Admin", "Developer"}
+
 func (s *studentService) Create(c context.Context, tenantID uuid.UUID, req dto.CreateStudentRequest) (*model.Student, error) {
 	// Create context logger for service
 	logger := util.NewServiceLogger(c)
@@ -53,7 +95,7 @@ func (s *studentService) Create(c context.Context, tenantID uuid.UUID, req dto.C
 			Str("tenant_user_id", req.TenantUserID.String()).
 			Str("tenant_id", tenantID.String()).
 			Msg("Tenant user not found during student creation")
-		return nil, errors.New("tenant user not found")
+		return nil, apperror.NotFound("tenant user not found")
 	}
 
 	// Verify tenant user belongs to the correct tenant
@@ -63,17 +105,33 @@ func (s *studentService) Create(c context.Context, tenantID uuid.UUID, req dto.C
 			Str("expected_tenant", tenantID.String()).
 			Str("actual_tenant", tenantUser.TenantID.String()).
 			Msg("Tenant user does not belong to the specified tenant")
-		return nil, errors.New("tenant user does not belong to this tenant")
+		return nil, apperror.Validation("tenant user does not belong to this tenant")
 	}
 
-	// Check if student number already exists within tenant
-	existingStudent, _ := s.studentRepo.GetByStudentNumber(c, req.StudentNumber, tenantID)
-	if existingStudent != nil {
+	now := util.NowFromContext(c)
+	if util.IsFutureDate(req.AdmissionDate, now) {
+		return nil, apperror.Validation("admission date must not be in the future")
+	}
+
+	if err := s.validateAgeForClass(c, tenantID, req.ClassID, tenantUser.User, now); err != nil {
 		logger.Warn().
-			Str("student_number", req.StudentNumber).
+			Err(err).
+			Str("tenant_user_id", req.TenantUserID.String()).
 			Str("tenant_id", tenantID.String()).
-			Msg("Student creation attempt with existing student number")
-		return nil, errors.New("student number already exists")
+			Msg("Student age does not match the class grade level")
+		return nil, err
+	}
+
+	// Check if student number already exists within tenant
+	if req.StudentNumber != "" {
+		existingStudent, _ := s.studentRepo.GetByStudentNumber(c, req.StudentNumber, tenantID)
+		if existingStudent != nil {
+			logger.Warn().
+				Str("student_number", req.StudentNumber).
+				Str("tenant_id", tenantID.String()).
+				Msg("Student creation attempt with existing student number")
+			return nil, apperror.Conflict("student number already exists")
+		}
 	}
 
 	// Create student
@@ -86,19 +144,146 @@ func (s *studentService) Create(c context.Context, tenantID uuid.UUID, req dto.C
 		ParentID:      req.ParentID,
 	}
 
-	err = s.studentRepo.Create(c, student)
+	if req.StudentNumber == "" {
+		err = s.createWithGeneratedNumber(c, student)
+	} else {
+		err = s.studentRepo.Create(c, student)
+	}
 	if err != nil {
 		logger.Error().
 			Err(err).
-			Str("student_number", req.StudentNumber).
+			Str("student_number", student.StudentNumber).
 			Str("tenant_id", tenantID.String()).
 			Msg("Failed to create student in database")
-		return nil, errors.New("failed to create student")
+		return nil, apperror.Internal("failed to create student")
 	}
 
+	s.events.Publish(c, event.StudentCreated{
+		Metadata:      event.Metadata{TenantID: tenantID, OccurredAt: now},
+		StudentID:     student.ID,
+		StudentNumber: student.StudentNumber,
+		ClassID:       student.ClassID,
+	})
+
+	student.Age = studentAge(tenantUser.User, now)
 	return student, nil
 }
 
+// createWithGeneratedNumber creates the student with the next number of the tenant's format. The academic year
+// part is the start year of the active academic year, or the admission year when no year is active.
+func (s *studentService) createWithGeneratedNumber(c context.Context, student *model.Student) error {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	format, _, err := s.numberFormat(c, student.TenantID)
+	if err != nil {
+		return err
+	}
+
+	year := student.AdmissionDate.Year()
+	if academicYear, err := s.academicYearRepo.GetActive(c, student.TenantID); err == nil {
+		year = academicYear.StartDate.Year()
+	}
+
+	period := format.Period(year)
+
+	// The sequence stays locked until the student is inserted, so concurrent creations never share a number
+	err = s.studentRepo.WithTransaction(c, student.TenantID, func(txCtx context.Context) error {
+		for attempt := 1; attempt <= maxGeneratedNumberAttempts; attempt++ {
+			value, err := s.sequenceRepo.NextVal(txCtx, student.TenantID, format.SequenceName(period))
+			if err != nil {
+				return err
+			}
+			student.StudentNumber = format.Format(period, value)
+
+			// Skip numbers already entered by hand
+			if existing, _ := s.studentRepo.GetByStudentNumber(txCtx, student.StudentNumber, student.TenantID); existing == nil {
+				return s.studentRepo.Create(txCtx, student)
+			}
+		}
+		return errors.New("could not find an available student number")
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info().
+		Str("student_number", student.StudentNumber).
+		Str("tenant_id", student.TenantID.String()).
+		Msg("Student number generated")
+	return nil
+}
+
+// numberFormat returns the tenant's student number format, or the default format and true when it has none
+func (s *studentService) numberFormat(c context.Context, tenantID uuid.UUID) (model.StudentNumberFormat, bool, error) {
+	format, err := s.studentRepo.GetNumberFormat(c, tenantID)
+	if err != nil {
+		return model.StudentNumberFormat{}, false, err
+	}
+	if format == nil {
+		defaultFormat := model.DefaultStudentNumberFormat
+		defaultFormat.TenantID = tenantID
+		return defaultFormat, true, nil
+	}
+	return *format, false, nil
+}
+
+// GetNumberFormat returns the format used to generate the tenant's student numbers
+func (s *studentService) GetNumberFormat(c context.Context, tenantID uuid.UUID) (*dto.StudentNumberFormatResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	format, isDefault, err := s.numberFormat(c, tenantID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to get student number format")
+		return nil, apperror.Internal("failed to get student number format")
+	}
+	return studentNumberFormatResponse(c, format, isDefault), nil
+}
+
+// UpdateNumberFormat sets the format used to generate the tenant's student numbers
+func (s *studentService) UpdateNumberFormat(c context.Context, tenantID uuid.UUID, req dto.UpdateStudentNumberFormatRequest) (*dto.StudentNumberFormatResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	format := &model.StudentNumberFormat{
+		TenantID:            tenantID,
+		Prefix:              req.Prefix,
+		IncludeAcademicYear: req.IncludeAcademicYear,
+		SequenceDigits:      req.SequenceDigits,
+	}
+	if err := s.studentRepo.SaveNumberFormat(c, format); err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to update student number format")
+		return nil, apperror.Internal("failed to update student number format")
+	}
+
+	logger.Info().
+		Str("tenant_id", tenantID.String()).
+		Str("prefix", format.Prefix).
+		Bool("include_academic_year", format.IncludeAcademicYear).
+		Int("sequence_digits", format.SequenceDigits).
+		Msg("Student number format updated")
+
+	return studentNumberFormatResponse(c, *format, false), nil
+}
+
+// studentNumberFormatResponse converts a student number format, with the first number of the current year as example
+func studentNumberFormatResponse(c context.Context, format model.StudentNumberFormat, isDefault bool) *dto.StudentNumberFormatResponse {
+	return &dto.StudentNumberFormatResponse{
+		Prefix:              format.Prefix,
+		IncludeAcademicYear: format.IncludeAcademicYear,
+		SequenceDigits:      format.SequenceDigits,
+		Example:             format.Format(format.Period(util.NowFromContext(c).Year()), 1),
+		Default:             isDefault,
+	}
+}
+
 func (s *studentService) GetByID(c context.Context, id uuid.UUID) (*model.Student, error) {
 	// Create context logger for service
 	logger := util.NewServiceLogger(c)
@@ -109,8 +294,10 @@ func (s *studentService) GetByID(c context.Context, id uuid.UUID) (*model.Studen
 			Err(err).
 			Str("student_id", id.String()).
 			Msg("Failed to get student by ID")
-		return nil, errors.New("student not found")
+		return nil, apperror.NotFound("student not found")
 	}
+
+	setStudentAge(c, student)
 	return student, nil
 }
 
@@ -137,7 +324,7 @@ func (s *studentService) Update(c context.Context, id uuid.UUID, req dto.UpdateS
 				Str("student_id", id.String()).
 				Str("tenant_id", student.TenantID.String()).
 				Msg("Student update attempt with existing student number")
-			return nil, errors.New("student number already exists")
+			return nil, apperror.Conflict("student number already exists")
 		}
 	}
 
@@ -146,6 +333,9 @@ func (s *studentService) Update(c context.Context, id uuid.UUID, req dto.UpdateS
 		student.StudentNumber = *req.StudentNumber
 	}
 	if req.AdmissionDate != nil {
+		if util.IsFutureDate(*req.AdmissionDate, util.NowFromContext(c)) {
+			return nil, apperror.Validation("admission date must not be in the future")
+		}
 		student.AdmissionDate = *req.AdmissionDate
 	}
 	if req.ClassID != nil {
@@ -161,9 +351,15 @@ func (s *studentService) Update(c context.Context, id uuid.UUID, req dto.UpdateS
 			Err(err).
 			Str("student_id", id.String()).
 			Msg("Failed to update student in database")
-		return nil, errors.New("failed to update student")
+		return nil, apperror.Internal("failed to update student")
 	}
 
+	s.events.Publish(c, event.StudentUpdated{
+		Metadata:  event.Metadata{TenantID: student.TenantID, OccurredAt: util.NowFromContext(c)},
+		StudentID: student.ID,
+	})
+
+	setStudentAge(c, student)
 	return student, nil
 }
 
@@ -172,7 +368,7 @@ func (s *studentService) Delete(c context.Context, id uuid.UUID) error {
 	logger := util.NewServiceLogger(c)
 
 	// Check if student exists
-	_, err := s.studentRepo.GetByID(c, id)
+	student, err := s.studentRepo.GetByID(c, id)
 	if err != nil {
 		logger.Error().
 			Err(err).
@@ -190,6 +386,10 @@ func (s *studentService) Delete(c context.Context, id uuid.UUID) error {
 		return err
 	}
 
+	s.events.Publish(c, event.StudentDeleted{
+		Metadata:  event.Metadata{TenantID: student.TenantID, OccurredAt: util.NowFromContext(c)},
+		StudentID: id,
+	})
 	return nil
 }
 
@@ -198,30 +398,124 @@ func (s *studentService) BulkDelete(c context.Context, tenantID uuid.UUID, ids [
 	logger := util.NewServiceLogger(c)
 
 	if len(ids) == 0 {
-		return errors.New("no student IDs provided for bulk delete")
+		return apperror.Validation("no student IDs provided for bulk delete")
+	}
+
+	validIDs, _, err := s.filterTenantStudentIDs(c, tenantID, ids)
+	if err != nil {
+		return apperror.Internal("failed to validate students for bulk delete")
+	}
+
+	if len(validIDs) == 0 {
+		return apperror.Validation("no valid student IDs found for bulk delete in this tenant")
+	}
+
+	// Perform bulk delete
+	err = s.studentRepo.BulkDelete(c, validIDs)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Interface("student_ids", validIDs).
+			Msg("Failed to bulk delete students from database")
+		return apperror.Internal("failed to bulk delete students")
+	}
+
+	now := util.NowFromContext(c)
+	for _, id := range validIDs {
+		s.events.Publish(c, event.StudentDeleted{
+			Metadata:  event.Metadata{TenantID: tenantID, OccurredAt: now},
+			StudentID: id,
+		})
+	}
+	return nil
+}
+
+// BulkAssignClass moves the given students of the tenant into a class, skipping IDs that do not belong to the tenant
+func (s *studentService) BulkAssignClass(c context.Context, tenantID uuid.UUID, req dto.BulkAssignClassRequest) (*dto.BulkAssignClassResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	class, err := s.classRepo.GetByID(c, req.ClassID)
+	if err != nil || class.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("class_id", req.ClassID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Class not found in tenant for bulk class assignment")
+		return nil, apperror.NotFound("class not found")
+	}
+
+	validIDs, invalidIDs, err := s.filterTenantStudentIDs(c, tenantID, req.IDs)
+	if err != nil {
+		return nil, apperror.Internal("failed to validate students for class assignment")
+	}
+
+	if len(validIDs) == 0 {
+		return nil, apperror.Validation("no valid student IDs found for class assignment in this tenant")
+	}
+
+	updated, err := s.studentRepo.BulkUpdateClass(c, tenantID, validIDs, req.ClassID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Str("class_id", req.ClassID.String()).
+			Interface("student_ids", validIDs).
+			Msg("Failed to bulk update student class in database")
+		return nil, apperror.Internal("failed to assign students to class")
+	}
+
+	logger.Info().
+		Str("tenant_id", tenantID.String()).
+		Str("class_id", req.ClassID.String()).
+		Int64("updated", updated).
+		Int("skipped", len(invalidIDs)).
+		Msg("Students assigned to class")
+
+	return &dto.BulkAssignClassResponse{
+		ClassID:      req.ClassID,
+		UpdatedCount: updated,
+		SkippedCount: len(invalidIDs),
+		SkippedIDs:   invalidIDs,
+	}, nil
+}
+
+// filterTenantStudentIDs splits the given IDs, without duplicates, into students of the tenant and
+// IDs that do not exist or belong to another tenant
+func (s *studentService) filterTenantStudentIDs(c context.Context, tenantID uuid.UUID, ids []uuid.UUID) ([]uuid.UUID, []uuid.UUID, error) {
+	logger := util.NewServiceLogger(c)
+
+	uniqueIDs := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			uniqueIDs = append(uniqueIDs, id)
+		}
 	}
 
 	// Get students that belong to the tenant to validate they exist and log properly
-	students, _, err := s.studentRepo.List(c, tenantID, 0, len(ids)*2, "")
+	students, err := s.studentRepo.GetByIDs(c, tenantID, uniqueIDs)
 	if err != nil {
 		logger.Error().
 			Err(err).
 			Str("tenant_id", tenantID.String()).
-			Interface("student_ids", ids).
-			Msg("Failed to validate students for bulk delete")
-		return errors.New("failed to validate students for bulk delete")
+			Interface("student_ids", uniqueIDs).
+			Msg("Failed to validate student IDs")
+		return nil, nil, err
 	}
 
 	// Create a set of valid student IDs that belong to the tenant
-	validStudentMap := make(map[uuid.UUID]bool)
+	validStudentMap := make(map[uuid.UUID]bool, len(students))
 	for _, student := range students {
 		validStudentMap[student.ID] = true
 	}
 
 	// Filter IDs to only include students that belong to the tenant
-	var validIDs []uuid.UUID
-	var invalidIDs []uuid.UUID
-	for _, id := range ids {
+	validIDs := []uuid.UUID{}
+	invalidIDs := []uuid.UUID{}
+	for _, id := range uniqueIDs {
 		if validStudentMap[id] {
 			validIDs = append(validIDs, id)
 		} else {
@@ -236,22 +530,7 @@ func (s *studentService) BulkDelete(c context.Context, tenantID uuid.UUID, ids [
 			Msg("Some student IDs do not belong to the tenant or do not exist")
 	}
 
-	if len(validIDs) == 0 {
-		return errors.New("no valid student IDs found for bulk delete in this tenant")
-	}
-
-	// Perform bulk delete
-	err = s.studentRepo.BulkDelete(c, validIDs)
-	if err != nil {
-		logger.Error().
-			Err(err).
-			Str("tenant_id", tenantID.String()).
-			Interface("student_ids", validIDs).
-			Msg("Failed to bulk delete students from database")
-		return errors.New("failed to bulk delete students")
-	}
-
-	return nil
+	return validIDs, invalidIDs, nil
 }
 
 func (s *studentService) List(c context.Context, tenantID uuid.UUID, params dto.StudentQueryParams) ([]model.Student, *dto.PaginationMeta, error) {
@@ -268,42 +547,36 @@ func (s *studentService) List(c context.Context, tenantID uuid.UUID, params dto.
 
 	offset := (params.Page - 1) * params.Limit
 
-	var students []model.Student
-	var total int64
-	var err error
-
-	if params.ClassID != nil {
-		students, total, err = s.studentRepo.GetByClass(c, tenantID, *params.ClassID, offset, params.Limit)
-		if err != nil {
-			logger.Error().
-				Err(err).
-				Str("tenant_id", tenantID.String()).
-				Str("class_id", params.ClassID.String()).
-				Interface("params", params).
-				Msg("Failed to get students by class")
-		}
-	} else if params.ParentID != nil {
-		students, total, err = s.studentRepo.GetByParent(c, tenantID, *params.ParentID, offset, params.Limit)
-		if err != nil {
-			logger.Error().
-				Err(err).
-				Str("tenant_id", tenantID.String()).
-				Str("parent_id", params.ParentID.String()).
-				Interface("params", params).
-				Msg("Failed to get students by parent")
-		}
-	} else {
-		students, total, err = s.studentRepo.List(c, tenantID, offset, params.Limit, params.Search)
-		if err != nil {
-			logger.Error().
-				Err(err).
-				Str("tenant_id", tenantID.String()).
-				Interface("params", params).
-				Msg("Failed to get students by tenant")
-		}
-	}
-
+	// Only active students are listed unless a status is asked for
+	status := params.Status
+	switch status {
+	case "":
+		status = string(model.StudentActive)
+	case "all":
+		status = ""
+	}
+
+	// All filters are optional and combine with each other
+	students, total, err := s.studentRepo.List(c, tenantID, offset, params.Limit, repository.StudentFilter{
+		Expand:           studentExpansions(params),
+		Search:           params.Search,
+		ClassID:          params.ClassID,
+		ParentID:         params.ParentID,
+		AdmissionFrom:    params.EnrolledFrom,
+		AdmissionTo:      params.EnrolledTo,
+		EnrollmentStatus: params.EnrollmentStatus,
+		Status:           status,
+		CreatedAfter:     params.CreatedAfter,
+		UpdatedAfter:     params.UpdatedAfter,
+		SortBy:           params.SortBy,
+		SortDesc:         params.SortDir == "desc",
+	})
 	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Interface("params", params).
+			Msg("Failed to get students by tenant")
 		return nil, nil, err
 	}
 
@@ -316,79 +589,421 @@ func (s *studentService) List(c context.Context, tenantID uuid.UUID, params dto.
 		TotalPages: totalPages,
 	}
 
+	for i := range students {
+		setStudentAge(c, &students[i])
+	}
+
 	return students, meta, nil
 }
 
-func (s *studentService) GetByClass(c context.Context, tenantID, classID uuid.UUID, params dto.QueryParams) ([]model.Student, *dto.PaginationMeta, error) {
+// GetByClass lists the students of a class; it is a shorthand for List with the class filter set
+func (s *studentService) GetByClass(c context.Context, tenantID, classID uuid.UUID, params dto.StudentQueryParams) ([]model.Student, *dto.PaginationMeta, error) {
+	params.ClassID = &classID
+	return s.List(c, tenantID, params)
+}
+
+// GetByParent lists the students of a parent; it is a shorthand for List with the parent filter set
+func (s *studentService) GetByParent(c context.Context, tenantID, parentID uuid.UUID, params dto.StudentQueryParams) ([]model.Student, *dto.PaginationMeta, error) {
+	params.ParentID = &parentID
+	return s.List(c, tenantID, params)
+}
+
+// GetChildren lists the students of the tenant whose parent record has the user's verified email
+func (s *studentService) GetChildren(c context.Context, tenantID, userID uuid.UUID) ([]model.Student, error) {
 	// Create context logger for service
 	logger := util.NewServiceLogger(c)
 
-	// Set defaults
-	if params.Page < 1 {
-		params.Page = 1
+	students, err := s.studentRepo.GetAllByParentUser(c, tenantID, userID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", userID.String()).
+			Msg("Failed to list children of parent user")
+		return nil, apperror.Internal("failed to get children")
 	}
-	if params.Limit < 1 {
-		params.Limit = 10
+	return students, nil
+}
+
+// studentExpansions returns the relations to load for a listing: the expanded ones and the ones named in fields.
+// The user is also loaded when only the derived age is requested.
+func studentExpansions(params dto.StudentQueryParams) []string {
+	requested := make(map[string]bool, len(params.Expand)+len(params.Fields))
+	for _, relation := range params.Expand {
+		requested[relation] = true
+	}
+	for _, field := range params.Fields {
+		if field == "age" {
+			field = "tenant_user"
+		}
+		requested[field] = true
 	}
 
-	offset := (params.Page - 1) * params.Limit
+	var expand []string
+	for _, relation := range dto.StudentRelations {
+		if requested[relation] {
+			expand = append(expand, relation)
+		}
+	}
+	return expand
+}
+
+// Transfer copies a student into a destination tenant where the caller is an Admin or Developer,
+// optionally deactivating the student in the source tenant
+func (s *studentService) Transfer(c context.Context, tenantID, id, userID uuid.UUID, req dto.TransferStudentRequest) (*dto.TransferStudentResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	if req.DestinationTenantID == tenantID {
+		return nil, apperror.Validation("destination tenant must be different from the current tenant")
+	}
+
+	student, err := s.studentRepo.GetByID(c, id)
+	if err != nil || student.TenantID != tenantID || student.TenantUser == nil {
+		logger.Warn().
+			Err(err).
+			Str("student_id", id.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Student not found in tenant during transfer")
+		return nil, apperror.NotFound("student not found")
+	}
 
-	students, total, err := s.studentRepo.GetByClass(c, tenantID, classID, offset, params.Limit)
+	if err := s.ensureTenantAdmin(c, req.DestinationTenantID, userID); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("user_id", userID.String()).
+			Str("destination_tenant_id", req.DestinationTenantID.String()).
+			Msg("Student transfer to unauthorized tenant")
+		return nil, err
+	}
+
+	// Reuse the student's tenant user in the destination if it exists and is not a student there yet
+	var existingTenantUserID *uuid.UUID
+	existingTenantUser, _ := s.tenantUserRepo.GetByTenantAndUser(c, req.DestinationTenantID, student.TenantUser.UserID)
+	if existingTenantUser != nil {
+		existingStudent, _ := s.studentRepo.GetByTenantUserID(c, existingTenantUser.ID)
+		if existingStudent != nil {
+			logger.Warn().
+				Str("student_id", id.String()).
+				Str("destination_tenant_id", req.DestinationTenantID.String()).
+				Msg("Student transfer attempt for student already in destination tenant")
+			return nil, apperror.Conflict("student already exists in the destination tenant")
+		}
+		existingTenantUserID = &existingTenantUser.ID
+	}
+
+	// Carry the student's roles over to the destination tenant
+	tenantUserRoles, err := s.tenantUserRoleRepo.GetRolesByTenantUser(c, student.TenantUserID)
 	if err != nil {
 		logger.Error().
 			Err(err).
-			Str("tenant_id", tenantID.String()).
-			Str("class_id", classID.String()).
-			Interface("params", params).
-			Msg("Failed to get students by class")
-		return nil, nil, err
+			Str("tenant_user_id", student.TenantUserID.String()).
+			Msg("Failed to get student roles during transfer")
+		return nil, apperror.Internal("failed to transfer student")
+	}
+	roleIDs := make([]uuid.UUID, 0, len(tenantUserRoles))
+	for _, tenantUserRole := range tenantUserRoles {
+		roleIDs = append(roleIDs, tenantUserRole.RoleID)
 	}
 
-	totalPages := int(math.Ceil(float64(total) / float64(params.Limit)))
+	studentNumber := student.StudentNumber
+	if req.StudentNumber != nil && *req.StudentNumber != "" {
+		studentNumber = *req.StudentNumber
+	}
+	admissionDate := util.NowFromContext(c)
+	if req.AdmissionDate != nil {
+		if util.IsFutureDate(*req.AdmissionDate, admissionDate) {
+			return nil, apperror.Validation("admission date must not be in the future")
+		}
+		admissionDate = *req.AdmissionDate
+	}
+
+	transferred, err := s.studentRepo.TransferToTenant(c, repository.StudentTransfer{
+		Source:              student,
+		DestinationTenantID: req.DestinationTenantID,
+		StudentNumber:       studentNumber,
+		AdmissionDate:       admissionDate,
+		TenantUserID:        existingTenantUserID,
+		RoleIDs:             roleIDs,
+		DeactivateSource:    req.DeactivateSource,
+	})
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("student_id", id.String()).
+			Str("destination_tenant_id", req.DestinationTenantID.String()).
+			Msg("Failed to transfer student in database")
+		return nil, apperror.Internal("failed to transfer student")
+	}
+
+	logger.Info().
+		Str("student_id", id.String()).
+		Str("new_student_id", transferred.ID.String()).
+		Str("destination_tenant_id", req.DestinationTenantID.String()).
+		Bool("source_deactivated", req.DeactivateSource).
+		Msg("Student transferred to another tenant")
+
+	return &dto.TransferStudentResponse{
+		SourceStudentID:      id,
+		StudentID:            transferred.ID,
+		DestinationTenantID:  req.DestinationTenantID,
+		TenantUserID:         transferred.TenantUserID,
+		StudentNumber:        transferred.StudentNumber,
+		StudentNumberChanged: transferred.StudentNumber != studentNumber,
+		SourceDeactivated:    req.DeactivateSource,
+	}, nil
+}
 
-	meta := &dto.PaginationMeta{
-		Page:       params.Page,
-		Limit:      params.Limit,
-		TotalRows:  total,
-		TotalPages: totalPages,
+// studentStatusTransitions lists the statuses a student can move to from each status. Graduation is final.
+var studentStatusTransitions = map[model.StudentStatus]map[model.StudentStatus]bool{
+	model.StudentActive:    {model.StudentGraduated: true, model.StudentWithdrawn: true, model.StudentSuspended: true},
+	model.StudentSuspended: {model.StudentActive: true, model.StudentWithdrawn: true},
+	model.StudentWithdrawn: {model.StudentActive: true},
+}
+
+// ChangeStatus moves a student to another lifecycle status if the transition is allowed.
+// The student's enrollments, grades and attendance are kept.
+func (s *studentService) ChangeStatus(c context.Context, tenantID, id uuid.UUID, req dto.ChangeStudentStatusRequest) (*model.Student, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	student, err := s.studentRepo.GetByID(c, id)
+	if err != nil || student.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("student_id", id.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Student not found in tenant during status change")
+		return nil, apperror.NotFound("student not found")
 	}
 
-	return students, meta, nil
+	status := model.StudentStatus(req.Status)
+	if !studentStatusTransitions[student.Status][status] {
+		logger.Warn().
+			Str("student_id", id.String()).
+			Str("from_status", string(student.Status)).
+			Str("to_status", req.Status).
+			Msg("Invalid student status transition")
+		return nil, apperror.Validation(fmt.Sprintf("student status cannot change from %s to %s", student.Status, status))
+	}
+
+	changedAt := util.NowFromContext(c)
+	if err := s.studentRepo.UpdateStatus(c, tenantID, id, status, changedAt); err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil, err
+		}
+		logger.Error().
+			Err(err).
+			Str("student_id", id.String()).
+			Msg("Failed to update student status in database")
+		return nil, apperror.Internal("failed to change student status")
+	}
+
+	logger.Info().
+		Str("student_id", id.String()).
+		Str("from_status", string(student.Status)).
+		Str("to_status", string(status)).
+		Msg("Student status changed")
+
+	s.events.Publish(c, event.StudentStatusChanged{
+		Metadata:   event.Metadata{TenantID: tenantID, OccurredAt: changedAt},
+		StudentID:  id,
+		FromStatus: string(student.Status),
+		ToStatus:   string(status),
+	})
+
+	student.Status = status
+	student.StatusChangedAt = &changedAt
+	setStudentAge(c, student)
+	return student, nil
 }
 
-func (s *studentService) GetByParent(c context.Context, tenantID, parentID uuid.UUID, params dto.QueryParams) ([]model.Student, *dto.PaginationMeta, error) {
+// AddGuardian links a parent of the tenant to the student and returns the student's guardians
+func (s *studentService) AddGuardian(c context.Context, tenantID, id uuid.UUID, req dto.AddStudentGuardianRequest) ([]model.StudentGuardian, error) {
 	// Create context logger for service
 	logger := util.NewServiceLogger(c)
 
-	// Set defaults
-	if params.Page < 1 {
-		params.Page = 1
+	student, err := s.studentRepo.GetByID(c, id)
+	if err != nil || student.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("student_id", id.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Student not found in tenant while adding guardian")
+		return nil, apperror.NotFound("student not found")
 	}
-	if params.Limit < 1 {
-		params.Limit = 10
+
+	guardian := &model.StudentGuardian{
+		TenantID:     tenantID,
+		StudentID:    id,
+		ParentID:     req.ParentID,
+		Relationship: req.Relationship,
+		IsPrimary:    req.IsPrimary,
+	}
+	if err := s.studentRepo.SaveGuardian(c, guardian); err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil, err
+		}
+		logger.Error().
+			Err(err).
+			Str("student_id", id.String()).
+			Str("parent_id", req.ParentID.String()).
+			Msg("Failed to save student guardian in database")
+		return nil, apperror.Internal("failed to add guardian")
 	}
 
-	offset := (params.Page - 1) * params.Limit
+	s.events.Publish(c, event.StudentUpdated{
+		Metadata:  event.Metadata{TenantID: tenantID, OccurredAt: util.NowFromContext(c)},
+		StudentID: id,
+	})
 
-	students, total, err := s.studentRepo.GetByParent(c, tenantID, parentID, offset, params.Limit)
+	guardians, err := s.studentRepo.GetGuardians(c, tenantID, id)
 	if err != nil {
 		logger.Error().
 			Err(err).
+			Str("student_id", id.String()).
+			Msg("Failed to get student guardians")
+		return nil, apperror.Internal("failed to get guardians")
+	}
+	return guardians, nil
+}
+
+// RemoveGuardian unlinks a parent from the student; removing the primary guardian promotes the next one
+func (s *studentService) RemoveGuardian(c context.Context, tenantID, id, parentID uuid.UUID) error {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	student, err := s.studentRepo.GetByID(c, id)
+	if err != nil || student.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("student_id", id.String()).
 			Str("tenant_id", tenantID.String()).
+			Msg("Student not found in tenant while removing guardian")
+		return apperror.NotFound("student not found")
+	}
+
+	if err := s.studentRepo.RemoveGuardian(c, tenantID, id, parentID); err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return err
+		}
+		logger.Error().
+			Err(err).
+			Str("student_id", id.String()).
 			Str("parent_id", parentID.String()).
-			Interface("params", params).
-			Msg("Failed to get students by parent")
-		return nil, nil, err
+			Msg("Failed to remove student guardian from database")
+		return apperror.Internal("failed to remove guardian")
 	}
 
-	totalPages := int(math.Ceil(float64(total) / float64(params.Limit)))
+	s.events.Publish(c, event.StudentUpdated{
+		Metadata:  event.Metadata{TenantID: tenantID, OccurredAt: util.NowFromContext(c)},
+		StudentID: id,
+	})
+	return nil
+}
 
-	meta := &dto.PaginationMeta{
-		Page:       params.Page,
-		Limit:      params.Limit,
-		TotalRows:  total,
-		TotalPages: totalPages,
+// QRCode returns the student and a PNG QR code encoding the tenant and student number for attendance scanning
+func (s *studentService) QRCode(c context.Context, tenantID, id uuid.UUID) (*model.Student, []byte, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	student, err := s.studentRepo.GetByID(c, id)
+	if err != nil || student.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("student_id", id.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Student not found in tenant for QR code")
+		return nil, nil, apperror.NotFound("student not found")
 	}
 
-	return students, meta, nil
+	code, err := qrcode.Encode([]byte(util.StudentQRPayload(tenantID, student.StudentNumber)))
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("student_id", id.String()).
+			Msg("Failed to encode student QR code")
+		return nil, nil, apperror.Internal("failed to generate QR code")
+	}
+	image, err := code.PNG(studentQRScale)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("student_id", id.String()).
+			Msg("Failed to render student QR code")
+		return nil, nil, apperror.Internal("failed to generate QR code")
+	}
+
+	return student, image, nil
+}
+
+// ensureTenantAdmin checks that the user is an active Admin or Developer of the tenant
+func (s *studentService) ensureTenantAdmin(c context.Context, tenantID, userID uuid.UUID) error {
+	tenantUser, err := s.tenantUserRepo.GetByTenantAndUser(c, tenantID, userID)
+	if err != nil || !tenantUser.IsActive {
+		return apperror.Forbidden("you do not have access to the destination tenant")
+	}
+
+	tenantUserRoles, err := s.tenantUserRoleRepo.GetRolesByTenantUser(c, tenantUser.ID)
+	if err != nil {
+		return apperror.Internal("failed to verify destination tenant access")
+	}
+	for _, tenantUserRole := range tenantUserRoles {
+		if tenantUserRole.Role == nil {
+			continue
+		}
+		for _, allowed := range transferAdminRoles {
+			if strings.EqualFold(tenantUserRole.Role.Name, allowed) {
+				return nil
+			}
+		}
+	}
+	return apperror.Forbidden("you must be an admin of the destination tenant")
+}
+
+// validateAgeForClass checks the user's age against the age window the tenant expects for the class grade
+// level. It passes when the tenant has no age window, or the class, grade level or date of birth is unknown.
+func (s *studentService) validateAgeForClass(c context.Context, tenantID uuid.UUID, classID *uuid.UUID, user *model.User, now time.Time) error {
+	if classID == nil || user == nil || user.DateOfBirth == nil {
+		return nil
+	}
+
+	minAge, maxAge := s.settingService.StudentAgeWindow(c, tenantID)
+	if minAge == nil && maxAge == nil {
+		return nil
+	}
+
+	class, err := s.classRepo.GetByID(c, *classID)
+	if err != nil || class.TenantID != tenantID {
+		return apperror.NotFound("class not found")
+	}
+	if class.GradeLevel == nil {
+		return nil
+	}
+
+	// The window is the one of grade level 1, moved up a year per grade level
+	age := util.AgeAt(*user.DateOfBirth, now)
+	shift := *class.GradeLevel - 1
+	if minAge != nil && age < *minAge+shift {
+		return apperror.Validation(fmt.Sprintf("student age %d is below the expected minimum of %d for grade level %d", age, *minAge+shift, *class.GradeLevel))
+	}
+	if maxAge != nil && age > *maxAge+shift {
+		return apperror.Validation(fmt.Sprintf("student age %d is above the expected maximum of %d for grade level %d", age, *maxAge+shift, *class.GradeLevel))
+	}
+	return nil
+}
+
+// setStudentAge fills the derived age of a student loaded with its tenant user and user
+func setStudentAge(c context.Context, student *model.Student) {
+	if student.TenantUser != nil {
+		student.Age = studentAge(student.TenantUser.User, util.NowFromContext(c))
+	}
+}
+
+// studentAge returns the user's age, or nil when the date of birth is unknown
+func studentAge(user *model.User, now time.Time) *int {
+	if user == nil || user.DateOfBirth == nil {
+		return nil
+	}
+	age := util.AgeAt(*user.DateOfBirth, now)
+	return &age
 }
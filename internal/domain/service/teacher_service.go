@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// scheduleTimeLayout is the format of schedule start and end times
+const scheduleTimeLayout = "15:04"
+
+// TeacherService interface defines teacher service methods
+type TeacherService interface {
+	GetSchedule(c context.Context, tenantID, teacherID uuid.UUID) (*dto.TeacherScheduleResponse, error)
+}
+
+// teacherService implements TeacherService
+type teacherService struct {
+	teacherRepo  repository.TeacherRepository
+	scheduleRepo repository.ScheduleRepository
+}
+
+// NewTeacherService creates a new teacher service
+func NewTeacherService(teacherRepo repository.TeacherRepository, scheduleRepo repository.ScheduleRepository) TeacherService {
+	return &teacherService{
+		teacherRepo:  teacherRepo,
+		scheduleRepo: scheduleRepo,
+	}
+}
+
+// GetSchedule builds the teacher's weekly timetable, flagging overlapping entries as conflicts.
+// Overlapping entries are all counted in the weekly teaching hours.
+func (s *teacherService) GetSchedule(c context.Context, tenantID, teacherID uuid.UUID) (*dto.TeacherScheduleResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	teacher, err := s.teacherRepo.GetByID(c, teacherID)
+	if err != nil || teacher.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("teacher_id", teacherID.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Teacher not found in tenant for schedule")
+		return nil, apperror.NotFound("teacher not found")
+	}
+
+	entries, err := s.scheduleRepo.ListForTeacher(c, tenantID, teacherID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("teacher_id", teacherID.String()).
+			Msg("Failed to list teacher schedule")
+		return nil, apperror.Internal("failed to get teacher schedule")
+	}
+
+	// A schedule without a day or times cannot be placed in the timetable
+	scheduled := entries[:0]
+	for _, entry := range entries {
+		if entry.DayOfWeek == nil || entry.StartTime == nil || entry.EndTime == nil {
+			logger.Warn().
+				Str("schedule_id", entry.ScheduleID.String()).
+				Msg("Schedule without a day or times left out of the timetable")
+			continue
+		}
+		scheduled = append(scheduled, entry)
+	}
+	entries = scheduled
+
+	slots := make([]dto.TeacherScheduleSlot, len(entries))
+	starts := make([]time.Time, len(entries))
+	ends := make([]time.Time, len(entries))
+	var total time.Duration
+	for i, entry := range entries {
+		starts[i], err = time.Parse(scheduleTimeLayout, *entry.StartTime)
+		if err == nil {
+			ends[i], err = time.Parse(scheduleTimeLayout, *entry.EndTime)
+		}
+		if err != nil {
+			logger.Error().
+				Err(err).
+				Str("schedule_id", entry.ScheduleID.String()).
+				Msg("Invalid schedule time")
+			return nil, apperror.Internal("failed to get teacher schedule")
+		}
+		total += ends[i].Sub(starts[i])
+
+		slots[i] = dto.TeacherScheduleSlot{
+			ScheduleID:     entry.ScheduleID,
+			ClassSubjectID: entry.ClassSubjectID,
+			ClassID:        entry.ClassID,
+			ClassName:      entry.ClassName,
+			SubjectID:      entry.SubjectID,
+			SubjectName:    entry.SubjectName,
+			DayOfWeek:      string(*entry.DayOfWeek),
+			StartTime:      *entry.StartTime,
+			EndTime:        *entry.EndTime,
+			Room:           entry.Room,
+		}
+	}
+
+	// Entries are sorted by day and start time, so only the following entries of the same day
+	// starting before this one ends can overlap it
+	conflicts := []dto.TeacherScheduleConflict{}
+	for i := range entries {
+		for j := i + 1; j < len(entries) && *entries[j].DayOfWeek == *entries[i].DayOfWeek && starts[j].Before(ends[i]); j++ {
+			overlapEnd := ends[i]
+			if ends[j].Before(overlapEnd) {
+				overlapEnd = ends[j]
+			}
+			conflicts = append(conflicts, dto.TeacherScheduleConflict{
+				DayOfWeek:        string(*entries[i].DayOfWeek),
+				FirstScheduleID:  entries[i].ScheduleID,
+				SecondScheduleID: entries[j].ScheduleID,
+				OverlapStart:     *entries[j].StartTime,
+				OverlapEnd:       overlapEnd.Format(scheduleTimeLayout),
+			})
+			slots[i].HasConflict = true
+			slots[j].HasConflict = true
+		}
+	}
+
+	if len(conflicts) > 0 {
+		logger.Info().
+			Str("teacher_id", teacherID.String()).
+			Int("conflicts", len(conflicts)).
+			Msg("Teacher schedule has overlapping entries")
+	}
+
+	return &dto.TeacherScheduleResponse{
+		TeacherID:         teacherID,
+		TotalHoursPerWeek: math.Round(total.Hours()*100) / 100,
+		Slots:             slots,
+		Conflicts:         conflicts,
+	}, nil
+}
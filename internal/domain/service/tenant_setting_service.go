@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/config"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/cache"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// TenantSettingService interface defines tenant setting service methods. Besides the generic Get and Update,
+// it has a typed getter per known key that returns the default when the tenant has not set it.
+type TenantSettingService interface {
+	Get(c context.Context, tenantID uuid.UUID) (*dto.TenantSettingsResponse, error)
+	Update(c context.Context, tenantID uuid.UUID, req dto.UpdateTenantSettingsRequest) (*dto.TenantSettingsResponse, error)
+	SelfRegistration(c context.Context, tenantID uuid.UUID) bool
+	DefaultRole(c context.Context, tenantID uuid.UUID) string
+	Location(c context.Context, tenantID uuid.UUID) *time.Location
+	AutoAbsent(c context.Context, tenantID uuid.UUID) bool
+	AutoAbsentAfter(c context.Context, tenantID uuid.UUID) time.Duration
+	StudentAgeWindow(c context.Context, tenantID uuid.UUID) (minAge, maxAge *int)
+}
+
+// tenantSettingDefinition describes a known tenant setting
+type tenantSettingDefinition struct {
+	defaultValue interface{}                   // Values of the setting are decoded into its type
+	validate     func(value interface{}) error // Optional check of a decoded value
+}
+
+// tenantSettingService implements TenantSettingService
+type tenantSettingService struct {
+	settingRepo repository.TenantSettingRepository
+	redis       *cache.Redis
+	cacheTTL    time.Duration
+	definitions map[string]tenantSettingDefinition
+}
+
+// NewTenantSettingService creates a new tenant setting service. Settings default to the matching
+// configuration values.
+func NewTenantSettingService(settingRepo repository.TenantSettingRepository, redis *cache.Redis, cfg *config.Config) TenantSettingService {
+	return &tenantSettingService{
+		settingRepo: settingRepo,
+		redis:       redis,
+		cacheTTL:    cfg.GetTenantSettingsTTL(),
+		definitions: map[string]tenantSettingDefinition{
+			model.TenantSettingSelfRegistration: {defaultValue: cfg.Auth.SelfRegistration},
+			model.TenantSettingDefaultRole:      {defaultValue: cfg.Auth.DefaultRole},
+			model.TenantSettingTimezone:         {defaultValue: cfg.App.Timezone, validate: validateTimezone},
+			model.TenantSettingAutoAbsent:       {defaultValue: cfg.Jobs.AutoAbsent.Enabled},
+			model.TenantSettingAutoAbsentAfter:  {defaultValue: cfg.Jobs.AutoAbsent.After, validate: validateTimeOfDay},
+			model.TenantSettingStudentMinAge:    {defaultValue: (*int)(nil), validate: validateAge},
+			model.TenantSettingStudentMaxAge:    {defaultValue: (*int)(nil), validate: validateAge},
+		},
+	}
+}
+
+// validateTimezone accepts IANA time zone names
+func validateTimezone(value interface{}) error {
+	if _, err := time.LoadLocation(value.(string)); err != nil {
+		return fmt.Errorf("unknown time zone %q", value)
+	}
+	return nil
+}
+
+// timeOfDayLayout is the format of time of day settings
+const timeOfDayLayout = "15:04"
+
+// defaultAutoAbsentAfter is when a day is closed for attendance if the tenant's setting can't be parsed
+const defaultAutoAbsentAfter = 18 * time.Hour
+
+// validateTimeOfDay accepts times of day in the HH:MM format
+func validateTimeOfDay(value interface{}) error {
+	if _, err := time.Parse(timeOfDayLayout, value.(string)); err != nil {
+		return fmt.Errorf("%q is not a time of day in the HH:MM format", value)
+	}
+	return nil
+}
+
+// validateAge accepts ages that are not negative
+func validateAge(value interface{}) error {
+	if age := value.(*int); *age < 0 {
+		return fmt.Errorf("age %d must not be negative", *age)
+	}
+	return nil
+}
+
+// Get returns the value in effect of every known setting of the tenant
+func (s *tenantSettingService) Get(c context.Context, tenantID uuid.UUID) (*dto.TenantSettingsResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	overrides, err := s.overrides(c, tenantID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to get tenant settings")
+		return nil, apperror.Internal("failed to get tenant settings")
+	}
+
+	response := &dto.TenantSettingsResponse{
+		Settings:   make(map[string]json.RawMessage, len(s.definitions)),
+		Customized: []string{},
+	}
+	for key, definition := range s.definitions {
+		if value, ok := overrides[key]; ok {
+			response.Settings[key] = value
+			response.Customized = append(response.Customized, key)
+			continue
+		}
+		value, err := json.Marshal(definition.defaultValue)
+		if err != nil {
+			return nil, apperror.Internal("failed to encode tenant settings")
+		}
+		response.Settings[key] = value
+	}
+	sort.Strings(response.Customized)
+	return response, nil
+}
+
+// Update validates and stores the given settings, resetting the ones set to null, and clears the cached
+// settings of the tenant
+func (s *tenantSettingService) Update(c context.Context, tenantID uuid.UUID, req dto.UpdateTenantSettingsRequest) (*dto.TenantSettingsResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	now := util.NowFromContext(c)
+	var settings []model.TenantSetting
+	var removedKeys []string
+	for key, raw := range req.Settings {
+		definition, ok := s.definitions[key]
+		if !ok {
+			return nil, apperror.Validation(fmt.Sprintf("unknown setting %q", key))
+		}
+		if string(raw) == "null" {
+			removedKeys = append(removedKeys, key)
+			continue
+		}
+
+		value := reflect.New(reflect.TypeOf(definition.defaultValue))
+		if err := json.Unmarshal(raw, value.Interface()); err != nil {
+			return nil, apperror.Validation(fmt.Sprintf("setting %q must be a %T", key, definition.defaultValue))
+		}
+		if definition.validate != nil {
+			if err := definition.validate(value.Elem().Interface()); err != nil {
+				return nil, apperror.Validation(fmt.Sprintf("setting %q: %s", key, err))
+			}
+		}
+		settings = append(settings, model.TenantSetting{TenantID: tenantID, Key: key, Value: raw, UpdatedAt: now})
+	}
+	if err := s.validateAgeWindow(c, tenantID, req.Settings); err != nil {
+		return nil, err
+	}
+
+	if err := s.settingRepo.Update(c, tenantID, settings, removedKeys); err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to update tenant settings")
+		return nil, apperror.Internal("failed to update tenant settings")
+	}
+	if err := s.redis.Del(c, tenantSettingsCacheKey(tenantID)); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to clear cached tenant settings, the old values are used until they expire")
+	}
+
+	logger.Info().
+		Str("tenant_id", tenantID.String()).
+		Int("updated", len(settings)).
+		Int("reset", len(removedKeys)).
+		Msg("Tenant settings updated")
+	return s.Get(c, tenantID)
+}
+
+// SelfRegistration reports whether users may join the tenant by naming it at registration
+func (s *tenantSettingService) SelfRegistration(c context.Context, tenantID uuid.UUID) bool {
+	return tenantSettingValue[bool](c, s, tenantID, model.TenantSettingSelfRegistration)
+}
+
+// DefaultRole returns the name of the role given to users registering into the tenant, empty for none
+func (s *tenantSettingService) DefaultRole(c context.Context, tenantID uuid.UUID) string {
+	return tenantSettingValue[string](c, s, tenantID, model.TenantSettingDefaultRole)
+}
+
+// Location returns the time zone of the tenant, UTC when the configured one can't be loaded
+func (s *tenantSettingService) Location(c context.Context, tenantID uuid.UUID) *time.Location {
+	location, err := time.LoadLocation(tenantSettingValue[string](c, s, tenantID, model.TenantSettingTimezone))
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// AutoAbsent reports whether the tenant's students without attendance are marked absent at the end of the day
+func (s *tenantSettingService) AutoAbsent(c context.Context, tenantID uuid.UUID) bool {
+	return tenantSettingValue[bool](c, s, tenantID, model.TenantSettingAutoAbsent)
+}
+
+// AutoAbsentAfter returns the time since local midnight from which the tenant's day is closed for attendance
+func (s *tenantSettingService) AutoAbsentAfter(c context.Context, tenantID uuid.UUID) time.Duration {
+	after, err := time.Parse(timeOfDayLayout, tenantSettingValue[string](c, s, tenantID, model.TenantSettingAutoAbsentAfter))
+	if err != nil {
+		return defaultAutoAbsentAfter
+	}
+	return time.Duration(after.Hour())*time.Hour + time.Duration(after.Minute())*time.Minute
+}
+
+// StudentAgeWindow returns the youngest and oldest expected ages of the tenant's students in grade level 1, nil
+// for a bound that is not set
+func (s *tenantSettingService) StudentAgeWindow(c context.Context, tenantID uuid.UUID) (minAge, maxAge *int) {
+	return tenantSettingValue[*int](c, s, tenantID, model.TenantSettingStudentMinAge),
+		tenantSettingValue[*int](c, s, tenantID, model.TenantSettingStudentMaxAge)
+}
+
+// validateAgeWindow checks that the youngest expected student age is not above the oldest once the updates
+// are applied, taking a bound the updates leave alone from the tenant's current settings
+func (s *tenantSettingService) validateAgeWindow(c context.Context, tenantID uuid.UUID, updates map[string]json.RawMessage) error {
+	_, updatesMin := updates[model.TenantSettingStudentMinAge]
+	_, updatesMax := updates[model.TenantSettingStudentMaxAge]
+	if !updatesMin && !updatesMax {
+		return nil
+	}
+
+	overrides, err := s.overrides(c, tenantID)
+	if err != nil {
+		util.NewServiceLogger(c).Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to get tenant settings to validate the student age window")
+		return apperror.Internal("failed to update tenant settings")
+	}
+	bound := func(key string) *int {
+		raw, ok := updates[key]
+		if !ok {
+			raw = overrides[key]
+		}
+		var age *int
+		if raw != nil {
+			// Updated values are validated already, stored ones were when they were set
+			_ = json.Unmarshal(raw, &age)
+		}
+		return age
+	}
+
+	minAge, maxAge := bound(model.TenantSettingStudentMinAge), bound(model.TenantSettingStudentMaxAge)
+	if minAge != nil && maxAge != nil && *minAge > *maxAge {
+		return apperror.Validation(fmt.Sprintf("setting %q (%d) must not be greater than %q (%d)",
+			model.TenantSettingStudentMinAge, *minAge, model.TenantSettingStudentMaxAge, *maxAge))
+	}
+	return nil
+}
+
+// tenantSettingValue returns the tenant's value of a known setting, or its default when the tenant has not set
+// it or the settings can't be read
+func tenantSettingValue[T any](c context.Context, s *tenantSettingService, tenantID uuid.UUID, key string) T {
+	logger := util.NewServiceLogger(c)
+
+	defaultValue := s.definitions[key].defaultValue.(T)
+	overrides, err := s.overrides(c, tenantID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Str("key", key).
+			Msg("Failed to get tenant setting, using the default")
+		return defaultValue
+	}
+	raw, ok := overrides[key]
+	if !ok {
+		return defaultValue
+	}
+
+	var value T
+	if err := json.Unmarshal(raw, &value); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Str("key", key).
+			Msg("Invalid tenant setting value, using the default")
+		return defaultValue
+	}
+	return value
+}
+
+// overrides returns the values the tenant has set by key, from the cache when it has them. Cache failures
+// only cost a database query.
+func (s *tenantSettingService) overrides(c context.Context, tenantID uuid.UUID) (map[string]json.RawMessage, error) {
+	logger := util.NewServiceLogger(c)
+
+	cacheKey := tenantSettingsCacheKey(tenantID)
+	cached, found, err := s.redis.Get(c, cacheKey)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to read cached tenant settings")
+	}
+	if found {
+		var overrides map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(cached), &overrides); err == nil {
+			return overrides, nil
+		}
+	}
+
+	settings, err := s.settingRepo.List(c, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	overrides := make(map[string]json.RawMessage, len(settings))
+	for _, setting := range settings {
+		overrides[setting.Key] = setting.Value
+	}
+
+	encoded, err := json.Marshal(overrides)
+	if err == nil {
+		err = s.redis.Set(c, cacheKey, string(encoded), s.cacheTTL)
+	}
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Msg("Failed to cache tenant settings")
+	}
+	return overrides, nil
+}
+
+// tenantSettingsCacheKey is the cache key of a tenant's settings
+func tenantSettingsCacheKey(tenantID uuid.UUID) string {
+	return "tenant_settings:" + tenantID.String()
+}
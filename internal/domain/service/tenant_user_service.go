@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// TenantUserService interface defines tenant membership service methods
+type TenantUserService interface {
+	List(c context.Context, tenantID uuid.UUID, params dto.TenantUserQueryParams) ([]model.TenantUser, *dto.PaginationMeta, error)
+	UpdateRoles(c context.Context, tenantID, id uuid.UUID, req dto.UpdateTenantUserRolesRequest) (*model.TenantUser, error)
+	SetActive(c context.Context, tenantID, id uuid.UUID, active bool) (*model.TenantUser, error)
+}
+
+// tenantUserService implements TenantUserService
+type tenantUserService struct {
+	tenantUserRepo     repository.TenantUserRepository
+	tenantUserRoleRepo repository.TenantUserRoleRepository
+	roleRepo           repository.RoleRepository
+}
+
+// NewTenantUserService creates a new tenant user service
+func NewTenantUserService(
+	tenantUserRepo repository.TenantUserRepository,
+	tenantUserRoleRepo repository.TenantUserRoleRepository,
+	roleRepo repository.RoleRepository,
+) TenantUserService {
+	return &tenantUserService{
+		tenantUserRepo:     tenantUserRepo,
+		tenantUserRoleRepo: tenantUserRoleRepo,
+		roleRepo:           roleRepo,
+	}
+}
+
+func (s *tenantUserService) List(c context.Context, tenantID uuid.UUID, params dto.TenantUserQueryParams) ([]model.TenantUser, *dto.PaginationMeta, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	// Set defaults
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	tenantUsers, total, err := s.tenantUserRepo.List(c, tenantID, offset, params.Limit, repository.TenantUserFilter{
+		Search:   params.Search,
+		RoleID:   params.RoleID,
+		IsActive: params.IsActive,
+	})
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Interface("params", params).
+			Msg("Failed to list tenant users")
+		return nil, nil, err
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(params.Limit)))
+
+	meta := &dto.PaginationMeta{
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalRows:  total,
+		TotalPages: totalPages,
+	}
+
+	return tenantUsers, meta, nil
+}
+
+// roleRanks orders the roles by the access they grant. A caller manages the roles it holds and the ones ranked
+// below its highest role, so only Developers grant Developer.
+var roleRanks = map[string]int{
+	"developer": 4,
+	"admin":     3,
+	"teacher":   2,
+	"staff":     2,
+	"student":   1,
+	"parent":    1,
+}
+
+// UpdateRoles assigns and revokes roles of another membership and returns it with its resulting roles. The caller
+// can only assign and revoke the roles it holds or that rank below its own.
+func (s *tenantUserService) UpdateRoles(c context.Context, tenantID, id uuid.UUID, req dto.UpdateTenantUserRolesRequest) (*model.TenantUser, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	tenantUser, err := s.tenantUserRepo.GetByID(c, id)
+	if err != nil || tenantUser.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("tenant_user_id", id.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Tenant user not found for role update")
+		return nil, apperror.NotFound("tenant user not found")
+	}
+
+	callerID, ok := util.GetUserIDAsUUID(c)
+	if !ok {
+		return nil, apperror.Forbidden("you cannot change tenant user roles")
+	}
+	if callerID == tenantUser.UserID {
+		return nil, apperror.Forbidden("you cannot change your own roles")
+	}
+	held, callerRank, err := s.callerRoles(c, tenantID, callerID)
+	if err != nil {
+		return nil, err
+	}
+
+	revoking := make(map[uuid.UUID]bool, len(req.Revoke))
+	for _, roleID := range req.Revoke {
+		revoking[roleID] = true
+	}
+
+	for _, roleID := range req.Assign {
+		if revoking[roleID] {
+			return nil, apperror.Validation("a role cannot be assigned and revoked at the same time")
+		}
+		role, err := s.roleRepo.GetByID(c, roleID)
+		if err != nil {
+			logger.Warn().
+				Err(err).
+				Str("role_id", roleID.String()).
+				Msg("Invalid role ID provided during tenant user role update")
+			return nil, apperror.Validation("invalid role ID")
+		}
+		if !canManageRole(role.Name, held, callerRank) {
+			logger.Warn().
+				Str("tenant_user_id", id.String()).
+				Str("role", role.Name).
+				Msg("Caller tried to assign a role above its own")
+			return nil, apperror.Forbidden("you cannot assign the " + role.Name + " role")
+		}
+	}
+	for _, roleID := range req.Revoke {
+		role, err := s.roleRepo.GetByID(c, roleID)
+		if err != nil {
+			// An unknown role is not held, revoking it changes nothing
+			continue
+		}
+		if !canManageRole(role.Name, held, callerRank) {
+			logger.Warn().
+				Str("tenant_user_id", id.String()).
+				Str("role", role.Name).
+				Msg("Caller tried to revoke a role above its own")
+			return nil, apperror.Forbidden("you cannot revoke the " + role.Name + " role")
+		}
+	}
+
+	if err := s.tenantUserRoleRepo.UpdateRoles(c, tenantID, id, req.Assign, req.Revoke); err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_user_id", id.String()).
+			Msg("Failed to update tenant user roles")
+		return nil, apperror.Internal("failed to update tenant user roles")
+	}
+
+	roles, err := s.tenantUserRoleRepo.GetRolesByTenantUser(c, id)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_user_id", id.String()).
+			Msg("Failed to reload tenant user roles")
+		return nil, apperror.Internal("failed to reload tenant user roles")
+	}
+	tenantUser.TenantUserRoles = roles
+
+	return tenantUser, nil
+}
+
+// callerRoles returns the lowercased names of the roles the caller holds in the tenant and the highest rank
+// among them
+func (s *tenantUserService) callerRoles(c context.Context, tenantID, callerID uuid.UUID) (map[string]bool, int, error) {
+	logger := util.NewServiceLogger(c)
+
+	caller, err := s.tenantUserRepo.GetByTenantAndUser(c, tenantID, callerID)
+	if err != nil || !caller.IsActive {
+		return nil, 0, apperror.Forbidden("you cannot change tenant user roles")
+	}
+	tenantUserRoles, err := s.tenantUserRoleRepo.GetRolesByTenantUser(c, caller.ID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_user_id", caller.ID.String()).
+			Msg("Failed to get caller roles for tenant user role update")
+		return nil, 0, apperror.Internal("failed to update tenant user roles")
+	}
+
+	held := make(map[string]bool, len(tenantUserRoles))
+	rank := 0
+	for _, tenantUserRole := range tenantUserRoles {
+		if tenantUserRole.Role == nil {
+			continue
+		}
+		name := strings.ToLower(tenantUserRole.Role.Name)
+		held[name] = true
+		if roleRanks[name] > rank {
+			rank = roleRanks[name]
+		}
+	}
+	return held, rank, nil
+}
+
+// canManageRole reports whether a caller holding the given roles may assign or revoke the role. Roles without
+// a rank can only be managed by their holders.
+func canManageRole(name string, held map[string]bool, callerRank int) bool {
+	name = strings.ToLower(name)
+	if held[name] {
+		return true
+	}
+	rank, ok := roleRanks[name]
+	return ok && rank < callerRank
+}
+
+// SetActive activates or suspends a membership without touching the user account.
+// Admins can't deactivate their own membership to avoid locking themselves out.
+func (s *tenantUserService) SetActive(c context.Context, tenantID, id uuid.UUID, active bool) (*model.TenantUser, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	tenantUser, err := s.tenantUserRepo.GetByID(c, id)
+	if err != nil || tenantUser.TenantID != tenantID {
+		logger.Warn().
+			Err(err).
+			Str("tenant_user_id", id.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Tenant user not found for activation change")
+		return nil, apperror.NotFound("tenant user not found")
+	}
+
+	if !active {
+		if callerID, ok := util.GetUserIDAsUUID(c); ok && callerID == tenantUser.UserID {
+			return nil, apperror.Forbidden("you cannot deactivate your own membership")
+		}
+	}
+
+	if active {
+		err = s.tenantUserRepo.ActivateUser(c, tenantID, tenantUser.UserID)
+	} else {
+		err = s.tenantUserRepo.DeactivateUser(c, tenantID, tenantUser.UserID)
+	}
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_user_id", id.String()).
+			Bool("active", active).
+			Msg("Failed to change tenant user activation")
+		return nil, apperror.Internal("failed to update tenant user")
+	}
+
+	tenantUser.IsActive = active
+	return tenantUser, nil
+}
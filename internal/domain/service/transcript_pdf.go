@@ -0,0 +1,105 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/pkg/pdf"
+)
+
+// transcriptColumns are the columns of a transcript year table
+var transcriptColumns = []reportColumn{
+	{"Subject", reportMargin, 36},
+	{"Code", 250, 12},
+	{"Credit", 320, 8},
+	{"Grades", 380, 8},
+	{"Final", 450, 10},
+	{"Letter", 510, 6},
+}
+
+// transcriptSummaryColumns are the columns of the cumulative subject table
+var transcriptSummaryColumns = []reportColumn{
+	{"Subject", reportMargin, 36},
+	{"Code", 250, 12},
+	{"Credits", 320, 8},
+	{"Years", 380, 8},
+	{"Final", 450, 10},
+	{"Letter", 510, 6},
+}
+
+// renderTranscript renders a transcript as a PDF document with a table per academic year and a cumulative summary
+func renderTranscript(transcript *dto.Transcript) []byte {
+	w := &reportCardWriter{doc: pdf.New("Transcript " + transcript.StudentNumber)}
+	w.newPage()
+
+	w.text(reportMargin, pdf.HelveticaBold, 16, "ACADEMIC TRANSCRIPT")
+	w.next(reportLineHeight * 1.5)
+	w.text(reportMargin, pdf.HelveticaBold, 12, transcript.SchoolName)
+	w.next(reportLineHeight * 2)
+
+	for _, field := range [][2]string{
+		{"Name", transcript.FullName},
+		{"Student number", transcript.StudentNumber},
+		{"Admission date", transcript.AdmissionDate.Format("2 January 2006")},
+		{"Status", transcript.Status},
+	} {
+		w.text(reportMargin, pdf.HelveticaBold, reportFontSize, field[0])
+		w.text(reportMargin+100, pdf.Helvetica, reportFontSize, field[1])
+		w.next(reportLineHeight)
+	}
+	w.next(reportLineHeight)
+
+	if len(transcript.Years) == 0 {
+		w.text(reportMargin, pdf.Helvetica, reportFontSize, "No enrolled subjects")
+		w.next(reportLineHeight)
+	}
+	w.columns = transcriptColumns
+	for _, year := range transcript.Years {
+		if w.full(reportLineHeight * 5) {
+			w.newPage()
+		}
+		name := year.AcademicYear
+		if name == "" {
+			name = "Without academic year"
+		}
+		w.text(reportMargin, pdf.HelveticaBold, 12, name)
+		w.next(reportLineHeight * 1.5)
+		w.header()
+		for _, subject := range year.Subjects {
+			if w.full(reportLineHeight) {
+				w.newPage()
+				w.header()
+			}
+			w.row(pdf.Helvetica, subject.Name, subject.Code, fmt.Sprint(subject.Credit), fmt.Sprint(subject.GradeCount),
+				formatScore(subject.FinalScore), formatLetter(subject.LetterGrade))
+		}
+		w.line()
+		w.row(pdf.HelveticaBold, "Year average", "", fmt.Sprint(year.Credits), "", formatScore(year.WeightedAverage), formatLetter(year.LetterGrade))
+		w.next(reportLineHeight)
+	}
+
+	if len(transcript.Subjects) > 0 {
+		if w.full(reportLineHeight * 5) {
+			w.newPage()
+		}
+		w.text(reportMargin, pdf.HelveticaBold, 12, "Subject summary")
+		w.next(reportLineHeight * 1.5)
+		w.columns = transcriptSummaryColumns
+		w.header()
+		for _, subject := range transcript.Subjects {
+			if w.full(reportLineHeight) {
+				w.newPage()
+				w.header()
+			}
+			w.row(pdf.Helvetica, subject.Name, subject.Code, fmt.Sprint(subject.Credits), fmt.Sprint(subject.Years),
+				formatScore(subject.FinalScore), formatLetter(subject.LetterGrade))
+		}
+		w.line()
+		w.row(pdf.HelveticaBold, "Cumulative average", "", fmt.Sprint(transcript.TotalCredits), "",
+			formatScore(transcript.CumulativeAverage), formatLetter(transcript.CumulativeLetter))
+		w.next(reportLineHeight)
+	}
+
+	w.text(reportMargin, pdf.Helvetica, 8, "Generated on "+transcript.GeneratedAt.Format("2 January 2006 15:04 MST"))
+	return w.doc.Bytes()
+}
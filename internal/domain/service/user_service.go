@@ -2,13 +2,16 @@ package service
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"math"
 
 	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
 	"github.com/protocyber/kelasgo-api/internal/domain/dto"
 	"github.com/protocyber/kelasgo-api/internal/domain/model"
 	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/event"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/mail"
 	"github.com/protocyber/kelasgo-api/internal/util"
 )
 
@@ -20,6 +23,7 @@ type UserService interface {
 	Delete(c context.Context, id uuid.UUID) error
 	BulkDelete(c context.Context, tenantID uuid.UUID, ids []uuid.UUID) error
 	List(c context.Context, tenantID uuid.UUID, params dto.UserQueryParams) ([]model.User, *dto.PaginationMeta, error)
+	ResetPassword(c context.Context, tenantID, id uuid.UUID, req dto.ResetUserPasswordRequest) (*dto.ResetUserPasswordResponse, error)
 }
 
 // userService implements UserService
@@ -28,6 +32,10 @@ type userService struct {
 	roleRepo           repository.RoleRepository
 	tenantUserRepo     repository.TenantUserRepository
 	tenantUserRoleRepo repository.TenantUserRoleRepository
+	passwordHasher     *util.PasswordHasher
+	passwordPolicy     util.PasswordPolicy
+	mailer             *mail.Mailer
+	events             event.Publisher
 }
 
 // NewUserService creates a new user service
@@ -36,12 +44,20 @@ func NewUserService(
 	roleRepo repository.RoleRepository,
 	tenantUserRepo repository.TenantUserRepository,
 	tenantUserRoleRepo repository.TenantUserRoleRepository,
+	passwordHasher *util.PasswordHasher,
+	passwordPolicy util.PasswordPolicy,
+	mailer *mail.Mailer,
+	events event.Publisher,
 ) UserService {
 	return &userService{
 		userRepo:           userRepo,
 		roleRepo:           roleRepo,
 		tenantUserRepo:     tenantUserRepo,
 		tenantUserRoleRepo: tenantUserRoleRepo,
+		passwordHasher:     passwordHasher,
+		passwordPolicy:     passwordPolicy,
+		mailer:             mailer,
+		events:             events,
 	}
 }
 
@@ -56,7 +72,7 @@ func (s *userService) Create(c context.Context, tenantID uuid.UUID, req dto.Crea
 			Str("username", req.Username).
 			Str("tenant_id", tenantID.String()).
 			Msg("Username already exists within tenant")
-		return nil, errors.New("username already exists")
+		return nil, apperror.Conflict("username already exists")
 	}
 
 	// Check if email already exists within tenant (if provided)
@@ -67,7 +83,7 @@ func (s *userService) Create(c context.Context, tenantID uuid.UUID, req dto.Crea
 				Str("email", req.Email).
 				Str("tenant_id", tenantID.String()).
 				Msg("User creation attempt with existing email")
-			return nil, errors.New("email already exists")
+			return nil, apperror.Conflict("email already exists")
 		}
 	}
 
@@ -80,19 +96,19 @@ func (s *userService) Create(c context.Context, tenantID uuid.UUID, req dto.Crea
 				Str("role_id", req.RoleID.String()).
 				Str("tenant_id", tenantID.String()).
 				Msg("Invalid role ID provided during user creation")
-			return nil, errors.New("invalid role ID")
+			return nil, apperror.Validation("invalid role ID")
 		}
 	}
 
 	// Hash password
-	hashedPassword, err := util.HashPassword(req.Password)
+	hashedPassword, err := s.passwordHasher.Hash(req.Password)
 	if err != nil {
 		logger.Error().
 			Err(err).
 			Str("username", req.Username).
 			Str("tenant_id", tenantID.String()).
 			Msg("Failed to hash password during user creation")
-		return nil, errors.New("failed to hash password")
+		return nil, apperror.Internal("failed to hash password")
 	}
 
 	// Create user
@@ -105,7 +121,7 @@ func (s *userService) Create(c context.Context, tenantID uuid.UUID, req dto.Crea
 		Birthday:     req.Birthday,
 		Gender:       (*model.Gender)(req.Gender),
 		DateOfBirth:  req.DateOfBirth,
-		Phone:        req.Phone,
+		Phone:        util.NormalizeIndonesianPhonePtr(req.Phone),
 		Address:      req.Address,
 		IsActive:     true,
 	}
@@ -114,56 +130,60 @@ func (s *userService) Create(c context.Context, tenantID uuid.UUID, req dto.Crea
 		user.IsActive = *req.IsActive
 	}
 
-	err = s.userRepo.Create(c, user)
-	if err != nil {
-		logger.Error().
-			Err(err).
-			Str("username", req.Username).
-			Str("tenant_id", tenantID.String()).
-			Msg("Failed to create user in database")
-		return nil, errors.New("failed to create user")
-	}
-
-	// Create tenant-user relationship
-	tenantUser := &model.TenantUser{
-		TenantID: tenantID,
-		UserID:   user.ID,
-		IsActive: user.IsActive,
-	}
-
-	err = s.tenantUserRepo.Create(c, tenantUser)
-	if err != nil {
-		logger.Error().
-			Err(err).
-			Str("user_id", user.ID.String()).
-			Str("tenant_id", tenantID.String()).
-			Msg("Failed to create tenant-user relationship")
-		// If tenant-user creation fails, we should delete the user to maintain consistency
-		s.userRepo.Delete(c, user.ID)
-		return nil, errors.New("failed to create tenant-user relationship")
-	}
+	// Create the user, its tenant membership and role atomically
+	err = s.userRepo.WithTransaction(c, tenantID, func(txCtx context.Context) error {
+		if err := s.userRepo.Create(txCtx, user); err != nil {
+			logger.Error().
+				Err(err).
+				Str("username", req.Username).
+				Str("tenant_id", tenantID.String()).
+				Msg("Failed to create user in database")
+			return apperror.Internal("failed to create user")
+		}
 
-	// Create tenant user-role relationship if role is provided
-	if req.RoleID != nil {
-		tenantUserRole := &model.TenantUserRole{
-			TenantUserID: tenantUser.ID,
-			RoleID:       *req.RoleID,
+		// Create tenant-user relationship
+		tenantUser := &model.TenantUser{
+			TenantID: tenantID,
+			UserID:   user.ID,
+			IsActive: user.IsActive,
 		}
 
-		err = s.tenantUserRoleRepo.Create(c, tenantUserRole)
-		if err != nil {
+		if err := s.tenantUserRepo.Create(txCtx, tenantUser); err != nil {
 			logger.Error().
 				Err(err).
-				Str("tenant_user_id", tenantUser.ID.String()).
-				Str("role_id", req.RoleID.String()).
-				Msg("Failed to create tenant user-role relationship")
-			// If tenant user-role creation fails, cleanup user and tenant-user
-			s.tenantUserRepo.Delete(c, tenantUser.ID)
-			s.userRepo.Delete(c, user.ID)
-			return nil, errors.New("failed to create tenant user-role relationship")
+				Str("user_id", user.ID.String()).
+				Str("tenant_id", tenantID.String()).
+				Msg("Failed to create tenant-user relationship")
+			return apperror.Internal("failed to create tenant-user relationship")
+		}
+
+		// Create tenant user-role relationship if role is provided
+		if req.RoleID != nil {
+			tenantUserRole := &model.TenantUserRole{
+				TenantUserID: tenantUser.ID,
+				RoleID:       *req.RoleID,
+			}
+
+			if err := s.tenantUserRoleRepo.Create(txCtx, tenantUserRole); err != nil {
+				logger.Error().
+					Err(err).
+					Str("tenant_user_id", tenantUser.ID.String()).
+					Str("role_id", req.RoleID.String()).
+					Msg("Failed to create tenant user-role relationship")
+				return apperror.Internal("failed to create tenant user-role relationship")
+			}
 		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	s.events.Publish(c, event.UserCreated{
+		Metadata: event.Metadata{TenantID: tenantID, OccurredAt: util.NowFromContext(c)},
+		UserID:   user.ID,
+	})
 	return user, nil
 }
 
@@ -177,7 +197,7 @@ func (s *userService) GetByID(c context.Context, id uuid.UUID) (*model.User, err
 			Err(err).
 			Str("user_id", id.String()).
 			Msg("Failed to get user by ID")
-		return nil, errors.New("user not found")
+		return nil, apperror.NotFound("user not found")
 	}
 	return user, nil
 }
@@ -204,7 +224,7 @@ func (s *userService) Update(c context.Context, id uuid.UUID, req dto.UpdateUser
 		logger.Error().
 			Str("user_id", id.String()).
 			Msg("User is not associated with any tenant during update")
-		return nil, errors.New("user is not associated with any tenant")
+		return nil, apperror.Validation("user is not associated with any tenant")
 	}
 
 	// Check if email already exists (if changed and provided)
@@ -216,7 +236,7 @@ func (s *userService) Update(c context.Context, id uuid.UUID, req dto.UpdateUser
 				Str("user_id", id.String()).
 				Str("tenant_id", tenantID.String()).
 				Msg("User update attempt with existing email")
-			return nil, errors.New("email already exists")
+			return nil, apperror.Conflict("email already exists")
 		}
 	}
 
@@ -229,7 +249,7 @@ func (s *userService) Update(c context.Context, id uuid.UUID, req dto.UpdateUser
 				Str("role_id", req.RoleID.String()).
 				Str("user_id", id.String()).
 				Msg("Invalid role ID provided during user update")
-			return nil, errors.New("invalid role ID")
+			return nil, apperror.Validation("invalid role ID")
 		}
 
 		// Get tenant user
@@ -240,7 +260,7 @@ func (s *userService) Update(c context.Context, id uuid.UUID, req dto.UpdateUser
 				Str("user_id", id.String()).
 				Str("tenant_id", tenantID.String()).
 				Msg("Tenant user not found during role update")
-			return nil, errors.New("tenant user not found")
+			return nil, apperror.NotFound("tenant user not found")
 		}
 
 		// Delete existing tenant user roles and create new one
@@ -250,7 +270,7 @@ func (s *userService) Update(c context.Context, id uuid.UUID, req dto.UpdateUser
 				Err(err).
 				Str("tenant_user_id", tenantUser.ID.String()).
 				Msg("Failed to delete existing tenant user roles during update")
-			return nil, errors.New("failed to update tenant user role")
+			return nil, apperror.Internal("failed to update tenant user role")
 		}
 
 		tenantUserRole := &model.TenantUserRole{
@@ -265,7 +285,7 @@ func (s *userService) Update(c context.Context, id uuid.UUID, req dto.UpdateUser
 				Str("tenant_user_id", tenantUser.ID.String()).
 				Str("role_id", req.RoleID.String()).
 				Msg("Failed to create new tenant user role during update")
-			return nil, errors.New("failed to create new tenant user role")
+			return nil, apperror.Internal("failed to create new tenant user role")
 		}
 	}
 
@@ -289,7 +309,7 @@ func (s *userService) Update(c context.Context, id uuid.UUID, req dto.UpdateUser
 		user.DateOfBirth = req.DateOfBirth
 	}
 	if req.Phone != nil {
-		user.Phone = req.Phone
+		user.Phone = util.NormalizeIndonesianPhonePtr(req.Phone)
 	}
 	if req.Address != nil {
 		user.Address = req.Address
@@ -308,7 +328,7 @@ func (s *userService) Update(c context.Context, id uuid.UUID, req dto.UpdateUser
 					Str("user_id", id.String()).
 					Str("tenant_id", tenantID.String()).
 					Msg("Failed to update tenant-user status")
-				return nil, errors.New("failed to update tenant-user status")
+				return nil, apperror.Internal("failed to update tenant-user status")
 			}
 		}
 	}
@@ -319,7 +339,7 @@ func (s *userService) Update(c context.Context, id uuid.UUID, req dto.UpdateUser
 			Err(err).
 			Str("user_id", id.String()).
 			Msg("Failed to update user in database")
-		return nil, errors.New("failed to update user")
+		return nil, apperror.Internal("failed to update user")
 	}
 
 	return user, nil
@@ -348,6 +368,11 @@ func (s *userService) Delete(c context.Context, id uuid.UUID) error {
 		return err
 	}
 
+	// The deletion isn't scoped to a tenant
+	s.events.Publish(c, event.UserDeleted{
+		Metadata: event.Metadata{OccurredAt: util.NowFromContext(c)},
+		UserID:   id,
+	})
 	return nil
 }
 
@@ -356,18 +381,27 @@ func (s *userService) BulkDelete(c context.Context, tenantID uuid.UUID, ids []uu
 	logger := util.NewServiceLogger(c)
 
 	if len(ids) == 0 {
-		return errors.New("no user IDs provided for bulk delete")
+		return apperror.Validation("no user IDs provided for bulk delete")
+	}
+
+	uniqueIDs := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			uniqueIDs = append(uniqueIDs, id)
+		}
 	}
 
-	// Get users that belong to the tenant to validate they exist and log properly
-	users, _, err := s.userRepo.GetUsersByTenant(c, tenantID, 0, len(ids)*2, "")
+	// Get the requested users that belong to the tenant to validate they exist and log properly
+	users, err := s.userRepo.GetByIDs(c, tenantID, uniqueIDs)
 	if err != nil {
 		logger.Error().
 			Err(err).
 			Str("tenant_id", tenantID.String()).
 			Interface("user_ids", ids).
 			Msg("Failed to validate users for bulk delete")
-		return errors.New("failed to validate users for bulk delete")
+		return apperror.Internal("failed to validate users for bulk delete")
 	}
 
 	// Create a set of valid user IDs that belong to the tenant
@@ -379,7 +413,7 @@ func (s *userService) BulkDelete(c context.Context, tenantID uuid.UUID, ids []uu
 	// Filter IDs to only include users that belong to the tenant
 	var validIDs []uuid.UUID
 	var invalidIDs []uuid.UUID
-	for _, id := range ids {
+	for _, id := range uniqueIDs {
 		if validUserMap[id] {
 			validIDs = append(validIDs, id)
 		} else {
@@ -395,7 +429,7 @@ func (s *userService) BulkDelete(c context.Context, tenantID uuid.UUID, ids []uu
 	}
 
 	if len(validIDs) == 0 {
-		return errors.New("no valid user IDs found for bulk delete in this tenant")
+		return apperror.Validation("no valid user IDs found for bulk delete in this tenant")
 	}
 
 	// Perform bulk delete
@@ -406,9 +440,16 @@ func (s *userService) BulkDelete(c context.Context, tenantID uuid.UUID, ids []uu
 			Str("tenant_id", tenantID.String()).
 			Interface("user_ids", validIDs).
 			Msg("Failed to bulk delete users from database")
-		return errors.New("failed to bulk delete users")
+		return apperror.Internal("failed to bulk delete users")
 	}
 
+	now := util.NowFromContext(c)
+	for _, id := range validIDs {
+		s.events.Publish(c, event.UserDeleted{
+			Metadata: event.Metadata{TenantID: tenantID, OccurredAt: now},
+			UserID:   id,
+		})
+	}
 	return nil
 }
 
@@ -426,32 +467,23 @@ func (s *userService) List(c context.Context, tenantID uuid.UUID, params dto.Use
 
 	offset := (params.Page - 1) * params.Limit
 
-	var users []model.User
-	var total int64
-	var err error
-
-	if params.RoleID != nil {
-		users, total, err = s.userRepo.GetByRole(c, tenantID, *params.RoleID, offset, params.Limit)
-		if err != nil {
-			logger.Error().
-				Err(err).
-				Str("tenant_id", tenantID.String()).
-				Str("role_id", params.RoleID.String()).
-				Interface("params", params).
-				Msg("Failed to get users by role")
-		}
-	} else {
-		users, total, err = s.userRepo.GetUsersByTenant(c, tenantID, offset, params.Limit, params.Search)
-		if err != nil {
-			logger.Error().
-				Err(err).
-				Str("tenant_id", tenantID.String()).
-				Interface("params", params).
-				Msg("Failed to get users by tenant")
-		}
+	filter := repository.UserFilter{
+		Search:       params.Search,
+		RoleID:       params.RoleID,
+		IsActive:     params.IsActive,
+		CreatedAfter: params.CreatedAfter,
+		UpdatedAfter: params.UpdatedAfter,
+		SortBy:       params.SortBy,
+		SortDesc:     params.SortDir == "desc",
 	}
 
+	users, total, err := s.userRepo.GetUsersByTenant(c, tenantID, offset, params.Limit, filter)
 	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenantID.String()).
+			Interface("params", params).
+			Msg("Failed to get users by tenant")
 		return nil, nil, err
 	}
 
@@ -466,3 +498,85 @@ func (s *userService) List(c context.Context, tenantID uuid.UUID, params dto.Use
 
 	return users, meta, nil
 }
+
+// ResetPassword sets a temporary password for a member of the tenant, generating one when none is given, and
+// requires the user to change it on their next login. The password is emailed to the user when asked; a
+// generated one is returned to the admin unless it was emailed.
+func (s *userService) ResetPassword(c context.Context, tenantID, id uuid.UUID, req dto.ResetUserPasswordRequest) (*dto.ResetUserPasswordResponse, error) {
+	// Create context logger for service
+	logger := util.NewServiceLogger(c)
+
+	// Only members of the admin's tenant can be reset
+	if _, err := s.tenantUserRepo.GetByTenantAndUser(c, tenantID, id); err != nil {
+		logger.Warn().
+			Err(err).
+			Str("user_id", id.String()).
+			Str("tenant_id", tenantID.String()).
+			Msg("Password reset attempt for user outside the tenant")
+		return nil, apperror.NotFound("user not found")
+	}
+	user, err := s.userRepo.GetByID(c, id)
+	if err != nil || user.DeletedAt != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", id.String()).
+			Msg("User not found during password reset")
+		return nil, apperror.NotFound("user not found")
+	}
+	if req.SendEmail && user.Email == "" {
+		return nil, apperror.Validation("user has no email address")
+	}
+
+	password := req.Password
+	generated := password == ""
+	if generated {
+		password, err = util.GenerateTemporaryPassword(s.passwordPolicy)
+		if err != nil {
+			logger.Error().
+				Err(err).
+				Str("user_id", id.String()).
+				Msg("Failed to generate temporary password")
+			return nil, apperror.Internal("failed to generate password")
+		}
+	}
+
+	hashedPassword, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", id.String()).
+			Msg("Failed to hash password during password reset")
+		return nil, apperror.Internal("failed to hash password")
+	}
+	if err := s.userRepo.ResetPassword(c, id, hashedPassword); err != nil {
+		return nil, apperror.Internal("failed to reset password")
+	}
+
+	logger.Info().
+		Str("user_id", id.String()).
+		Str("tenant_id", tenantID.String()).
+		Msg("User password reset by admin")
+
+	response := &dto.ResetUserPasswordResponse{}
+	if req.SendEmail {
+		err := s.mailer.Enqueue(mail.Message{
+			To:      user.Email,
+			Subject: "Your password was reset",
+			Body: fmt.Sprintf("Hi %s,\n\nAn administrator reset your password. Log in with the temporary password below, "+
+				"you will be asked to choose a new one:\n\n%s\n", user.FullName, password),
+		})
+		if err != nil {
+			// The password is already reset, so the admin gets it to pass on instead
+			logger.Error().
+				Err(err).
+				Str("user_id", id.String()).
+				Msg("Failed to send temporary password email")
+		} else {
+			response.EmailSent = true
+		}
+	}
+	if generated && !response.EmailSent {
+		response.TemporaryPassword = password
+	}
+	return response, nil
+}
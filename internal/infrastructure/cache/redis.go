@@ -1 +1,286 @@
 package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/protocyber/kelasgo-api/internal/config"
+)
+
+const (
+	// maxIdleConns bounds how many connections are kept open between commands
+	maxIdleConns = 10
+	// dialTimeout bounds connecting to Redis and authenticating
+	dialTimeout = 5 * time.Second
+	// commandTimeout bounds a single command when the context has no deadline
+	commandTimeout = 3 * time.Second
+)
+
+// incrScript increments a counter and sets its expiry when it is new, in one step so a counter can never
+// be left without an expiry
+const incrScript = `local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count`
+
+// Redis is a minimal Redis client speaking RESP over a small pool of connections
+type Redis struct {
+	addr     string
+	password string
+	db       int
+	idle     chan *redisConn
+}
+
+// redisConn is a single connection to Redis
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedis creates a client for the primary Redis. Connections are opened lazily.
+func NewRedis(cfg *config.Config) *Redis {
+	primary := cfg.Cache.Redis.Primary
+	return &Redis{
+		addr:     fmt.Sprintf("%s:%d", primary.Host, primary.Port),
+		password: primary.Password,
+		db:       primary.DB,
+		idle:     make(chan *redisConn, maxIdleConns),
+	}
+}
+
+// Ping checks that Redis is reachable
+func (r *Redis) Ping(ctx context.Context) error {
+	_, err := r.do(ctx, "PING")
+	return err
+}
+
+// Get returns the value stored at key, reporting false when the key does not exist
+func (r *Redis) Get(ctx context.Context, key string) (string, bool, error) {
+	reply, err := r.do(ctx, "GET", key)
+	if err != nil {
+		return "", false, err
+	}
+	if reply == nil {
+		return "", false, nil
+	}
+	return reply.(string), true, nil
+}
+
+// Set stores value at key with the given time to live
+func (r *Redis) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	_, err := r.do(ctx, "SET", key, value, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+// SetNX stores value at key only when the key does not exist yet, reporting whether it was stored
+func (r *Redis) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	reply, err := r.do(ctx, "SET", key, value, "PX", strconv.FormatInt(ttl.Milliseconds(), 10), "NX")
+	if err != nil {
+		return false, err
+	}
+	return reply != nil, nil
+}
+
+// Incr increments the counter at key and returns its new value. A new counter expires after ttl, so the
+// count covers a fixed window starting at the first increment.
+func (r *Redis) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	reply, err := r.do(ctx, "EVAL", incrScript, "1", key, strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return 0, err
+	}
+	count, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("redis: unexpected INCR reply %v", reply)
+	}
+	return count, nil
+}
+
+// LPush prepends value to the list at key
+func (r *Redis) LPush(ctx context.Context, key, value string) error {
+	_, err := r.do(ctx, "LPUSH", key, value)
+	return err
+}
+
+// BRPop removes and returns the last value of the list at key, waiting up to timeout for one to arrive and
+// reporting false when none did
+func (r *Redis) BRPop(ctx context.Context, key string, timeout time.Duration) (string, bool, error) {
+	// The connection must outlive the server side wait
+	ctx, cancel := context.WithTimeout(ctx, timeout+commandTimeout)
+	defer cancel()
+
+	seconds := strconv.FormatFloat(timeout.Seconds(), 'f', 3, 64)
+	reply, err := r.do(ctx, "BRPOP", key, seconds)
+	if err != nil || reply == nil {
+		return "", false, err
+	}
+	// The reply is the list name and the value
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return "", false, fmt.Errorf("redis: unexpected BRPOP reply %v", reply)
+	}
+	value, _ := values[1].(string)
+	return value, true, nil
+}
+
+// Del removes the given keys
+func (r *Redis) Del(ctx context.Context, keys ...string) error {
+	args := append([]string{"DEL"}, keys...)
+	_, err := r.do(ctx, args...)
+	return err
+}
+
+// Close closes every idle connection
+func (r *Redis) Close() error {
+	for {
+		select {
+		case rc := <-r.idle:
+			rc.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do runs a single command and returns its reply: a string, an int64, nil, or a slice of replies
+func (r *Redis) do(ctx context.Context, args ...string) (interface{}, error) {
+	rc, err := r.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(commandTimeout)
+	}
+	rc.conn.SetDeadline(deadline)
+
+	reply, err := rc.command(args...)
+	var redisErr redisError
+	if err != nil && !errors.As(err, &redisErr) {
+		// The connection state is unknown after an I/O error
+		rc.conn.Close()
+		return nil, err
+	}
+
+	r.put(rc)
+	return reply, err
+}
+
+// get takes an idle connection or dials a new one
+func (r *Redis) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case rc := <-r.idle:
+		return rc, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", r.addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	rc := &redisConn{conn: conn, reader: bufio.NewReader(conn)}
+	conn.SetDeadline(time.Now().Add(dialTimeout))
+	if r.password != "" {
+		if _, err := rc.command("AUTH", r.password); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to authenticate to redis: %w", err)
+		}
+	}
+	if r.db != 0 {
+		if _, err := rc.command("SELECT", strconv.Itoa(r.db)); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to select redis database: %w", err)
+		}
+	}
+	return rc, nil
+}
+
+// put returns a connection to the idle pool, closing it when the pool is full
+func (r *Redis) put(rc *redisConn) {
+	select {
+	case r.idle <- rc:
+	default:
+		rc.conn.Close()
+	}
+}
+
+// redisError is an error reply sent by Redis, which leaves the connection usable
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// command writes args as a RESP array and reads the reply
+func (rc *redisConn) command(args ...string) (interface{}, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(rc.conn, b.String()); err != nil {
+		return nil, err
+	}
+	return rc.readReply()
+}
+
+// readReply reads a single RESP reply
+func (rc *redisConn) readReply() (interface{}, error) {
+	line, err := rc.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(rc.reader, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		replies := make([]interface{}, count)
+		for i := range replies {
+			if replies[i], err = rc.readReply(); err != nil {
+				return nil, err
+			}
+		}
+		return replies, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
@@ -0,0 +1,232 @@
+package database
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// EncryptedSerializerName is the serializer tag of columns encrypted at rest, e.g. `gorm:"serializer:encrypted"`
+const EncryptedSerializerName = "encrypted"
+
+// encryptedPrefix marks a column value written by the field cipher, so rows written before encryption
+// was enabled can still be read until they are backfilled
+const encryptedPrefix = "enc:v1:"
+
+// encryptedDateLayout is how date fields are written before encryption, matching the text of a DATE column
+const encryptedDateLayout = "2006-01-02"
+
+// blindIndexTag names the encrypted field a column holds the blind index of, e.g. `gorm:"blindindex:Phone"`
+const blindIndexTag = "BLINDINDEX"
+
+// FieldCipher encrypts single column values with AES-256-GCM
+type FieldCipher struct {
+	aead     cipher.AEAD
+	indexKey []byte
+}
+
+// NewFieldCipher creates a field cipher whose AES key is the SHA-256 of the configured key. Blind indexes use
+// a separate key derived from it, so an index never reveals anything about the encryption key.
+func NewFieldCipher(key string) (*FieldCipher, error) {
+	if key == "" {
+		return nil, errors.New("encryption key is empty")
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	indexKey := sha256.Sum256([]byte("blind-index:" + key))
+	return &FieldCipher{aead: aead, indexKey: indexKey[:]}, nil
+}
+
+// BlindIndex returns the keyed hash of a plaintext value. Equal values have equal indexes, so an encrypted
+// column can be matched exactly through its index column without decrypting it.
+func (fc *FieldCipher) BlindIndex(plaintext string) string {
+	mac := hmac.New(sha256.New, fc.indexKey)
+	mac.Write([]byte(plaintext))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted reports whether the column value was written by a field cipher
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// Encrypt returns the prefixed, base64 encoded nonce and ciphertext of plaintext
+func (fc *FieldCipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, fc.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := fc.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the plaintext of an encrypted column value. Values without the prefix are returned as they are.
+func (fc *FieldCipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("decode encrypted value: %w", err)
+	}
+	nonceSize := fc.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", errors.New("encrypted value is too short")
+	}
+	plaintext, err := fc.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// EncryptedSerializer encrypts string and date fields on write and decrypts them on read.
+// Nil pointers are stored as NULL so IS NULL checks keep working.
+type EncryptedSerializer struct {
+	cipher *FieldCipher
+}
+
+// RegisterEncryptedSerializer makes the encrypted serializer available to model tags. It must run before
+// a model using it is first parsed, so it is called when the connections are created.
+func RegisterEncryptedSerializer(fieldCipher *FieldCipher) {
+	schema.RegisterSerializer(EncryptedSerializerName, EncryptedSerializer{cipher: fieldCipher})
+}
+
+// RegisterBlindIndexes fills the columns tagged with blindindex from their encrypted field before rows are
+// created or updated from a model
+func RegisterBlindIndexes(db *gorm.DB, fieldCipher *FieldCipher) error {
+	fill := func(tx *gorm.DB) {
+		if tx.Error != nil || tx.Statement.Schema == nil || !tx.Statement.ReflectValue.IsValid() {
+			return
+		}
+		for _, indexField := range tx.Statement.Schema.Fields {
+			sourceName, ok := indexField.TagSettings[blindIndexTag]
+			if !ok {
+				continue
+			}
+			sourceField := tx.Statement.Schema.LookUpField(sourceName)
+			if sourceField == nil {
+				tx.AddError(fmt.Errorf("blind index %s: unknown field %s", indexField.Name, sourceName))
+				return
+			}
+
+			setIndex := func(row reflect.Value) {
+				// The plain field value, since ValueOf of a serializer field wraps it for encryption
+				var index *string
+				if plaintext, ok := sourceField.ReflectValueOf(tx.Statement.Context, row).Interface().(*string); ok && plaintext != nil {
+					hash := fieldCipher.BlindIndex(*plaintext)
+					index = &hash
+				}
+				indexField.ReflectValueOf(tx.Statement.Context, row).Set(reflect.ValueOf(index))
+			}
+
+			rows := tx.Statement.ReflectValue
+			switch rows.Kind() {
+			case reflect.Slice, reflect.Array:
+				for i := 0; i < rows.Len(); i++ {
+					setIndex(reflect.Indirect(rows.Index(i)))
+				}
+			case reflect.Struct:
+				setIndex(rows)
+			}
+		}
+	}
+
+	if err := db.Callback().Create().Before("gorm:create").Register("kelasgo:blind_index_create", fill); err != nil {
+		return err
+	}
+	return db.Callback().Update().Before("gorm:update").Register("kelasgo:blind_index_update", fill)
+}
+
+// Scan implements schema.SerializerInterface
+func (s EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType).Elem()
+
+	if dbValue != nil {
+		var value string
+		switch v := dbValue.(type) {
+		case string:
+			value = v
+		case []byte:
+			value = string(v)
+		case time.Time:
+			// A DATE column not yet converted to text
+			value = v.Format(encryptedDateLayout)
+		default:
+			return fmt.Errorf("unsupported value %T for encrypted field %s", dbValue, field.Name)
+		}
+
+		plaintext, err := s.cipher.Decrypt(value)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		target := fieldValue
+		if target.Kind() == reflect.Ptr {
+			target.Set(reflect.New(target.Type().Elem()))
+			target = target.Elem()
+		}
+		switch target.Interface().(type) {
+		case string:
+			target.SetString(plaintext)
+		case time.Time:
+			date, err := time.Parse(encryptedDateLayout, plaintext)
+			if err != nil {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			target.Set(reflect.ValueOf(date))
+		default:
+			return fmt.Errorf("unsupported type %s for encrypted field %s", field.FieldType, field.Name)
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue)
+	return nil
+}
+
+// Value implements schema.SerializerValuerInterface
+func (s EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	value := reflect.ValueOf(fieldValue)
+	if !value.IsValid() {
+		return nil, nil
+	}
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, nil
+		}
+		value = value.Elem()
+	}
+
+	var plaintext string
+	switch v := value.Interface().(type) {
+	case string:
+		plaintext = v
+	case time.Time:
+		plaintext = v.Format(encryptedDateLayout)
+	default:
+		return nil, fmt.Errorf("unsupported type %s for encrypted field %s", field.FieldType, field.Name)
+	}
+	return s.cipher.Encrypt(plaintext)
+}
@@ -0,0 +1,305 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// migrationLockID is the advisory lock held while migrating, so concurrent runs wait for each other
+const migrationLockID = 7263941058
+
+// ErrDirtyMigration is returned when golang-migrate left a failed migration behind, the schema must be
+// repaired by hand and the version forced before migrating again
+var ErrDirtyMigration = errors.New("database is dirty, fix the failed migration and force the version")
+
+// migrationFile matches {version}_{name}.up.sql and {version}_{name}.down.sql
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration is a versioned schema change with the SQL that applies and reverts it
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus is a migration and whether it is applied
+type MigrationStatus struct {
+	Migration
+	Applied bool
+}
+
+// Migrator applies the versioned SQL migrations. The current version is kept in schema_migrations
+// the way golang-migrate keeps it, so databases migrated with its CLI can switch to this runner.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	// OnApply is called after each migration is applied or reverted
+	OnApply func(migration Migration, up bool)
+}
+
+// LoadMigrations reads the migrations in dir of fsys ordered by version
+func LoadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// NewMigrator creates a migrator for the migrations, which must be ordered by version
+func NewMigrator(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Version returns the current version, 0 when no migration is applied, and whether it is dirty
+func (m *Migrator) Version(c context.Context) (uint64, bool, error) {
+	conn, err := m.db.Conn(c)
+	if err != nil {
+		return 0, false, err
+	}
+	defer conn.Close()
+
+	if err := ensureMigrationsTable(c, conn); err != nil {
+		return 0, false, err
+	}
+	return currentVersion(c, conn)
+}
+
+// Status lists every migration and whether it is applied
+func (m *Migrator) Status(c context.Context) ([]MigrationStatus, uint64, bool, error) {
+	version, dirty, err := m.Version(c)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = MigrationStatus{Migration: migration, Applied: migration.Version <= version}
+	}
+	return statuses, version, dirty, nil
+}
+
+// Up applies up to steps pending migrations, all of them when steps is 0, and returns how many were applied
+func (m *Migrator) Up(c context.Context, steps int) (int, error) {
+	return m.migrate(c, func(version uint64) ([]Migration, error) {
+		var pending []Migration
+		for _, migration := range m.migrations {
+			if migration.Version > version && (steps <= 0 || len(pending) < steps) {
+				pending = append(pending, migration)
+			}
+		}
+		return pending, nil
+	}, true)
+}
+
+// Down reverts up to steps applied migrations, newest first, and returns how many were reverted
+func (m *Migrator) Down(c context.Context, steps int) (int, error) {
+	return m.migrate(c, func(version uint64) ([]Migration, error) {
+		var applied []Migration
+		for i := len(m.migrations) - 1; i >= 0 && len(applied) < steps; i-- {
+			if m.migrations[i].Version > version {
+				continue
+			}
+			if m.migrations[i].Down == "" {
+				return nil, fmt.Errorf("migration %d_%s has no down file", m.migrations[i].Version, m.migrations[i].Name)
+			}
+			applied = append(applied, m.migrations[i])
+		}
+		return applied, nil
+	}, false)
+}
+
+// Force sets the version without running any migration and clears the dirty flag, 0 meaning none applied
+func (m *Migrator) Force(c context.Context, version uint64) error {
+	if version != 0 && m.index(version) < 0 {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	conn, err := m.db.Conn(c)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return withMigrationLock(c, conn, func() error {
+		tx, err := conn.BeginTx(c, nil)
+		if err != nil {
+			return err
+		}
+		if err := setVersion(c, tx, version); err != nil {
+			tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// migrate runs the migrations selected for the current version in order, each in its own transaction
+// together with the version update, so a failed migration leaves the schema and version unchanged
+func (m *Migrator) migrate(c context.Context, selectMigrations func(version uint64) ([]Migration, error), up bool) (int, error) {
+	conn, err := m.db.Conn(c)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	applied := 0
+	err = withMigrationLock(c, conn, func() error {
+		version, dirty, err := currentVersion(c, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return ErrDirtyMigration
+		}
+		if version != 0 && m.index(version) < 0 {
+			return fmt.Errorf("database is at version %d which has no migration file", version)
+		}
+
+		migrations, err := selectMigrations(version)
+		if err != nil {
+			return err
+		}
+		for _, migration := range migrations {
+			script, next := migration.Up, migration.Version
+			if !up {
+				script, next = migration.Down, m.previous(migration.Version)
+			}
+			if err := m.apply(c, conn, script, next); err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			applied++
+			if m.OnApply != nil {
+				m.OnApply(migration, up)
+			}
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// apply runs a migration script and records the resulting version in one transaction
+func (m *Migrator) apply(c context.Context, conn *sql.Conn, script string, version uint64) error {
+	tx, err := conn.BeginTx(c, nil)
+	if err != nil {
+		return err
+	}
+	// Without arguments the script runs over the simple query protocol, which accepts several statements
+	if _, err := tx.ExecContext(c, script); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := setVersion(c, tx, version); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// index returns the position of the migration with the version, or -1
+func (m *Migrator) index(version uint64) int {
+	for i, migration := range m.migrations {
+		if migration.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// previous returns the version before the given one, 0 when it is the first
+func (m *Migrator) previous(version uint64) uint64 {
+	if i := m.index(version); i > 0 {
+		return m.migrations[i-1].Version
+	}
+	return 0
+}
+
+// withMigrationLock runs fn holding the migration advisory lock on the connection
+func withMigrationLock(c context.Context, conn *sql.Conn, fn func() error) error {
+	if _, err := conn.ExecContext(c, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	if err := ensureMigrationsTable(c, conn); err != nil {
+		return err
+	}
+	return fn()
+}
+
+// ensureMigrationsTable creates schema_migrations with the layout golang-migrate uses
+func ensureMigrationsTable(c context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(c, "CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)")
+	return err
+}
+
+// currentVersion reads the single row of schema_migrations, 0 when it is empty
+func currentVersion(c context.Context, conn *sql.Conn) (uint64, bool, error) {
+	var version int64
+	var dirty bool
+	err := conn.QueryRowContext(c, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return uint64(version), dirty, nil
+}
+
+// setVersion replaces the recorded version, leaving the table empty for version 0
+func setVersion(c context.Context, tx *sql.Tx, version uint64) error {
+	if _, err := tx.ExecContext(c, "DELETE FROM schema_migrations"); err != nil {
+		return err
+	}
+	if version == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(c, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, FALSE)", int64(version))
+	return err
+}
@@ -1,85 +1,164 @@
 package database
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
 	"github.com/protocyber/kelasgo-api/internal/config"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 // DatabaseConnections holds both read and write database connections
 type DatabaseConnections struct {
 	Write *gorm.DB
 	Read  *gorm.DB
+
+	// SearchSimilarityThreshold is the minimum trigram similarity used by fuzzy searches
+	SearchSimilarityThreshold float64
+
+	// MaxPageSize is the most rows a paginated list query returns
+	MaxPageSize int
+
+	// UserFieldCipher encrypts the sensitive user columns tagged with the encrypted serializer
+	UserFieldCipher *FieldCipher
 }
 
 // NewConnections creates both read and write database connections
 func NewConnections(cfg *config.Config) (*DatabaseConnections, error) {
+	queryTimeout := time.Duration(cfg.Database.QueryTimeoutMS) * time.Millisecond
+
+	// Models are parsed on first use, so the serializer has to exist before any query runs
+	userFieldCipher, err := NewFieldCipher(cfg.Encryption.Key.Users)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption.key.users: %w", err)
+	}
+	RegisterEncryptedSerializer(userFieldCipher)
+
+	slowQueryLevel, err := zerolog.ParseLevel(cfg.Database.SlowQueryLogLevel)
+	if err != nil || slowQueryLevel == zerolog.NoLevel {
+		return nil, fmt.Errorf("invalid db.slow_query_log_level %q", cfg.Database.SlowQueryLogLevel)
+	}
+	queryLogger := NewQueryLogger(QueryLoggerConfig{
+		SlowThreshold: time.Duration(cfg.Database.SlowQueryMS) * time.Millisecond,
+		SlowLevel:     slowQueryLevel,
+		RedactParams:  cfg.Database.RedactQueryParams,
+	})
+
 	// Create write connection
-	writeDB, err := createConnection(cfg.GetWriteDSN(), cfg.Database.PG.Write, "write")
+	writeDB, err := createConnection(cfg.GetWriteDSN(), cfg.Database.PG.Write, "write", queryTimeout, queryLogger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create write connection: %w", err)
 	}
 
+	// Keep the blind indexes of encrypted columns in step with their values
+	if err := RegisterBlindIndexes(writeDB, userFieldCipher); err != nil {
+		return nil, fmt.Errorf("failed to register blind indexes: %w", err)
+	}
+
 	// Create read connection
-	readDB, err := createConnection(cfg.GetReadDSN(), cfg.Database.PG.Read, "read")
+	readDB, err := createConnection(cfg.GetReadDSN(), cfg.Database.PG.Read, "read", queryTimeout, queryLogger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create read connection: %w", err)
 	}
 
 	return &DatabaseConnections{
-		Write: writeDB,
-		Read:  readDB,
+		Write:                     writeDB,
+		Read:                      readDB,
+		SearchSimilarityThreshold: cfg.Database.Search.SimilarityThreshold,
+		MaxPageSize:               cfg.App.Pagination.MaxLimit,
+		UserFieldCipher:           userFieldCipher,
 	}, nil
 }
 
 // createConnection creates a database connection with the given configuration
-func createConnection(dsn string, connCfg config.PGConnectionConfig, connectionType string) (*gorm.DB, error) {
-	// Configure GORM logger
-	gormLogger := logger.Default.LogMode(logger.Info)
-
+func createConnection(dsn string, connCfg config.PGConnectionConfig, connectionType string, queryTimeout time.Duration, queryLogger *QueryLogger) (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: gormLogger,
+		Logger: queryLogger,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Bound every statement so a runaway query can't hang a request
+	if err := registerQueryTimeout(db, queryTimeout); err != nil {
+		return nil, fmt.Errorf("failed to register query timeout: %w", err)
+	}
+
 	// Get underlying sql.DB to configure connection pool
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database instance: %w", err)
 	}
 
-	// Parse connection lifetime
-	maxLifetime, err := time.ParseDuration(connCfg.MaxConnectionLifetime)
-	if err != nil {
-		log.Warn().Err(err).Msg("Failed to parse max connection lifetime, using default 5m")
-		maxLifetime = 5 * time.Minute
-	}
-
 	// Configure connection pool based on config
-	sqlDB.SetMaxIdleConns(connCfg.MaxIdleConnection)
-	sqlDB.SetMaxOpenConns(connCfg.MaxOpenConnection)
-	sqlDB.SetConnMaxLifetime(maxLifetime)
+	pool := configurePool(sqlDB, connCfg, connectionType)
 
 	log.Info().
 		Str("type", connectionType).
 		Str("host", connCfg.Host).
 		Str("port", connCfg.Port).
 		Str("database", connCfg.Name).
-		Int("max_idle", connCfg.MaxIdleConnection).
-		Int("max_open", connCfg.MaxOpenConnection).
-		Dur("max_lifetime", maxLifetime).
+		Int("max_idle", pool.maxIdle).
+		Int("max_open", pool.maxOpen).
+		Dur("max_lifetime", pool.maxLifetime).
+		Dur("query_timeout", queryTimeout).
 		Msg("Database connection established")
 
 	return db, nil
 }
 
+// defaultMaxConnectionLifetime is used when max_connection_lifetime is empty or invalid
+const defaultMaxConnectionLifetime = 5 * time.Minute
+
+// poolSettings are the connection pool limits in effect
+type poolSettings struct {
+	maxIdle     int
+	maxOpen     int
+	maxLifetime time.Duration
+}
+
+// configurePool applies the pool limits of the connection config and returns the ones in effect. A max open
+// of 0 leaves open connections unlimited, and idle connections never exceed it.
+func configurePool(sqlDB *sql.DB, connCfg config.PGConnectionConfig, connectionType string) poolSettings {
+	maxLifetime := defaultMaxConnectionLifetime
+	if connCfg.MaxConnectionLifetime != "" {
+		parsed, err := time.ParseDuration(connCfg.MaxConnectionLifetime)
+		if err != nil || parsed < 0 {
+			log.Warn().
+				Err(err).
+				Str("type", connectionType).
+				Str("max_connection_lifetime", connCfg.MaxConnectionLifetime).
+				Msg("Invalid max connection lifetime, using default 5m")
+		} else {
+			maxLifetime = parsed
+		}
+	}
+
+	maxIdle := connCfg.MaxIdleConnection
+	if connCfg.MaxOpenConnection > 0 && maxIdle > connCfg.MaxOpenConnection {
+		log.Warn().
+			Str("type", connectionType).
+			Int("max_idle", maxIdle).
+			Int("max_open", connCfg.MaxOpenConnection).
+			Msg("Max idle connections exceed max open connections, limiting them to max open")
+		maxIdle = connCfg.MaxOpenConnection
+	}
+
+	sqlDB.SetMaxOpenConns(connCfg.MaxOpenConnection)
+	sqlDB.SetMaxIdleConns(maxIdle)
+	sqlDB.SetConnMaxLifetime(maxLifetime)
+
+	return poolSettings{
+		maxIdle:     maxIdle,
+		maxOpen:     sqlDB.Stats().MaxOpenConnections,
+		maxLifetime: maxLifetime,
+	}
+}
+
 // Close closes both database connections
 func (dc *DatabaseConnections) Close() error {
 	var errors []error
@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	request_id "github.com/protocyber/kelasgo-api/pkg/gin-request-id"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// QueryLoggerConfig controls which statements are logged and how
+type QueryLoggerConfig struct {
+	// SlowThreshold is the duration from which a statement is logged as slow, 0 disables slow query logging
+	SlowThreshold time.Duration
+	// SlowLevel is the level slow statements are logged at
+	SlowLevel zerolog.Level
+	// RedactParams logs statements with their placeholders instead of the bound values
+	RedactParams bool
+}
+
+// QueryLogger writes GORM logs to zerolog. Every statement is logged at debug level, failed ones at error
+// level and the ones taking at least the slow threshold at the configured level.
+type QueryLogger struct {
+	config   QueryLoggerConfig
+	logLevel logger.LogLevel
+}
+
+// NewQueryLogger creates a GORM logger bridged to zerolog
+func NewQueryLogger(config QueryLoggerConfig) *QueryLogger {
+	return &QueryLogger{config: config, logLevel: logger.Info}
+}
+
+// LogMode implements logger.Interface
+func (l *QueryLogger) LogMode(level logger.LogLevel) logger.Interface {
+	copied := *l
+	copied.logLevel = level
+	return &copied
+}
+
+// Info implements logger.Interface
+func (l *QueryLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.logLevel >= logger.Info {
+		l.event(ctx, zerolog.InfoLevel).Msgf(msg, args...)
+	}
+}
+
+// Warn implements logger.Interface
+func (l *QueryLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.logLevel >= logger.Warn {
+		l.event(ctx, zerolog.WarnLevel).Msgf(msg, args...)
+	}
+}
+
+// Error implements logger.Interface
+func (l *QueryLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.logLevel >= logger.Error {
+		l.event(ctx, zerolog.ErrorLevel).Msgf(msg, args...)
+	}
+}
+
+// Trace implements logger.Interface, logging the statement once it has run
+func (l *QueryLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.logLevel <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	slow := l.config.SlowThreshold > 0 && elapsed >= l.config.SlowThreshold
+	var level zerolog.Level
+	var msg string
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.logLevel >= logger.Error:
+		level, msg = zerolog.ErrorLevel, "Database query failed"
+	case slow && l.logLevel >= logger.Warn:
+		level, msg = l.config.SlowLevel, "Slow database query"
+	case l.logLevel >= logger.Info:
+		level, msg = zerolog.DebugLevel, "Database query"
+	default:
+		return
+	}
+
+	// Skip building the SQL when the level is filtered out
+	event := l.event(ctx, level)
+	if event == nil {
+		return
+	}
+	sql, rows := fc()
+	if err != nil {
+		event = event.Err(err)
+	}
+	event.
+		Str("sql", sql).
+		Float64("duration_ms", float64(elapsed.Microseconds())/1000).
+		Int64("rows", rows).
+		Bool("slow", slow).
+		Msg(msg)
+}
+
+// ParamsFilter implements gorm.ParamsFilter, dropping the bound values from logged statements when redaction
+// is enabled so personal data and secrets don't end up in the logs
+func (l *QueryLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.config.RedactParams {
+		return sql, nil
+	}
+	return sql, params
+}
+
+// event starts a log event at the level, with the request ID of the context when it has one
+func (l *QueryLogger) event(ctx context.Context, level zerolog.Level) *zerolog.Event {
+	event := log.WithLevel(level)
+	if event != nil && ctx != nil {
+		if requestID, ok := ctx.Value(request_id.XRequestIDKey).(string); ok {
+			event = event.Str("request_id", requestID)
+		}
+	}
+	return event
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+// RLSTableStatus is the row level security state of a table with a tenant_id column
+type RLSTableStatus struct {
+	Table     string `gorm:"column:table_name"`
+	Enabled   bool   `gorm:"column:enabled"`
+	Forced    bool   `gorm:"column:forced"`
+	Owned     bool   `gorm:"column:owned"` // Owned by the connecting role, which RLS skips unless forced
+	HasPolicy bool   `gorm:"column:has_policy"`
+}
+
+// RLSReport describes how far row level security isolates tenants for the connecting role
+type RLSReport struct {
+	Tables []RLSTableStatus
+	// BypassRole is set when the role is a superuser or has BYPASSRLS, so no policy applies to it
+	BypassRole bool
+}
+
+// Unprotected returns the tenant tables without RLS enabled or without a policy
+func (r *RLSReport) Unprotected() []string {
+	var tables []string
+	for _, table := range r.Tables {
+		if !table.Enabled || !table.HasPolicy {
+			tables = append(tables, table.Table)
+		}
+	}
+	return tables
+}
+
+// OwnedUnforced returns the protected tenant tables whose policies skip the connecting role because it owns them
+func (r *RLSReport) OwnedUnforced() []string {
+	var tables []string
+	for _, table := range r.Tables {
+		if table.Enabled && table.HasPolicy && table.Owned && !table.Forced {
+			tables = append(tables, table.Table)
+		}
+	}
+	return tables
+}
+
+// CheckRowLevelSecurity reports the RLS state of every table with a tenant_id column on the write connection
+func (dc *DatabaseConnections) CheckRowLevelSecurity(c context.Context) (*RLSReport, error) {
+	db := dc.Write.WithContext(c)
+	report := &RLSReport{}
+
+	err := db.Raw(`
+		SELECT c.relname AS table_name,
+			c.relrowsecurity AS enabled,
+			c.relforcerowsecurity AS forced,
+			pg_has_role(current_user, c.relowner, 'USAGE') AS owned,
+			EXISTS (SELECT 1 FROM pg_policies p WHERE p.schemaname = n.nspname AND p.tablename = c.relname) AS has_policy
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public'
+		AND c.relkind IN ('r', 'p')
+		AND EXISTS (
+			SELECT 1 FROM pg_attribute a
+			WHERE a.attrelid = c.oid AND a.attname = 'tenant_id' AND NOT a.attisdropped
+		)
+		ORDER BY c.relname`).Scan(&report.Tables).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read row level security state: %w", err)
+	}
+
+	err = db.Raw("SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user").Scan(&report.BypassRole).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read database role: %w", err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// ErrQueryTimeout is returned when a statement runs longer than the configured query timeout
+var ErrQueryTimeout = errors.New("database query timed out")
+
+// queryTimeoutKey stores the deadline added to a statement so it can be released afterwards
+const queryTimeoutKey = "kelasgo:query_timeout"
+
+// queryDeadline remembers the statement's own context so chained calls such as
+// Count followed by Find don't inherit an already cancelled deadline
+type queryDeadline struct {
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+// registerQueryTimeout bounds every statement without a sooner deadline of its own by timeout.
+// Row and Rows are left alone because their result is read after the callbacks have finished.
+func registerQueryTimeout(db *gorm.DB, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+
+	start := func(tx *gorm.DB) {
+		ctx := tx.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		// Nested statements such as preloads share the deadline of their parent, and a request deadline
+		// sooner than the timeout already bounds the statement
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= timeout {
+			return
+		}
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+		tx.InstanceSet(queryTimeoutKey, queryDeadline{parent: tx.Statement.Context, cancel: cancel})
+		tx.Statement.Context = timeoutCtx
+	}
+
+	end := func(tx *gorm.DB) {
+		value, ok := tx.InstanceGet(queryTimeoutKey)
+		if !ok {
+			return
+		}
+		deadline, ok := value.(queryDeadline)
+		if !ok {
+			return
+		}
+		tx.InstanceSet(queryTimeoutKey, nil)
+		timedOut := errors.Is(tx.Statement.Context.Err(), context.DeadlineExceeded)
+		deadline.cancel()
+		tx.Statement.Context = deadline.parent
+
+		if tx.Error != nil && timedOut {
+			log.Error().
+				Err(tx.Error).
+				Str("table", tx.Statement.Table).
+				Dur("timeout", timeout).
+				Msg("Database query timed out")
+			tx.Error = fmt.Errorf("%w after %s: %v", ErrQueryTimeout, timeout, tx.Error)
+		}
+	}
+
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("*").Register("kelasgo:create_timeout_start", start),
+		callbacks.Create().After("*").Register("kelasgo:create_timeout_end", end),
+		callbacks.Query().Before("*").Register("kelasgo:query_timeout_start", start),
+		callbacks.Query().After("*").Register("kelasgo:query_timeout_end", end),
+		callbacks.Update().Before("*").Register("kelasgo:update_timeout_start", start),
+		callbacks.Update().After("*").Register("kelasgo:update_timeout_end", end),
+		callbacks.Delete().Before("*").Register("kelasgo:delete_timeout_start", start),
+		callbacks.Delete().After("*").Register("kelasgo:delete_timeout_end", end),
+		callbacks.Raw().Before("*").Register("kelasgo:raw_timeout_start", start),
+		callbacks.Raw().After("*").Register("kelasgo:raw_timeout_end", end),
+	)
+}
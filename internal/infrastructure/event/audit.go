@@ -0,0 +1,29 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// SubscribeAuditLog logs every domain event with its payload and request ID, as an audit trail of changes
+// made through the services next to the row level audit_logs
+func SubscribeAuditLog(b *Bus) {
+	for _, name := range Names() {
+		b.Subscribe(name, auditLog)
+	}
+}
+
+// auditLog writes one event to the log
+func auditLog(ctx context.Context, e Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	util.NewServiceLogger(ctx).Info().
+		Str("event", e.EventName()).
+		RawJSON("payload", payload).
+		Msg("Domain event")
+	return nil
+}
@@ -0,0 +1,306 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/protocyber/kelasgo-api/internal/config"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/cache"
+	request_id "github.com/protocyber/kelasgo-api/pkg/gin-request-id"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// queueSize bounds how many events may wait for a worker with the memory backend
+	queueSize = 100
+	// redisQueueKey is the Redis list events wait in with the redis backend
+	redisQueueKey = "kelasgo:events"
+	// redisPollTimeout bounds how long a worker waits for an event before checking for shutdown
+	redisPollTimeout = 5 * time.Second
+	// redisRetryDelay is how long a worker waits after failing to read from Redis
+	redisRetryDelay = time.Second
+)
+
+var (
+	// ErrQueueFull is returned when an event cannot be queued without blocking
+	ErrQueueFull = errors.New("event queue is full")
+	// ErrBusClosed is returned when an event is published after shutdown started
+	ErrBusClosed = errors.New("event bus is shut down")
+)
+
+// Event is a domain event published by a service once its change is saved
+type Event interface {
+	// EventName identifies the kind of event, e.g. "student.created"
+	EventName() string
+}
+
+// Handler consumes an event. Errors are logged; the event is not retried.
+type Handler func(ctx context.Context, e Event) error
+
+// Publisher is what services publish domain events to
+type Publisher interface {
+	Publish(ctx context.Context, e Event)
+}
+
+// envelope is a queued event with the request it was published in
+type envelope struct {
+	Name      string          `json:"name"`
+	RequestID string          `json:"request_id,omitempty"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// Bus delivers published events to the handlers subscribed to their name from a pool of background workers.
+// With the memory backend events wait in process. With the redis backend they wait in a Redis list shared by
+// every instance, so each event is handled once, by whichever instance takes it, and survives a restart.
+type Bus struct {
+	redis *cache.Redis
+	queue chan envelope
+	stop  chan struct{}
+	wg    sync.WaitGroup
+
+	mu       sync.RWMutex
+	closed   bool
+	handlers map[string][]Handler
+}
+
+// NewBus creates an event bus with the configured backend and starts its workers
+func NewBus(cfg *config.Config, redis *cache.Redis) (*Bus, error) {
+	b := &Bus{
+		queue:    make(chan envelope, queueSize),
+		stop:     make(chan struct{}),
+		handlers: make(map[string][]Handler),
+	}
+
+	backend := cfg.Events.Backend
+	switch backend {
+	case "", "memory":
+		backend = "memory"
+	case "redis":
+		b.redis = redis
+	default:
+		return nil, fmt.Errorf("unknown events backend %q, expected memory or redis", backend)
+	}
+
+	workers := cfg.Events.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		b.wg.Add(1)
+		if b.redis != nil {
+			go b.workRedis()
+		} else {
+			go b.work()
+		}
+	}
+
+	log.Info().
+		Str("backend", backend).
+		Int("workers", workers).
+		Msg("Event bus started")
+
+	return b, nil
+}
+
+// Subscribe registers a handler for events with the name. Handlers are registered at startup, and each one
+// receives a pointer to the event struct.
+func (b *Bus) Subscribe(name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[name] = append(b.handlers[name], handler)
+}
+
+// Publish queues the event for its subscribers without waiting for them. Failing to queue it is logged and
+// never fails the change the event describes.
+func (b *Bus) Publish(ctx context.Context, e Event) {
+	if err := b.enqueue(ctx, e); err != nil {
+		event := log.Error().
+			Err(err).
+			Str("event", e.EventName())
+		if requestID, ok := ctx.Value(request_id.XRequestIDKey).(string); ok {
+			event = event.Str("request_id", requestID)
+		}
+		event.Msg("Failed to publish event")
+	}
+}
+
+// enqueue wraps the event in an envelope and queues it on the backend
+func (b *Bus) enqueue(ctx context.Context, e Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	env := envelope{Name: e.EventName(), Payload: payload}
+	if requestID, ok := ctx.Value(request_id.XRequestIDKey).(string); ok {
+		env.RequestID = requestID
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return ErrBusClosed
+	}
+
+	if b.redis != nil {
+		data, err := json.Marshal(env)
+		if err != nil {
+			return err
+		}
+		// The change is already saved, so the event is queued even when the request was cancelled meanwhile
+		return b.redis.LPush(context.WithoutCancel(ctx), redisQueueKey, string(data))
+	}
+
+	select {
+	case b.queue <- env:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Shutdown stops accepting events and waits for the workers. With the memory backend the queue is flushed
+// first, and events still queued when ctx expires are logged as dropped. With the redis backend queued events
+// stay in Redis for the other or next instance.
+func (b *Bus) Shutdown(ctx context.Context) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	if b.redis != nil {
+		close(b.stop)
+	} else {
+		close(b.queue)
+	}
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Info().Msg("Event bus stopped")
+		return nil
+	case <-ctx.Done():
+	}
+
+	if b.redis != nil {
+		log.Warn().Msg("Event bus stopped before its workers finished")
+		return ctx.Err()
+	}
+
+	// Out of time: let workers finish the event they are handling and drop the rest
+	close(b.stop)
+	dropped := 0
+	for env := range b.queue {
+		dropped++
+		log.Warn().
+			Str("event", env.Name).
+			Str("request_id", env.RequestID).
+			Msg("Dropped queued event during shutdown")
+	}
+
+	log.Warn().
+		Int("dropped", dropped).
+		Msg("Event bus stopped before the queue was flushed")
+	return ctx.Err()
+}
+
+// work handles queued events until the queue is closed or the bus is stopped
+func (b *Bus) work() {
+	defer b.wg.Done()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case env, ok := <-b.queue:
+			if !ok {
+				return
+			}
+			b.dispatch(env)
+		}
+	}
+}
+
+// workRedis takes events from the Redis list until the bus is stopped
+func (b *Bus) workRedis() {
+	defer b.wg.Done()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		default:
+		}
+
+		data, ok, err := b.redis.BRPop(context.Background(), redisQueueKey, redisPollTimeout)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("Failed to take event from Redis")
+			select {
+			case <-b.stop:
+				return
+			case <-time.After(redisRetryDelay):
+			}
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		var env envelope
+		if err := json.Unmarshal([]byte(data), &env); err != nil {
+			log.Error().
+				Err(err).
+				Msg("Failed to decode queued event")
+			continue
+		}
+		b.dispatch(env)
+	}
+}
+
+// dispatch decodes the event and runs its handlers in subscription order
+func (b *Bus) dispatch(env envelope) {
+	b.mu.RLock()
+	handlers := b.handlers[env.Name]
+	b.mu.RUnlock()
+	if len(handlers) == 0 {
+		return
+	}
+
+	e, err := decode(env.Name, env.Payload)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("event", env.Name).
+			Str("request_id", env.RequestID).
+			Msg("Failed to decode event")
+		return
+	}
+
+	ctx := context.Background()
+	if env.RequestID != "" {
+		ctx = context.WithValue(ctx, request_id.XRequestIDKey, env.RequestID)
+	}
+	for _, handler := range handlers {
+		if err := handler(ctx, e); err != nil {
+			log.Error().
+				Err(err).
+				Str("event", env.Name).
+				Str("request_id", env.RequestID).
+				Msg("Event handler failed")
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Names of the domain events
+const (
+	StudentCreatedName       = "student.created"
+	StudentUpdatedName       = "student.updated"
+	StudentDeletedName       = "student.deleted"
+	StudentStatusChangedName = "student.status_changed"
+	UserCreatedName          = "user.created"
+	UserDeletedName          = "user.deleted"
+)
+
+// eventTypes creates an empty event of every name, for decoding queued events
+var eventTypes = map[string]func() Event{
+	StudentCreatedName:       func() Event { return &StudentCreated{} },
+	StudentUpdatedName:       func() Event { return &StudentUpdated{} },
+	StudentDeletedName:       func() Event { return &StudentDeleted{} },
+	StudentStatusChangedName: func() Event { return &StudentStatusChanged{} },
+	UserCreatedName:          func() Event { return &UserCreated{} },
+	UserDeletedName:          func() Event { return &UserDeleted{} },
+}
+
+// Names returns the name of every domain event
+func Names() []string {
+	names := make([]string, 0, len(eventTypes))
+	for name := range eventTypes {
+		names = append(names, name)
+	}
+	return names
+}
+
+// decode returns a pointer to the event of the name with the JSON payload
+func decode(name string, payload []byte) (Event, error) {
+	newEvent, ok := eventTypes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %q", name)
+	}
+	e := newEvent()
+	if err := json.Unmarshal(payload, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Metadata is common to every domain event
+type Metadata struct {
+	// TenantID is the tenant of the change, uuid.Nil when it wasn't made within one
+	TenantID   uuid.UUID `json:"tenant_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// StudentCreated is published when a student is created
+type StudentCreated struct {
+	Metadata
+	StudentID     uuid.UUID  `json:"student_id"`
+	StudentNumber string     `json:"student_number"`
+	ClassID       *uuid.UUID `json:"class_id,omitempty"`
+}
+
+// EventName implements Event
+func (StudentCreated) EventName() string { return StudentCreatedName }
+
+// StudentUpdated is published when a student's details change
+type StudentUpdated struct {
+	Metadata
+	StudentID uuid.UUID `json:"student_id"`
+}
+
+// EventName implements Event
+func (StudentUpdated) EventName() string { return StudentUpdatedName }
+
+// StudentDeleted is published for every deleted student
+type StudentDeleted struct {
+	Metadata
+	StudentID uuid.UUID `json:"student_id"`
+}
+
+// EventName implements Event
+func (StudentDeleted) EventName() string { return StudentDeletedName }
+
+// StudentStatusChanged is published when a student's lifecycle status changes
+type StudentStatusChanged struct {
+	Metadata
+	StudentID  uuid.UUID `json:"student_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+}
+
+// EventName implements Event
+func (StudentStatusChanged) EventName() string { return StudentStatusChangedName }
+
+// UserCreated is published when a user is created in a tenant
+type UserCreated struct {
+	Metadata
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// EventName implements Event
+func (UserCreated) EventName() string { return UserCreatedName }
+
+// UserDeleted is published for every deleted user
+type UserDeleted struct {
+	Metadata
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// EventName implements Event
+func (UserDeleted) EventName() string { return UserDeletedName }
@@ -0,0 +1,178 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/protocyber/kelasgo-api/internal/config"
+	"github.com/rs/zerolog/log"
+)
+
+// queueSize bounds how many messages may wait for a worker
+const queueSize = 100
+
+var (
+	// ErrQueueFull is returned when a message cannot be queued without blocking
+	ErrQueueFull = errors.New("mail queue is full")
+	// ErrMailerClosed is returned when a message is queued after shutdown started
+	ErrMailerClosed = errors.New("mailer is shut down")
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails over SMTP from a pool of background workers
+type Mailer struct {
+	addr  string
+	auth  smtp.Auth
+	from  string
+	queue chan Message
+	stop  chan struct{}
+	wg    sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewMailer creates a mailer and starts the configured number of workers
+func NewMailer(cfg *config.Config) *Mailer {
+	from := cfg.Mail.From
+	if from == "" {
+		from = cfg.Mail.Username
+	}
+
+	var auth smtp.Auth
+	if cfg.Mail.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Mail.Username, cfg.Mail.Password, cfg.Mail.Host)
+	}
+
+	m := &Mailer{
+		addr:  fmt.Sprintf("%s:%d", cfg.Mail.Host, cfg.Mail.Port),
+		auth:  auth,
+		from:  from,
+		queue: make(chan Message, queueSize),
+		stop:  make(chan struct{}),
+	}
+
+	workers := cfg.Mail.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		m.wg.Add(1)
+		go m.work()
+	}
+
+	log.Info().
+		Str("addr", m.addr).
+		Int("workers", workers).
+		Msg("Mail workers started")
+
+	return m
+}
+
+// Enqueue queues a message for delivery without waiting for it to be sent
+func (m *Mailer) Enqueue(msg Message) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.closed {
+		return ErrMailerClosed
+	}
+
+	select {
+	case m.queue <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Shutdown stops accepting messages and waits for the queue to be flushed.
+// When ctx expires first the workers are stopped and every message still queued is logged as dropped.
+func (m *Mailer) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	close(m.queue)
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Info().Msg("Mail workers stopped")
+		return nil
+	case <-ctx.Done():
+	}
+
+	// Out of time: let workers finish the message they are sending and drop the rest
+	close(m.stop)
+	dropped := 0
+	for msg := range m.queue {
+		dropped++
+		log.Warn().
+			Str("to", msg.To).
+			Str("subject", msg.Subject).
+			Msg("Dropped queued email during shutdown")
+	}
+
+	log.Warn().
+		Int("dropped", dropped).
+		Msg("Mail workers stopped before the queue was flushed")
+	return ctx.Err()
+}
+
+// work sends queued messages until the queue is closed or the mailer is stopped
+func (m *Mailer) work() {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case msg, ok := <-m.queue:
+			if !ok {
+				return
+			}
+			if err := m.send(msg); err != nil {
+				log.Error().
+					Err(err).
+					Str("to", msg.To).
+					Str("subject", msg.Subject).
+					Msg("Failed to send email")
+			}
+		}
+	}
+}
+
+// send delivers a single message, upgrading to TLS when the server supports it
+func (m *Mailer) send(msg Message) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, []byte(b.String()))
+}
@@ -0,0 +1,210 @@
+// Package scheduler runs registered jobs at fixed intervals in the API process. With several instances a Redis
+// lock elects the instance of each run, so a job runs once per interval across the deployment. Without Redis
+// every instance runs the jobs itself.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/cache"
+	request_id "github.com/protocyber/kelasgo-api/pkg/gin-request-id"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// lockKeyPrefix prefixes the Redis key electing the instance that runs a job
+	lockKeyPrefix = "scheduler:lock:"
+	// pingTimeout bounds checking that Redis is reachable at start
+	pingTimeout = 5 * time.Second
+)
+
+// Func is the work of a job. Its context is cancelled at shutdown and once the run takes longer than its
+// interval, and carries a request ID of its own.
+type Func func(ctx context.Context) error
+
+// job is a registered job
+type job struct {
+	name     string
+	interval time.Duration
+	run      Func
+}
+
+// Scheduler runs registered jobs at their intervals
+type Scheduler struct {
+	redis      *cache.Redis
+	instanceID string
+	jobs       []job
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// New creates a scheduler electing the instance of each run with Redis locks. A nil redis runs every job on
+// this instance, for deployments without Redis.
+func New(redis *cache.Redis) *Scheduler {
+	return &Scheduler{
+		redis:      redis,
+		instanceID: uuid.NewString(),
+	}
+}
+
+// Register adds a job to run every interval once the scheduler is started. A job with an interval of 0 or
+// less is not scheduled but can still be run with Run. Jobs are registered before Start.
+func (s *Scheduler) Register(name string, interval time.Duration, run Func) {
+	s.jobs = append(s.jobs, job{name: name, interval: interval, run: run})
+}
+
+// Names returns the names of the registered jobs in registration order
+func (s *Scheduler) Names() []string {
+	names := make([]string, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		names = append(names, j.name)
+	}
+	return names
+}
+
+// Start schedules every job with an interval until Shutdown. Each job first runs one interval after starting.
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	if s.redis == nil {
+		log.Warn().Msg("Scheduler has no Redis, every instance runs the jobs")
+	} else {
+		pingCtx, cancelPing := context.WithTimeout(ctx, pingTimeout)
+		if err := s.redis.Ping(pingCtx); err != nil {
+			log.Error().
+				Err(err).
+				Msg("Scheduler cannot reach Redis, jobs are skipped until it is reachable")
+		}
+		cancelPing()
+	}
+
+	for _, j := range s.jobs {
+		if j.interval <= 0 {
+			log.Info().Str("job", j.name).Msg("Job not scheduled, its interval is 0")
+			continue
+		}
+		s.wg.Add(1)
+		go s.schedule(ctx, j)
+		log.Info().Str("job", j.name).Dur("interval", j.interval).Msg("Job scheduled")
+	}
+}
+
+// Run runs a registered job once right away, without its lock
+func (s *Scheduler) Run(ctx context.Context, name string) error {
+	for _, j := range s.jobs {
+		if j.name == name {
+			return s.execute(ctx, j)
+		}
+	}
+	return fmt.Errorf("unknown job %q", name)
+}
+
+// Shutdown stops scheduling runs and waits for the running ones to return, which are cancelled, until ctx is done
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Info().Msg("Scheduler stopped")
+		return nil
+	case <-ctx.Done():
+		log.Warn().Msg("Scheduler stopped before its jobs returned")
+		return ctx.Err()
+	}
+}
+
+// schedule runs the job at every tick of its interval until ctx is cancelled
+func (s *Scheduler) schedule(ctx context.Context, j job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runElected(ctx, j)
+		}
+	}
+}
+
+// runElected runs the job when this instance takes its lock. The lock is not released after the run but
+// expires shortly before the next tick, so instances ticking later in the same interval skip the run too.
+// Without Redis there is nothing to elect and the job always runs.
+func (s *Scheduler) runElected(ctx context.Context, j job) {
+	lockTTL := j.interval - j.interval/10
+	if s.redis == nil {
+		runCtx, cancel := context.WithTimeout(ctx, lockTTL)
+		defer cancel()
+		s.execute(runCtx, j)
+		return
+	}
+
+	elected, err := s.redis.SetNX(ctx, lockKeyPrefix+j.name, s.instanceID, lockTTL)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("job", j.name).
+			Msg("Failed to take job lock, skipping the run")
+		return
+	}
+	if !elected {
+		log.Debug().
+			Str("job", j.name).
+			Msg("Job run by another instance")
+		return
+	}
+
+	// Runs never overlap, a run still going when the lock expires is cancelled
+	runCtx, cancel := context.WithTimeout(ctx, lockTTL)
+	defer cancel()
+	s.execute(runCtx, j)
+}
+
+// execute runs the job with a generated request ID, logging its outcome. A panicking job is logged as failed.
+func (s *Scheduler) execute(ctx context.Context, j job) (err error) {
+	requestID := uuid.NewString()
+	ctx = context.WithValue(ctx, request_id.XRequestIDKey, requestID)
+
+	started := time.Now()
+	log.Info().
+		Str("job", j.name).
+		Str("request_id", requestID).
+		Msg("Job started")
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job panicked: %v", recovered)
+		}
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("job", j.name).
+				Str("request_id", requestID).
+				Dur("elapsed", time.Since(started)).
+				Msg("Job failed")
+			return
+		}
+		log.Info().
+			Str("job", j.name).
+			Str("request_id", requestID).
+			Dur("elapsed", time.Since(started)).
+			Msg("Job completed")
+	}()
+
+	return j.run(ctx)
+}
@@ -0,0 +1,192 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/protocyber/kelasgo-api/internal/config"
+	"github.com/rs/zerolog/log"
+)
+
+// queueSize bounds how many messages may wait for a worker
+const queueSize = 100
+
+var (
+	// ErrQueueFull is returned when a message cannot be queued without blocking
+	ErrQueueFull = errors.New("sms queue is full")
+	// ErrDispatcherClosed is returned when a message is queued after shutdown started
+	ErrDispatcherClosed = errors.New("sms dispatcher is shut down")
+)
+
+// Message is a text message to a phone number in E.164 format
+type Message struct {
+	To   string
+	Body string
+}
+
+// SMSSender delivers a single text message through a provider
+type SMSSender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewSender creates the sender of the configured provider
+func NewSender(cfg *config.Config) (SMSSender, error) {
+	switch cfg.SMS.Provider {
+	case "", "log":
+		return logSender{}, nil
+	case "twilio":
+		return newTwilioSender(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown sms provider %q", cfg.SMS.Provider)
+	}
+}
+
+// logSender logs messages instead of sending them, for development. Bodies carry codes and personal data, so
+// only their length is logged.
+type logSender struct{}
+
+func (logSender) Send(_ context.Context, msg Message) error {
+	log.Info().
+		Str("to", maskPhone(msg.To)).
+		Int("body_length", len(msg.Body)).
+		Msg("SMS not sent, no provider configured")
+	return nil
+}
+
+// maskPhone hides all but the last 3 characters of a phone number for logging
+func maskPhone(phone string) string {
+	if len(phone) <= 3 {
+		return strings.Repeat("*", len(phone))
+	}
+	return strings.Repeat("*", len(phone)-3) + phone[len(phone)-3:]
+}
+
+// Dispatcher sends text messages from a pool of background workers
+type Dispatcher struct {
+	sender  SMSSender
+	timeout time.Duration
+	queue   chan Message
+	stop    chan struct{}
+	wg      sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewDispatcher creates a dispatcher and starts the configured number of workers
+func NewDispatcher(cfg *config.Config, sender SMSSender) *Dispatcher {
+	timeout := time.Duration(cfg.SMS.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	d := &Dispatcher{
+		sender:  sender,
+		timeout: timeout,
+		queue:   make(chan Message, queueSize),
+		stop:    make(chan struct{}),
+	}
+
+	workers := cfg.SMS.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+
+	log.Info().
+		Str("provider", cfg.SMS.Provider).
+		Int("workers", workers).
+		Msg("SMS workers started")
+
+	return d
+}
+
+// Enqueue queues a message for delivery without waiting for it to be sent
+func (d *Dispatcher) Enqueue(msg Message) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return ErrDispatcherClosed
+	}
+
+	select {
+	case d.queue <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Shutdown stops accepting messages and waits for the queue to be flushed.
+// When ctx expires first the workers are stopped and every message still queued is logged as dropped.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
+	}
+	d.closed = true
+	close(d.queue)
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Info().Msg("SMS workers stopped")
+		return nil
+	case <-ctx.Done():
+	}
+
+	// Out of time: let workers finish the message they are sending and drop the rest
+	close(d.stop)
+	dropped := 0
+	for msg := range d.queue {
+		dropped++
+		log.Warn().
+			Str("to", maskPhone(msg.To)).
+			Msg("Dropped queued SMS during shutdown")
+	}
+
+	log.Warn().
+		Int("dropped", dropped).
+		Msg("SMS workers stopped before the queue was flushed")
+	return ctx.Err()
+}
+
+// work sends queued messages until the queue is closed or the dispatcher is stopped
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case msg, ok := <-d.queue:
+			if !ok {
+				return
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+			err := d.sender.Send(ctx, msg)
+			cancel()
+			if err != nil {
+				log.Error().
+					Err(err).
+					Str("to", maskPhone(msg.To)).
+					Msg("Failed to send SMS")
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/protocyber/kelasgo-api/internal/config"
+)
+
+// defaultTwilioBaseURL is the Twilio API; compatible providers are used by configuring their base URL
+const defaultTwilioBaseURL = "https://api.twilio.com"
+
+// twilioSender sends messages through the Twilio Messages API or a provider with the same API
+type twilioSender struct {
+	endpoint   string
+	accountSID string
+	authToken  string
+	from       string
+	client     *http.Client
+}
+
+// newTwilioSender creates a sender for the configured account
+func newTwilioSender(cfg *config.Config) *twilioSender {
+	baseURL := strings.TrimRight(cfg.SMS.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultTwilioBaseURL
+	}
+	return &twilioSender{
+		endpoint:   fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", baseURL, url.PathEscape(cfg.SMS.AccountSID)),
+		accountSID: cfg.SMS.AccountSID,
+		authToken:  cfg.SMS.AuthToken,
+		from:       cfg.SMS.From,
+		client:     &http.Client{},
+	}
+}
+
+func (s *twilioSender) Send(ctx context.Context, msg Message) error {
+	form := url.Values{
+		"To":   {msg.To},
+		"From": {s.from},
+		"Body": {msg.Body},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.accountSID, s.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("sms provider returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
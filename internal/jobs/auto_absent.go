@@ -0,0 +1,103 @@
+// Package jobs holds the recurring background jobs, run once per invocation of cmd/jobs
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/domain/repository"
+	"github.com/protocyber/kelasgo-api/internal/domain/service"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// AutoAbsentName is the name the auto absent job is run by
+const AutoAbsentName = "auto-absent"
+
+// AutoAbsent marks students absent from the sessions nobody recorded attendance for, in the tenants that
+// enabled the attendance_auto_absent setting
+type AutoAbsent struct {
+	tenantRepo        repository.TenantRepository
+	settingService    service.TenantSettingService
+	attendanceService service.AttendanceService
+}
+
+// NewAutoAbsent creates the auto absent job
+func NewAutoAbsent(tenantRepo repository.TenantRepository, settingService service.TenantSettingService, attendanceService service.AttendanceService) *AutoAbsent {
+	return &AutoAbsent{
+		tenantRepo:        tenantRepo,
+		settingService:    settingService,
+		attendanceService: attendanceService,
+	}
+}
+
+// Run marks today's sessions of every tenant whose day is over at now, that is past its
+// attendance_auto_absent_after time in its time zone. Tenants whose day is still going are skipped, so the
+// job can run as often as wanted.
+func (j *AutoAbsent) Run(c context.Context, now time.Time) error {
+	return j.run(c, func(tenantID uuid.UUID) (time.Time, bool) {
+		local := now.In(j.settingService.Location(c, tenantID))
+		midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+		if local.Sub(midnight) < j.settingService.AutoAbsentAfter(c, tenantID) {
+			return time.Time{}, false
+		}
+		return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC), true
+	})
+}
+
+// RunDate marks the sessions on the date in every tenant, e.g. to catch up on a day the job did not run
+func (j *AutoAbsent) RunDate(c context.Context, date time.Time) error {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return j.run(c, func(uuid.UUID) (time.Time, bool) {
+		return day, true
+	})
+}
+
+// run marks the date returned for each active tenant with the setting enabled, skipping tenants without one.
+// A failing tenant does not stop the others.
+func (j *AutoAbsent) run(c context.Context, dateFor func(tenantID uuid.UUID) (time.Time, bool)) error {
+	logger := util.NewServiceLogger(c)
+
+	tenants, err := j.tenantRepo.ListActive(c)
+	if err != nil {
+		return fmt.Errorf("list active tenants: %w", err)
+	}
+
+	var marked int64
+	var failed int
+	for _, tenant := range tenants {
+		if err := c.Err(); err != nil {
+			return err
+		}
+		if !j.settingService.AutoAbsent(c, tenant.ID) {
+			continue
+		}
+		date, ok := dateFor(tenant.ID)
+		if !ok {
+			continue
+		}
+
+		count, err := j.attendanceService.MarkAbsent(c, tenant.ID, date)
+		if err != nil {
+			failed++
+			continue
+		}
+		marked += count
+		logger.Info().
+			Str("tenant_id", tenant.ID.String()).
+			Str("date", date.Format("2006-01-02")).
+			Int64("marked", count).
+			Msg("Students without attendance marked absent")
+	}
+
+	logger.Info().
+		Int("tenants", len(tenants)).
+		Int("failed", failed).
+		Int64("marked", marked).
+		Msg("Auto absent job finished")
+	if failed > 0 {
+		return fmt.Errorf("marking absent students failed in %d tenants", failed)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+// Package seed bootstraps a fresh database with the default roles, a first tenant and a Developer user,
+// so a new deployment can log in without manual SQL. Running it again leaves existing rows untouched.
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/config"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/util"
+	"gorm.io/gorm"
+)
+
+// DeveloperRole is the role given to the bootstrap user
+const DeveloperRole = "Developer"
+
+// DefaultRoles are the roles the routes and services check by name
+var DefaultRoles = []struct {
+	Name        string
+	Description string
+}{
+	{DeveloperRole, "Platform developer"},
+	{"Admin", "System Administrator"},
+	{"Teacher", "Teaching staff"},
+	{"Staff", "School staff"},
+	{"Student", "Enrolled student"},
+	{"Parent", "Parent or guardian"},
+}
+
+// Result reports what a seed run created
+type Result struct {
+	RolesCreated  int
+	TenantID      uuid.UUID
+	TenantCreated bool
+	UserID        uuid.UUID
+	UserCreated   bool
+}
+
+// Run creates whatever is missing of the default roles, the configured tenant and its Developer user
+// in a single transaction. An existing user keeps its password.
+func Run(c context.Context, db *gorm.DB, cfg *config.Config) (*Result, error) {
+	seedCfg := cfg.Seed
+	if err := validate(cfg); err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	err := db.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		for _, role := range DefaultRoles {
+			created := tx.Exec("INSERT INTO roles (name, description) VALUES (?, ?) ON CONFLICT (name) DO NOTHING",
+				role.Name, role.Description)
+			if created.Error != nil {
+				return fmt.Errorf("create role %s: %w", role.Name, created.Error)
+			}
+			result.RolesCreated += int(created.RowsAffected)
+		}
+
+		tenant, created, err := ensureTenant(tx, seedCfg.TenantName, seedCfg.TenantDomain)
+		if err != nil {
+			return err
+		}
+		result.TenantID, result.TenantCreated = tenant.ID, created
+
+		// Tenant users are behind row level security
+		if err := tx.Exec("SELECT set_config('app.current_tenant', ?, true)", tenant.ID.String()).Error; err != nil {
+			return err
+		}
+
+		user, created, err := ensureUser(tx, cfg)
+		if err != nil {
+			return err
+		}
+		result.UserID, result.UserCreated = user.ID, created
+
+		var tenantUser model.TenantUser
+		err = tx.Where("tenant_id = ? AND user_id = ?", tenant.ID, user.ID).First(&tenantUser).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			tenantUser = model.TenantUser{TenantID: tenant.ID, UserID: user.ID, IsActive: true}
+			err = tx.Create(&tenantUser).Error
+		}
+		if err != nil {
+			return fmt.Errorf("add user to tenant: %w", err)
+		}
+
+		var roleID uuid.UUID
+		if err := tx.Table("roles").Select("id").Where("name = ?", DeveloperRole).Scan(&roleID).Error; err != nil {
+			return fmt.Errorf("find %s role: %w", DeveloperRole, err)
+		}
+		err = tx.Exec("INSERT INTO tenant_user_roles (tenant_user_id, role_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+			tenantUser.ID, roleID).Error
+		if err != nil {
+			return fmt.Errorf("assign %s role: %w", DeveloperRole, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// validate checks the seed settings before anything is written
+func validate(cfg *config.Config) error {
+	seedCfg := cfg.Seed
+	if strings.TrimSpace(seedCfg.TenantName) == "" {
+		return errors.New("seed.tenant_name is required")
+	}
+	if strings.TrimSpace(seedCfg.Username) == "" || strings.TrimSpace(seedCfg.Email) == "" {
+		return errors.New("seed.username and seed.email are required")
+	}
+	if seedCfg.Password == "" {
+		return errors.New("seed.password is required, set it in config.yaml or SEED_PASSWORD")
+	}
+	if unmet := util.NewPasswordPolicy(cfg.Security.Password).UnmetRules(seedCfg.Password); len(unmet) > 0 {
+		return fmt.Errorf("seed.password does not meet the password policy: %s", strings.Join(unmet, ", "))
+	}
+	return nil
+}
+
+// ensureTenant finds the tenant by domain, or by name when no domain is configured, creating it if missing
+func ensureTenant(tx *gorm.DB, name, domain string) (*model.Tenant, bool, error) {
+	var tenant model.Tenant
+	query := tx.Where("name = ?", name)
+	if domain != "" {
+		query = tx.Where("domain = ?", domain)
+	}
+	err := query.First(&tenant).Error
+	if err == nil {
+		return &tenant, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, fmt.Errorf("find tenant: %w", err)
+	}
+
+	tenant = model.Tenant{Name: name, SubscriptionStatus: model.SubscriptionStatusActive}
+	if domain != "" {
+		tenant.Domain = &domain
+	}
+	if err := tx.Create(&tenant).Error; err != nil {
+		return nil, false, fmt.Errorf("create tenant: %w", err)
+	}
+	return &tenant, true, nil
+}
+
+// ensureUser finds the user by username or email, creating a verified developer account if missing
+func ensureUser(tx *gorm.DB, cfg *config.Config) (*model.User, bool, error) {
+	seedCfg := cfg.Seed
+	email := strings.TrimSpace(seedCfg.Email)
+
+	var user model.User
+	err := tx.Where("username = ? OR email = ?", seedCfg.Username, email).First(&user).Error
+	if err == nil {
+		return &user, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, fmt.Errorf("find user: %w", err)
+	}
+
+	hash, err := util.NewPasswordHasher(cfg.Security.BcryptCost).Hash(seedCfg.Password)
+	if err != nil {
+		return nil, false, fmt.Errorf("hash password: %w", err)
+	}
+	fullName := seedCfg.FullName
+	if fullName == "" {
+		fullName = seedCfg.Username
+	}
+	now := time.Now()
+	user = model.User{
+		Username:        seedCfg.Username,
+		PasswordHash:    hash,
+		Email:           email,
+		FullName:        fullName,
+		IsActive:        true,
+		EmailVerified:   true,
+		EmailVerifiedAt: &now,
+		IsDeveloper:     true,
+	}
+	if err := tx.Create(&user).Error; err != nil {
+		return nil, false, fmt.Errorf("create user: %w", err)
+	}
+	return &user, true, nil
+}
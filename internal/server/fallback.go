@@ -0,0 +1,30 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	request_id "github.com/protocyber/kelasgo-api/pkg/gin-request-id"
+)
+
+// notFoundHandler answers requests for unknown routes in the same JSON shape as every other response
+func notFoundHandler(c *gin.Context) {
+	c.JSON(http.StatusNotFound, dto.Response{
+		Success:   false,
+		Message:   "Route not found",
+		Error:     "No route matches " + c.Request.Method + " " + c.Request.URL.Path,
+		RequestID: request_id.GetRequestIDFromContext(c),
+	})
+}
+
+// methodNotAllowedHandler answers requests for a known route with an unsupported method. Gin has already
+// listed the supported methods in the Allow header.
+func methodNotAllowedHandler(c *gin.Context) {
+	c.JSON(http.StatusMethodNotAllowed, dto.Response{
+		Success:   false,
+		Message:   "Method not allowed",
+		Error:     c.Request.Method + " is not supported on " + c.Request.URL.Path + ", allowed: " + c.Writer.Header().Get("Allow"),
+		RequestID: request_id.GetRequestIDFromContext(c),
+	})
+}
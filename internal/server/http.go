@@ -6,7 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/protocyber/kelasgo-api/internal/app"
-	request_id "github.com/protocyber/kelasgo-api/pkg/gin-request-id"
+	"github.com/protocyber/kelasgo-api/internal/server/middleware"
 	"github.com/rs/zerolog/log"
 )
 
@@ -31,9 +31,15 @@ func NewHTTPServer(app *app.App, setupRoutes SetupRoutesFunc) *HTTPServer {
 
 	// Create Gin router
 	g := gin.New()
-	g.Use(gin.Recovery())
-	g.Use(gin.LoggerWithConfig(request_id.GetLoggerConfig(nil, nil, nil)))
-	// g.Use(gin.Logger())
+
+	// Only trust forwarding headers from the configured proxies, so ClientIP is the real client behind them
+	// and the connection's address otherwise
+	if err := g.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatal().Err(err).Strs("trusted_proxies", cfg.Server.TrustedProxies).Msg("Invalid server.trusted_proxies")
+	}
+	// The logger wraps recovery, so requests that panicked are logged too
+	g.Use(middleware.RequestLoggerMiddleware(cfg.Server.RequestLogSkip))
+	g.Use(middleware.RecoveryMiddleware())
 
 	// Setup routes
 	setupRoutes(g, app)
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// APIKeyHeader is the header server-to-server clients send their API key in
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyContextKey holds the API key a request was authenticated with
+const apiKeyContextKey = "api_key"
+
+// APIKeyAuthenticator returns the API key matching a key presented by a client
+type APIKeyAuthenticator interface {
+	Authenticate(c context.Context, key string) (*model.APIKey, error)
+}
+
+// APIKeyMiddleware authenticates server-to-server requests by the X-API-Key header, as an alternative to
+// JWTMiddleware on the routes meant for integrations. The request acts within the key's tenant with the
+// Service role, and RequirePermission allows only the key's scopes.
+func APIKeyMiddleware(authenticator APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := GetContextLogger(c)
+
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			logger.Warn().
+				Str("remote_ip", c.ClientIP()).
+				Str("uri", c.Request.URL.Path).
+				Msg("Request without API key")
+			c.JSON(http.StatusUnauthorized, dto.Response{
+				Success: false,
+				Message: "Unauthorized",
+				Error:   "API key required",
+			})
+			c.Abort()
+			return
+		}
+
+		apiKey, err := authenticator.Authenticate(util.CreateServiceContextFromGin(c), key)
+		if err != nil {
+			logger.Warn().
+				Err(err).
+				Str("remote_ip", c.ClientIP()).
+				Str("uri", c.Request.URL.Path).
+				Msg("API key authentication failed")
+			c.JSON(apperror.HTTPStatus(err), dto.Response{
+				Success: false,
+				Message: "Unauthorized",
+				Error:   err.Error(),
+			})
+			c.Abort()
+			return
+		}
+
+		logger.Info().
+			Str("api_key_id", apiKey.ID.String()).
+			Str("key_prefix", apiKey.KeyPrefix).
+			Str("tenant_id", apiKey.TenantID.String()).
+			Str("method", c.Request.Method).
+			Str("uri", c.Request.URL.Path).
+			Msg("Request authenticated with API key")
+
+		c.Set("role", model.RoleService)
+		c.Set(apiKeyContextKey, apiKey)
+		setTenantID(c, apiKey.TenantID)
+
+		c.Next()
+	}
+}
+
+// GetAPIKey returns the API key the request was authenticated with, if any
+func GetAPIKey(c *gin.Context) (*model.APIKey, bool) {
+	if value, exists := c.Get(apiKeyContextKey); exists {
+		if apiKey, ok := value.(*model.APIKey); ok {
+			return apiKey, true
+		}
+	}
+	return nil, false
+}
@@ -1,11 +1,16 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/protocyber/kelasgo-api/internal/apperror"
 	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
 	"github.com/protocyber/kelasgo-api/internal/util"
 	"github.com/rs/zerolog/log"
 )
@@ -60,6 +65,76 @@ func JWTMiddleware(jwtService *util.JWTService) gin.HandlerFunc {
 	}
 }
 
+// PasswordChangeChecker reports whether a user must change the password before doing anything else
+type PasswordChangeChecker interface {
+	MustChangePassword(c context.Context, userID uuid.UUID) (bool, error)
+}
+
+// PasswordChangeMiddleware rejects requests of users who must change their password, except on the exempt
+// routes, so a user whose password was reset by an admin changes it before doing anything else. Tokens issued
+// before the reset carry no flag, so the user's current flag is looked up unless the token has it already.
+// It must run after JWTMiddleware.
+func PasswordChangeMiddleware(checker PasswordChangeChecker, exemptPaths ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, _ := c.Get("claims")
+		jwtClaims, ok := claims.(*util.JWTClaims)
+		if !ok {
+			c.Next()
+			return
+		}
+		for _, path := range exemptPaths {
+			if c.FullPath() == path {
+				c.Next()
+				return
+			}
+		}
+
+		mustChange := jwtClaims.MustChangePassword
+		if !mustChange {
+			var err error
+			mustChange, err = checker.MustChangePassword(util.CreateServiceContextFromGin(c), jwtClaims.UserID)
+			if err != nil {
+				log.Error().
+					Err(err).
+					Str("user_id", jwtClaims.UserID.String()).
+					Str("uri", c.Request.URL.Path).
+					Msg("Failed to check whether the password must be changed")
+				if errors.Is(err, apperror.ErrNotFound) {
+					c.JSON(http.StatusUnauthorized, dto.Response{
+						Success: false,
+						Message: "Unauthorized",
+						Error:   "User not found",
+					})
+				} else {
+					c.JSON(http.StatusInternalServerError, dto.Response{
+						Success: false,
+						Message: "Failed to check password status",
+					})
+				}
+				c.Abort()
+				return
+			}
+		}
+		if !mustChange {
+			c.Next()
+			return
+		}
+
+		log.Warn().
+			Str("user_id", jwtClaims.UserID.String()).
+			Str("remote_ip", c.ClientIP()).
+			Str("uri", c.Request.URL.Path).
+			Msg("Request rejected until the password is changed")
+		c.JSON(http.StatusForbidden, dto.Response{
+			Success: false,
+			Message: "Password change required",
+			Error:   "Change your password, then log in again with the new one",
+			Code:    dto.ErrorCodePasswordChangeRequired,
+		})
+		c.Abort()
+	}
+}
+
 // RoleMiddleware creates a role-based authorization middleware
 func RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -123,3 +198,92 @@ func RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// PermissionChecker reports whether a user is granted a permission within a tenant
+type PermissionChecker interface {
+	HasPermission(c context.Context, tenantID, userID uuid.UUID, permission string) (bool, error)
+}
+
+// RequirePermission creates an authorization middleware that allows the request when the user's roles in the
+// current tenant are granted the permission, e.g. "students:write". It must run after TenantMiddleware.
+// Users whose token role has the permission in model.RoleFallbackPermissions are allowed without a lookup,
+// and requests authenticated by APIKeyMiddleware are allowed when the key has the permission in its scopes.
+func RequirePermission(checker PermissionChecker, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if apiKey, ok := GetAPIKey(c); ok {
+			if apiKey.HasScope(permission) {
+				c.Next()
+				return
+			}
+			log.Warn().
+				Str("api_key_id", apiKey.ID.String()).
+				Str("permission", permission).
+				Str("remote_ip", c.ClientIP()).
+				Str("uri", c.Request.URL.Path).
+				Msg("API key scope does not include the permission")
+			c.JSON(http.StatusForbidden, dto.Response{
+				Success: false,
+				Message: "Forbidden",
+				Error:   "Insufficient permissions",
+			})
+			c.Abort()
+			return
+		}
+
+		if model.HasFallbackPermission(c.GetString("role"), permission) {
+			c.Next()
+			return
+		}
+
+		userID, ok := c.Get("user_id")
+		uid, isUUID := userID.(uuid.UUID)
+		if !ok || !isUUID || uid == uuid.Nil {
+			log.Error().
+				Str("remote_ip", c.ClientIP()).
+				Str("uri", c.Request.URL.Path).
+				Str("permission", permission).
+				Msg("User ID not found in context during permission check")
+			c.JSON(http.StatusUnauthorized, dto.Response{
+				Success: false,
+				Message: "Unauthorized",
+				Error:   "User ID not found in context",
+			})
+			c.Abort()
+			return
+		}
+
+		granted, err := checker.HasPermission(util.CreateServiceContextFromGin(c), GetTenantID(c), uid, permission)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("user_id", uid.String()).
+				Str("permission", permission).
+				Str("uri", c.Request.URL.Path).
+				Msg("Failed to check permission")
+			c.JSON(http.StatusInternalServerError, dto.Response{
+				Success: false,
+				Message: "Failed to check permissions",
+			})
+			c.Abort()
+			return
+		}
+
+		if !granted {
+			log.Warn().
+				Str("user_id", uid.String()).
+				Str("permission", permission).
+				Str("remote_ip", c.ClientIP()).
+				Str("uri", c.Request.URL.Path).
+				Msg("Insufficient permissions for permission-based access")
+			c.JSON(http.StatusForbidden, dto.Response{
+				Success: false,
+				Message: "Forbidden",
+				Error:   "Insufficient permissions",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,238 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/protocyber/kelasgo-api/internal/config"
+)
+
+// incompressibleTypes are content types that are already compressed or streamed to the client
+var incompressibleTypes = []string{
+	"image/png", "image/jpeg", "image/gif", "image/webp",
+	"video/", "audio/", "font/woff",
+	"application/zip", "application/gzip", "application/x-gzip",
+	"application/vnd.openxmlformats-officedocument.",
+	"text/event-stream",
+}
+
+// CompressionMiddleware gzips responses for clients that accept it once the body reaches the configured
+// minimum size. Smaller responses, already compressed content types, event streams, WebSocket upgrades
+// and excluded path prefixes are sent as is.
+func CompressionMiddleware(compressionConfig config.CompressionConfig) gin.HandlerFunc {
+	level := compressionConfig.Level
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+	excludedPaths := ParsePaths(compressionConfig.ExcludedPaths)
+	writers := sync.Pool{New: func() any {
+		gz, _ := gzip.NewWriterLevel(nil, level)
+		return gz
+	}}
+
+	return func(c *gin.Context) {
+		if !compressionConfig.Enabled || !acceptsGzip(c.Request) || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+		for _, prefix := range excludedPaths {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				c.Next()
+				return
+			}
+		}
+
+		original := c.Writer
+		writer := &gzipResponseWriter{ResponseWriter: original, minSize: compressionConfig.MinSize, pool: &writers}
+		c.Writer = writer
+		defer func() {
+			writer.finish()
+			c.Writer = original
+		}()
+
+		c.Next()
+	}
+}
+
+// ParsePaths parses comma-separated path prefixes and returns them as a slice
+func ParsePaths(paths string) []string {
+	if paths == "" {
+		return []string{}
+	}
+
+	parts := strings.Split(paths, ",")
+	result := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		trimmed := strings.TrimSpace(part)
+		if trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+
+	return result
+}
+
+// acceptsGzip reports whether the request accepts a gzip encoded response and is not a stream or upgrade
+func acceptsGzip(r *http.Request) bool {
+	if r.Header.Get("Upgrade") != "" || strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		return false
+	}
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		if !strings.EqualFold(strings.TrimSpace(name), "gzip") && strings.TrimSpace(name) != "*" {
+			continue
+		}
+		// "gzip;q=0" explicitly refuses gzip
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if value, err := strconv.ParseFloat(q, 64); err == nil && value == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the start of the body until it reaches minSize, then compresses the rest.
+// The status code is held back with it because compressing changes the headers.
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	minSize int
+	pool    *sync.Pool
+	status  int
+	buf     []byte
+	gz      *gzip.Writer
+	passed  bool // Headers and body go to the client uncompressed
+}
+
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if w.gz == nil && !w.passed {
+		w.status = code
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *gzipResponseWriter) WriteHeaderNow() {
+	if w.gz == nil && !w.passed {
+		w.passThrough()
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	switch {
+	case w.gz != nil:
+		return w.gz.Write(data)
+	case w.passed:
+		return w.ResponseWriter.Write(data)
+	case !w.compressible():
+		w.passThrough()
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf = append(w.buf, data...)
+	if len(w.buf) >= w.minSize {
+		if err := w.startGzip(); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *gzipResponseWriter) Status() int {
+	if w.gz == nil && !w.passed && w.status != 0 {
+		return w.status
+	}
+	return w.ResponseWriter.Status()
+}
+
+func (w *gzipResponseWriter) Written() bool {
+	return w.gz != nil || w.passed || len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+// Flush sends what is buffered so far, compressed when the response qualifies
+func (w *gzipResponseWriter) Flush() {
+	switch {
+	case w.gz != nil:
+		w.gz.Flush()
+	case !w.passed && len(w.buf) > 0 && w.compressible():
+		w.startGzip()
+		w.gz.Flush()
+	case !w.passed:
+		w.passThrough()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// compressible reports whether the response headers allow compressing the body
+func (w *gzipResponseWriter) compressible() bool {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	for _, incompressible := range incompressibleTypes {
+		if strings.HasPrefix(contentType, incompressible) {
+			return false
+		}
+	}
+	return true
+}
+
+// startGzip sends the headers of a compressed response and compresses the buffered body
+func (w *gzipResponseWriter) startGzip() error {
+	header := w.Header()
+	header.Set("Content-Encoding", "gzip")
+	header.Add("Vary", "Accept-Encoding")
+	header.Del("Content-Length")
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+
+	w.gz = w.pool.Get().(*gzip.Writer)
+	w.gz.Reset(w.ResponseWriter)
+	buf := w.buf
+	w.buf = nil
+	_, err := w.gz.Write(buf)
+	return err
+}
+
+// passThrough sends the held back status and body uncompressed
+func (w *gzipResponseWriter) passThrough() {
+	w.passed = true
+	if w.compressible() {
+		// The encoding depended on Accept-Encoding even though this response was too small
+		w.Header().Add("Vary", "Accept-Encoding")
+	}
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	if len(w.buf) > 0 {
+		w.ResponseWriter.Write(w.buf)
+		w.buf = nil
+	}
+}
+
+// finish completes the response once the handlers are done
+func (w *gzipResponseWriter) finish() {
+	if w.gz != nil {
+		w.gz.Close()
+		w.gz.Reset(nil)
+		w.pool.Put(w.gz)
+		w.gz = nil
+		return
+	}
+	if !w.passed && (w.status != 0 || len(w.buf) > 0) {
+		w.passThrough()
+	}
+}
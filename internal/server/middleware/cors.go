@@ -23,6 +23,8 @@ func CORSMiddleware(corsConfig config.CORSConfig) gin.HandlerFunc {
 		c.Header("Access-Control-Allow-Methods", corsConfig.AllowedMethods)
 		c.Header("Access-Control-Allow-Headers", corsConfig.AllowedHeaders)
 		c.Header("Access-Control-Allow-Credentials", strconv.FormatBool(corsConfig.AllowCredentials))
+		// Lets browser clients read the ETag for conditional requests
+		c.Header("Access-Control-Expose-Headers", "ETag, X-Total-Rows, X-Result-Truncated")
 
 		if corsConfig.MaxAgeSeconds > 0 {
 			c.Header("Access-Control-Max-Age", strconv.Itoa(corsConfig.MaxAgeSeconds))
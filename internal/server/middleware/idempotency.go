@@ -0,0 +1,217 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/cache"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client generated key of a create request
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed from an earlier request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength bounds the accepted key length
+	maxIdempotencyKeyLength = 255
+	// idempotencyLockTTL bounds how long a key stays reserved by a request that never finishes
+	idempotencyLockTTL = time.Minute
+	// idempotencyStoreTimeout bounds storing the outcome once the request has finished
+	idempotencyStoreTimeout = 3 * time.Second
+)
+
+// idempotencyRecord is the state of an idempotency key stored in Redis
+type idempotencyRecord struct {
+	Completed   bool   `json:"completed"`
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// responseRecorder captures the response body while writing it to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware replays the original response when a create request is retried with the same
+// Idempotency-Key header instead of creating a duplicate. Keys are scoped per tenant, user and endpoint.
+// Requests without the header are processed normally, and Redis failures never block a request.
+func IdempotencyMiddleware(redis *cache.Redis, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, dto.Response{
+				Success: false,
+				Message: "Invalid idempotency key",
+				Error:   "Idempotency-Key must be at most 255 characters",
+			})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.Response{
+				Success: false,
+				Message: "Invalid request body",
+				Error:   err.Error(),
+			})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		storeKey := idempotencyStoreKey(c, key)
+		fingerprint := idempotencyFingerprint(body)
+
+		pending, _ := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+		reserved, err := redis.SetNX(ctx, storeKey, string(pending), idempotencyLockTTL)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("uri", c.Request.URL.Path).
+				Msg("Failed to reserve idempotency key, processing request without it")
+			c.Next()
+			return
+		}
+
+		if !reserved {
+			replayIdempotentResponse(c, redis, storeKey, fingerprint)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// The request context may already be cancelled by the request timeout or a client that went away,
+		// and the outcome must still be stored or the key stays reserved until the lock expires
+		storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), idempotencyStoreTimeout)
+		defer cancel()
+
+		// Server errors are not remembered so the client can retry with the same key
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			if err := redis.Del(storeCtx, storeKey); err != nil {
+				log.Error().
+					Err(err).
+					Str("uri", c.Request.URL.Path).
+					Msg("Failed to release idempotency key")
+			}
+			return
+		}
+
+		completed, _ := json.Marshal(idempotencyRecord{
+			Completed:   true,
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err := redis.Set(storeCtx, storeKey, string(completed), ttl); err != nil {
+			log.Error().
+				Err(err).
+				Str("uri", c.Request.URL.Path).
+				Msg("Failed to store idempotent response")
+		}
+	}
+}
+
+// replayIdempotentResponse answers a request whose key was already used
+func replayIdempotentResponse(c *gin.Context, redis *cache.Redis, storeKey, fingerprint string) {
+	value, found, err := redis.Get(c.Request.Context(), storeKey)
+	if err != nil || !found {
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("uri", c.Request.URL.Path).
+				Msg("Failed to load idempotency key")
+		}
+		// The first request released the key or it just expired, ask the client to retry
+		c.JSON(http.StatusConflict, dto.Response{
+			Success: false,
+			Message: "Request with this idempotency key is being processed",
+			Error:   "Retry the request",
+		})
+		c.Abort()
+		return
+	}
+
+	var record idempotencyRecord
+	if err := json.Unmarshal([]byte(value), &record); err != nil {
+		log.Error().
+			Err(err).
+			Str("uri", c.Request.URL.Path).
+			Msg("Failed to decode idempotency record")
+		c.JSON(http.StatusInternalServerError, dto.Response{
+			Success: false,
+			Message: "Failed to replay request",
+		})
+		c.Abort()
+		return
+	}
+
+	if record.Fingerprint != fingerprint {
+		c.JSON(http.StatusUnprocessableEntity, dto.Response{
+			Success: false,
+			Message: "Idempotency key reused",
+			Error:   "The idempotency key was already used with a different request body",
+		})
+		c.Abort()
+		return
+	}
+
+	if !record.Completed {
+		c.JSON(http.StatusConflict, dto.Response{
+			Success: false,
+			Message: "Request with this idempotency key is being processed",
+			Error:   "Retry the request",
+		})
+		c.Abort()
+		return
+	}
+
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(record.Status, record.ContentType, record.Body)
+	c.Abort()
+}
+
+// idempotencyStoreKey scopes the client key to the tenant, the user and the endpoint
+func idempotencyStoreKey(c *gin.Context, key string) string {
+	userID, _ := c.Get("user_id")
+	return fmt.Sprintf("idempotency:%s:%v:%s:%s:%s", GetTenantID(c), userID, c.Request.Method, c.FullPath(), key)
+}
+
+// idempotencyFingerprint hashes the request body so a reused key with a different payload is detected
+func idempotencyFingerprint(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
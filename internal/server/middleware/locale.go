@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// LocaleMiddleware resolves the language of the response from the lang query parameter, then the
+// Accept-Language header, then the configured default locale, and stores it for handlers and the validator.
+// Languages without translations are skipped, ending at English.
+func LocaleMiddleware(defaultLocale string) gin.HandlerFunc {
+	defaultLanguage := util.SupportedLanguage(defaultLocale)
+
+	return func(c *gin.Context) {
+		language := defaultLanguage
+		if lang := util.AcceptedLanguage(c.Query("lang")); lang != "" {
+			language = lang
+		} else if accepted := util.AcceptedLanguage(c.GetHeader("Accept-Language")); accepted != "" {
+			language = accepted
+		}
+
+		c.Set(string(util.LocaleKey), language)
+		c.Header("Content-Language", language)
+		c.Next()
+	}
+}
+
+// GetLocale returns the language resolved for the request, empty when LocaleMiddleware did not run
+func GetLocale(c *gin.Context) string {
+	if locale, exists := c.Get(string(util.LocaleKey)); exists {
+		if language, ok := locale.(string); ok {
+			return language
+		}
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/infrastructure/cache"
+	"github.com/rs/zerolog/log"
+)
+
+// RateLimitMiddleware allows each client IP at most limit requests to the endpoint per window, answering
+// 429 with a Retry-After header after that. A limit of 0 or less disables it, and Redis failures never
+// block a request.
+func RateLimitMiddleware(redis *cache.Redis, limit int, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		// Fixed windows aligned to the window length, so every counter of a window expires together
+		windowStart := time.Now().Truncate(window)
+		key := fmt.Sprintf("ratelimit:%s:%s:%d", c.FullPath(), c.ClientIP(), windowStart.Unix())
+		count, err := redis.Incr(c.Request.Context(), key, window)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("uri", c.Request.URL.Path).
+				Msg("Failed to count request for rate limit, processing request without it")
+			c.Next()
+			return
+		}
+
+		if count > int64(limit) {
+			retryAfter := time.Until(windowStart.Add(window))
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, dto.Response{
+				Success: false,
+				Message: "Too many requests",
+				Error:   "Rate limit exceeded, retry later",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/protocyber/kelasgo-api/internal/util"
+)
+
+// RecoveryMiddleware recovers from panics in later handlers, logs the stack trace and answers
+// with a 500 JSON response. It is registered right after the request logger so it covers every other
+// middleware, and the logger still records the request with its 500 status.
+func RecoveryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			err, ok := recovered.(error)
+			if !ok {
+				err = fmt.Errorf("%v", recovered)
+			}
+
+			util.NewContextLogger(c).Error().
+				Err(err).
+				Str("method", c.Request.Method).
+				Str("path", c.Request.URL.Path).
+				Bytes("stack", debug.Stack()).
+				Msg("Recovered from panic")
+
+			// Nothing can be sent when the client is gone or the response already started
+			brokenPipe := errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+			if brokenPipe || c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.Response{
+				Success: false,
+				Message: "Internal server error",
+				Error:   "An unexpected error occurred",
+			})
+		}()
+
+		c.Next()
+	}
+}
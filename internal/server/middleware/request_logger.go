@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/protocyber/kelasgo-api/internal/util"
+	"github.com/rs/zerolog"
+)
+
+// RequestLoggerMiddleware logs every request as a structured event once it has been handled. The request,
+// tenant and user IDs are read after the handler chain so IDs set by later middleware are included. Requests
+// to the skipped routes, polled by probes, are not logged since they would drown out real traffic.
+func RequestLoggerMiddleware(skipRoutes []string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(skipRoutes))
+	for _, route := range skipRoutes {
+		skip[route] = true
+	}
+
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if skip[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		logger := util.NewContextLogger(c)
+
+		var event *zerolog.Event
+		switch {
+		case status >= http.StatusInternalServerError:
+			event = logger.Error()
+		case status >= http.StatusBadRequest:
+			event = logger.Warn()
+		default:
+			event = logger.Info()
+		}
+
+		if errs := c.Errors.ByType(gin.ErrorTypePrivate).String(); errs != "" {
+			event = event.Str("errors", errs)
+		}
+
+		event.
+			Str("method", c.Request.Method).
+			Str("path", path).
+			Str("route", c.FullPath()).
+			Int("status", status).
+			Dur("latency", time.Since(start)).
+			Str("client_ip", c.ClientIP()).
+			Int("response_size", c.Writer.Size()).
+			Msg("Request handled")
+	}
+}
@@ -6,14 +6,13 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/protocyber/kelasgo-api/internal/infrastructure/database"
 	"github.com/protocyber/kelasgo-api/internal/util"
 	"github.com/rs/zerolog/log"
 )
 
-// TenantMiddleware extracts tenant ID from various sources and adds it to context
-// It also sets the PostgreSQL session variable for Row Level Security
-func TenantMiddleware(db *database.DatabaseConnections) gin.HandlerFunc {
+// TenantMiddleware extracts tenant ID from various sources and adds it to context.
+// Repositories apply it to Row Level Security inside the transaction of each query.
+func TenantMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var tenantID uuid.UUID
 		var err error
@@ -61,23 +60,30 @@ func TenantMiddleware(db *database.DatabaseConnections) gin.HandlerFunc {
 				c.Abort()
 				return
 			}
+		}
 
-			// Set PostgreSQL session variable for Row Level Security
-			if err := setTenantContext(db, tenantID); err != nil {
-				log.Error().
-					Err(err).
+		// A token issued by SelectTenant is bound to that tenant: it is used when the request
+		// names no tenant and any other requested tenant is rejected
+		if tokenTenantID := getTokenTenantID(c); tokenTenantID != uuid.Nil {
+			if tenantID == uuid.Nil {
+				tenantID = tokenTenantID
+			} else if tenantID != tokenTenantID {
+				log.Warn().
 					Str("tenant_id", tenantID.String()).
+					Str("token_tenant_id", tokenTenantID.String()).
 					Str("remote_ip", c.ClientIP()).
 					Str("uri", c.Request.URL.Path).
-					Msg("Failed to set tenant context in database")
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":   "Failed to set tenant context",
-					"message": "Unable to establish tenant isolation",
+					Msg("Requested tenant does not match the tenant in the token")
+				c.JSON(http.StatusForbidden, gin.H{
+					"error":   "Tenant mismatch",
+					"message": "The requested tenant does not match the selected tenant of the token",
 				})
 				c.Abort()
 				return
 			}
+		}
 
+		if tenantID != uuid.Nil {
 			log.Debug().
 				Str("tenant_id", tenantID.String()).
 				Str("uri", c.Request.URL.Path).
@@ -85,16 +91,41 @@ func TenantMiddleware(db *database.DatabaseConnections) gin.HandlerFunc {
 		}
 
 		// Add tenant ID to context (even if empty - some operations might not require tenant)
-		ctx := context.WithValue(c.Request.Context(), util.XTenantIDKey, tenantID)
-		c.Request = c.Request.WithContext(ctx)
+		setTenantID(c, tenantID)
 
-		// Also set in Gin context for easier access
-		c.Set(string(util.XTenantIDKey), tenantID)
+		c.Next()
+	}
+}
+
+// TenantFromToken adds the tenant selected in the JWT claims to the context, ignoring any X-Tenant-ID
+// header or tenant_id query parameter. It runs after JWTMiddleware on authenticated routes that act on the
+// token's tenant, so clients don't resend the tenant and a header can't drift from the token. Tokens without
+// a selected tenant leave uuid.Nil, which RequireTenant rejects.
+func TenantFromToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := getTokenTenantID(c)
+		if tenantID != uuid.Nil {
+			log.Debug().
+				Str("tenant_id", tenantID.String()).
+				Str("uri", c.Request.URL.Path).
+				Msg("Tenant context established from token")
+		}
+
+		setTenantID(c, tenantID)
 
 		c.Next()
 	}
 }
 
+// setTenantID stores the tenant ID in the request context for repositories and in the Gin context for handlers
+func setTenantID(c *gin.Context, tenantID uuid.UUID) {
+	ctx := context.WithValue(c.Request.Context(), util.XTenantIDKey, tenantID)
+	c.Request = c.Request.WithContext(ctx)
+
+	// Also set in Gin context for easier access
+	c.Set(string(util.XTenantIDKey), tenantID)
+}
+
 // RequireTenant is a middleware that ensures a tenant ID is present
 func RequireTenant() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -137,6 +168,16 @@ func GetTenantIDFromContext(ctx context.Context) uuid.UUID {
 	return uuid.Nil
 }
 
+// getTokenTenantID returns the tenant selected in the JWT claims, or uuid.Nil when none was selected
+func getTokenTenantID(c *gin.Context) uuid.UUID {
+	if claims, exists := c.Get("claims"); exists {
+		if jwtClaims, ok := claims.(*util.JWTClaims); ok {
+			return jwtClaims.TenantID
+		}
+	}
+	return uuid.Nil
+}
+
 // extractSubdomain extracts subdomain from host
 // This is a basic implementation - adjust based on your domain structure
 // func extractSubdomain(host string) string {
@@ -160,53 +201,3 @@ func GetTenantIDFromContext(ctx context.Context) uuid.UUID {
 // 	// No subdomain found
 // 	return ""
 // }
-
-// setTenantContext sets the PostgreSQL session variable for Row Level Security
-func setTenantContext(db *database.DatabaseConnections, tenantID uuid.UUID) error {
-	// Set the current tenant for Row Level Security on both read and write connections
-	sql := "SELECT set_config('app.current_tenant', ?, false)"
-
-	// Set on write connection
-	if err := db.Write.Exec(sql, tenantID.String()).Error; err != nil {
-		log.Error().
-			Err(err).
-			Str("tenant_id", tenantID.String()).
-			Str("connection", "write").
-			Msg("Failed to set tenant context on write connection")
-		return err
-	}
-
-	// Set on read connection (if different from write)
-	if db.Read != db.Write {
-		if err := db.Read.Exec(sql, tenantID.String()).Error; err != nil {
-			log.Error().
-				Err(err).
-				Str("tenant_id", tenantID.String()).
-				Str("connection", "read").
-				Msg("Failed to set tenant context on read connection")
-			return err
-		}
-	}
-
-	return nil
-}
-
-// ClearTenantContext clears the PostgreSQL session variable
-func ClearTenantContext(db *database.DatabaseConnections) error {
-	// Clear the current tenant context
-	sql := "SELECT set_config('app.current_tenant', '', false)"
-
-	// Clear on write connection
-	if err := db.Write.Exec(sql).Error; err != nil {
-		return err
-	}
-
-	// Clear on read connection (if different from write)
-	if db.Read != db.Write {
-		if err := db.Read.Exec(sql).Error; err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
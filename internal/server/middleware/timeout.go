@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+)
+
+// TimeoutMiddleware bounds each request by timeout through its context, which services and repositories
+// receive, so database queries and other calls still running at the deadline are cancelled and the handler
+// answers 503. The middleware answers 503 itself when the handler wrote nothing. Routes in bypass, given as
+// registered, e.g. "/v1/auth/me/export", are not bounded. A timeout of 0 or less disables it.
+func TimeoutMiddleware(timeout time.Duration, bypass []string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(bypass))
+	for _, path := range bypass {
+		skip[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if timeout <= 0 || skip[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return
+		}
+		GetContextLogger(c).Warn().
+			Dur("timeout", timeout).
+			Str("method", c.Request.Method).
+			Str("uri", c.Request.URL.Path).
+			Int("status", c.Writer.Status()).
+			Msg("Request timed out")
+		if !c.Writer.Written() {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, dto.Response{
+				Success: false,
+				Message: "Request timed out",
+				Error:   "The request took too long to process, retry later",
+			})
+		}
+	}
+}
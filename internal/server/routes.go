@@ -2,9 +2,11 @@ package server
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/protocyber/kelasgo-api/internal/app"
+	"github.com/protocyber/kelasgo-api/internal/domain/model"
 	"github.com/protocyber/kelasgo-api/internal/server/middleware"
 	request_id "github.com/protocyber/kelasgo-api/pkg/gin-request-id"
 )
@@ -12,20 +14,52 @@ import (
 // SetupRoutes configures all API routes
 func SetupRoutes(r *gin.Engine, app *app.App) {
 	var (
-		cfg            = app.Config
-		db             = app.DBConns
-		jwtService     = app.JWTService
-		authHandler    = app.AuthHandler
-		userHandler    = app.UserHandler
-		studentHandler = app.StudentHandler
+		cfg                   = app.Config
+		jwtService            = app.JWTService
+		idempotency           = middleware.IdempotencyMiddleware(app.Redis, cfg.GetIdempotencyTTL())
+		availabilityRateLimit = middleware.RateLimitMiddleware(app.Redis, cfg.Security.RateLimit.AvailabilityCheck, time.Minute)
+		authHandler           = app.AuthHandler
+		userHandler           = app.UserHandler
+		studentHandler        = app.StudentHandler
+		classHandler          = app.ClassHandler
+		enrollmentHandler     = app.EnrollmentHandler
+		classSubjectHandler   = app.ClassSubjectHandler
+		attendanceHandler     = app.AttendanceHandler
+		feeHandler            = app.FeeHandler
+		tenantUserHandler     = app.TenantUserHandler
+		teacherHandler        = app.TeacherHandler
+		gradeHandler          = app.GradeHandler
+		reportCardHandler     = app.ReportCardHandler
+		gpaHandler            = app.GPAHandler
+		notificationHandler   = app.NotificationHandler
+		dashboardHandler      = app.DashboardHandler
+		accountHandler        = app.AccountHandler
+		diagnosticsHandler    = app.DiagnosticsHandler
+		apiKeyHandler         = app.APIKeyHandler
+		tenantSettingHandler  = app.TenantSettingHandler
+		permissions           = app.PermissionRepo
+		userRepo              = app.UserRepo
 	)
 
 	// Middleware
 	r.Use(request_id.RequestID(nil))
 	r.Use(middleware.AppContextMiddleware(cfg))
+	r.Use(middleware.LocaleMiddleware(cfg.App.Locale))
+	r.Use(middleware.TimeoutMiddleware(cfg.GetRequestTimeout(), cfg.Server.RequestTimeoutBypass))
 	r.Use(middleware.CORSMiddleware(cfg.App.CORS))
+	r.Use(middleware.CompressionMiddleware(cfg.App.Compression))
 	// Note: TenantMiddleware is now optional and applied per route group as needed
 
+	// Unknown routes and methods get JSON responses like the rest of the API
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(notFoundHandler)
+	r.NoMethod(methodNotAllowedHandler)
+
+	// API documentation (not exposed in production)
+	if !cfg.IsProduction() {
+		r.GET("/swagger/*any", swaggerHandler)
+	}
+
 	// API group
 	api := r.Group("/v1")
 
@@ -60,15 +94,27 @@ func SetupRoutes(r *gin.Engine, app *app.App) {
 	{
 		auth.POST("/login", authHandler.Login)
 		auth.POST("/register", authHandler.Register)
+		auth.GET("/verify-email", authHandler.VerifyEmail)
+		auth.POST("/resend-verification", authHandler.ResendVerification)
+		auth.GET("/password-policy", authHandler.PasswordPolicy) // Rules for new passwords, for display in forms
+		auth.GET("/check-availability", availabilityRateLimit, authHandler.CheckAvailability)
 	}
 
 	// Protected routes
 	protected := api.Group("")
 	protected.Use(middleware.JWTMiddleware(jwtService))
+	// Users whose password was reset by an admin can only see their profile and change the password
+	protected.Use(middleware.PasswordChangeMiddleware(userRepo, "/v1/auth/me", "/v1/auth/change-password"))
 
-	// Auth protected routes (for authenticated users - no tenant context required)
+	// Auth protected routes (for authenticated users - no tenant context required, the selected tenant of
+	// the token is available when there is one)
 	authProtected := protected.Group("/auth")
+	authProtected.Use(middleware.TenantFromToken())
 	{
+		authProtected.GET("/me", authHandler.Me)                      // Get authenticated user's profile
+		authProtected.DELETE("/me", accountHandler.Delete)            // Anonymize own account, requires the password
+		authProtected.GET("/me/export", accountHandler.Export)        // All own personal data as JSON
+		authProtected.GET("/me/permissions", authHandler.Permissions) // Effective permissions in the selected tenant
 		authProtected.POST("/change-password", authHandler.ChangePassword)
 		authProtected.GET("/tenants", authHandler.GetUserTenants)      // Get user's available tenants
 		authProtected.POST("/select-tenant", authHandler.SelectTenant) // Select a tenant and get new token
@@ -76,55 +122,118 @@ func SetupRoutes(r *gin.Engine, app *app.App) {
 
 	// User routes (Admin and Developer only - requires tenant context)
 	users := protected.Group("/users")
-	users.Use(middleware.TenantMiddleware(db))
+	users.Use(middleware.TenantMiddleware())
 	users.Use(middleware.RequireTenant())
 	users.Use(middleware.RoleMiddleware("Admin", "Developer"))
 	{
-		users.POST("", userHandler.Create)
+		users.POST("", idempotency, userHandler.Create)
 		users.GET("", userHandler.List)
 		users.GET("/:id", userHandler.GetByID)
 		users.PUT("/:id", userHandler.Update)
 		users.DELETE("/:id", userHandler.Delete)
 		users.DELETE("", userHandler.BulkDelete)
+		users.POST("/:id/reset-password", userHandler.ResetPassword) // Temporary password, changed on next login
+	}
+
+	// Tenant membership routes (Admin and Developer only - requires tenant context)
+	tenantUsers := protected.Group("/tenant-users")
+	tenantUsers.Use(middleware.TenantMiddleware())
+	tenantUsers.Use(middleware.RequireTenant())
+	tenantUsers.Use(middleware.RoleMiddleware("Admin", "Developer"))
+	{
+		tenantUsers.GET("", tenantUserHandler.List)
+		tenantUsers.POST("/:id/roles", tenantUserHandler.UpdateRoles) // Assign and revoke roles
+		tenantUsers.POST("/:id/activate", tenantUserHandler.Activate)
+		tenantUsers.POST("/:id/deactivate", tenantUserHandler.Deactivate)
 	}
 
-	// Student routes (can be accessed by Teachers, Admin, Developer)
+	// Student routes (permission based; Teachers, Admin, Developer keep access by role during the transition)
 	students := protected.Group("/students")
-	students.Use(middleware.TenantMiddleware(db))
+	students.Use(middleware.TenantMiddleware())
 	students.Use(middleware.RequireTenant())
-	students.Use(middleware.RoleMiddleware("Teacher", "Admin", "Developer"))
 	{
-		students.POST("", studentHandler.Create)
-		students.GET("", studentHandler.List)
-		students.GET("/:id", studentHandler.GetByID)
-		students.PUT("/:id", studentHandler.Update)
-		students.DELETE("/:id", studentHandler.Delete)
-		students.DELETE("", studentHandler.BulkDelete)
-		students.GET("/class/:class_id", studentHandler.GetByClass)
-		students.GET("/parent/:parent_id", studentHandler.GetByParent)
+		studentsRead := middleware.RequirePermission(permissions, model.PermissionStudentsRead)
+		studentsWrite := middleware.RequirePermission(permissions, model.PermissionStudentsWrite)
+		studentsManage := middleware.RequirePermission(permissions, model.PermissionStudentsManage)
+
+		students.POST("", studentsWrite, idempotency, studentHandler.Create)
+		students.GET("", studentsRead, studentHandler.List)
+		students.GET("/number-format", studentsRead, studentHandler.GetNumberFormat)
+		students.PUT("/number-format", studentsManage, studentHandler.UpdateNumberFormat)
+		students.GET("/:id", studentsRead, studentHandler.GetByID)
+		students.PUT("/:id", studentsWrite, studentHandler.Update)
+		students.DELETE("/:id", studentsWrite, studentHandler.Delete)
+		students.DELETE("", studentsWrite, studentHandler.BulkDelete)
+		students.PATCH("/bulk-class", studentsManage, studentHandler.BulkAssignClass)
+		students.GET("/class/:class_id", studentsRead, studentHandler.GetByClass)
+		students.GET("/parent/:parent_id", studentsRead, studentHandler.GetByParent)
+		students.GET("/:id/attendance/summary", studentsRead, attendanceHandler.StudentSummary)
+		students.GET("/:id/enrollments", studentsRead, enrollmentHandler.History)
+		students.GET("/:id/qr", studentsRead, studentHandler.QRCode)
+		students.GET("/:id/report-card", studentsRead, reportCardHandler.Download)
+		students.GET("/:id/transcript", studentsRead, reportCardHandler.Transcript)
+		students.GET("/:id/gpa", studentsRead, gpaHandler.Get)
+		students.POST("/:id/transfer", studentsManage, studentHandler.Transfer)
+		students.POST("/:id/status", studentsManage, studentHandler.ChangeStatus)
+		students.POST("/:id/guardians", studentsWrite, studentHandler.AddGuardian)
+		students.DELETE("/:id/guardians/:parent_id", studentsWrite, studentHandler.RemoveGuardian)
 	}
 
 	// Teacher routes (can be accessed by Admin, Developer)
 	teachers := protected.Group("/teachers")
-	teachers.Use(middleware.TenantMiddleware(db))
+	teachers.Use(middleware.TenantMiddleware())
 	teachers.Use(middleware.RequireTenant())
 	teachers.Use(middleware.RoleMiddleware("Admin", "Developer"))
 	{
-		// TODO: Add teacher handlers
+		// TODO: Add teacher CRUD handlers
+		teachers.GET("/:id/schedule", teacherHandler.Schedule)                                 // Weekly timetable with workload and conflicts
+		teachers.POST("/:id/class-subjects", idempotency, classSubjectHandler.AssignToTeacher) // Bulk subject assignment for term setup
 	}
 
 	// Class routes (can be accessed by Teachers, Admin, Developer)
 	classes := protected.Group("/classes")
-	classes.Use(middleware.TenantMiddleware(db))
+	classes.Use(middleware.TenantMiddleware())
 	classes.Use(middleware.RequireTenant())
 	classes.Use(middleware.RoleMiddleware("Teacher", "Admin", "Developer"))
 	{
-		// TODO: Add class handlers
+		// TODO: Add class CRUD handlers
+		classes.POST("/:id/promote", middleware.RoleMiddleware("Admin", "Developer"), classHandler.Promote)
+		classes.GET("/:id/gradebook", classHandler.Gradebook)
+		classes.PUT("/:id/homeroom-teacher", middleware.RoleMiddleware("Admin", "Developer"), classHandler.AssignHomeroomTeacher)
+	}
+
+	// Class subject routes (can be accessed by Teachers, Admin, Developer; changes by Admin, Developer)
+	classSubjects := protected.Group("/class-subjects")
+	classSubjects.Use(middleware.TenantMiddleware())
+	classSubjects.Use(middleware.RequireTenant())
+	classSubjects.Use(middleware.RoleMiddleware("Teacher", "Admin", "Developer"))
+	{
+		classSubjects.POST("", middleware.RoleMiddleware("Admin", "Developer"), idempotency, classSubjectHandler.Create)
+		classSubjects.GET("", classSubjectHandler.List)
+		classSubjects.GET("/:id", classSubjectHandler.GetByID)
+		classSubjects.PUT("/:id", middleware.RoleMiddleware("Admin", "Developer"), classSubjectHandler.Update)
+		classSubjects.DELETE("/:id", middleware.RoleMiddleware("Admin", "Developer"), classSubjectHandler.Delete)
+		classSubjects.GET("/teacher/:teacher_id", classSubjectHandler.GetByTeacher)
+		classSubjects.POST("/:id/grades", gradeHandler.BulkSave)
+		classSubjects.GET("/:id/grades/export", gradeHandler.Export)
+	}
+
+	// Enrollment routes (can be accessed by Teachers, Admin, Developer)
+	enrollments := protected.Group("/enrollments")
+	enrollments.Use(middleware.TenantMiddleware())
+	enrollments.Use(middleware.RequireTenant())
+	enrollments.Use(middleware.RoleMiddleware("Teacher", "Admin", "Developer"))
+	{
+		enrollments.POST("", idempotency, enrollmentHandler.Create)
+		enrollments.POST("/bulk", idempotency, enrollmentHandler.BulkEnroll) // Enroll all students of the class subject's class
+		enrollments.DELETE("/:id", enrollmentHandler.Delete)
+		enrollments.GET("/student/:student_id", enrollmentHandler.ListByStudent)
+		enrollments.GET("/class-subject/:class_subject_id", enrollmentHandler.ListByClassSubject)
 	}
 
 	// Subject routes (can be accessed by Teachers, Admin, Developer)
 	subjects := protected.Group("/subjects")
-	subjects.Use(middleware.TenantMiddleware(db))
+	subjects.Use(middleware.TenantMiddleware())
 	subjects.Use(middleware.RequireTenant())
 	subjects.Use(middleware.RoleMiddleware("Teacher", "Admin", "Developer"))
 	{
@@ -133,40 +242,103 @@ func SetupRoutes(r *gin.Engine, app *app.App) {
 
 	// Attendance routes (can be accessed by Teachers, Admin, Developer)
 	attendance := protected.Group("/attendance")
-	attendance.Use(middleware.TenantMiddleware(db))
+	attendance.Use(middleware.TenantMiddleware())
 	attendance.Use(middleware.RequireTenant())
 	attendance.Use(middleware.RoleMiddleware("Teacher", "Admin", "Developer"))
 	{
-		// TODO: Add attendance handlers
+		attendance.GET("", attendanceHandler.List)
+		attendance.POST("/scan", attendanceHandler.Scan)
 	}
 
 	// Grade routes (can be accessed by Teachers, Admin, Developer)
 	grades := protected.Group("/grades")
-	grades.Use(middleware.TenantMiddleware(db))
+	grades.Use(middleware.TenantMiddleware())
 	grades.Use(middleware.RequireTenant())
 	grades.Use(middleware.RoleMiddleware("Teacher", "Admin", "Developer"))
 	{
 		// TODO: Add grade handlers
+		grades.GET("/scale", gradeHandler.GetScale)
+		grades.PUT("/scale", middleware.RoleMiddleware("Admin", "Developer"), gradeHandler.UpdateScale)
 	}
 
 	// Fee routes (can be accessed by Staff, Admin, Developer)
 	fees := protected.Group("/fees")
-	fees.Use(middleware.TenantMiddleware(db))
+	fees.Use(middleware.TenantMiddleware())
 	fees.Use(middleware.RequireTenant())
 	fees.Use(middleware.RoleMiddleware("Staff", "Admin", "Developer"))
 	{
-		// TODO: Add fee handlers
+		// TODO: Add fee CRUD handlers
+		fees.GET("/summary", feeHandler.Summary)
+		fees.GET("/outstanding", feeHandler.ListOutstanding)
+		fees.POST("/:id/pay", idempotency, feeHandler.RecordPayment)
+		fees.GET("/:id/payments", feeHandler.ListPayments)
+		fees.GET("/:id/receipt", feeHandler.Receipt)
+		fees.PUT("/types/:id/penalty", middleware.RoleMiddleware("Admin", "Developer"), feeHandler.UpdateFeeTypePenalty)
 	}
 
 	// Notification routes (can be accessed by all authenticated users)
-	// notifications := protected.Group("/notifications")
-	// {
-	// 	// TODO: Add notification handlers
-	// }
-
-	// Dashboard routes (role-based access)
-	// dashboard := protected.Group("/dashboard")
-	// {
-	// 	// TODO: Add dashboard handlers with role-specific data
-	// }
+	notifications := protected.Group("/notifications")
+	{
+		notifications.GET("/preferences", notificationHandler.GetPreferences)    // Own channels per notification type
+		notifications.PUT("/preferences", notificationHandler.UpdatePreferences) // Choose email and/or SMS per type
+		notifications.POST("", middleware.TenantMiddleware(), middleware.RequireTenant(),
+			middleware.RoleMiddleware("Admin", "Developer"), idempotency, notificationHandler.Send)
+	}
+
+	// Dashboard routes (role-based access, the payload depends on the role)
+	dashboard := protected.Group("/dashboard")
+	dashboard.Use(middleware.TenantMiddleware())
+	dashboard.Use(middleware.RequireTenant())
+	dashboard.Use(middleware.RoleMiddleware("Teacher", "Parent", "Staff", "Admin", "Developer"))
+	{
+		dashboard.GET("", dashboardHandler.Get)
+		dashboard.GET("/summary", middleware.RoleMiddleware("Teacher", "Staff", "Admin", "Developer"), dashboardHandler.Summary)
+	}
+
+	// Tenant settings routes (Admin and Developer only - requires tenant context)
+	tenant := protected.Group("/tenant")
+	tenant.Use(middleware.TenantMiddleware())
+	tenant.Use(middleware.RequireTenant())
+	tenant.Use(middleware.RoleMiddleware("Admin", "Developer"))
+	{
+		tenant.GET("/settings", tenantSettingHandler.Get)
+		tenant.PUT("/settings", tenantSettingHandler.Update) // Null values reset a setting to its default
+	}
+
+	// Diagnostics routes (Developer only - requires tenant context)
+	diagnostics := protected.Group("/diagnostics")
+	diagnostics.Use(middleware.TenantMiddleware())
+	diagnostics.Use(middleware.RequireTenant())
+	diagnostics.Use(middleware.RoleMiddleware("Developer"))
+	{
+		diagnostics.GET("/session-isolation", diagnosticsHandler.SessionIsolation) // Tenant context must not outlive its transaction
+	}
+
+	if cfg.Security.APIKeys.Enabled {
+		// API key management (Admin and Developer only - requires tenant context)
+		apiKeys := protected.Group("/api-keys")
+		apiKeys.Use(middleware.TenantMiddleware())
+		apiKeys.Use(middleware.RequireTenant())
+		apiKeys.Use(middleware.RoleMiddleware("Admin", "Developer"))
+		{
+			apiKeys.POST("", apiKeyHandler.Create) // The key is only returned here
+			apiKeys.GET("", apiKeyHandler.List)
+			apiKeys.DELETE("/:id", apiKeyHandler.Revoke)
+		}
+
+		// Integration routes (X-API-Key instead of a token; the key's tenant and scopes apply)
+		integrations := api.Group("/integrations")
+		integrations.Use(middleware.APIKeyMiddleware(app.APIKeyService))
+		integrations.Use(middleware.RequireTenant())
+		{
+			studentsRead := middleware.RequirePermission(permissions, model.PermissionStudentsRead)
+			studentsWrite := middleware.RequirePermission(permissions, model.PermissionStudentsWrite)
+
+			integrations.GET("/students", studentsRead, studentHandler.List)
+			integrations.GET("/students/:id", studentsRead, studentHandler.GetByID)
+			integrations.GET("/students/class/:class_id", studentsRead, studentHandler.GetByClass)
+			integrations.POST("/students", studentsWrite, idempotency, studentHandler.Create)
+			integrations.PUT("/students/:id", studentsWrite, studentHandler.Update)
+		}
+	}
 }
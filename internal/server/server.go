@@ -43,6 +43,11 @@ func (s *Server) Start() error {
 		}
 	}()
 
+	// Schedule the recurring jobs, every instance takes part in the election of each run
+	if s.app.Config.Jobs.Scheduler.Enabled {
+		s.app.Scheduler.Start()
+	}
+
 	// Wait for interrupt signal or server error
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
@@ -55,24 +60,79 @@ func (s *Server) Start() error {
 	}
 }
 
-// shutdown handles graceful shutdown of the server
+// shutdown handles graceful shutdown of the server. In-flight requests get the configured grace period,
+// then queued work and connections get the cleanup period.
 func (s *Server) shutdown() error {
+	started := time.Now()
+	gracePeriod := s.app.Config.GetShutdownGracePeriod()
+	cleanupPeriod := s.app.Config.GetShutdownCleanupPeriod()
+	log.Info().
+		Dur("grace_period", gracePeriod).
+		Dur("cleanup_period", cleanupPeriod).
+		Msg("Shutting down server")
+
 	// Create context with timeout for graceful shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), gracePeriod)
 	defer cancel()
 
-	// Gracefully shutdown the HTTP server
-	if err := s.httpServer.Shutdown(ctx); err != nil {
-		log.Error().Err(err).Msg("Failed to gracefully shutdown HTTP server")
-		return err
+	// Gracefully shutdown the HTTP server, the remaining resources are released even if it times out
+	httpErr := s.httpServer.Shutdown(ctx)
+	if httpErr != nil {
+		log.Error().Err(httpErr).Msg("Failed to gracefully shutdown HTTP server")
+	}
+	log.Info().
+		Dur("elapsed", time.Since(started)).
+		Msg("HTTP server stopped")
+
+	cleanupCtx, cancelCleanup := context.WithTimeout(context.Background(), cleanupPeriod)
+	defer cancelCleanup()
+
+	// Stop the scheduled jobs first, they may still publish events
+	if err := s.app.Scheduler.Shutdown(cleanupCtx); err != nil {
+		log.Error().Err(err).Msg("Failed to stop scheduled jobs before shutdown")
+	}
+
+	// Handle queued events first, their subscribers may still queue emails
+	if err := s.app.EventBus.Shutdown(cleanupCtx); err != nil {
+		log.Error().Err(err).Msg("Failed to flush event queue before shutdown")
+	}
+
+	// Flush queued emails within the cleanup period, requests can no longer enqueue new ones
+	if err := s.app.Mailer.Shutdown(cleanupCtx); err != nil {
+		log.Error().Err(err).Msg("Failed to flush mail queue before shutdown")
+	}
+	if err := s.app.SMSDispatcher.Shutdown(cleanupCtx); err != nil {
+		log.Error().Err(err).Msg("Failed to flush SMS queue before shutdown")
+	}
+
+	// Closing does not take a context, so it is abandoned once the cleanup period is over
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+
+		// Close idle Redis connections
+		if err := s.app.Redis.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close Redis connections")
+		}
+
+		// Close database connections if the app has them
+		if err := s.app.DBConns.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close database connections")
+			// Don't return error here, just log it
+		}
+	}()
+	select {
+	case <-closed:
+	case <-cleanupCtx.Done():
+		log.Error().Msg("Timed out closing Redis and database connections")
 	}
 
-	// Close database connections if the app has them
-	if err := s.app.DBConns.Close(); err != nil {
-		log.Error().Err(err).Msg("Failed to close database connections")
-		// Don't return error here, just log it
+	if httpErr != nil {
+		return httpErr
 	}
 
-	log.Info().Msg("Server shutdown complete")
+	log.Info().
+		Dur("elapsed", time.Since(started)).
+		Msg("Server shutdown complete")
 	return nil
 }
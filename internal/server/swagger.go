@@ -0,0 +1,45 @@
+package server
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// swaggerSpecPath is the OpenAPI spec generated by `make swagger`
+const swaggerSpecPath = "docs/swagger.json"
+
+// swaggerUIPage renders Swagger UI for the spec served at /swagger/doc.json
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<title>KelasGo API</title>
+	<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+	<script>
+		window.ui = SwaggerUIBundle({ url: "doc.json", dom_id: "#swagger-ui" });
+	</script>
+</body>
+</html>`
+
+// swaggerHandler serves the generated OpenAPI spec at /swagger/doc.json and Swagger UI for any other path
+func swaggerHandler(c *gin.Context) {
+	if c.Param("any") == "/doc.json" {
+		if _, err := os.Stat(swaggerSpecPath); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "API spec not found",
+				"message": "Run `make swagger` to generate " + swaggerSpecPath,
+			})
+			return
+		}
+		c.File(swaggerSpecPath)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
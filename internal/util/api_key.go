@@ -0,0 +1,31 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// apiKeyPrefix starts every API key so leaked keys are easy to recognize
+const apiKeyPrefix = "kg_"
+
+// apiKeyDisplayLength is how many characters of a key are kept to identify it in listings
+const apiKeyDisplayLength = len(apiKeyPrefix) + 8
+
+// GenerateAPIKey returns a new random API key with its display prefix and the hash to store
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+	key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return key, key[:apiKeyDisplayLength], HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the SHA-256 of an API key. Keys are 256 bit random values, so a fast hash is enough
+// and lets a key be found by its hash.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
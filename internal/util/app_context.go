@@ -15,6 +15,8 @@ type AppContextKeyType string
 
 const (
 	AppContextKey AppContextKeyType = "app_context"
+	// LocaleKey holds the language resolved for the request
+	LocaleKey AppContextKeyType = "locale"
 )
 
 // AppContext wraps application context with configuration
@@ -95,6 +97,22 @@ func (ac *AppContext) GetPaginationDefaults() (defaultLimit, maxLimit int, enabl
 	return ac.Config.App.Pagination.DefaultLimit, ac.Config.App.Pagination.MaxLimit, ac.Config.App.Pagination.Enabled
 }
 
+// GetResultSizeWarning returns the total rows from which list responses warn about their size, and whether the
+// warning is repeated in the response meta
+func (ac *AppContext) GetResultSizeWarning() (largeResultThreshold int64, metaWarning bool) {
+	return ac.Config.App.Pagination.LargeResultThreshold, ac.Config.App.Pagination.MetaWarning
+}
+
+// GetBulkMaxItems returns the most items a bulk request may contain
+func (ac *AppContext) GetBulkMaxItems() int {
+	return ac.Config.App.BulkMaxItems
+}
+
+// GetPasswordPolicy returns the rules new passwords must satisfy
+func (ac *AppContext) GetPasswordPolicy() PasswordPolicy {
+	return NewPasswordPolicy(ac.Config.Security.Password)
+}
+
 // WithAppContext adds app context to a regular context
 func WithAppContext(ctx context.Context, appCtx *AppContext) context.Context {
 	return context.WithValue(ctx, AppContextKey, appCtx)
@@ -117,7 +135,8 @@ func CreateServiceContext(ctx context.Context, appCtx *AppContext) context.Conte
 
 // CreateServiceContextFromGin creates a service context from gin context
 func CreateServiceContextFromGin(ginCtx *gin.Context) context.Context {
-	ctx := context.Background()
+	// Derived from the request, so its deadline and cancellation reach repositories
+	ctx := ginCtx.Request.Context()
 
 	// Extract app context if available
 	if appCtx, exists := ginCtx.Get("app_context"); exists {
@@ -141,13 +160,26 @@ func CreateServiceContextFromGin(ginCtx *gin.Context) context.Context {
 		ctx = context.WithValue(ctx, "user_id", userID)
 	}
 
+	// Copy request language
+	if locale, exists := ginCtx.Get(string(LocaleKey)); exists {
+		ctx = context.WithValue(ctx, LocaleKey, locale)
+	}
+
 	return ctx
 }
 
+// GetLocaleFromContext returns the language resolved for the request, empty when there is none
+func GetLocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(LocaleKey).(string)
+	return locale
+}
+
 // GetTenantIDFromContext extracts tenant ID from context
 func GetTenantIDFromContext(ctx context.Context) (string, bool) {
-	tenantID, ok := ctx.Value(XTenantIDKey).(string)
-	return tenantID, ok && tenantID != ""
+	if tenantID, ok := GetTenantIDAsUUID(ctx); ok {
+		return tenantID.String(), true
+	}
+	return "", false
 }
 
 // GetUserIDFromContext extracts user ID from context
@@ -177,13 +209,13 @@ func GetUserIDAsUUID(ctx context.Context) (uuid.UUID, bool) {
 
 // GetTenantIDAsUUID extracts tenant ID from context as UUID
 func GetTenantIDAsUUID(ctx context.Context) (uuid.UUID, bool) {
-	tenantID, ok := ctx.Value(XTenantIDKey).(string)
-	if !ok || tenantID == "" {
-		return uuid.Nil, false
-	}
-
-	if parsed, err := uuid.Parse(tenantID); err == nil {
-		return parsed, true
+	switch v := ctx.Value(XTenantIDKey).(type) {
+	case uuid.UUID:
+		return v, v != uuid.Nil
+	case string:
+		if parsed, err := uuid.Parse(v); err == nil {
+			return parsed, true
+		}
 	}
 
 	return uuid.Nil, false
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"context"
+	"time"
+)
+
+// dateLayout is the YYYY-MM-DD format used for calendar dates
+const dateLayout = "2006-01-02"
+
+// NowFromContext returns the current time in the timezone of the app context carried by ctx,
+// falling back to the local time when there is none
+func NowFromContext(ctx context.Context) time.Time {
+	if appCtx, ok := GetAppContextFromContext(ctx); ok && appCtx != nil {
+		return appCtx.Now()
+	}
+	return time.Now()
+}
+
+// TodayFromContext returns today's calendar date in the timezone of the app context carried by ctx.
+// The date is at midnight UTC so it is stored unchanged in DATE columns regardless of the server timezone.
+func TodayFromContext(ctx context.Context) time.Time {
+	now := NowFromContext(ctx)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// IsFutureDate reports whether the calendar date of t is after the calendar date of now
+func IsFutureDate(t, now time.Time) bool {
+	return t.Format(dateLayout) > now.Format(dateLayout)
+}
+
+// AgeAt returns the age in whole years of someone born on birthDate at the given time
+func AgeAt(birthDate, at time.Time) int {
+	age := at.Year() - birthDate.Year()
+	if at.Month() < birthDate.Month() || (at.Month() == birthDate.Month() && at.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
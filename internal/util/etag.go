@@ -0,0 +1,31 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WeakETag builds a weak entity tag for a resource from its ID and the times its representation depends on
+func WeakETag(id uuid.UUID, versions ...time.Time) string {
+	hash := sha256.New()
+	hash.Write(id[:])
+	for _, version := range versions {
+		hash.Write([]byte(version.UTC().Format(time.RFC3339Nano)))
+	}
+	return `W/"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// ETagMatches reports whether an If-None-Match header matches etag, using the weak comparison
+func ETagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
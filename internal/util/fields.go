@@ -0,0 +1,33 @@
+package util
+
+import (
+	"encoding/json"
+)
+
+// SelectFields projects a slice of JSON serializable items onto the given top-level JSON fields,
+// omitting fields an item does not have. It returns data unchanged when no fields are given.
+func SelectFields(data interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return data, nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, err
+	}
+
+	projected := make([]map[string]json.RawMessage, len(items))
+	for i, item := range items {
+		projected[i] = make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := item[field]; ok {
+				projected[i][field] = value
+			}
+		}
+	}
+	return projected, nil
+}
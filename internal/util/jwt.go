@@ -3,6 +3,7 @@ package util
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -12,43 +13,106 @@ import (
 
 // JWTClaims represents the JWT claims
 type JWTClaims struct {
-	UserID   uuid.UUID `json:"user_id"`
-	TenantID uuid.UUID `json:"tenant_id"`
-	Username string    `json:"username"`
-	Email    string    `json:"email"`
-	Role     string    `json:"role"`
+	UserID             uuid.UUID `json:"user_id"`
+	TenantID           uuid.UUID `json:"tenant_id"`
+	Username           string    `json:"username"`
+	Email              string    `json:"email"`
+	Role               string    `json:"role"`
+	MustChangePassword bool      `json:"must_change_password,omitempty"` // Only the password change is allowed
 	jwt.RegisteredClaims
 }
 
+// EmailVerificationClaims represents the claims of an email verification token
+type EmailVerificationClaims struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// emailVerificationKeySuffix derives a separate signing key so verification tokens are never accepted as access tokens
+const emailVerificationKeySuffix = ":email-verification"
+
+// jwtLeeway tolerates small clock skew between servers when checking exp, nbf and iat
+const jwtLeeway = 30 * time.Second
+
 // JWTService handles JWT operations
 type JWTService struct {
-	secret     string
-	expireTime int
+	secret         string
+	expireTime     int
+	roleExpireTime map[string]time.Duration
+	issuer         string
+	audience       string
 }
 
-// NewJWTService creates a new JWT service
+// NewJWTService creates a new JWT service. Role expiry overrides are matched case-insensitively, since
+// configuration keys are lowercased when loaded.
 func NewJWTService(cfg *config.JWTConfig) *JWTService {
+	roleExpireTime := make(map[string]time.Duration, len(cfg.RoleExpireTime))
+	for role, minutes := range cfg.RoleExpireTime {
+		if minutes > 0 {
+			roleExpireTime[strings.ToLower(role)] = time.Duration(minutes) * time.Minute
+		}
+	}
 	return &JWTService{
-		secret:     cfg.Secret,
-		expireTime: cfg.ExpireTime,
+		secret:         cfg.Secret,
+		expireTime:     cfg.ExpireTime,
+		roleExpireTime: roleExpireTime,
+		issuer:         cfg.Issuer,
+		audience:       cfg.Audience,
+	}
+}
+
+// tokenLifetime returns how long a token of a user with the given roles is valid: the shortest override of
+// their roles, or the default expire time when none of them has one
+func (j *JWTService) tokenLifetime(roles []string) time.Duration {
+	lifetime := time.Duration(j.expireTime) * time.Hour
+	overridden := false
+	for _, role := range roles {
+		roleLifetime, ok := j.roleExpireTime[strings.ToLower(role)]
+		if ok && (!overridden || roleLifetime < lifetime) {
+			lifetime = roleLifetime
+			overridden = true
+		}
+	}
+	return lifetime
+}
+
+// parserOptions returns the claim checks every token issued by this service must pass
+func (j *JWTService) parserOptions() []jwt.ParserOption {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(jwtLeeway),
+	}
+	if j.issuer != "" {
+		options = append(options, jwt.WithIssuer(j.issuer))
 	}
+	if j.audience != "" {
+		options = append(options, jwt.WithAudience(j.audience))
+	}
+	return options
 }
 
-// GenerateToken generates a JWT token for the given user
-func (j *JWTService) GenerateToken(userID, tenantID uuid.UUID, username, email, role string) (string, time.Time, error) {
-	expirationTime := time.Now().Add(time.Duration(j.expireTime) * time.Hour)
+// GenerateToken generates a JWT token for the given user. Roles are all of the user's roles in the tenant,
+// which decide the token's lifetime; role is the one carried in the claims. Tokens of users who must change
+// their password are marked so they only give access to the password change.
+func (j *JWTService) GenerateToken(userID, tenantID uuid.UUID, username, email, role string, roles []string, mustChangePassword bool) (string, time.Time, error) {
+	expirationTime := time.Now().Add(j.tokenLifetime(roles))
 
 	claims := &JWTClaims{
-		UserID:   userID,
-		TenantID: tenantID,
-		Username: username,
-		Email:    email,
-		Role:     role,
+		UserID:             userID,
+		TenantID:           tenantID,
+		Username:           username,
+		Email:              email,
+		Role:               role,
+		MustChangePassword: mustChangePassword,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "kelasgo-api",
+			Issuer:    j.issuer,
+			Audience:  jwt.ClaimStrings{j.audience},
 			Subject:   userID.String(),
 		},
 	}
@@ -69,7 +133,7 @@ func (j *JWTService) ValidateToken(tokenString string) (*JWTClaims, error) {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 		return []byte(j.secret), nil
-	})
+	}, j.parserOptions()...)
 
 	if err != nil {
 		return nil, err
@@ -82,6 +146,45 @@ func (j *JWTService) ValidateToken(tokenString string) (*JWTClaims, error) {
 	return nil, errors.New("invalid token")
 }
 
+// GenerateEmailVerificationToken generates a token confirming that the user owns the email address
+func (j *JWTService) GenerateEmailVerificationToken(userID uuid.UUID, email string, expireHours int) (string, error) {
+	claims := &EmailVerificationClaims{
+		UserID: userID,
+		Email:  email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(expireHours) * time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Issuer:    j.issuer,
+			Audience:  jwt.ClaimStrings{j.audience},
+			Subject:   userID.String(),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(j.secret + emailVerificationKeySuffix))
+}
+
+// ValidateEmailVerificationToken validates an email verification token and returns the claims
+func (j *JWTService) ValidateEmailVerificationToken(tokenString string) (*EmailVerificationClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &EmailVerificationClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(j.secret + emailVerificationKeySuffix), nil
+	}, j.parserOptions()...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*EmailVerificationClaims); ok && token.Valid {
+		return claims, nil
+	}
+
+	return nil, errors.New("invalid token")
+}
+
 // ExtractTokenFromAuthHeader extracts token from Authorization header
 func ExtractTokenFromAuthHeader(authHeader string) (string, error) {
 	if authHeader == "" {
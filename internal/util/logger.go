@@ -30,8 +30,11 @@ func NewContextLogger(c *gin.Context) *ContextLogger {
 
 	// Extract tenant ID if available
 	if tenantID, exists := c.Get(string(XTenantIDKey)); exists {
-		if id, ok := tenantID.(string); ok {
-			logger.tenantID = id
+		switch v := tenantID.(type) {
+		case string:
+			logger.tenantID = v
+		case uuid.UUID:
+			logger.tenantID = v.String()
 		}
 	}
 
@@ -61,8 +64,11 @@ func NewServiceLogger(c context.Context) *ContextLogger {
 
 	// Extract tenant ID if available
 	if tenantID := c.Value(XTenantIDKey); tenantID != nil {
-		if id, ok := tenantID.(string); ok {
-			logger.tenantID = id
+		switch v := tenantID.(type) {
+		case string:
+			logger.tenantID = v
+		case uuid.UUID:
+			logger.tenantID = v.String()
 		}
 	}
 
@@ -4,14 +4,39 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-// HashPassword hashes a password using bcrypt
-func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+// DefaultBcryptCost is used when no valid bcrypt cost is configured
+const DefaultBcryptCost = 12
+
+// PasswordHasher hashes and verifies passwords with a configurable bcrypt cost
+type PasswordHasher struct {
+	cost int
+}
+
+// NewPasswordHasher creates a password hasher, falling back to DefaultBcryptCost for out of range costs
+func NewPasswordHasher(cost int) *PasswordHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = DefaultBcryptCost
+	}
+	return &PasswordHasher{cost: cost}
+}
+
+// Hash hashes a password using bcrypt with the configured cost
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
 	return string(bytes), err
 }
 
-// CheckPassword checks if a password matches the hash
-func CheckPassword(password, hash string) bool {
+// Check checks if a password matches the hash
+func (h *PasswordHasher) Check(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
+
+// NeedsRehash reports whether the hash was created with a lower cost than the configured one
+func (h *PasswordHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return false
+	}
+	return cost < h.cost
+}
@@ -0,0 +1,163 @@
+package util
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/protocyber/kelasgo-api/internal/config"
+)
+
+// MinPasswordLength is the floor of the configurable minimum password length
+const MinPasswordLength = 6
+
+// PasswordPolicy holds the rules a new password must satisfy
+type PasswordPolicy struct {
+	MinLength     int  `json:"min_length"`
+	RequireUpper  bool `json:"require_upper"`
+	RequireLower  bool `json:"require_lower"`
+	RequireDigit  bool `json:"require_digit"`
+	RequireSymbol bool `json:"require_symbol"`
+}
+
+// NewPasswordPolicy creates a password policy, raising the minimum length to MinPasswordLength
+func NewPasswordPolicy(cfg config.PasswordPolicyConfig) PasswordPolicy {
+	policy := PasswordPolicy{
+		MinLength:     cfg.MinLength,
+		RequireUpper:  cfg.RequireUpper,
+		RequireLower:  cfg.RequireLower,
+		RequireDigit:  cfg.RequireDigit,
+		RequireSymbol: cfg.RequireSymbol,
+	}
+	if policy.MinLength < MinPasswordLength {
+		policy.MinLength = MinPasswordLength
+	}
+	return policy
+}
+
+// passwordRuleMessages describe unmet password rules in one language; minLength takes the length
+type passwordRuleMessages struct {
+	minLength string
+	upper     string
+	lower     string
+	digit     string
+	symbol    string
+}
+
+// englishPasswordRules are the default descriptions of unmet password rules
+var englishPasswordRules = passwordRuleMessages{
+	minLength: "must be at least %d characters",
+	upper:     "must contain an uppercase letter",
+	lower:     "must contain a lowercase letter",
+	digit:     "must contain a digit",
+	symbol:    "must contain a symbol",
+}
+
+// UnmetRules describes every rule the password does not satisfy, empty when it is acceptable
+func (p PasswordPolicy) UnmetRules(password string) []string {
+	return p.unmetRules(password, englishPasswordRules)
+}
+
+// unmetRules describes every rule the password does not satisfy with the given messages
+func (p PasswordPolicy) unmetRules(password string, messages passwordRuleMessages) []string {
+	var length int
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		length++
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	var unmet []string
+	if length < p.MinLength {
+		unmet = append(unmet, fmt.Sprintf(messages.minLength, p.MinLength))
+	}
+	if p.RequireUpper && !hasUpper {
+		unmet = append(unmet, messages.upper)
+	}
+	if p.RequireLower && !hasLower {
+		unmet = append(unmet, messages.lower)
+	}
+	if p.RequireDigit && !hasDigit {
+		unmet = append(unmet, messages.digit)
+	}
+	if p.RequireSymbol && !hasSymbol {
+		unmet = append(unmet, messages.symbol)
+	}
+	return unmet
+}
+
+// temporaryPasswordLength is the length of generated passwords when the policy asks for no more
+const temporaryPasswordLength = 12
+
+// temporaryPasswordClasses are the characters of generated passwords, without look-alikes such as 0 and O
+// since the password may be read out or copied by hand. One character of each class is always included.
+var temporaryPasswordClasses = []string{
+	"ABCDEFGHJKLMNPQRSTUVWXYZ",
+	"abcdefghijkmnpqrstuvwxyz",
+	"23456789",
+	"!@#$%*?",
+}
+
+// GenerateTemporaryPassword returns a random password satisfying the policy, for admins resetting the password
+// of a user
+func GenerateTemporaryPassword(policy PasswordPolicy) (string, error) {
+	length := temporaryPasswordLength
+	if policy.MinLength > length {
+		length = policy.MinLength
+	}
+
+	var all string
+	password := make([]byte, 0, length)
+	for _, class := range temporaryPasswordClasses {
+		all += class
+		c, err := randomChar(class)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+	for len(password) < length {
+		c, err := randomChar(all)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	// Shuffle so the required classes aren't always at the start
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+	return string(password), nil
+}
+
+// randomChar returns a uniformly random character of the set
+func randomChar(set string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+	if err != nil {
+		return 0, err
+	}
+	return set[n.Int64()], nil
+}
+
+// RegisterPasswordPolicy registers the "password" validation tag enforcing the policy
+func RegisterPasswordPolicy(v *validator.Validate, policy PasswordPolicy) error {
+	return v.RegisterValidation("password", func(fl validator.FieldLevel) bool {
+		return len(policy.UnmetRules(fl.Field().String())) == 0
+	})
+}
@@ -0,0 +1,67 @@
+package util
+
+import (
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// indonesianCountryCode prefixes every canonical Indonesian phone number
+const indonesianCountryCode = "+62"
+
+// NormalizeIndonesianPhone converts an Indonesian phone number written as 08xx, 628xx or +62 8xx, with optional
+// spaces, dashes, dots and parentheses, to the canonical +62 form, e.g. "0812-3456-7890" becomes "+6281234567890".
+// It reports false when the number is not a valid Indonesian number.
+func NormalizeIndonesianPhone(phone string) (string, bool) {
+	digits := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(phone))
+
+	switch {
+	case strings.HasPrefix(digits, "+62"):
+		digits = digits[3:]
+	case strings.HasPrefix(digits, "62"):
+		digits = digits[2:]
+	case strings.HasPrefix(digits, "0"):
+		digits = digits[1:]
+	default:
+		return "", false
+	}
+
+	// The national number has 8 to 12 digits and never starts with 0
+	if len(digits) < 8 || len(digits) > 12 || digits[0] == '0' {
+		return "", false
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return "", false
+		}
+	}
+	return indonesianCountryCode + digits, true
+}
+
+// NormalizeIndonesianPhonePtr normalizes an optional phone number, leaving empty and invalid values unchanged
+func NormalizeIndonesianPhonePtr(phone *string) *string {
+	if phone == nil {
+		return nil
+	}
+	if normalized, ok := NormalizeIndonesianPhone(*phone); ok {
+		return &normalized
+	}
+	return phone
+}
+
+// validateIndonesianPhone is the "id_phone" validation tag. An empty value is accepted since omitempty
+// does not skip a pointer to an empty string.
+func validateIndonesianPhone(fl validator.FieldLevel) bool {
+	phone := fl.Field().String()
+	if phone == "" {
+		return true
+	}
+	_, ok := NormalizeIndonesianPhone(phone)
+	return ok
+}
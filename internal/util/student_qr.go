@@ -0,0 +1,32 @@
+package util
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// studentQRPrefix marks QR payloads that identify a student
+const studentQRPrefix = "KELASGO-STUDENT"
+
+// ErrInvalidStudentQRPayload is returned for payloads that are not student QR codes
+var ErrInvalidStudentQRPayload = errors.New("invalid student QR payload")
+
+// StudentQRPayload builds the text encoded in a student's QR code
+func StudentQRPayload(tenantID uuid.UUID, studentNumber string) string {
+	return studentQRPrefix + ":" + tenantID.String() + ":" + studentNumber
+}
+
+// ParseStudentQRPayload extracts the tenant ID and student number from a scanned student QR code
+func ParseStudentQRPayload(payload string) (uuid.UUID, string, error) {
+	parts := strings.SplitN(strings.TrimSpace(payload), ":", 3)
+	if len(parts) != 3 || parts[0] != studentQRPrefix || parts[2] == "" {
+		return uuid.Nil, "", ErrInvalidStudentQRPayload
+	}
+	tenantID, err := uuid.Parse(parts[1])
+	if err != nil {
+		return uuid.Nil, "", ErrInvalidStudentQRPayload
+	}
+	return tenantID, parts[2], nil
+}
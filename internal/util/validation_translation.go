@@ -0,0 +1,151 @@
+package util
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	id_translations "github.com/go-playground/validator/v10/translations/id"
+)
+
+// DefaultLanguage is the language of validation messages when the locale has no translation
+const DefaultLanguage = "en"
+
+// validationTranslators holds the translator of every supported language other than English, whose
+// messages come from validationMessage
+var validationTranslators = map[string]ut.Translator{}
+
+// passwordRuleTranslations are the descriptions of unmet password rules by language
+var passwordRuleTranslations = map[string]passwordRuleMessages{
+	"en": englishPasswordRules,
+	"id": {
+		minLength: "minimal harus %d karakter",
+		upper:     "harus mengandung huruf besar",
+		lower:     "harus mengandung huruf kecil",
+		digit:     "harus mengandung angka",
+		symbol:    "harus mengandung simbol",
+	},
+}
+
+// customTranslations are the messages of the tags the app registers itself, by language
+var customTranslations = map[string]map[string]string{
+	"id": {
+		"id_phone": "{0} harus berupa nomor telepon Indonesia yang valid, contoh 081234567890 atau +6281234567890",
+		"password": "{0} tidak memenuhi kebijakan kata sandi",
+	},
+}
+
+// registerValidationTranslations registers the validator's Indonesian translations plus the app's own tags
+func registerValidationTranslations(v *validator.Validate) error {
+	uni := ut.New(en.New(), en.New(), id.New())
+
+	trans, _ := uni.GetTranslator("id")
+	if err := id_translations.RegisterDefaultTranslations(v, trans); err != nil {
+		return err
+	}
+	for tag, message := range customTranslations["id"] {
+		message := message
+		err := v.RegisterTranslation(tag, trans, func(t ut.Translator) error {
+			return t.Add(tag, message, true)
+		}, func(t ut.Translator, fe validator.FieldError) string {
+			translated, err := t.T(fe.Tag(), fe.Field())
+			if err != nil {
+				return fe.Error()
+			}
+			return translated
+		})
+		if err != nil {
+			return err
+		}
+	}
+	validationTranslators["id"] = trans
+	return nil
+}
+
+// SupportedLanguage returns the language of a locale such as "id", "id-ID" or "en_US" when validation
+// messages are translated to it, and DefaultLanguage otherwise
+func SupportedLanguage(locale string) string {
+	if language, ok := translatedLanguage(locale); ok {
+		return language
+	}
+	return DefaultLanguage
+}
+
+// translatedLanguage returns the language of a locale and whether messages are available in it
+func translatedLanguage(locale string) (string, bool) {
+	language := strings.ToLower(strings.TrimSpace(locale))
+	if idx := strings.IndexAny(language, "-_"); idx >= 0 {
+		language = language[:idx]
+	}
+	_, ok := passwordRuleTranslations[language]
+	return language, ok
+}
+
+// AcceptedLanguage returns the supported language the client prefers most in an Accept-Language header,
+// e.g. "id" for "fr-FR, id;q=0.8, en;q=0.5", or an empty string when it accepts none of them
+func AcceptedLanguage(header string) string {
+	type accepted struct {
+		language string
+		quality  float64
+	}
+
+	var candidates []accepted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if language, ok := translatedLanguage(tag); ok && quality > 0 {
+			candidates = append(candidates, accepted{language: language, quality: quality})
+		}
+	}
+
+	// Stable, so equally preferred languages keep the client's order
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[0].language
+}
+
+// responseMessageTranslations are the generic response messages by language, keyed by their English text
+var responseMessageTranslations = map[string]map[string]string{
+	"id": {
+		"Validation failed":              "Validasi gagal",
+		"One or more fields are invalid": "Satu atau lebih kolom tidak valid",
+	},
+}
+
+// TranslateMessage returns a generic response message in the language, or the English message when it has
+// no translation
+func TranslateMessage(language, message string) string {
+	if translated, ok := responseMessageTranslations[language][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// translateValidationError returns the message of a validation error in the language, falling back to
+// English when the language or the tag has no translation
+func translateValidationError(fe validator.FieldError, language string) string {
+	trans, ok := validationTranslators[language]
+	if !ok {
+		return validationMessage(fe)
+	}
+	// Translate returns the raw error when the tag has no translation
+	if message := fe.Translate(trans); message != fe.Error() {
+		return message
+	}
+	return validationMessage(fe)
+}
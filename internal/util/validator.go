@@ -0,0 +1,123 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/protocyber/kelasgo-api/internal/domain/dto"
+	"github.com/rs/zerolog/log"
+)
+
+// NewValidator creates a validator that reports field names using their JSON tags and enforces the password
+// policy on "password" tagged fields
+func NewValidator(policy PasswordPolicy) *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	_ = v.RegisterValidation("id_phone", validateIndonesianPhone)
+	_ = RegisterPasswordPolicy(v, policy)
+	if err := registerValidationTranslations(v); err != nil {
+		log.Error().Err(err).Msg("Failed to register validation translations, messages will be in English")
+	}
+	return v
+}
+
+// FormatValidationErrors converts validator errors into a list of field errors with messages in the locale's
+// language, or English when it has no translation. Passwords failing the policy are explained rule by rule.
+func FormatValidationErrors(err error, locale string, policy PasswordPolicy) []dto.FieldError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+
+	language := SupportedLanguage(locale)
+	fieldErrors := make([]dto.FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		// A password failing the policy gets one error per unmet rule
+		if fe.Tag() == "password" {
+			rules := policy.unmetRules(fmt.Sprint(fe.Value()), passwordRuleTranslations[language])
+			if len(rules) > 0 {
+				for _, rule := range rules {
+					fieldErrors = append(fieldErrors, dto.FieldError{
+						Field:   fieldPath(fe),
+						Tag:     fe.Tag(),
+						Message: fmt.Sprintf("%s %s", fe.Field(), rule),
+					})
+				}
+				continue
+			}
+		}
+
+		fieldErrors = append(fieldErrors, dto.FieldError{
+			Field:   fieldPath(fe),
+			Tag:     fe.Tag(),
+			Message: translateValidationError(fe, language),
+		})
+	}
+	return fieldErrors
+}
+
+// fieldPath returns the field namespace without the top-level struct name
+func fieldPath(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if idx := strings.Index(namespace, "."); idx >= 0 {
+		return namespace[idx+1:]
+	}
+	return fe.Field()
+}
+
+// validationMessage returns a human friendly message for a validation error
+func validationMessage(fe validator.FieldError) string {
+	field := fe.Field()
+
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", field)
+	case "uuid":
+		return fmt.Sprintf("%s must be a valid UUID", field)
+	case "id_phone":
+		return fmt.Sprintf("%s must be a valid Indonesian phone number, e.g. 081234567890 or +6281234567890", field)
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "min":
+		return fmt.Sprintf("%s must be at least %s%s", field, fe.Param(), lengthUnit(fe.Kind()))
+	case "max":
+		return fmt.Sprintf("%s must be at most %s%s", field, fe.Param(), lengthUnit(fe.Kind()))
+	case "len":
+		return fmt.Sprintf("%s must be exactly %s characters", field, fe.Param())
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", field, fe.Param())
+	case "gte":
+		return fmt.Sprintf("%s must be greater than or equal to %s", field, fe.Param())
+	case "lt":
+		return fmt.Sprintf("%s must be less than %s", field, fe.Param())
+	case "lte":
+		return fmt.Sprintf("%s must be less than or equal to %s", field, fe.Param())
+	default:
+		return fmt.Sprintf("%s is invalid", field)
+	}
+}
+
+// lengthUnit returns the unit used by min/max for the given kind
+func lengthUnit(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items"
+	}
+	return ""
+}
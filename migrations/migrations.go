@@ -0,0 +1,9 @@
+// Package migrations embeds the versioned SQL migrations so the migrate command ships with them
+package migrations
+
+import "embed"
+
+// Postgres holds the files of migrations/postgres, named {version}_{name}.up.sql and {version}_{name}.down.sql
+//
+//go:embed postgres/*.sql
+var Postgres embed.FS
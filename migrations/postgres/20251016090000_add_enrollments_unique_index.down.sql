@@ -0,0 +1,4 @@
+-- =========================================
+-- ROLLBACK DUPLICATE ENROLLMENT PREVENTION
+-- =========================================
+DROP INDEX IF EXISTS idx_enrollments_student_class_subject_year;
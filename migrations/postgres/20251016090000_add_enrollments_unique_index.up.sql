@@ -0,0 +1,6 @@
+-- =========================================
+-- PREVENT DUPLICATE ENROLLMENTS
+-- =========================================
+-- A student may only be enrolled once in a class subject per academic year
+CREATE UNIQUE INDEX IF NOT EXISTS idx_enrollments_student_class_subject_year
+ON enrollments (student_id, class_subject_id, academic_year_id);
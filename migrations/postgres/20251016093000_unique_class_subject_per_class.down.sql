@@ -0,0 +1,6 @@
+-- =========================================
+-- ROLLBACK ONE SUBJECT PER CLASS
+-- =========================================
+ALTER TABLE class_subjects DROP CONSTRAINT IF EXISTS class_subjects_class_id_subject_id_key;
+
+ALTER TABLE class_subjects ADD CONSTRAINT class_subjects_class_id_subject_id_teacher_id_key UNIQUE (class_id, subject_id, teacher_id);
@@ -0,0 +1,14 @@
+-- =========================================
+-- ROLLBACK ACCENT INSENSITIVE TRIGRAM SEARCH
+-- =========================================
+DROP INDEX IF EXISTS idx_students_student_number_trgm;
+
+DROP INDEX IF EXISTS idx_users_email_trgm;
+
+DROP INDEX IF EXISTS idx_users_username_trgm;
+
+DROP INDEX IF EXISTS idx_users_full_name_trgm;
+
+DROP FUNCTION IF EXISTS f_unaccent(text);
+
+-- The extensions are left installed since other objects may depend on them
@@ -0,0 +1,19 @@
+-- =========================================
+-- ACCENT INSENSITIVE TRIGRAM SEARCH
+-- =========================================
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() is only STABLE, so wrap it in an IMMUTABLE function that can be used in index expressions
+CREATE OR REPLACE FUNCTION f_unaccent(text) RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent', $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+CREATE INDEX idx_users_full_name_trgm ON users USING gin (f_unaccent(full_name) gin_trgm_ops);
+
+CREATE INDEX idx_users_username_trgm ON users USING gin (f_unaccent(username) gin_trgm_ops);
+
+CREATE INDEX idx_users_email_trgm ON users USING gin (f_unaccent(email) gin_trgm_ops);
+
+CREATE INDEX idx_students_student_number_trgm ON students USING gin (f_unaccent(student_number) gin_trgm_ops);
@@ -0,0 +1,6 @@
+-- =========================================
+-- ROLLBACK EMAIL VERIFICATION
+-- =========================================
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
+
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified;
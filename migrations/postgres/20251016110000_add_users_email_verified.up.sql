@@ -0,0 +1,9 @@
+-- =========================================
+-- EMAIL VERIFICATION
+-- =========================================
+ALTER TABLE users ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+
+-- Accounts created before verification existed are trusted as verified
+UPDATE users SET email_verified = TRUE, email_verified_at = CURRENT_TIMESTAMP;
@@ -0,0 +1,6 @@
+-- =========================================
+-- ROLLBACK STUDENT AGE WINDOW
+-- =========================================
+ALTER TABLE tenants DROP COLUMN IF EXISTS student_age_tolerance;
+
+ALTER TABLE tenants DROP COLUMN IF EXISTS student_age_offset;
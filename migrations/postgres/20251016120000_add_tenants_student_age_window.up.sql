@@ -0,0 +1,8 @@
+-- =========================================
+-- STUDENT AGE WINDOW
+-- =========================================
+-- Expected student age is grade_level + student_age_offset, give or take student_age_tolerance years.
+-- The check is disabled while student_age_offset is NULL.
+ALTER TABLE tenants ADD COLUMN student_age_offset SMALLINT;
+
+ALTER TABLE tenants ADD COLUMN student_age_tolerance SMALLINT NOT NULL DEFAULT 1 CHECK (student_age_tolerance >= 0);
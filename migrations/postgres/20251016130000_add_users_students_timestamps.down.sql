@@ -0,0 +1,18 @@
+-- =========================================
+-- ROLLBACK CREATED / UPDATED TIMESTAMPS
+-- =========================================
+DROP INDEX IF EXISTS idx_students_tenant_updated_at;
+
+DROP INDEX IF EXISTS idx_users_updated_at;
+
+DROP TRIGGER IF EXISTS trg_students_updated_at ON students;
+
+DROP TRIGGER IF EXISTS trg_users_updated_at ON users;
+
+DROP FUNCTION IF EXISTS fn_set_updated_at();
+
+ALTER TABLE students DROP COLUMN IF EXISTS updated_at,
+DROP COLUMN IF EXISTS created_at;
+
+ALTER TABLE users DROP COLUMN IF EXISTS updated_at,
+DROP COLUMN IF EXISTS created_at;
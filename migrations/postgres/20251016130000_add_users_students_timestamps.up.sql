@@ -0,0 +1,28 @@
+-- =========================================
+-- CREATED / UPDATED TIMESTAMPS
+-- =========================================
+-- Existing rows get the migration time as both timestamps
+ALTER TABLE users ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE students ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- Keeps updated_at current for writes that do not set it, e.g. raw SQL updates
+CREATE OR REPLACE FUNCTION fn_set_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at := CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_users_updated_at BEFORE UPDATE ON users
+FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+CREATE TRIGGER trg_students_updated_at BEFORE UPDATE ON students
+FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+-- Support incremental sync by change time
+CREATE INDEX idx_users_updated_at ON users (updated_at);
+
+CREATE INDEX idx_students_tenant_updated_at ON students (tenant_id, updated_at);
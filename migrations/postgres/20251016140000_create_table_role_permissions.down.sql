@@ -0,0 +1,6 @@
+-- =========================================
+-- ROLLBACK ROLE PERMISSIONS
+-- =========================================
+DROP POLICY IF EXISTS tenant_isolation ON role_permissions;
+
+DROP TABLE IF EXISTS role_permissions;
@@ -0,0 +1,44 @@
+-- =========================================
+-- ROLE PERMISSIONS
+-- =========================================
+-- Grants a permission such as 'students:write' to a role within a tenant, so each tenant
+-- decides what its roles may do
+CREATE TABLE
+  role_permissions (
+    tenant_id UUID NOT NULL,
+    role_id UUID NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, role_id, permission)
+  );
+
+ALTER TABLE role_permissions ADD CONSTRAINT fk_role_permissions_tenant_id FOREIGN KEY (tenant_id) REFERENCES tenants (id) ON DELETE CASCADE,
+ADD CONSTRAINT fk_role_permissions_role_id FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE;
+
+CREATE INDEX idx_role_permissions_role_id ON role_permissions (role_id);
+
+ALTER TABLE role_permissions ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON role_permissions USING (tenant_id = current_tenant_id());
+
+-- =========================================
+-- DEFAULT GRANTS
+-- =========================================
+-- Mirror the access the routes granted by role name so existing tenants keep their behavior
+INSERT INTO
+  role_permissions (tenant_id, role_id, permission)
+SELECT
+  t.id,
+  r.id,
+  p.permission
+FROM
+  tenants t
+  CROSS JOIN roles r
+  JOIN (
+    VALUES
+      ('Admin', 'students:read'),
+      ('Admin', 'students:write'),
+      ('Admin', 'students:manage'),
+      ('Teacher', 'students:read'),
+      ('Teacher', 'students:write')
+  ) AS p (role_name, permission) ON p.role_name = r.name;
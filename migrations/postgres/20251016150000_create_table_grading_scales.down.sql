@@ -0,0 +1,10 @@
+-- =========================================
+-- ROLLBACK GRADING SCALES
+-- =========================================
+DROP POLICY IF EXISTS tenant_isolation ON grade_type_weights;
+
+DROP TABLE IF EXISTS grade_type_weights;
+
+DROP POLICY IF EXISTS tenant_isolation ON grading_scale_bands;
+
+DROP TABLE IF EXISTS grading_scale_bands;
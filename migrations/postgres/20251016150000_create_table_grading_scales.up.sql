@@ -0,0 +1,38 @@
+-- =========================================
+-- GRADING SCALE BANDS
+-- =========================================
+-- A letter grade is given to scores at or above its minimum score and below the next band.
+-- Tenants without bands use the default scale defined in the application
+CREATE TABLE
+  grading_scale_bands (
+    tenant_id UUID NOT NULL,
+    letter VARCHAR(5) NOT NULL,
+    min_score DECIMAL(5, 2) NOT NULL CHECK (min_score BETWEEN 0 AND 100),
+    PRIMARY KEY (tenant_id, letter)
+  );
+
+ALTER TABLE grading_scale_bands ADD CONSTRAINT fk_grading_scale_bands_tenant_id FOREIGN KEY (tenant_id) REFERENCES tenants (id) ON DELETE CASCADE;
+
+ALTER TABLE grading_scale_bands ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON grading_scale_bands USING (tenant_id = current_tenant_id());
+
+-- =========================================
+-- GRADE TYPE WEIGHTS
+-- =========================================
+-- Relative weight of each grade type in a final score; weights need not add up to 100
+CREATE TABLE
+  grade_type_weights (
+    tenant_id UUID NOT NULL,
+    grade_type VARCHAR(50) NOT NULL CHECK (
+      grade_type IN ('Assignment', 'Midterm', 'Final', 'Other')
+    ),
+    weight DECIMAL(5, 2) NOT NULL CHECK (weight BETWEEN 0 AND 100),
+    PRIMARY KEY (tenant_id, grade_type)
+  );
+
+ALTER TABLE grade_type_weights ADD CONSTRAINT fk_grade_type_weights_tenant_id FOREIGN KEY (tenant_id) REFERENCES tenants (id) ON DELETE CASCADE;
+
+ALTER TABLE grade_type_weights ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON grade_type_weights USING (tenant_id = current_tenant_id());
@@ -0,0 +1,6 @@
+-- =========================================
+-- ROLLBACK GRADE SEMESTER
+-- =========================================
+DROP INDEX IF EXISTS idx_grades_enrollment_id_semester;
+
+ALTER TABLE grades DROP COLUMN IF EXISTS semester;
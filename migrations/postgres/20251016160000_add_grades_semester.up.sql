@@ -0,0 +1,8 @@
+-- =========================================
+-- GRADE SEMESTER
+-- =========================================
+-- Semester of the academic year a grade belongs to, used to build per semester report cards.
+-- Grades without a semester are only included in full year reports.
+ALTER TABLE grades ADD COLUMN semester SMALLINT CHECK (semester IN (1, 2));
+
+CREATE INDEX idx_grades_enrollment_id_semester ON grades (enrollment_id, semester);
@@ -0,0 +1,6 @@
+-- =========================================
+-- ROLLBACK NOTIFICATION CHANNELS
+-- =========================================
+DROP TABLE IF EXISTS notification_preferences;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS type;
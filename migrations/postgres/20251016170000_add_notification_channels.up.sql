@@ -0,0 +1,22 @@
+-- =========================================
+-- NOTIFICATION TYPE
+-- =========================================
+-- The type decides which channels a notification is delivered through by default
+ALTER TABLE notifications ADD COLUMN type VARCHAR(50) NOT NULL DEFAULT 'general';
+
+-- =========================================
+-- NOTIFICATION PREFERENCES
+-- =========================================
+-- A user's choice of channels per notification type, overriding the defaults of the type.
+-- Preferences belong to the user across tenants, like the email address and phone they apply to.
+CREATE TABLE
+  notification_preferences (
+    user_id UUID NOT NULL,
+    notification_type VARCHAR(50) NOT NULL,
+    email BOOLEAN NOT NULL,
+    sms BOOLEAN NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, notification_type)
+  );
+
+ALTER TABLE notification_preferences ADD CONSTRAINT fk_notification_preferences_user_id FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
@@ -0,0 +1,11 @@
+-- =========================================
+-- ROLLBACK ROW LEVEL SECURITY ON EVERY TENANT TABLE
+-- =========================================
+-- Only subscriptions and invoices were not covered by earlier migrations
+DROP POLICY IF EXISTS tenant_isolation ON invoices;
+
+ALTER TABLE IF EXISTS invoices DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON subscriptions;
+
+ALTER TABLE IF EXISTS subscriptions DISABLE ROW LEVEL SECURITY;
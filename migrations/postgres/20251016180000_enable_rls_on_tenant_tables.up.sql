@@ -0,0 +1,31 @@
+-- =========================================
+-- ROW LEVEL SECURITY ON EVERY TENANT TABLE
+-- =========================================
+-- Earlier migrations enable RLS table by table and missed subscriptions and invoices. Enable it and
+-- create the tenant_isolation policy on every table with a tenant_id column that lacks them. Tables
+-- added later need their own policy, the startup check warns about any that are missing one.
+DO $$
+DECLARE
+    tbl TEXT;
+BEGIN
+    FOR tbl IN
+        SELECT c.table_name FROM information_schema.columns c
+        JOIN pg_tables t ON t.schemaname = c.table_schema AND t.tablename = c.table_name
+        WHERE c.table_schema = 'public'
+        AND c.column_name = 'tenant_id'
+    LOOP
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', tbl);
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_policies
+            WHERE schemaname = 'public' AND tablename = tbl AND policyname = 'tenant_isolation'
+        ) THEN
+            EXECUTE format(
+                'CREATE POLICY tenant_isolation ON %I
+                 USING (tenant_id = current_tenant_id())',
+                tbl
+            );
+        END IF;
+    END LOOP;
+END;
+$$;
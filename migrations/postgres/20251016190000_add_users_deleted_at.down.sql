@@ -0,0 +1,4 @@
+-- =========================================
+-- ROLLBACK ACCOUNT SELF-DELETION
+-- =========================================
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
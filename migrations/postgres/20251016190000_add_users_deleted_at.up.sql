@@ -0,0 +1,5 @@
+-- =========================================
+-- ACCOUNT SELF-DELETION
+-- =========================================
+-- Set when a user deletes their account; the row stays, anonymized, so academic records keep their references
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
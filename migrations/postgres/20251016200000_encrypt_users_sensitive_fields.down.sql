@@ -0,0 +1,6 @@
+-- =========================================
+-- ROLLBACK ENCRYPT SENSITIVE USER FIELDS
+-- =========================================
+-- Decrypt the values first with `make encrypt-users ARGS=-decrypt`, the casts fail on ciphertext
+ALTER TABLE users ALTER COLUMN date_of_birth TYPE DATE USING date_of_birth::DATE;
+ALTER TABLE users ALTER COLUMN phone TYPE VARCHAR(20);
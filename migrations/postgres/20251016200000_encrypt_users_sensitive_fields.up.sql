@@ -0,0 +1,7 @@
+-- =========================================
+-- ENCRYPT SENSITIVE USER FIELDS
+-- =========================================
+-- phone, address and date_of_birth hold AES-GCM ciphertext written by the application, which does not fit
+-- VARCHAR(20) or DATE. Existing plaintext values stay readable; run `make encrypt-users` to encrypt them.
+ALTER TABLE users ALTER COLUMN phone TYPE TEXT;
+ALTER TABLE users ALTER COLUMN date_of_birth TYPE TEXT USING to_char(date_of_birth, 'YYYY-MM-DD');
@@ -0,0 +1,5 @@
+-- =========================================
+-- ROLLBACK PHONE SEARCH
+-- =========================================
+DROP INDEX IF EXISTS idx_users_phone_index;
+ALTER TABLE users DROP COLUMN IF EXISTS phone_index;
@@ -0,0 +1,7 @@
+-- =========================================
+-- PHONE SEARCH
+-- =========================================
+-- users.phone is encrypted with a random nonce, so searches match the keyed hash of the normalized number instead.
+-- The application fills it on every write; run `make encrypt-users` to fill it for existing rows.
+ALTER TABLE users ADD COLUMN phone_index VARCHAR(64);
+CREATE INDEX idx_users_phone_index ON users (phone_index);
@@ -0,0 +1,7 @@
+-- =========================================
+-- ROLLBACK STUDENT LIFECYCLE STATUS
+-- =========================================
+DROP INDEX IF EXISTS idx_students_tenant_status;
+ALTER TABLE students DROP COLUMN IF EXISTS status_changed_at;
+ALTER TABLE students DROP COLUMN IF EXISTS status;
+DROP TYPE IF EXISTS student_status_enum;
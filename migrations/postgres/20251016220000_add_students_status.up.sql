@@ -0,0 +1,10 @@
+-- =========================================
+-- STUDENT LIFECYCLE STATUS
+-- =========================================
+-- Graduated, withdrawn and suspended students keep their row, grades and attendance instead of being deleted
+CREATE TYPE student_status_enum AS ENUM ('active', 'graduated', 'withdrawn', 'suspended');
+
+ALTER TABLE students ADD COLUMN status student_status_enum NOT NULL DEFAULT 'active';
+ALTER TABLE students ADD COLUMN status_changed_at TIMESTAMP;
+
+CREATE INDEX idx_students_tenant_status ON students (tenant_id, status);
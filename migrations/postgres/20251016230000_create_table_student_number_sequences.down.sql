@@ -0,0 +1,10 @@
+-- =========================================
+-- ROLLBACK STUDENT NUMBER SEQUENCES
+-- =========================================
+DROP POLICY IF EXISTS tenant_isolation ON student_number_counters;
+
+DROP TABLE IF EXISTS student_number_counters;
+
+DROP POLICY IF EXISTS tenant_isolation ON student_number_formats;
+
+DROP TABLE IF EXISTS student_number_formats;
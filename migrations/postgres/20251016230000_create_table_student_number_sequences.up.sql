@@ -0,0 +1,38 @@
+-- =========================================
+-- STUDENT NUMBER FORMATS
+-- =========================================
+-- How student numbers are generated when a student is created without one: prefix, optional year of the
+-- academic year and a zero-padded sequence. Tenants without a row use the default format defined in the application
+CREATE TABLE
+  student_number_formats (
+    tenant_id UUID PRIMARY KEY,
+    prefix VARCHAR(20) NOT NULL DEFAULT '',
+    include_academic_year BOOLEAN NOT NULL DEFAULT TRUE,
+    sequence_digits INT NOT NULL DEFAULT 4 CHECK (sequence_digits BETWEEN 1 AND 10),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+  );
+
+ALTER TABLE student_number_formats ADD CONSTRAINT fk_student_number_formats_tenant_id FOREIGN KEY (tenant_id) REFERENCES tenants (id) ON DELETE CASCADE;
+
+ALTER TABLE student_number_formats ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON student_number_formats USING (tenant_id = current_tenant_id());
+
+-- =========================================
+-- STUDENT NUMBER COUNTERS
+-- =========================================
+-- Last sequence value given per tenant and period (the academic year part of the number, empty when the
+-- format has none). The row is locked while a number is generated so concurrent creations never collide
+CREATE TABLE
+  student_number_counters (
+    tenant_id UUID NOT NULL,
+    period VARCHAR(20) NOT NULL DEFAULT '',
+    last_value INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant_id, period)
+  );
+
+ALTER TABLE student_number_counters ADD CONSTRAINT fk_student_number_counters_tenant_id FOREIGN KEY (tenant_id) REFERENCES tenants (id) ON DELETE CASCADE;
+
+ALTER TABLE student_number_counters ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON student_number_counters USING (tenant_id = current_tenant_id());
@@ -0,0 +1,35 @@
+-- =========================================
+-- ROLLBACK SEQUENCES
+-- =========================================
+CREATE TABLE
+  student_number_counters (
+    tenant_id UUID NOT NULL,
+    period VARCHAR(20) NOT NULL DEFAULT '',
+    last_value INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant_id, period)
+  );
+
+ALTER TABLE student_number_counters ADD CONSTRAINT fk_student_number_counters_tenant_id FOREIGN KEY (tenant_id) REFERENCES tenants (id) ON DELETE CASCADE;
+
+ALTER TABLE student_number_counters ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON student_number_counters USING (tenant_id = current_tenant_id());
+
+INSERT INTO
+  student_number_counters (tenant_id, period, last_value)
+SELECT
+  tenant_id,
+  CASE
+    WHEN name = 'student_number' THEN ''
+    ELSE SUBSTRING(name FROM LENGTH('student_number:') + 1)
+  END,
+  current_value
+FROM
+  sequences
+WHERE
+  name = 'student_number'
+  OR name LIKE 'student_number:%';
+
+DROP POLICY IF EXISTS tenant_isolation ON sequences;
+
+DROP TABLE IF EXISTS sequences;
@@ -0,0 +1,35 @@
+-- =========================================
+-- SEQUENCES
+-- =========================================
+-- Named counters of a tenant for generated identifiers such as student and receipt numbers. A row is locked
+-- with SELECT ... FOR UPDATE while its next value is taken, so concurrent requests never get the same value
+CREATE TABLE
+  sequences (
+    tenant_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    current_value BIGINT NOT NULL DEFAULT 0 CHECK (current_value >= 0),
+    PRIMARY KEY (tenant_id, name)
+  );
+
+ALTER TABLE sequences ADD CONSTRAINT fk_sequences_tenant_id FOREIGN KEY (tenant_id) REFERENCES tenants (id) ON DELETE CASCADE;
+
+ALTER TABLE sequences ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON sequences USING (tenant_id = current_tenant_id());
+
+-- Student number counters become sequences named after their period
+INSERT INTO
+  sequences (tenant_id, name, current_value)
+SELECT
+  tenant_id,
+  CASE
+    WHEN period = '' THEN 'student_number'
+    ELSE 'student_number:' || period
+  END,
+  last_value
+FROM
+  student_number_counters;
+
+DROP POLICY IF EXISTS tenant_isolation ON student_number_counters;
+
+DROP TABLE IF EXISTS student_number_counters;
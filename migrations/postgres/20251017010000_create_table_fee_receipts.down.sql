@@ -0,0 +1,10 @@
+-- =========================================
+-- ROLLBACK FEE RECEIPTS
+-- =========================================
+DROP TRIGGER IF EXISTS trg_fee_receipts_immutable ON fee_receipts;
+
+DROP FUNCTION IF EXISTS fn_prevent_fee_receipt_update();
+
+DROP POLICY IF EXISTS tenant_isolation ON fee_receipts;
+
+DROP TABLE IF EXISTS fee_receipts;
@@ -0,0 +1,44 @@
+-- =========================================
+-- FEE RECEIPTS
+-- =========================================
+-- A receipt copies the school, student and payment details at the time it is issued, so it reads the same
+-- however the fee, student or tenant change later. Paid fees with a receipt cannot be deleted
+CREATE TABLE
+  fee_receipts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    tenant_id UUID NOT NULL,
+    student_fee_id UUID NOT NULL UNIQUE,
+    receipt_number VARCHAR(50) NOT NULL,
+    school_name VARCHAR(255) NOT NULL,
+    school_address TEXT,
+    school_phone VARCHAR(50),
+    student_id UUID,
+    student_number VARCHAR(50) NOT NULL,
+    student_name VARCHAR(255) NOT NULL,
+    fee_type_name VARCHAR(100),
+    academic_year_name VARCHAR(50),
+    amount DECIMAL(10, 2) NOT NULL,
+    payment_method VARCHAR(50),
+    payment_date DATE NOT NULL,
+    issued_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    issued_by UUID,
+    UNIQUE (tenant_id, receipt_number)
+  );
+
+ALTER TABLE fee_receipts ADD CONSTRAINT fk_fee_receipts_tenant_id FOREIGN KEY (tenant_id) REFERENCES tenants (id) ON DELETE CASCADE;
+
+ALTER TABLE fee_receipts ADD CONSTRAINT fk_fee_receipts_student_fee_id FOREIGN KEY (student_fee_id) REFERENCES student_fees (id) ON DELETE RESTRICT;
+
+ALTER TABLE fee_receipts ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON fee_receipts USING (tenant_id = current_tenant_id());
+
+-- Issued receipts are immutable
+CREATE OR REPLACE FUNCTION fn_prevent_fee_receipt_update() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'fee receipt % is immutable', OLD.receipt_number;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_fee_receipts_immutable BEFORE UPDATE ON fee_receipts
+FOR EACH ROW EXECUTE FUNCTION fn_prevent_fee_receipt_update();
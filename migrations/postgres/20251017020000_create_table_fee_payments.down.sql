@@ -0,0 +1,6 @@
+-- =========================================
+-- ROLLBACK FEE PAYMENTS
+-- =========================================
+DROP POLICY IF EXISTS tenant_isolation ON fee_payments;
+
+DROP TABLE IF EXISTS fee_payments;
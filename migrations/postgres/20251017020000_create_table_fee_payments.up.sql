@@ -0,0 +1,46 @@
+-- =========================================
+-- FEE PAYMENTS
+-- =========================================
+-- Each installment paid toward a student fee. The fee is paid when its payments add up to its amount and
+-- partial before that; its payment date and method are those of the latest payment
+CREATE TABLE
+  fee_payments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    tenant_id UUID NOT NULL,
+    student_fee_id UUID NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+    payment_date DATE NOT NULL,
+    payment_method VARCHAR(50) NOT NULL,
+    receipt_number VARCHAR(50),
+    notes TEXT,
+    recorded_by UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tenant_id, receipt_number)
+  );
+
+ALTER TABLE fee_payments ADD CONSTRAINT fk_fee_payments_tenant_id FOREIGN KEY (tenant_id) REFERENCES tenants (id) ON DELETE CASCADE;
+
+ALTER TABLE fee_payments ADD CONSTRAINT fk_fee_payments_student_fee_id FOREIGN KEY (student_fee_id) REFERENCES student_fees (id) ON DELETE RESTRICT;
+
+CREATE INDEX idx_fee_payments_student_fee_id ON fee_payments (student_fee_id, payment_date);
+
+ALTER TABLE fee_payments ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON fee_payments USING (tenant_id = current_tenant_id());
+
+-- Fees already marked paid become a single payment of the full amount
+INSERT INTO
+  fee_payments (tenant_id, student_fee_id, amount, payment_date, payment_method, receipt_number)
+SELECT
+  sf.tenant_id,
+  sf.id,
+  sf.amount,
+  COALESCE(sf.payment_date, sf.due_date),
+  COALESCE(sf.payment_method, 'unknown'),
+  fr.receipt_number
+FROM
+  student_fees sf
+  LEFT JOIN fee_receipts fr ON fr.student_fee_id = sf.id
+WHERE
+  sf.status = 'paid'
+  AND sf.amount > 0;
@@ -0,0 +1,7 @@
+-- =========================================
+-- ROLLBACK FEE TYPE LATE PENALTY
+-- =========================================
+ALTER TABLE fee_types DROP CONSTRAINT IF EXISTS chk_fee_types_penalty_percentage;
+
+ALTER TABLE fee_types DROP COLUMN IF EXISTS penalty_value,
+DROP COLUMN IF EXISTS penalty_type;
@@ -0,0 +1,12 @@
+-- =========================================
+-- FEE TYPE LATE PENALTY
+-- =========================================
+-- A fee not paid by its due date owes a penalty: a flat amount or a percentage of the fee amount.
+-- Fee types without a penalty type charge none
+ALTER TABLE fee_types ADD COLUMN penalty_type VARCHAR(20) CHECK (penalty_type IN ('flat', 'percentage'));
+ALTER TABLE fee_types ADD COLUMN penalty_value DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (penalty_value >= 0);
+
+ALTER TABLE fee_types ADD CONSTRAINT chk_fee_types_penalty_percentage CHECK (
+  penalty_type IS DISTINCT FROM 'percentage'
+  OR penalty_value <= 100
+);
@@ -0,0 +1,4 @@
+-- =========================================
+-- ROLLBACK API KEYS
+-- =========================================
+DROP TABLE IF EXISTS api_keys;
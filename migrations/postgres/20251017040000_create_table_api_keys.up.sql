@@ -0,0 +1,26 @@
+-- =========================================
+-- API KEYS
+-- =========================================
+-- Keys of server-to-server integrations, each acting within one tenant with the permissions in its
+-- scopes. Only the SHA-256 hash of a key is stored; key_prefix identifies it in listings. Keys are
+-- looked up by hash before the tenant is known, so the table has no tenant isolation policy
+CREATE TABLE
+  api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    tenant_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    key_prefix VARCHAR(20) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    scopes JSONB NOT NULL DEFAULT '[]',
+    created_by UUID,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+  );
+
+ALTER TABLE api_keys ADD CONSTRAINT fk_api_keys_tenant_id FOREIGN KEY (tenant_id) REFERENCES tenants (id) ON DELETE CASCADE;
+
+ALTER TABLE api_keys ADD CONSTRAINT fk_api_keys_created_by FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_api_keys_tenant_id ON api_keys (tenant_id, created_at);
@@ -0,0 +1,4 @@
+-- =========================================
+-- ROLLBACK FORCED PASSWORD CHANGE
+-- =========================================
+ALTER TABLE users DROP COLUMN IF EXISTS must_change_password;
@@ -0,0 +1,5 @@
+-- =========================================
+-- FORCED PASSWORD CHANGE
+-- =========================================
+-- Set when an admin resets the password, so the user replaces the temporary password on their next login
+ALTER TABLE users ADD COLUMN must_change_password BOOLEAN NOT NULL DEFAULT FALSE;
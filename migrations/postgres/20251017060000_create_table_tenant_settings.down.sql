@@ -0,0 +1,6 @@
+-- =========================================
+-- ROLLBACK TENANT SETTINGS
+-- =========================================
+DROP POLICY IF EXISTS tenant_isolation ON tenant_settings;
+
+DROP TABLE IF EXISTS tenant_settings;
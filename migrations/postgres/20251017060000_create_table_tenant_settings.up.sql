@@ -0,0 +1,19 @@
+-- =========================================
+-- TENANT SETTINGS
+-- =========================================
+-- Per tenant overrides of configurable behavior as JSON values by key. Keys without a row use the default
+-- defined in the application
+CREATE TABLE
+  tenant_settings (
+    tenant_id UUID NOT NULL,
+    key VARCHAR(100) NOT NULL,
+    value JSONB NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, key)
+  );
+
+ALTER TABLE tenant_settings ADD CONSTRAINT fk_tenant_settings_tenant_id FOREIGN KEY (tenant_id) REFERENCES tenants (id) ON DELETE CASCADE;
+
+ALTER TABLE tenant_settings ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON tenant_settings USING (tenant_id = current_tenant_id());
@@ -0,0 +1,4 @@
+-- =========================================
+-- ROLLBACK GRADING SCALE BAND GRADE POINTS
+-- =========================================
+ALTER TABLE grading_scale_bands DROP COLUMN IF EXISTS grade_point;
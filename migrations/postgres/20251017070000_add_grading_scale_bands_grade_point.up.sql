@@ -0,0 +1,6 @@
+-- =========================================
+-- GRADING SCALE BAND GRADE POINTS
+-- =========================================
+-- Grade point of a letter grade, used to compute credit weighted GPAs.
+-- Bands without one count as many points as there are bands below them, so A B C D E give 4 to 0.
+ALTER TABLE grading_scale_bands ADD COLUMN grade_point DECIMAL(4, 2) CHECK (grade_point BETWEEN 0 AND 10);
@@ -0,0 +1,6 @@
+-- =========================================
+-- ROLLBACK STUDENT GUARDIANS
+-- =========================================
+DROP POLICY IF EXISTS tenant_isolation ON student_guardians;
+
+DROP TABLE IF EXISTS student_guardians;
@@ -0,0 +1,40 @@
+-- =========================================
+-- STUDENT GUARDIANS
+-- =========================================
+-- Parents and guardians linked to a student. One of them is the primary guardian, whose ID is kept in
+-- students.parent_id for clients reading the single parent link.
+CREATE TABLE
+  student_guardians (
+    tenant_id UUID NOT NULL,
+    student_id UUID NOT NULL,
+    parent_id UUID NOT NULL,
+    relationship VARCHAR(50),
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (student_id, parent_id)
+  );
+
+ALTER TABLE student_guardians ADD CONSTRAINT fk_student_guardians_tenant_id FOREIGN KEY (tenant_id) REFERENCES tenants (id) ON DELETE CASCADE,
+ADD CONSTRAINT fk_student_guardians_student_id FOREIGN KEY (student_id) REFERENCES students (id) ON DELETE CASCADE,
+ADD CONSTRAINT fk_student_guardians_parent_id FOREIGN KEY (parent_id) REFERENCES parents (id) ON DELETE CASCADE;
+
+CREATE INDEX idx_student_guardians_parent_id ON student_guardians (parent_id);
+
+CREATE UNIQUE INDEX idx_student_guardians_primary ON student_guardians (student_id) WHERE is_primary;
+
+-- The existing parent links become primary guardians
+INSERT INTO
+  student_guardians (tenant_id, student_id, parent_id, relationship, is_primary)
+SELECT
+  s.tenant_id,
+  s.id,
+  s.parent_id,
+  p.relationship,
+  TRUE
+FROM
+  students s
+  JOIN parents p ON p.id = s.parent_id;
+
+ALTER TABLE student_guardians ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON student_guardians USING (tenant_id = current_tenant_id());
@@ -0,0 +1,49 @@
+-- =========================================
+-- ROLLBACK EMPTY TENANT AND USER SETTINGS
+-- =========================================
+CREATE OR REPLACE FUNCTION fn_audit_log() RETURNS TRIGGER AS $$
+DECLARE
+    tenant UUID;
+    record_uuid UUID;
+BEGIN
+    -- Handle special tenant derivation for tables without direct tenant_id
+    IF TG_TABLE_NAME = 'tenant_user_roles' THEN
+        -- Get tenant_id from the related tenant_user record
+        SELECT tu.tenant_id INTO tenant
+        FROM tenant_users tu
+        WHERE tu.id = COALESCE(NEW.tenant_user_id, OLD.tenant_user_id);
+        record_uuid := NULL; -- Composite key table, no single UUID
+    ELSE
+        tenant := current_tenant_id();
+        record_uuid := COALESCE(NEW.id, OLD.id);
+    END IF;
+
+    IF (TG_OP = 'INSERT') THEN
+        INSERT INTO audit_logs(tenant_id, user_id, table_name, record_id, action, new_data)
+        VALUES (tenant, current_setting('app.current_user', true)::UUID, TG_TABLE_NAME, record_uuid, 'INSERT', row_to_json(NEW)::jsonb);
+        RETURN NEW;
+
+    ELSIF (TG_OP = 'UPDATE') THEN
+        INSERT INTO audit_logs(tenant_id, user_id, table_name, record_id, action, old_data, new_data)
+        VALUES (tenant, current_setting('app.current_user', true)::UUID, TG_TABLE_NAME, record_uuid, 'UPDATE', row_to_json(OLD)::jsonb, row_to_json(NEW)::jsonb);
+        RETURN NEW;
+
+    ELSIF (TG_OP = 'DELETE') THEN
+        INSERT INTO audit_logs(tenant_id, user_id, table_name, record_id, action, old_data)
+        VALUES (tenant, current_setting('app.current_user', true)::UUID, TG_TABLE_NAME, record_uuid, 'DELETE', row_to_json(OLD)::jsonb);
+        RETURN OLD;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP POLICY IF EXISTS own_memberships ON tenant_users;
+
+DROP FUNCTION IF EXISTS current_user_id();
+
+CREATE OR REPLACE FUNCTION current_tenant_id() RETURNS UUID AS $$
+BEGIN
+    RETURN current_setting('app.current_tenant', true)::UUID;
+END;
+$$ LANGUAGE plpgsql STABLE;
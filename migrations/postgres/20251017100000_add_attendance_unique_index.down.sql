@@ -0,0 +1,4 @@
+-- =========================================
+-- ROLLBACK DUPLICATE ATTENDANCE PREVENTION
+-- =========================================
+DROP INDEX IF EXISTS idx_attendance_student_schedule_date;
@@ -0,0 +1,21 @@
+-- =========================================
+-- PREVENT DUPLICATE ATTENDANCE
+-- =========================================
+-- A student has one attendance record per session: concurrent QR scans, or a scan racing the auto absent
+-- job, could insert a second one. Existing duplicates are removed first, keeping a record that is not
+-- absent when there is one.
+DELETE FROM attendance
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (
+            PARTITION BY student_id, schedule_id, attendance_date
+            ORDER BY status = 'absent', id
+        ) AS position
+        FROM attendance
+        WHERE student_id IS NOT NULL AND schedule_id IS NOT NULL
+    ) ranked
+    WHERE position > 1
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_attendance_student_schedule_date
+ON attendance (student_id, schedule_id, attendance_date);
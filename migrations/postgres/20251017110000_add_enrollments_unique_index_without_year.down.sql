@@ -0,0 +1,4 @@
+-- =========================================
+-- ROLLBACK DUPLICATE ENROLLMENT WITHOUT A YEAR PREVENTION
+-- =========================================
+DROP INDEX IF EXISTS idx_enrollments_student_class_subject_no_year;
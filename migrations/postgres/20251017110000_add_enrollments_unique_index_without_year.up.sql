@@ -0,0 +1,9 @@
+-- =========================================
+-- PREVENT DUPLICATE ENROLLMENTS WITHOUT A YEAR
+-- =========================================
+-- The unique index on (student_id, class_subject_id, academic_year_id) treats NULL academic years as
+-- distinct, so it lets a student be enrolled twice in a class subject without a year. This partial index
+-- covers those rows.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_enrollments_student_class_subject_no_year
+ON enrollments (student_id, class_subject_id)
+WHERE academic_year_id IS NULL;
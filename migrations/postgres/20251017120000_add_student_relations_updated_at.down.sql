@@ -0,0 +1,14 @@
+-- =========================================
+-- ROLLBACK UPDATED TIMESTAMPS OF STUDENT RELATIONS
+-- =========================================
+DROP TRIGGER IF EXISTS trg_student_guardians_updated_at ON student_guardians;
+
+DROP TRIGGER IF EXISTS trg_parents_updated_at ON parents;
+
+DROP TRIGGER IF EXISTS trg_classes_updated_at ON classes;
+
+ALTER TABLE student_guardians DROP COLUMN IF EXISTS updated_at;
+
+ALTER TABLE parents DROP COLUMN IF EXISTS updated_at;
+
+ALTER TABLE classes DROP COLUMN IF EXISTS updated_at;
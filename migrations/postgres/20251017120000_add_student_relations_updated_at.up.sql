@@ -0,0 +1,19 @@
+-- =========================================
+-- UPDATED TIMESTAMPS OF STUDENT RELATIONS
+-- =========================================
+-- A student's ETag covers the class, parent and guardians embedded in its JSON, so they need their own
+-- last update time. Existing rows get the migration time.
+ALTER TABLE classes ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE parents ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE student_guardians ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE TRIGGER trg_classes_updated_at BEFORE UPDATE ON classes
+FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+CREATE TRIGGER trg_parents_updated_at BEFORE UPDATE ON parents
+FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+CREATE TRIGGER trg_student_guardians_updated_at BEFORE UPDATE ON student_guardians
+FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
@@ -0,0 +1,5 @@
+-- =========================================
+-- ROLLBACK FEE RECEIPT PENALTY AND TOTAL PAID
+-- =========================================
+ALTER TABLE fee_receipts DROP COLUMN IF EXISTS total_paid,
+DROP COLUMN IF EXISTS penalty_amount;
@@ -0,0 +1,26 @@
+-- =========================================
+-- FEE RECEIPT PENALTY AND TOTAL PAID
+-- =========================================
+-- A receipt shows the fee amount, the late penalty and the sum of the payments actually received. Existing
+-- receipts take them from the fee's payments, or the fee amount for fees marked paid without any.
+ALTER TABLE fee_receipts ADD COLUMN penalty_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+ADD COLUMN total_paid DECIMAL(10, 2);
+
+ALTER TABLE fee_receipts DISABLE TRIGGER trg_fee_receipts_immutable;
+
+UPDATE fee_receipts r
+SET
+  total_paid = COALESCE(
+    (
+      SELECT SUM(p.amount)
+      FROM fee_payments p
+      WHERE p.student_fee_id = r.student_fee_id
+    ),
+    r.amount
+  );
+
+UPDATE fee_receipts SET penalty_amount = GREATEST(total_paid - amount, 0);
+
+ALTER TABLE fee_receipts ENABLE TRIGGER trg_fee_receipts_immutable;
+
+ALTER TABLE fee_receipts ALTER COLUMN total_paid SET NOT NULL;
@@ -0,0 +1,6 @@
+-- =========================================
+-- ROLLBACK DEFAULT GRANTS OF NEW TENANTS
+-- =========================================
+DROP TRIGGER IF EXISTS trg_tenants_default_role_permissions ON tenants;
+
+DROP FUNCTION IF EXISTS fn_seed_default_role_permissions();
@@ -0,0 +1,33 @@
+-- =========================================
+-- DEFAULT GRANTS OF NEW TENANTS
+-- =========================================
+-- New tenants get the same default grants existing tenants got when role_permissions was created, whether
+-- the seeder or an operator creates them. The function runs as its owner since the new tenant is not the
+-- current tenant, so the row level security policy would reject the rows.
+CREATE OR REPLACE FUNCTION fn_seed_default_role_permissions() RETURNS TRIGGER
+SECURITY DEFINER
+SET search_path = public AS $$
+BEGIN
+    INSERT INTO
+      role_permissions (tenant_id, role_id, permission)
+    SELECT
+      NEW.id,
+      r.id,
+      p.permission
+    FROM
+      roles r
+      JOIN (
+        VALUES
+          ('Admin', 'students:read'),
+          ('Admin', 'students:write'),
+          ('Admin', 'students:manage'),
+          ('Teacher', 'students:read'),
+          ('Teacher', 'students:write')
+      ) AS p (role_name, permission) ON p.role_name = r.name
+    ON CONFLICT DO NOTHING;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_tenants_default_role_permissions AFTER INSERT ON tenants
+FOR EACH ROW EXECUTE FUNCTION fn_seed_default_role_permissions();
@@ -0,0 +1,18 @@
+-- =========================================
+-- ROLLBACK STUDENT AGE WINDOW AS TENANT SETTINGS
+-- =========================================
+-- The columns hold a window centered on the expected age, so a window with a single bound is dropped and an
+-- uneven one is narrowed to the whole years around its middle
+ALTER TABLE tenants ADD COLUMN student_age_offset SMALLINT;
+
+ALTER TABLE tenants ADD COLUMN student_age_tolerance SMALLINT NOT NULL DEFAULT 1 CHECK (student_age_tolerance >= 0);
+
+UPDATE tenants t
+SET student_age_offset = (lo.value::int + hi.value::int) / 2 - 1,
+    student_age_tolerance = (hi.value::int - lo.value::int) / 2
+FROM tenant_settings lo, tenant_settings hi
+WHERE lo.tenant_id = t.id AND lo.key = 'student_min_age'
+  AND hi.tenant_id = t.id AND hi.key = 'student_max_age'
+  AND lo.value::int <= hi.value::int;
+
+DELETE FROM tenant_settings WHERE key IN ('student_min_age', 'student_max_age');
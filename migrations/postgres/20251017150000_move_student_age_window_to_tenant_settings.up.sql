@@ -0,0 +1,22 @@
+-- =========================================
+-- STUDENT AGE WINDOW AS TENANT SETTINGS
+-- =========================================
+-- The age window moves from the tenants columns to the student_min_age and student_max_age settings, so admins
+-- can set it through the tenant settings API. The settings are the ages expected in grade level 1; the window
+-- of grade_level + student_age_offset, give or take student_age_tolerance years, starts there at
+-- 1 + student_age_offset - student_age_tolerance and ends at 1 + student_age_offset + student_age_tolerance.
+INSERT INTO tenant_settings (tenant_id, key, value)
+SELECT id, 'student_min_age', to_jsonb(GREATEST(1 + student_age_offset - student_age_tolerance, 0))
+FROM tenants
+WHERE student_age_offset IS NOT NULL
+ON CONFLICT (tenant_id, key) DO NOTHING;
+
+INSERT INTO tenant_settings (tenant_id, key, value)
+SELECT id, 'student_max_age', to_jsonb(GREATEST(1 + student_age_offset + student_age_tolerance, 0))
+FROM tenants
+WHERE student_age_offset IS NOT NULL
+ON CONFLICT (tenant_id, key) DO NOTHING;
+
+ALTER TABLE tenants DROP COLUMN student_age_tolerance;
+
+ALTER TABLE tenants DROP COLUMN student_age_offset;
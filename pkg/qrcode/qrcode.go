@@ -0,0 +1,472 @@
+// Package qrcode is a minimal QR code encoder for short payloads. It encodes bytes in byte mode with
+// error correction level M using versions 1 to 10, which holds up to 213 bytes, and renders PNG images.
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// quietZone is the light border, in modules, required around the symbol
+const quietZone = 4
+
+// ErrTooLong is returned when the data does not fit in the largest supported version
+var ErrTooLong = errors.New("qrcode: data too long")
+
+// versionInfo describes the error correction blocks of a version at level M
+type versionInfo struct {
+	ecPerBlock int
+	blocks1    int // blocks in the first group
+	data1      int // data codewords per block in the first group
+	blocks2    int // blocks in the second group, which hold one more data codeword
+	alignment  []int
+}
+
+// versions holds versions 1 to 10 at error correction level M
+var versions = []versionInfo{
+	{10, 1, 16, 0, nil},
+	{16, 1, 28, 0, []int{6, 18}},
+	{26, 1, 44, 0, []int{6, 22}},
+	{18, 2, 32, 0, []int{6, 26}},
+	{24, 2, 43, 0, []int{6, 30}},
+	{16, 4, 27, 0, []int{6, 34}},
+	{18, 4, 31, 0, []int{6, 22, 38}},
+	{22, 2, 38, 2, []int{6, 24, 42}},
+	{22, 3, 36, 2, []int{6, 26, 46}},
+	{26, 4, 43, 1, []int{6, 28, 50}},
+}
+
+// dataCodewords returns the number of data codewords of the version
+func (v versionInfo) dataCodewords() int {
+	return v.blocks1*v.data1 + v.blocks2*(v.data1+1)
+}
+
+// Code is an encoded QR symbol
+type Code struct {
+	size     int
+	modules  [][]bool // true is dark
+	function [][]bool // true for modules that are not data
+}
+
+// Size returns the number of modules per side, without the quiet zone
+func (q *Code) Size() int {
+	return q.size
+}
+
+// Dark reports whether the module at column x and row y is dark
+func (q *Code) Dark(x, y int) bool {
+	return q.modules[y][x]
+}
+
+// Encode encodes data into the smallest version that holds it
+func Encode(data []byte) (*Code, error) {
+	version := 0
+	for i, v := range versions {
+		countBits := 8
+		if i+1 >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(data) <= 8*v.dataCodewords() {
+			version = i + 1
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+
+	info := versions[version-1]
+	codewords := interleave(info, encodeData(version, info, data))
+
+	size := 17 + 4*version
+	q := &Code{size: size, modules: newGrid(size), function: newGrid(size)}
+	q.drawFunctionPatterns(version, info)
+	q.drawCodewords(codewords)
+
+	// Pick the mask with the lowest penalty
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormatBits(mask)
+		if penalty := q.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		q.applyMask(mask)
+	}
+	q.applyMask(bestMask)
+	q.drawFormatBits(bestMask)
+	return q, nil
+}
+
+// Image renders the code with scale pixels per module and the quiet zone
+func (q *Code) Image(scale int) image.Image {
+	if scale < 1 {
+		scale = 1
+	}
+	side := (q.size + 2*quietZone) * scale
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if !q.modules[y][x] {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetColorIndex((x+quietZone)*scale+dx, (y+quietZone)*scale+dy, 1)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// PNG renders the code as a PNG image with scale pixels per module
+func (q *Code) PNG(scale int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, q.Image(scale)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeData builds the data codewords: byte mode header, data, terminator and padding
+func encodeData(version int, info versionInfo, data []byte) []byte {
+	capacity := info.dataCodewords()
+	var bits bitBuffer
+	bits.append(0x4, 4) // byte mode
+	if version >= 10 {
+		bits.append(len(data), 16)
+	} else {
+		bits.append(len(data), 8)
+	}
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+
+	// Terminator of up to four zero bits, then zero bits up to a byte boundary
+	terminator := 8*capacity - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bits.append(0, terminator)
+	if rem := len(bits) % 8; rem != 0 {
+		bits.append(0, 8-rem)
+	}
+
+	codewords := bits.bytes()
+	for pad := byte(0xEC); len(codewords) < capacity; pad ^= 0xEC ^ 0x11 {
+		codewords = append(codewords, pad)
+	}
+	return codewords
+}
+
+// interleave splits the data into blocks, adds error correction to each and interleaves them
+func interleave(info versionInfo, data []byte) []byte {
+	generator := rsGenerator(info.ecPerBlock)
+	var dataBlocks, ecBlocks [][]byte
+	offset := 0
+	for i := 0; i < info.blocks1+info.blocks2; i++ {
+		length := info.data1
+		if i >= info.blocks1 {
+			length++
+		}
+		block := data[offset : offset+length]
+		offset += length
+		dataBlocks = append(dataBlocks, block)
+		ecBlocks = append(ecBlocks, rsRemainder(block, generator))
+	}
+
+	result := make([]byte, 0, len(data)+len(ecBlocks)*info.ecPerBlock)
+	for i := 0; i <= info.data1; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < info.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+// drawFunctionPatterns draws the timing, finder and alignment patterns and reserves the format and version areas
+func (q *Code) drawFunctionPatterns(version int, info versionInfo) {
+	for i := 0; i < q.size; i++ {
+		q.setFunction(6, i, i%2 == 0)
+		q.setFunction(i, 6, i%2 == 0)
+	}
+
+	q.drawFinder(3, 3)
+	q.drawFinder(q.size-4, 3)
+	q.drawFinder(3, q.size-4)
+
+	last := len(info.alignment) - 1
+	for i, y := range info.alignment {
+		for j, x := range info.alignment {
+			// Skip the positions overlapping the finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			q.drawAlignment(x, y)
+		}
+	}
+
+	// Reserve the format areas; the real bits are drawn once the mask is known
+	q.drawFormatBits(0)
+	q.drawVersion(version)
+}
+
+// drawFinder draws a finder pattern and its separator centered at x, y
+func (q *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= q.size || yy < 0 || yy >= q.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			q.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawAlignment draws an alignment pattern centered at x, y
+func (q *Code) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			q.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormatBits draws both copies of the format information for level M and the mask, and the dark module
+func (q *Code) drawFormatBits(mask int) {
+	// Level M is 00, followed by the mask and a BCH(15,5) remainder
+	data := mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+
+	for i := 0; i <= 5; i++ {
+		q.setFunction(8, i, bit(bits, i))
+	}
+	q.setFunction(8, 7, bit(bits, 6))
+	q.setFunction(8, 8, bit(bits, 7))
+	q.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		q.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	for i := 0; i < 8; i++ {
+		q.setFunction(q.size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunction(8, q.size-15+i, bit(bits, i))
+	}
+	q.setFunction(8, q.size-8, true)
+}
+
+// drawVersion draws both copies of the version information, present from version 7
+func (q *Code) drawVersion(version int) {
+	if version < 7 {
+		return
+	}
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := version<<12 | rem
+	for i := 0; i < 18; i++ {
+		a, b := q.size-11+i%3, i/3
+		q.setFunction(a, b, bit(bits, i))
+		q.setFunction(b, a, bit(bits, i))
+	}
+}
+
+// drawCodewords places the codewords in the zigzag order, two columns at a time from the bottom right
+func (q *Code) drawCodewords(codewords []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = q.size - 1 - vert
+				}
+				if !q.function[y][x] && i < len(codewords)*8 {
+					q.modules[y][x] = bit(int(codewords[i>>3]), 7-(i&7))
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask flips the data modules selected by the mask; applying it twice undoes it
+func (q *Code) applyMask(mask int) {
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if q.function[y][x] {
+				continue
+			}
+			var flip bool
+			switch mask {
+			case 0:
+				flip = (x+y)%2 == 0
+			case 1:
+				flip = y%2 == 0
+			case 2:
+				flip = x%3 == 0
+			case 3:
+				flip = (x+y)%3 == 0
+			case 4:
+				flip = (x/3+y/2)%2 == 0
+			case 5:
+				flip = x*y%2+x*y%3 == 0
+			case 6:
+				flip = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				flip = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if flip {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol with the four mask evaluation rules; lower is better
+func (q *Code) penalty() int {
+	penalty := 0
+	finderLike := []bool{true, false, true, true, true, false, true}
+
+	for _, vertical := range []bool{false, true} {
+		for a := 0; a < q.size; a++ {
+			at := func(b int) bool {
+				if vertical {
+					return q.modules[b][a]
+				}
+				return q.modules[a][b]
+			}
+
+			// Rule 1: runs of five or more modules of the same color
+			run := 1
+			for b := 1; b <= q.size; b++ {
+				if b < q.size && at(b) == at(b-1) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					penalty += 3 + run - 5
+				}
+				run = 1
+			}
+
+			// Rule 3: finder-like patterns with four light modules on either side
+			for b := 0; b+len(finderLike) <= q.size; b++ {
+				matches := true
+				for k, dark := range finderLike {
+					if at(b+k) != dark {
+						matches = false
+						break
+					}
+				}
+				if matches && (q.lightRun(at, b-4, b) || q.lightRun(at, b+7, b+11)) {
+					penalty += 40
+				}
+			}
+		}
+	}
+
+	// Rule 2: 2x2 blocks of the same color
+	dark := 0
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if q.modules[y][x] {
+				dark++
+			}
+			if x+1 < q.size && y+1 < q.size {
+				c := q.modules[y][x]
+				if q.modules[y][x+1] == c && q.modules[y+1][x] == c && q.modules[y+1][x+1] == c {
+					penalty += 3
+				}
+			}
+		}
+	}
+
+	// Rule 4: deviation of the dark share from 50%, in steps of 5%
+	total := q.size * q.size
+	deviation := abs(dark*100/total - 50)
+	penalty += deviation / 5 * 10
+	return penalty
+}
+
+// lightRun reports whether the modules in [from, to) are light, treating modules outside the symbol as light
+func (q *Code) lightRun(at func(int) bool, from, to int) bool {
+	for b := from; b < to; b++ {
+		if b >= 0 && b < q.size && at(b) {
+			return false
+		}
+	}
+	return true
+}
+
+// setFunction sets a function module
+func (q *Code) setFunction(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.function[y][x] = true
+}
+
+// newGrid creates a size by size grid
+func newGrid(size int) [][]bool {
+	grid := make([][]bool, size)
+	for i := range grid {
+		grid[i] = make([]bool, size)
+	}
+	return grid
+}
+
+// bit reports whether bit i of value is set
+func bit(value, i int) bool {
+	return (value>>i)&1 != 0
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// bitBuffer is a sequence of bits
+type bitBuffer []bool
+
+// append appends the low length bits of value, most significant first
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, bit(value, i))
+	}
+}
+
+// bytes packs the bits into bytes; the length must be a multiple of 8
+func (b bitBuffer) bytes() []byte {
+	result := make([]byte, len(b)/8)
+	for i, set := range b {
+		if set {
+			result[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return result
+}
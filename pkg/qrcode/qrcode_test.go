@@ -0,0 +1,244 @@
+package qrcode
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// The expected values below are the published ones from ISO/IEC 18004: the Reed-Solomon example of
+// annex I, the format and version information tables of annexes C and D, and the byte mode capacities
+// of table 7 at level M.
+
+func TestRSRemainder(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		ec   []byte
+	}{
+		{
+			name: "01234567 at 1-M",
+			data: []byte{16, 32, 12, 86, 97, 128, 236, 17, 236, 17, 236, 17, 236, 17, 236, 17},
+			ec:   []byte{165, 36, 212, 193, 237, 54, 199, 135, 44, 85},
+		},
+		{
+			name: "HELLO WORLD at 1-M",
+			data: []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17},
+			ec:   []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rsRemainder(tt.data, rsGenerator(len(tt.ec))); !bytes.Equal(got, tt.ec) {
+				t.Errorf("rsRemainder() = %v, want %v", got, tt.ec)
+			}
+		})
+	}
+}
+
+func TestEncodeData(t *testing.T) {
+	// Mode 0100, count 00000010, "hi", terminator 0000, then the EC/11 padding
+	want := []byte{0x40, 0x26, 0x86, 0x90, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11}
+	if got := encodeData(1, versions[0], []byte("hi")); !bytes.Equal(got, want) {
+		t.Errorf("encodeData() = % X, want % X", got, want)
+	}
+}
+
+func TestEncodeVersion(t *testing.T) {
+	// Byte mode capacity of versions 1 to 10 at level M
+	capacities := []int{14, 26, 42, 62, 84, 106, 122, 152, 180, 213}
+	for i, capacity := range capacities {
+		version := i + 1
+		for _, tt := range []struct {
+			length  int
+			version int
+		}{
+			{capacity, version},
+			{capacity + 1, version + 1},
+		} {
+			code, err := Encode(bytes.Repeat([]byte{'a'}, tt.length))
+			if tt.version > len(versions) {
+				if err != ErrTooLong {
+					t.Errorf("Encode(%d bytes) error = %v, want ErrTooLong", tt.length, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("Encode(%d bytes) error = %v", tt.length, err)
+			}
+			if want := 17 + 4*tt.version; code.Size() != want {
+				t.Errorf("Encode(%d bytes) size = %d, want %d (version %d)", tt.length, code.Size(), want, tt.version)
+			}
+		}
+	}
+}
+
+func TestFormatBits(t *testing.T) {
+	// Format information of level M for masks 0 to 7
+	want := []string{
+		"101010000010010",
+		"101000100100101",
+		"101111001111100",
+		"101101101001011",
+		"100010111111001",
+		"100000011001110",
+		"100111110010111",
+		"100101010100000",
+	}
+	for mask, bits := range want {
+		q := &Code{size: 21, modules: newGrid(21), function: newGrid(21)}
+		q.drawFormatBits(mask)
+		first, second := q.formatBits()
+		if first != bits || second != bits {
+			t.Errorf("mask %d: format bits = %s and %s, want %s", mask, first, second, bits)
+		}
+	}
+}
+
+func TestVersionBits(t *testing.T) {
+	want := map[int]string{
+		7:  "000111110010010100",
+		8:  "001000010110111100",
+		9:  "001001101010011001",
+		10: "001010010011010011",
+	}
+	for version, bits := range want {
+		size := 17 + 4*version
+		q := &Code{size: size, modules: newGrid(size), function: newGrid(size)}
+		q.drawVersion(version)
+		var right, bottom strings.Builder
+		for i := 17; i >= 0; i-- {
+			a, b := size-11+i%3, i/3
+			right.WriteByte(moduleBit(q.modules[b][a]))
+			bottom.WriteByte(moduleBit(q.modules[a][b]))
+		}
+		if right.String() != bits || bottom.String() != bits {
+			t.Errorf("version %d: version bits = %s and %s, want %s", version, right.String(), bottom.String(), bits)
+		}
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	payloads := []string{
+		"hi",
+		"https://example.com/attendance/scan?token=0123456789abcdef",
+		strings.Repeat("kelasgo ", 20),
+	}
+	formats := []string{
+		"101010000010010", "101000100100101", "101111001111100", "101101101001011",
+		"100010111111001", "100000011001110", "100111110010111", "100101010100000",
+	}
+	for _, payload := range payloads {
+		code, err := Encode([]byte(payload))
+		if err != nil {
+			t.Fatalf("Encode(%q) error = %v", payload, err)
+		}
+		version := (code.Size() - 17) / 4
+		info := versions[version-1]
+
+		// Both copies of the format information name the same mask at level M
+		first, second := code.formatBits()
+		if first != second {
+			t.Fatalf("Encode(%q) format copies differ: %s and %s", payload, first, second)
+		}
+		mask := -1
+		for m, bits := range formats {
+			if bits == first {
+				mask = m
+			}
+		}
+		if mask < 0 {
+			t.Fatalf("Encode(%q) format bits %s are not level M", payload, first)
+		}
+		if !code.Dark(8, code.Size()-8) {
+			t.Errorf("Encode(%q) dark module is light", payload)
+		}
+
+		// The chosen mask has the lowest penalty
+		penalties := make([]int, 8)
+		for m := range penalties {
+			code.applyMask(mask)
+			code.applyMask(m)
+			code.drawFormatBits(m)
+			penalties[m] = code.penalty()
+			code.applyMask(m)
+			code.applyMask(mask)
+		}
+		code.drawFormatBits(mask)
+		for m, p := range penalties {
+			if p < penalties[mask] || (p == penalties[mask] && m < mask) {
+				t.Errorf("Encode(%q) chose mask %d with penalty %d, mask %d has %d", payload, mask, penalties[mask], m, p)
+			}
+		}
+
+		// Unmasking and reading the modules back gives the encoded codewords
+		want := interleave(info, encodeData(version, info, []byte(payload)))
+		code.applyMask(mask)
+		got := code.readCodewords(len(want))
+		code.applyMask(mask)
+		if !bytes.Equal(got, want) {
+			t.Errorf("Encode(%q) codewords = % X, want % X", payload, got, want)
+		}
+	}
+}
+
+// formatBits reads both copies of the format information, most significant bit first
+func (q *Code) formatBits() (string, string) {
+	var first, second [15]byte
+	for i := 0; i < 15; i++ {
+		var x, y int
+		switch {
+		case i <= 5:
+			x, y = 8, i
+		case i == 6:
+			x, y = 8, 7
+		case i == 7:
+			x, y = 8, 8
+		case i == 8:
+			x, y = 7, 8
+		default:
+			x, y = 14-i, 8
+		}
+		first[14-i] = moduleBit(q.modules[y][x])
+		if i < 8 {
+			x, y = q.size-1-i, 8
+		} else {
+			x, y = 8, q.size-15+i
+		}
+		second[14-i] = moduleBit(q.modules[y][x])
+	}
+	return string(first[:]), string(second[:])
+}
+
+// readCodewords reads count codewords from the data modules in placement order
+func (q *Code) readCodewords(count int) []byte {
+	result := make([]byte, count)
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = q.size - 1 - vert
+				}
+				if !q.function[y][x] && i < count*8 {
+					if q.modules[y][x] {
+						result[i>>3] |= 1 << (7 - i&7)
+					}
+					i++
+				}
+			}
+		}
+	}
+	return result
+}
+
+func moduleBit(dark bool) byte {
+	if dark {
+		return '1'
+	}
+	return '0'
+}